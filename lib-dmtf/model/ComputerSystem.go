@@ -867,7 +867,7 @@ type Metrics struct {
 //	On Success - returns nil value
 //	On Failure - return non nil value
 func (c *ComputerSystem) SaveInMemory(deviceUUID string) *errors.Error {
-	connPool, err := common.GetDBConnection(common.InMemory)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("computersystem", common.InMemory))
 	if err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to connect to DB: ", err.Error())
 	}
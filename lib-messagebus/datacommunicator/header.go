@@ -21,6 +21,7 @@ package datacommunicator
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // BrokerType defines the underline MQ platform to be selected for the
@@ -45,6 +46,23 @@ type MQBus interface {
 	Close() error
 }
 
+// OffsetStore defines the functions for persisting the position of the last
+// consumed message of a pipe, so that the consumption can be resumed from it
+// after the consumer is restarted. GetOffset returns an empty offset without
+// error when no offset is stored for the pipe.
+type OffsetStore interface {
+	GetOffset(pipe string) (string, error)
+	SaveOffset(pipe, offset string) error
+}
+
+// OffsetConsumer is implemented by the Broker platforms which support resuming
+// the consumption of a pipe from a stored offset. AcceptFromOffset consumes the
+// messages published after the stored offset and persists the offset of the
+// consumed messages in the given interval.
+type OffsetConsumer interface {
+	AcceptFromOffset(fn MsgProcess, store OffsetStore, persistInterval time.Duration) error
+}
+
 // MsgProcess defines the functions for processing accepted messages. Any client
 // who wants to accept and handle the events / notifications / messages, should
 // implement this function as part of their procedure. That same function should
@@ -90,6 +108,20 @@ func Communicator(bt string, messageQueueConfigPath, pipe string) (MQBus, error)
 	}
 }
 
+// PipeExists checks whether the specified pipe (Topic / Stream) is available
+// on the configured Broker platform. It is meant for validating the pipes
+// advertised by the clients before they are subscribed to.
+func PipeExists(bt, pipe string) (bool, error) {
+	switch bt {
+	case KAFKA:
+		return kafkaTopicExists(pipe)
+	case REDISSTREAMS:
+		return redisStreamExists(pipe)
+	default:
+		return false, fmt.Errorf("Broker: \"Broker Type\" is not supported - %s", bt)
+	}
+}
+
 // Encode converts the interface into Byte stream (ENCODE).
 func Encode(d interface{}) ([]byte, error) {
 
@@ -22,6 +22,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"sync"
@@ -149,6 +150,40 @@ func kafkaConnect(kp *KafkaPacket) error {
 	return nil
 }
 
+// kafkaTopicExists connects to the first reachable KAFKA server and reads the
+// partitions of the given topic. UnknownTopicOrPartition from the server means
+// the topic is not available on the broker.
+func kafkaTopicExists(topic string) (bool, error) {
+	if MQ.KafkaF == nil {
+		return false, fmt.Errorf("kafka configuration is not available")
+	}
+	kp := new(KafkaPacket)
+	if e := kafkaConnect(kp); e != nil {
+		return false, e
+	}
+	var lastErr error
+	for _, server := range kp.ServersInfo {
+		conn, e := kp.DialerConn.DialContext(context.Background(), "tcp", server)
+		if e != nil {
+			lastErr = e
+			continue
+		}
+		_, e = conn.ReadPartitions(topic)
+		conn.Close()
+		if e == nil {
+			return true, nil
+		}
+		if errors.Is(e, kafka.UnknownTopicOrPartition) {
+			return false, nil
+		}
+		lastErr = e
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no kafka servers configured")
+	}
+	return false, fmt.Errorf("unable to read the topic %s from kafka: %s", topic, lastErr.Error())
+}
+
 // Distribute defines the Producer / Publisher role and functionality. Writer
 // would be created for each Pipe comes-in for communication. If Writer already
 // exists, that connection would be used for this call. Before publishing the
@@ -18,7 +18,9 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -27,6 +29,9 @@ import (
 
 const (
 	DefaultTLSMinVersion = tls.VersionTLS12
+	// readGroupBlockTime is the time a read of the group waits for the new messages,
+	// the reader checks whether it is stopped after every read
+	readGroupBlockTime = time.Second
 )
 
 // RedisStreamsPacket defines the RedisStreamsPacket Message Packet Object. Apart from Base Packet, it
@@ -104,12 +109,33 @@ func (rp *RedisStreamsPacket) Distribute(data interface{}) error {
 	return nil
 }
 
-// Accept implmentation need to be added
+// redisStreamExists checks whether the stream with the given name is present
+func redisStreamExists(stream string) (bool, error) {
+	if MQ.RedisStreams == nil {
+		return false, fmt.Errorf("redis streams configuration is not available")
+	}
+	redisClient, err := getDBConnection()
+	if err != nil {
+		return false, err
+	}
+	defer redisClient.Close()
+	count, err := redisClient.Exists(context.Background(), stream).Result()
+	if err != nil {
+		return false, fmt.Errorf("unable to check the stream %s in redis: %s", stream, err.Error())
+	}
+	return count > 0, nil
+}
+
+// Accept reads the messages of the stream as part of the group until reading fails.
+// The readers of the stream are stopped before returning, so that the stream is not
+// read by a consumer which has returned.
 func (rp *RedisStreamsPacket) Accept(fn MsgProcess) error {
 	redisClient, err := getDBConnection()
 	if err != nil {
 		return err
 	}
+	defer redisClient.Close()
+	// create a unique consumer id for the  instance
 	var id = uuid.NewV4().String()
 	rerr := redisClient.XGroupCreateMkStream(context.Background(),
 		rp.pipe, EVENTREADERGROUPNAME, "$").Err()
@@ -119,58 +145,170 @@ func (rp *RedisStreamsPacket) Accept(fn MsgProcess) error {
 			if err != nil {
 				return err
 			}
+			defer redisClient.Close()
 		}
 
 	}
+	return runReaders(
+		func(ctx context.Context) error {
+			return rp.checkUnacknowledgedEvents(ctx, fn, id)
+		},
+		func(ctx context.Context) error {
+			return rp.readGroup(ctx, redisClient, fn, id)
+		},
+	)
+}
 
-	// create a unique consumer id for the  instance
-	errChan := make(chan error)
-	defer close(errChan)
-	go rp.checkUnacknowledgedEvents(fn, id, errChan)
-	err = <-errChan
-	if err != nil {
-		return err
+// runReaders runs the readers until one of them fails and returns its error. The context of the
+// readers is cancelled on the failure and runReaders returns only after all the readers have exited.
+func runReaders(readers ...func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the channel can hold the error of every reader, so that the readers
+	// which fail after the first one don't block on sending the error
+	errChan := make(chan error, len(readers))
+	var wg sync.WaitGroup
+	for _, read := range readers {
+		wg.Add(1)
+		go func(read func(ctx context.Context) error) {
+			defer wg.Done()
+			errChan <- read(ctx)
+		}(read)
 	}
-	go func() {
-		for {
-			events, err := redisClient.XReadGroup(context.Background(),
-				&redis.XReadGroupArgs{
-					Group:    EVENTREADERGROUPNAME,
-					Consumer: id,
-					Count:    1,
-					Streams:  []string{rp.pipe, ">"},
-				}).Result()
-			if err != nil {
-				errChan <- fmt.Errorf("unable to get data from the group %s", err.Error())
-				if strings.Contains(err.Error(), " connection timed out") {
-					redisClient, err = getDBConnection()
-					if err != nil {
-						errChan <- err
-						return
-					}
-				}
-			} else {
+	err := <-errChan
+	cancel()
+	wg.Wait()
+	return err
+}
 
-				if len(events) > 0 && len(events[0].Messages) > 0 {
-					messageID := events[0].Messages[0].ID
-					evtStr := events[0].Messages[0].Values["data"].(string)
-					var evt interface{}
-					err := Decode([]byte(evtStr), &evt)
-					if err != nil {
-						errChan <- err
-						return
-					}
-					fn(evt)
-					redisClient.XAck(context.Background(), rp.pipe, EVENTREADERGROUPNAME, messageID)
-				}
+// readGroup reads the new messages of the stream as part of the group until reading fails
+// or the context is cancelled
+func (rp *RedisStreamsPacket) readGroup(ctx context.Context, redisClient *redis.Client, fn MsgProcess, id string) error {
+	for ctx.Err() == nil {
+		events, err := redisClient.XReadGroup(ctx,
+			&redis.XReadGroupArgs{
+				Group:    EVENTREADERGROUPNAME,
+				Consumer: id,
+				Count:    1,
+				Streams:  []string{rp.pipe, ">"},
+				Block:    readGroupBlockTime,
+			}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				break
 			}
+			return fmt.Errorf("unable to get data from the group %s", err.Error())
 		}
-	}()
-	err = <-errChan
+		if len(events) > 0 && len(events[0].Messages) > 0 {
+			messageID := events[0].Messages[0].ID
+			evtStr := events[0].Messages[0].Values["data"].(string)
+			var evt interface{}
+			if err := Decode([]byte(evtStr), &evt); err != nil {
+				return err
+			}
+			fn(evt)
+			redisClient.XAck(context.Background(), rp.pipe, EVENTREADERGROUPNAME, messageID)
+		}
+	}
+	return ctx.Err()
+}
+
+// AcceptFromOffset reads the messages of the stream which are published after the offset
+// stored for the stream. Unlike Accept, the messages are not read as part of the group,
+// so the stream ID of the last consumed message is persisted in the store in the given
+// interval to resume the consumption from it after a restart.
+func (rp *RedisStreamsPacket) AcceptFromOffset(fn MsgProcess, store OffsetStore, persistInterval time.Duration) error {
+	redisClient, err := getDBConnection()
 	if err != nil {
 		return err
 	}
-	return nil
+	defer redisClient.Close()
+	lastID := func() (string, error) {
+		info, err := redisClient.XInfoStream(context.Background(), rp.pipe).Result()
+		if err != nil {
+			// the stream is created with the first message, so there are no messages to skip
+			if strings.Contains(err.Error(), "no such key") {
+				return "0-0", nil
+			}
+			return "", fmt.Errorf("unable to get the last ID of the stream %s: %s", rp.pipe, err.Error())
+		}
+		return info.LastGeneratedID, nil
+	}
+	read := func(offset string) ([]redis.XMessage, error) {
+		streams, err := redisClient.XRead(context.Background(), &redis.XReadArgs{
+			Streams: []string{rp.pipe, offset},
+			Count:   100,
+			Block:   time.Second,
+		}).Result()
+		if err == redis.Nil {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the stream %s: %s", rp.pipe, err.Error())
+		}
+		if len(streams) == 0 {
+			return nil, nil
+		}
+		return streams[0].Messages, nil
+	}
+	return consumeFromOffset(rp.pipe, lastID, read, fn, store, persistInterval)
+}
+
+// consumeFromOffset reads the messages of the pipe starting after the stored offset until the
+// read fails. The offset of the last consumed message is persisted in the given interval
+// and before returning. If no offset is stored, only the messages published after the last
+// message of the pipe at the start are consumed. The offset is resolved to the ID of that
+// message once, so that the messages published between the reads are not skipped.
+// The messages which can't be decoded are logged and skipped.
+func consumeFromOffset(pipe string, lastID func() (string, error), read func(offset string) ([]redis.XMessage, error), fn MsgProcess, store OffsetStore, persistInterval time.Duration) error {
+	offset, err := store.GetOffset(pipe)
+	if err != nil {
+		return fmt.Errorf("unable to get the stored offset of %s: %s", pipe, err.Error())
+	}
+	if offset == "" {
+		if offset, err = lastID(); err != nil {
+			return err
+		}
+	}
+	savedOffset := offset
+	lastPersisted := time.Now()
+	persist := func() {
+		if offset != savedOffset {
+			if err := store.SaveOffset(pipe, offset); err != nil {
+				log.Printf("unable to save the offset %s of %s: %s", offset, pipe, err.Error())
+			} else {
+				savedOffset = offset
+			}
+		}
+		lastPersisted = time.Now()
+	}
+	defer persist()
+	for {
+		messages, err := read(offset)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			offset = message.ID
+			evtStr, ok := message.Values["data"].(string)
+			if !ok {
+				log.Printf("skipping the message %s of %s without data", message.ID, pipe)
+				continue
+			}
+			var evt interface{}
+			if err := Decode([]byte(evtStr), &evt); err != nil {
+				log.Printf("skipping the message %s of %s: %s", message.ID, pipe, err.Error())
+				continue
+			}
+			fn(evt)
+		}
+		if time.Since(lastPersisted) >= persistInterval {
+			persist()
+		}
+	}
 }
 
 // Read implmentation need to be added
@@ -193,14 +331,14 @@ func (rp *RedisStreamsPacket) Remove() error {
 func (rp *RedisStreamsPacket) Close() error {
 	return nil
 }
-func (rp *RedisStreamsPacket) checkUnacknowledgedEvents(fn MsgProcess, id string, errChan chan<- error) {
+func (rp *RedisStreamsPacket) checkUnacknowledgedEvents(ctx context.Context, fn MsgProcess, id string) error {
 	redisClient, err := getDBConnection()
 	if err != nil {
-		errChan <- err
-		return
+		return err
 	}
+	defer func() { redisClient.Close() }()
 	for {
-		events, _, err := redisClient.XAutoClaim(context.Background(), &redis.XAutoClaimArgs{
+		events, _, err := redisClient.XAutoClaim(ctx, &redis.XAutoClaimArgs{
 			Stream:   rp.pipe,
 			Group:    EVENTREADERGROUPNAME,
 			Consumer: id,
@@ -210,10 +348,10 @@ func (rp *RedisStreamsPacket) checkUnacknowledgedEvents(fn MsgProcess, id string
 		}).Result()
 		if err != nil {
 			if strings.Contains(err.Error(), " connection timed out") {
+				redisClient.Close()
 				redisClient, err = getDBConnection()
 				if err != nil {
-					errChan <- err
-					return
+					return err
 				}
 			}
 		}
@@ -221,14 +359,16 @@ func (rp *RedisStreamsPacket) checkUnacknowledgedEvents(fn MsgProcess, id string
 			messageID := event.ID
 			evtStr := event.Values["data"].(string)
 			var evt interface{}
-			Decode([]byte(evtStr), &evt)
-			if err != nil {
-				errChan <- err
-				return
+			if err := Decode([]byte(evtStr), &evt); err != nil {
+				return err
 			}
 			fn(evt)
 			redisClient.XAck(context.Background(), rp.pipe, EVENTREADERGROUPNAME, messageID)
 		}
-		time.Sleep(time.Minute * 10)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Minute * 10):
+		}
 	}
 }
@@ -0,0 +1,165 @@
+//(C) Copyright [2021] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package datacommunicator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type mockOffsetStore struct {
+	offsets map[string]string
+	err     error
+}
+
+func (m *mockOffsetStore) GetOffset(pipe string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.offsets[pipe], nil
+}
+
+func (m *mockOffsetStore) SaveOffset(pipe, offset string) error {
+	m.offsets[pipe] = offset
+	return nil
+}
+
+func TestConsumeFromOffset(t *testing.T) {
+	store := &mockOffsetStore{offsets: map[string]string{"EVENTS": "5-0"}}
+	stream := []redis.XMessage{
+		{ID: "6-0", Values: map[string]interface{}{"data": `"event6"`}},
+		{ID: "7-0", Values: map[string]interface{}{"data": `"event7"`}},
+	}
+	var readOffsets []string
+	read := func(offset string) ([]redis.XMessage, error) {
+		readOffsets = append(readOffsets, offset)
+		if len(readOffsets) == 1 {
+			return stream, nil
+		}
+		return nil, fmt.Errorf("connection closed")
+	}
+	lastID := func() (string, error) { return "9-0", nil }
+	var consumed []interface{}
+	err := consumeFromOffset("EVENTS", lastID, read, func(d interface{}) { consumed = append(consumed, d) }, store, time.Hour)
+	if err == nil {
+		t.Errorf("consumeFromOffset() should return the read error")
+	}
+	if want := []string{"5-0", "7-0"}; !reflect.DeepEqual(readOffsets, want) {
+		t.Errorf("consumeFromOffset() read offsets = %v, want %v", readOffsets, want)
+	}
+	if want := []interface{}{"event6", "event7"}; !reflect.DeepEqual(consumed, want) {
+		t.Errorf("consumeFromOffset() consumed = %v, want %v", consumed, want)
+	}
+	if store.offsets["EVENTS"] != "7-0" {
+		t.Errorf("consumeFromOffset() stored offset = %v, want 7-0", store.offsets["EVENTS"])
+	}
+}
+
+func TestConsumeFromOffsetWithoutStoredOffset(t *testing.T) {
+	store := &mockOffsetStore{offsets: map[string]string{}}
+	var readOffsets []string
+	// the reads don't return any message till the read fails
+	read := func(offset string) ([]redis.XMessage, error) {
+		readOffsets = append(readOffsets, offset)
+		if len(readOffsets) == 3 {
+			return nil, fmt.Errorf("connection closed")
+		}
+		return nil, nil
+	}
+	lastID := func() (string, error) { return "9-0", nil }
+	consumeFromOffset("ALERTS", lastID, read, func(d interface{}) {}, store, time.Hour)
+	// the offset is resolved once, so that the messages published between the reads are consumed
+	if want := []string{"9-0", "9-0", "9-0"}; !reflect.DeepEqual(readOffsets, want) {
+		t.Errorf("consumeFromOffset() read offsets = %v, want %v", readOffsets, want)
+	}
+
+	readOffsets = nil
+	err := consumeFromOffset("ALERTS", func() (string, error) { return "", fmt.Errorf("connection refused") }, read, func(d interface{}) {}, store, time.Hour)
+	if err == nil || len(readOffsets) > 0 {
+		t.Errorf("consumeFromOffset() should fail without reading when the last ID is not available")
+	}
+}
+
+func TestConsumeFromOffsetStoreError(t *testing.T) {
+	store := &mockOffsetStore{offsets: map[string]string{}, err: fmt.Errorf("connection refused")}
+	var reads int
+	read := func(offset string) ([]redis.XMessage, error) {
+		reads++
+		return nil, fmt.Errorf("connection closed")
+	}
+	err := consumeFromOffset("EVENTS", func() (string, error) { return "9-0", nil }, read, func(d interface{}) {}, store, time.Hour)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("consumeFromOffset() error = %v, want the error of the offset store", err)
+	}
+	if reads != 0 {
+		t.Errorf("consumeFromOffset() should not read the stream when the stored offset is not available")
+	}
+}
+
+func TestConsumeFromOffsetInvalidMessages(t *testing.T) {
+	store := &mockOffsetStore{offsets: map[string]string{"EVENTS": "5-0"}}
+	stream := []redis.XMessage{
+		{ID: "6-0", Values: map[string]interface{}{"other": `"event6"`}},
+		{ID: "7-0", Values: map[string]interface{}{"data": `{invalid`}},
+		{ID: "8-0", Values: map[string]interface{}{"data": `"event8"`}},
+	}
+	var reads int
+	read := func(offset string) ([]redis.XMessage, error) {
+		reads++
+		if reads == 1 {
+			return stream, nil
+		}
+		return nil, fmt.Errorf("connection closed")
+	}
+	var consumed []interface{}
+	consumeFromOffset("EVENTS", func() (string, error) { return "9-0", nil }, read, func(d interface{}) { consumed = append(consumed, d) }, store, time.Hour)
+	if want := []interface{}{"event8"}; !reflect.DeepEqual(consumed, want) {
+		t.Errorf("consumeFromOffset() consumed = %v, want %v", consumed, want)
+	}
+	if store.offsets["EVENTS"] != "8-0" {
+		t.Errorf("consumeFromOffset() stored offset = %v, want 8-0", store.offsets["EVENTS"])
+	}
+}
+
+func TestRunReaders(t *testing.T) {
+	var running int32
+	blockingReader := func(ctx context.Context) error {
+		atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	failingReader := func(ctx context.Context) error {
+		// fail once the other reader is running
+		for atomic.LoadInt32(&running) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return fmt.Errorf("connection closed")
+	}
+	err := runReaders(blockingReader, failingReader)
+	if err == nil || err.Error() != "connection closed" {
+		t.Errorf("runReaders() error = %v, want the error of the failing reader", err)
+	}
+	if n := atomic.LoadInt32(&running); n != 0 {
+		t.Errorf("runReaders() returned while %d readers are running", n)
+	}
+}
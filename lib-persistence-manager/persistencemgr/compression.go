@@ -0,0 +1,73 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package persistencemgr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+)
+
+// compressedDataMarker prefixes the data stored compressed, so that each record is read based on
+// how it was stored. JSON data can't begin with the NUL character
+const compressedDataMarker = "\x00gzip:"
+
+// compressionThresholdBytes is the size below which the data is stored uncompressed even when the
+// compression is enabled, as the header of gzip outweighs the saving for the small data
+const compressionThresholdBytes = 512
+
+// isCompressionEnabled checks if the resources need to be stored compressed
+func isCompressionEnabled() bool {
+	return config.Data.DBConf != nil && config.Data.DBConf.CompressResources
+}
+
+// compressData returns the data to be stored in DB, it is compressed with gzip and prefixed
+// with the compressedDataMarker when the compression of the resources is enabled
+func compressData(data []byte) ([]byte, error) {
+	if !isCompressionEnabled() || len(data) < compressionThresholdBytes {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(compressedDataMarker)
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressData returns the data read from DB, the data stored compressed is decompressed
+// irrespective of the compression is enabled or not
+func decompressData(data string) (string, error) {
+	if !strings.HasPrefix(data, compressedDataMarker) {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(strings.NewReader(strings.TrimPrefix(data, compressedDataMarker)))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(decompressed), nil
+}
@@ -0,0 +1,102 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package persistencemgr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestCompressData(t *testing.T) {
+	config.SetUpMockConfig(t)
+	largeData := []byte(`{"Data":"` + strings.Repeat("value", 200) + `"}`)
+
+	config.Data.DBConf.CompressResources = false
+	got, err := compressData(largeData)
+	if err != nil || string(got) != string(largeData) {
+		t.Errorf("data should be stored uncompressed by default, got %q, error %v", got, err)
+	}
+
+	config.Data.DBConf.CompressResources = true
+	got, err = compressData([]byte(`"small"`))
+	if err != nil || string(got) != `"small"` {
+		t.Errorf("small data should be stored uncompressed, got %q, error %v", got, err)
+	}
+	got, err = compressData(largeData)
+	if err != nil {
+		t.Fatalf("error while compressing the data: %v", err)
+	}
+	if !strings.HasPrefix(string(got), compressedDataMarker) || len(got) >= len(largeData) {
+		t.Errorf("large data should be stored compressed, got %d bytes for %d bytes", len(got), len(largeData))
+	}
+	decompressed, err := decompressData(string(got))
+	if err != nil || decompressed != string(largeData) {
+		t.Errorf("decompressed data mismatch, got %q, error %v", decompressed, err)
+	}
+
+	// the data stored uncompressed is read as it is
+	decompressed, err = decompressData(`"small"`)
+	if err != nil || decompressed != `"small"` {
+		t.Errorf("uncompressed data should be read as it is, got %q, error %v", decompressed, err)
+	}
+	if _, err = decompressData(compressedDataMarker + "corrupted"); err == nil {
+		t.Errorf("error expected for the corrupted compressed data")
+	}
+}
+
+func TestAddResourceData_compressed(t *testing.T) {
+	c, err := MockDBConnection(t)
+	if err != nil {
+		t.Fatal("Error while making mock DB connection:", err)
+	}
+	config.Data.DBConf.CompressResources = true
+	defer func() {
+		if derr := c.Delete("table", "key"); derr != nil {
+			t.Errorf("Error while deleting Data: %v\n", derr.Error())
+		}
+	}()
+	data := sample{Data1: strings.Repeat("Value1", 100), Data2: "Value2", Data3: "Value3"}
+	if cerr := c.AddResourceData("table", "key", data); cerr != nil {
+		t.Fatalf("Error while making data entry: %v\n", cerr.Error())
+	}
+
+	readConn := c.ReadPool.Get()
+	defer readConn.Close()
+	stored, rerr := redis.String(readConn.Do("GET", "table:key"))
+	if rerr != nil {
+		t.Fatalf("Error while reading the stored data: %v\n", rerr)
+	}
+	if !strings.HasPrefix(stored, compressedDataMarker) {
+		t.Errorf("resource should be stored compressed")
+	}
+
+	// the resource is read irrespective of the compression is enabled or not
+	config.Data.DBConf.CompressResources = false
+	got, gerr := c.Read("table", "key")
+	if gerr != nil {
+		t.Fatalf("Error while reading data: %v\n", gerr.Error())
+	}
+	var res sample
+	if jerr := json.Unmarshal([]byte(got), &res); jerr != nil {
+		t.Errorf("Error while unmarshaling data : %v\n", jerr)
+	}
+	if res != data {
+		t.Errorf("Mismatch in fetched data")
+	}
+}
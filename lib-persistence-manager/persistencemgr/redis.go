@@ -458,7 +458,11 @@ func (p *ConnPool) Read(table, key string) (string, *errors.Error) {
 	if err != nil {
 		return "", errors.PackError(errors.UndefinedErrorType, "error while trying to convert the data into string: ", err)
 	}
-	return string(data), nil
+	data, err = decompressData(data)
+	if err != nil {
+		return "", errors.PackError(errors.UndefinedErrorType, "error while trying to decompress the data: ", err)
+	}
+	return data, nil
 }
 
 // FindOrNull is a wrapper for Read function. If requested asset doesn't exist errors.DBKeyNotFound error returned by Read is converted to nil
@@ -475,6 +479,47 @@ func (p *ConnPool) FindOrNull(table, key string) (string, error) {
 	return r, nil
 }
 
+// ReadMultipleKeys fetches the data of the keys of the table with MGET, in batches of count keys,
+// so that the data of many keys is read without a DB call for each key. The keys without data
+// are not part of the returned map.
+func (p *ConnPool) ReadMultipleKeys(table string, keys []string) (map[string]string, *errors.Error) {
+	readConn := p.ReadPool.Get()
+	defer readConn.Close()
+	data := make(map[string]string, len(keys))
+	for start := 0; start < len(keys); start += count {
+		end := start + count
+		if end > len(keys) {
+			end = len(keys)
+		}
+		args := make([]interface{}, 0, end-start)
+		for _, key := range keys[start:end] {
+			args = append(args, table+":"+key)
+		}
+		values, err := redis.Values(readConn.Do("MGET", args...))
+		if err != nil {
+			if errs, aye := isDbConnectError(err); aye {
+				return nil, errs
+			}
+			return nil, errors.PackError(errors.DBKeyFetchFailed, errorCollectingData, err)
+		}
+		for i, value := range values {
+			if value == nil {
+				continue
+			}
+			str, err := redis.String(value, nil)
+			if err != nil {
+				return nil, errors.PackError(errors.UndefinedErrorType, "error while trying to convert the data into string: ", err)
+			}
+			str, err = decompressData(str)
+			if err != nil {
+				return nil, errors.PackError(errors.UndefinedErrorType, "error while trying to decompress the data: ", err)
+			}
+			data[keys[start+i]] = str
+		}
+	}
+	return data, nil
+}
+
 // GetAllDetails will fetch all the keys present in the database
 func (p *ConnPool) GetAllDetails(table string) ([]string, *errors.Error) {
 	readConn := p.ReadPool.Get()
@@ -664,6 +709,11 @@ func (p *ConnPool) SaveBMCInventory(data map[string]interface{}) *errors.Error {
 			writeConn.Send("DISCARD")
 			return errors.PackError(errors.UndefinedErrorType, "Write to DB in json form failed: "+err.Error())
 		}
+		jsondata, err = compressData(jsondata)
+		if err != nil {
+			writeConn.Send("DISCARD")
+			return errors.PackError(errors.UndefinedErrorType, "error while trying to compress the data: "+err.Error())
+		}
 		_, createErr := writeConn.Do("SET", key, jsondata)
 		if createErr != nil {
 			writeConn.Send("DISCARD")
@@ -902,6 +952,10 @@ func (p *ConnPool) AddResourceData(table, key string, data interface{}) *errors.
 	if err != nil {
 		return errors.PackError(errors.UndefinedErrorType, "Write to DB in json form failed: "+err.Error())
 	}
+	jsondata, err = compressData(jsondata)
+	if err != nil {
+		return errors.PackError(errors.UndefinedErrorType, "error while trying to compress the data: "+err.Error())
+	}
 	_, createErr := writeConn.Do("SET", saveID, jsondata)
 	if createErr != nil {
 		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&p.WritePool)), nil)
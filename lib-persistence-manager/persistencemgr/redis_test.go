@@ -193,6 +193,46 @@ func TestRead_nonExistingData(t *testing.T) {
 
 }
 
+func TestReadMultipleKeys(t *testing.T) {
+
+	c, err := MockDBConnection(t)
+	if err != nil {
+		t.Fatal("Error while making mock DB connection:", err)
+	}
+	data := sample{Data1: "Value1", Data2: "Value2", Data3: "Value3"}
+	for _, key := range []string{"key1", "key2"} {
+		if cerr := c.Create("table", key, data); cerr != nil {
+			t.Errorf("Error: %v\n", cerr.Error())
+		}
+	}
+	defer func() {
+		for _, key := range []string{"key1", "key2"} {
+			if derr := c.Delete("table", key); derr != nil {
+				t.Errorf("Error while deleting Data: %v\n", derr.Error())
+			}
+		}
+	}()
+	got, rerr := c.ReadMultipleKeys("table", []string{"key1", "key2", "key3"})
+	if rerr != nil {
+		t.Errorf("Error while reading data: %v\n", rerr.Error())
+	}
+	if len(got) != 2 {
+		t.Errorf("Expected the data of 2 keys, got %d", len(got))
+	}
+	for _, key := range []string{"key1", "key2"} {
+		var res sample
+		if jerr := json.Unmarshal([]byte(got[key]), &res); jerr != nil {
+			t.Errorf("Error while unmarshaling data : %v\n", jerr)
+		}
+		if res != data {
+			t.Errorf("Mismatch in fetched data of %s", key)
+		}
+	}
+	if _, ok := got["key3"]; ok {
+		t.Errorf("Key without data shouldn't be returned")
+	}
+}
+
 func TestUpdate(t *testing.T) {
 
 	c, err := MockDBConnection(t)
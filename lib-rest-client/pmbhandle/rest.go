@@ -18,8 +18,11 @@ package pmbhandle
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
 	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
@@ -27,8 +30,98 @@ import (
 	"github.com/ODIM-Project/ODIM/lib-utilities/config"
 )
 
+// pluginTransport is the transport kept for the calls to a plugin
+type pluginTransport struct {
+	transport *http.Transport
+	// source is the TLS config of the default transport the transport was created from,
+	// the transport is created again when the default TLS config is replaced
+	source *tls.Config
+	// maxIdleConnsPerHost and idleConnTimeout are the pool settings the transport was created with
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+}
+
+var (
+	// pluginTransportsLock is used for avoiding race conditions on pluginTransports
+	pluginTransportsLock sync.Mutex
+	// pluginTransports holds the transport of each plugin, keyed by the plugin IP:Port and the server name
+	pluginTransports = make(map[string]pluginTransport)
+)
+
 // ContactPlugin is used to send a request to plugin to add a resource
 func ContactPlugin(ctx context.Context, url, method, token string, odataID string, body interface{}, collaboratedInfo map[string]string) (*http.Response, error) {
+	req, err := newPluginRequest(ctx, url, method, token, odataID, body, collaboratedInfo)
+	if err != nil {
+		return nil, err
+	}
+	// indicate to close the request created
+	req.Close = true
+
+	httpConf := &config.HTTPConfig{
+		CACertificate: &config.Data.KeyCertConf.RootCACertificate,
+	}
+	httpClient, err := httpConf.GetHTTPClientObj()
+	if err != nil {
+		return nil, err
+	}
+	if timeout, ok := ctx.Value(common.PluginTimeout).(time.Duration); ok && timeout > 0 {
+		// the client is shared, a copy is used for the timeout of this call
+		client := *httpClient
+		client.Timeout = timeout
+		httpClient = &client
+	}
+	config.TLSConfMutex.RLock()
+	httpClient.Transport.(*http.Transport).TLSClientConfig.ServerName = collaboratedInfo["ServerName"]
+	resp, err := httpClient.Do(req)
+	config.TLSConfMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		l.Log.Warn("got " + resp.Status + " while fetching " + url + " with method " + method)
+	}
+
+	return resp, nil
+}
+
+// ContactPluginPooled is used to send a request to plugin like ContactPlugin, but the connections
+// to each plugin are kept open and reused by the next calls to the same plugin
+func ContactPluginPooled(ctx context.Context, url, method, token string, odataID string, body interface{}, collaboratedInfo map[string]string) (*http.Response, error) {
+	req, err := newPluginRequest(ctx, url, method, token, odataID, body, collaboratedInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	httpConf := &config.HTTPConfig{
+		CACertificate: &config.Data.KeyCertConf.RootCACertificate,
+	}
+	// the default client is set up first for loading the TLS config shared by the plugin transports
+	defaultClient, err := httpConf.GetHTTPClientObj()
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{
+		Transport: getPluginTransport(req.URL.Host, collaboratedInfo["ServerName"]),
+		Timeout:   defaultClient.Timeout,
+	}
+	if timeout, ok := ctx.Value(common.PluginTimeout).(time.Duration); ok && timeout > 0 {
+		httpClient.Timeout = timeout
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		l.Log.Warn("got " + resp.Status + " while fetching " + url + " with method " + method)
+	}
+
+	return resp, nil
+}
+
+// newPluginRequest is used to create the request to the plugin with the headers and the credentials
+func newPluginRequest(ctx context.Context, url, method, token string, odataID string, body interface{}, collaboratedInfo map[string]string) (*http.Request, error) {
 	jsonStr, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
@@ -39,8 +132,6 @@ func ContactPlugin(ctx context.Context, url, method, token string, odataID strin
 		return nil, err
 	}
 	req = CreateHeader(req, ctx)
-	// indicate to close the request created
-	req.Close = true
 
 	// TODO: it can be saved inside inMemory db for use
 	req.Header.Set("Content-Type", "application/json")
@@ -53,26 +144,55 @@ func ContactPlugin(ctx context.Context, url, method, token string, odataID strin
 	if odataID != "" {
 		req.Header.Set("OdataID", odataID)
 	}
-	httpConf := &config.HTTPConfig{
-		CACertificate: &config.Data.KeyCertConf.RootCACertificate,
-	}
-	httpClient, err := httpConf.GetHTTPClientObj()
-	if err != nil {
-		return nil, err
-	}
+	return req, nil
+}
+
+// getPluginTransport returns the transport for the calls to the plugin at host, which is created
+// from the default transport with the TLS config of the plugin server name. The transport is
+// created again when the default TLS config or the PluginConnPoolConf is changed.
+func getPluginTransport(host, serverName string) *http.Transport {
+	maxIdleConnsPerHost := config.DefaultPluginMaxIdleConnsPerHost
+	idleConnTimeout := time.Duration(config.DefaultPluginIdleConnTimeoutInSecs) * time.Second
 	config.TLSConfMutex.RLock()
-	httpClient.Transport.(*http.Transport).TLSClientConfig.ServerName = collaboratedInfo["ServerName"]
-	resp, err := httpClient.Do(req)
-	config.TLSConfMutex.RUnlock()
-	if err != nil {
-		return nil, err
+	defer config.TLSConfMutex.RUnlock()
+	if poolConf := config.Data.PluginConnPoolConf; poolConf != nil {
+		if poolConf.MaxIdleConnsPerHost > 0 {
+			maxIdleConnsPerHost = poolConf.MaxIdleConnsPerHost
+		}
+		if poolConf.IdleConnTimeoutInSecs > 0 {
+			idleConnTimeout = time.Duration(poolConf.IdleConnTimeoutInSecs) * time.Second
+		}
 	}
+	source := config.DefaultHTTPTransport.TLSClientConfig
 
-	if resp.StatusCode >= 300 {
-		l.Log.Warn("got " + resp.Status + " while fetching " + url + " with method " + method)
+	key := host + "/" + serverName
+	pluginTransportsLock.Lock()
+	defer pluginTransportsLock.Unlock()
+	if pt, ok := pluginTransports[key]; ok && pt.source == source &&
+		pt.maxIdleConnsPerHost == maxIdleConnsPerHost && pt.idleConnTimeout == idleConnTimeout {
+		return pt.transport
+	} else if ok {
+		pt.transport.CloseIdleConnections()
 	}
 
-	return resp, nil
+	tlsConfig := source.Clone()
+	tlsConfig.ServerName = serverName
+	transport := &http.Transport{
+		Proxy:                 config.DefaultHTTPTransport.Proxy,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   config.DefaultHTTPTransport.TLSHandshakeTimeout,
+		ExpectContinueTimeout: config.DefaultHTTPTransport.ExpectContinueTimeout,
+		MaxIdleConns:          maxIdleConnsPerHost,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+	}
+	pluginTransports[key] = pluginTransport{
+		transport:           transport,
+		source:              source,
+		maxIdleConnsPerHost: maxIdleConnsPerHost,
+		idleConnTimeout:     idleConnTimeout,
+	}
+	return transport
 }
 
 // CreateHeader is used to get data from context and set it to header for http request call
@@ -91,5 +211,10 @@ func CreateHeader(req *http.Request, ctx context.Context) *http.Request {
 		req.Header.Set(common.ThreadName, threadName)
 		req.Header.Set(common.ProcessName, processName)
 	}
+	if headers, ok := ctx.Value(common.ForwardedHeaders).(map[string]string); ok {
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+	}
 	return req
 }
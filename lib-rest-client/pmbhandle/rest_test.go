@@ -0,0 +1,150 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package pmbhandle
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+)
+
+type contactClient func(ctx context.Context, url, method, token string, odataID string, body interface{}, collaboratedInfo map[string]string) (*http.Response, error)
+
+// startPluginServer starts a TLS server acting as the plugin, which counts the connections opened to it
+func startPluginServer(newConns *int32) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"@odata.id":"/ODIM/v1/Systems"}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(newConns, 1)
+		}
+	}
+	server.StartTLS()
+
+	config.Data.KeyCertConf = &config.KeyCertConf{
+		RootCACertificate: pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		}),
+	}
+	return server
+}
+
+func callPlugin(t testing.TB, client contactClient, url string) {
+	credentials := map[string]string{
+		"UserName":   "admin",
+		"Password":   "password",
+		"ServerName": "example.com",
+	}
+	resp, err := client(context.TODO(), url, http.MethodGet, "", "", nil, credentials)
+	if err != nil {
+		t.Fatalf("error while contacting the plugin: %v", err)
+	}
+	defer resp.Body.Close()
+	if _, err := ioutil.ReadAll(resp.Body); err != nil {
+		t.Fatalf("error while reading the plugin response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status code %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestContactPluginPooled(t *testing.T) {
+	tests := []struct {
+		name         string
+		client       contactClient
+		wantNewConns int32
+	}{
+		{
+			name:         "connections are reused by the pooled client",
+			client:       ContactPluginPooled,
+			wantNewConns: 1,
+		},
+		{
+			name:         "connections are closed by the client",
+			client:       ContactPlugin,
+			wantNewConns: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetUpMockConfig(t)
+			var newConns int32
+			server := startPluginServer(&newConns)
+			defer server.Close()
+			for i := 0; i < 5; i++ {
+				callPlugin(t, tt.client, server.URL+"/ODIM/v1/Systems")
+			}
+			if got := atomic.LoadInt32(&newConns); got != tt.wantNewConns {
+				t.Errorf("got %d connections to the plugin, want %d", got, tt.wantNewConns)
+			}
+		})
+	}
+}
+
+func TestContactPluginPooledPoolConf(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var newConns int32
+	server := startPluginServer(&newConns)
+	defer server.Close()
+	url := server.URL + "/ODIM/v1/Systems"
+	callPlugin(t, ContactPluginPooled, url)
+	callPlugin(t, ContactPluginPooled, url)
+
+	// the transport is created again when the pool configuration is changed
+	config.Data.PluginConnPoolConf.MaxIdleConnsPerHost++
+	callPlugin(t, ContactPluginPooled, url)
+	if got := atomic.LoadInt32(&newConns); got != 2 {
+		t.Errorf("got %d connections to the plugin, want %d", got, 2)
+	}
+}
+
+func BenchmarkContactPlugin(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		client contactClient
+	}{
+		{name: "ContactPlugin", client: ContactPlugin},
+		{name: "ContactPluginPooled", client: ContactPluginPooled},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			config.Data.PluginConnPoolConf = &config.PluginConnPoolConf{
+				MaxIdleConnsPerHost:   config.DefaultPluginMaxIdleConnsPerHost,
+				IdleConnTimeoutInSecs: config.DefaultPluginIdleConnTimeoutInSecs,
+			}
+			var newConns int32
+			server := startPluginServer(&newConns)
+			defer server.Close()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				callPlugin(b, bm.client, server.URL+"/ODIM/v1/Systems")
+			}
+			b.ReportMetric(float64(atomic.LoadInt32(&newConns))/float64(b.N), "conns/op")
+		})
+	}
+}
@@ -161,6 +161,12 @@ const (
 	ActionID      = "actionid"
 	ProcessName   = "processname"
 	RequestBody   = "requestbody"
+	// ForwardedHeaders is the context key of the northbound request headers forwarded to the plugin
+	ForwardedHeaders = "forwardedheaders"
+	// PluginTimeout is the context key of the timeout of the plugin call, it overrides SouthBoundRequestTimeoutInSecs
+	PluginTimeout = "plugintimeout"
+	// TenantID is the context key of the tenant of the authenticated request
+	TenantID = "tenantid"
 	// Below fields define Service Name
 	ManagerService     = "svc-managers"
 	AccountService     = "svc-account"
@@ -18,6 +18,7 @@ package common
 import (
 	"fmt"
 	"github.com/ODIM-Project/ODIM/lib-persistence-manager/persistencemgr"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
 	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
 )
 
@@ -49,6 +50,22 @@ func GetDBConnection(dbFlag DbType) (*persistencemgr.ConnPool, *errors.Error) {
 	}
 }
 
+// GetTableDBType returns the DB type configured for the table in DBConf.TableDBType
+// defaultDBType is returned if no DB type is configured for the table
+func GetTableDBType(table string, defaultDBType DbType) DbType {
+	if config.Data.DBConf == nil {
+		return defaultDBType
+	}
+	switch config.Data.DBConf.TableDBType[table] {
+	case config.InMemoryDBType:
+		return InMemory
+	case config.OnDiskDBType:
+		return OnDisk
+	default:
+		return defaultDBType
+	}
+}
+
 // TruncateDB will clear DB. It will be useful for test cases
 // Takes DbFlag of type DbType/int32 to choose Inmemory or OnDisk db to truncate
 //dbFlag:
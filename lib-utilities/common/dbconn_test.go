@@ -218,3 +218,23 @@ func TestCheckDBConnection(t *testing.T) {
 		reportError(t, undefinedErr, fmt.Sprintf("expected err to be nil while using CheckDBConnection but got: %v", err))
 	}
 }
+
+func TestGetTableDBType(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DBConf.TableDBType = map[string]string{
+		"MetricReports": config.OnDiskDBType,
+		"Systems":       config.InMemoryDBType,
+	}
+	defer func() {
+		config.Data.DBConf.TableDBType = nil
+	}()
+	if dbType := GetTableDBType("MetricReports", InMemory); dbType != OnDisk {
+		t.Errorf("expected OnDisk DB type for remapped table but got %v", dbType)
+	}
+	if dbType := GetTableDBType("Systems", OnDisk); dbType != InMemory {
+		t.Errorf("expected InMemory DB type for remapped table but got %v", dbType)
+	}
+	if dbType := GetTableDBType("Chassis", InMemory); dbType != InMemory {
+		t.Errorf("expected default DB type for table not remapped but got %v", dbType)
+	}
+}
@@ -54,6 +54,8 @@ type PluginStatus struct {
 	PluginPrefferedAuthType string
 	//CACertificate to use while making HTTP queries
 	CACertificate *[]byte
+	// Version - the version reported by the plugin in the successful status check
+	Version string
 }
 
 // StatusRequest is the plugin request for status check
@@ -76,6 +78,7 @@ type StatusResponse struct {
 	Version         string                `json:"Version"`
 	Status          *PluginResponseStatus `json:"Status"`
 	EventMessageBus *EventMessageBus      `json:"EventMessageBus"`
+	Capabilities    []string              `json:"Capabilities,omitempty"`
 }
 
 // PluginResponseStatus hold status data of Plugin
@@ -116,7 +119,9 @@ func (p *PluginStatus) CheckStatus() (bool, int, []string, error) {
 		statusChan := make(chan bool)
 		errChan := make(chan error)
 		queueListChan := make(chan []string)
-		go p.getStatus(requestBody, statusChan, queueListChan, errChan)
+		retryAfterChan := make(chan time.Duration, 1)
+		versionChan := make(chan string, 1)
+		go p.getStatus(requestBody, statusChan, queueListChan, errChan, retryAfterChan, versionChan)
 		go responseTimer(p.ResponseWaitTime, statusChan, queueListChan, errChan)
 
 		roundError := <-errChan
@@ -126,19 +131,68 @@ func (p *PluginStatus) CheckStatus() (bool, int, []string, error) {
 		alive := <-statusChan
 		queueList = <-queueListChan
 		if alive {
+			select {
+			case p.Version = <-versionChan:
+			default:
+			}
 			err = nil
 			if statusLog != "" {
 				err = fmt.Errorf("error logs: %v", statusLog)
 			}
 			return true, i + 1, queueList, err
 		}
-		time.Sleep(time.Duration(p.RetryInterval) * time.Minute)
+		time.Sleep(p.getRetryWait(retryAfterChan))
 
 	}
 
 	return false, p.Count, queueList, fmt.Errorf("error: maximum retries are over. unable to contact the plugin: error logs: %v", statusLog)
 }
 
+// getRetryWait returns the wait time before the next try. The Retry-After sent by a busy
+// plugin is honored when present, otherwise the configured RetryInterval is used
+func (p *PluginStatus) getRetryWait(retryAfterChan chan time.Duration) time.Duration {
+	select {
+	case retryAfter := <-retryAfterChan:
+		return retryAfter
+	default:
+		return time.Duration(p.RetryInterval) * time.Minute
+	}
+}
+
+// parseRetryAfter parses the Retry-After header which can either be the delay in seconds
+// or a HTTP date, the delay is bounded by maxWait
+func parseRetryAfter(retryAfter string, maxWait time.Duration) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		wait = time.Duration(seconds) * time.Second
+	} else if retryTime, err := http.ParseTime(retryAfter); err == nil {
+		wait = time.Until(retryTime)
+		if wait < 0 {
+			wait = 0
+		}
+	} else {
+		return 0, false
+	}
+	if wait > maxWait {
+		wait = maxWait
+	}
+	return wait, true
+}
+
+// getMaxRetryAfter returns the maximum time a Retry-After from the plugin is honored
+func getMaxRetryAfter() time.Duration {
+	if config.Data.PluginStatusPolling == nil || config.Data.PluginStatusPolling.MaxRetryAfterInSecs <= 0 {
+		return time.Duration(config.DefaultMaxRetryAfterInSecs) * time.Second
+	}
+	return time.Duration(config.Data.PluginStatusPolling.MaxRetryAfterInSecs) * time.Second
+}
+
 // responseTimer helps the CheckStatus function to keep an eye on the response wait time
 func responseTimer(waitTime int, statusChan chan bool, queueListChan chan []string, errChan chan error) {
 	time.Sleep(time.Duration(waitTime) * time.Second)
@@ -148,7 +202,7 @@ func responseTimer(waitTime int, statusChan chan bool, queueListChan chan []stri
 }
 
 // getStatus helps the CheckStatus by making a call to the plugin for the status
-func (p *PluginStatus) getStatus(requestBody *bytes.Buffer, statusChan chan bool, queueListChan chan []string, errChan chan error) {
+func (p *PluginStatus) getStatus(requestBody *bytes.Buffer, statusChan chan bool, queueListChan chan []string, errChan chan error, retryAfterChan chan time.Duration, versionChan chan string) {
 	url := fmt.Sprintf("https://%s:%s/ODIM/v1/Status", p.PluginIP, p.PluginPort)
 	req, err := http.NewRequest(p.Method, url, requestBody)
 	var queueList = make([]string, 0)
@@ -187,6 +241,10 @@ func (p *PluginStatus) getStatus(requestBody *bytes.Buffer, statusChan chan bool
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
+		// the wait is passed before the result, so that it is available once the try is concluded
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), getMaxRetryAfter()); ok {
+			retryAfterChan <- wait
+		}
 		errChan <- fmt.Errorf("error: expected response from plugin %v, but got %v", http.StatusOK, resp.StatusCode)
 		statusChan <- false
 		queueListChan <- queueList
@@ -216,6 +274,8 @@ func (p *PluginStatus) getStatus(requestBody *bytes.Buffer, statusChan chan bool
 	}
 	if bodyData.Status != nil {
 		if strings.EqualFold(bodyData.Status.Available, "yes") {
+			// the version is passed before the result, so that it is available once the try is concluded
+			versionChan <- bodyData.Version
 			errChan <- nil
 			statusChan <- true
 			queueListChan <- queueList
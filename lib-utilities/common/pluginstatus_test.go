@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -239,3 +240,88 @@ func mockPluginHandler2(ctx iris.Context) {
 	}
 	return
 }
+
+func TestCheckPluginStatusRetryAfter(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var tries int32
+	go mockBusyPlugin(t, &tries)
+	time.Sleep(2 * time.Second)
+
+	var pluginStatus = &PluginStatus{
+		PluginIP:                "localhost",
+		PluginPort:              "45101",
+		PluginUsername:          "admin",
+		PluginUserPassword:      "admin",
+		PluginPrefferedAuthType: "BasicAuth",
+		CACertificate:           &config.Data.KeyCertConf.RootCACertificate,
+		Method:                  http.MethodGet,
+		RequestBody: StatusRequest{
+			Comment: PluginStatusRequestComment,
+			Name:    PluginStatusRequestName,
+			Version: "v0.1",
+		},
+		ResponseWaitTime: 1,
+		Count:            2,
+		// the retry would take a minute if the Retry-After is not honored
+		RetryInterval: 1,
+	}
+	start := time.Now()
+	alive, count, _, _ := pluginStatus.CheckStatus()
+	elapsed := time.Since(start)
+	if !alive || count != 2 {
+		t.Errorf("PluginStatus.CheckStatus() got = %v, %v, want true, 2", alive, count)
+	}
+	if elapsed < time.Second || elapsed > 30*time.Second {
+		t.Errorf("PluginStatus.CheckStatus() retried after %v, want the Retry-After of 1 second", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+		wantOk     bool
+	}{
+		{name: "delay in seconds", retryAfter: "5", want: 5 * time.Second, wantOk: true},
+		{name: "delay bounded by the max", retryAfter: "120", want: 10 * time.Second, wantOk: true},
+		{name: "date in the past", retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOk: true},
+		{name: "not sent", retryAfter: "", want: 0, wantOk: false},
+		{name: "invalid value", retryAfter: "soon", want: 0, wantOk: false},
+		{name: "negative delay", retryAfter: "-1", want: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.retryAfter, 10*time.Second)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+// mockBusyPlugin responds with 503 and Retry-After to the first status request
+func mockBusyPlugin(t *testing.T, tries *int32) {
+	conf := &config.HTTPConfig{
+		Certificate:   &config.Data.APIGatewayConf.Certificate,
+		PrivateKey:    &config.Data.APIGatewayConf.PrivateKey,
+		CACertificate: &config.Data.KeyCertConf.RootCACertificate,
+		ServerAddress: "localhost",
+		ServerPort:    "45101",
+	}
+	mockServer, err := conf.GetHTTPServerObj()
+	if err != nil {
+		t.Fatalf("fatal: error while initializing server: %v", err)
+	}
+	router := iris.New()
+	plug := router.Party("/ODIM/v1")
+	plug.Get("/Status", func(ctx iris.Context) {
+		if atomic.AddInt32(tries, 1) == 1 {
+			ctx.Header("Retry-After", "1")
+			ctx.StatusCode(http.StatusServiceUnavailable)
+			return
+		}
+		mockPluginHandler(ctx)
+	})
+	router.Run(iris.Server(mockServer))
+}
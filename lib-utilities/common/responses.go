@@ -61,6 +61,9 @@ func GetContextData(ctx context.Context) context.Context {
 		ctx = context.WithValue(ctx, ThreadID, md[ThreadID][0])
 		ctx = context.WithValue(ctx, ThreadName, md[ThreadName][0])
 	}
+	if len(md[TenantID]) > 0 {
+		ctx = context.WithValue(ctx, TenantID, md[TenantID][0])
+	}
 
 	return ctx
 }
@@ -76,6 +79,9 @@ func CreateMetadata(ctx context.Context) context.Context {
 			ThreadID:      ctx.Value(ThreadID).(string),
 			ThreadName:    ctx.Value(ThreadName).(string),
 		})
+		if tenantID, ok := ctx.Value(TenantID).(string); ok && tenantID != "" {
+			md.Set(TenantID, tenantID)
+		}
 		ctx = metadata.NewOutgoingContext(ctx, md)
 	}
 
@@ -101,5 +107,8 @@ func CreateNewRequestContext(ctx context.Context) context.Context {
 	reqCtx = context.WithValue(reqCtx, ActionName, actionName)
 	reqCtx = context.WithValue(reqCtx, ThreadID, threadID)
 	reqCtx = context.WithValue(reqCtx, ThreadName, threadName)
+	if tenantID, ok := ctx.Value(TenantID).(string); ok {
+		reqCtx = context.WithValue(reqCtx, TenantID, tenantID)
+	}
 	return reqCtx
 }
@@ -61,6 +61,11 @@ type configModel struct {
 	URLTranslation                 *URLTranslation          `json:"URLTranslation"`
 	PluginStatusPolling            *PluginStatusPolling     `json:"PluginStatusPolling"`
 	ExecPriorityDelayConf          *ExecPriorityDelayConf   `json:"ExecPriorityDelayConf"`
+	DiscoveryConf                  *DiscoveryConf           `json:"DiscoveryConf"`
+	PluginTaskConf                 *PluginTaskConf          `json:"PluginTaskConf"`
+	PluginTimeoutConf              *PluginTimeoutConf       `json:"PluginTimeoutConf"`
+	PluginConnPoolConf             *PluginConnPoolConf      `json:"PluginConnPoolConf"`
+	TaskCreationConf               *TaskCreationConf        `json:"TaskCreationConf"`
 	TLSConf                        *TLSConf                 `json:"TLSConf"`
 	TaskQueueConf                  *TaskQueueConf           `json:"TaskQueueConf"`
 	SupportedPluginTypes           []string                 `json:"SupportedPluginTypes"`
@@ -72,6 +77,7 @@ type configModel struct {
 	LogLevel                       log.Level                `json:"LogLevel"`
 	LogFormat                      lgr.LogFormat            `json:"LogFormat"`
 	ImageRegistryAddress           string                   `json:"ImageRegistryAddress,omitempty"`
+	PluginTenantHeader             string                   `json:"PluginTenantHeader"` // holds the name of the header in which the tenant ID of the request is sent to the plugins
 }
 
 // DBConf holds all DB related configurations
@@ -94,6 +100,8 @@ type DBConf struct {
 	RedisOnDiskPasswordFilePath   string `json:"RedisOnDiskPasswordFilePath"`
 	RedisInMemoryPassword         []byte
 	RedisOnDiskPassword           []byte
+	TableDBType                   map[string]string `json:"TableDBType"`       // holds the DB type(InMemory or OnDisk) for the tables which need to be stored other than the default DB
+	CompressResources             bool              `json:"CompressResources"` // holds the flag to store the discovered resources compressed with gzip in the DB
 }
 
 // MessageBusConf holds all message bus configurations
@@ -151,17 +159,103 @@ type AddComputeSkipResources struct {
 
 // URLTranslation ...
 type URLTranslation struct {
-	NorthBoundURL map[string]string `json:"NorthBoundURL"` // holds value of NorthBound Translation
-	SouthBoundURL map[string]string `json:"SouthBoundURL"` // holds value of SouthBound Translation
+	NorthBoundURL   map[string]string                 `json:"NorthBoundURL"`   // holds value of NorthBound Translation
+	SouthBoundURL   map[string]string                 `json:"SouthBoundURL"`   // holds value of SouthBound Translation
+	PluginOverrides map[string]URLTranslationOverride `json:"PluginOverrides"` // holds the translations of the plugins keyed by plugin ID, merged over the global translations
+}
+
+// URLTranslationOverride holds the translations of a plugin which are merged over the global translations
+type URLTranslationOverride struct {
+	NorthBoundURL map[string]string `json:"NorthBoundURL"`
+	SouthBoundURL map[string]string `json:"SouthBoundURL"`
 }
 
 // PluginStatusPolling stores all inforamtion related to status polling
 type PluginStatusPolling struct {
-	PollingFrequencyInMins  int `json:"PollingFrequencyInMins"` // holds value of  duration in which status polling to be intiated ,value will be in minutes
-	MaxRetryAttempt         int `json:"MaxRetryAttempt"`        // holds value number retry attempts
-	RetryIntervalInMins     int `json:"RetryIntervalInMins"`    // holds value of  duration in which retry of status polling to be intiated,value will be in minutes
-	ResponseTimeoutInSecs   int `json:"ResponseTimeoutInSecs"`  // holds value of duation in which it need wait for resposne ,value will be in seconds
-	StartUpResouceBatchSize int `json:"StartUpResouceBatchSize"`
+	PollingFrequencyInMins    int  `json:"PollingFrequencyInMins"` // holds value of  duration in which status polling to be intiated ,value will be in minutes
+	MaxRetryAttempt           int  `json:"MaxRetryAttempt"`        // holds value number retry attempts
+	RetryIntervalInMins       int  `json:"RetryIntervalInMins"`    // holds value of  duration in which retry of status polling to be intiated,value will be in minutes
+	ResponseTimeoutInSecs     int  `json:"ResponseTimeoutInSecs"`  // holds value of duation in which it need wait for resposne ,value will be in seconds
+	StartUpResouceBatchSize   int  `json:"StartUpResouceBatchSize"`
+	MaxRetryAfterInSecs       int  `json:"MaxRetryAfterInSecs"`       // holds the maximum time the Retry-After sent by a busy plugin is honored, value will be in seconds
+	RediscoverOnVersionChange bool `json:"RediscoverOnVersionChange"` // holds the flag to rediscover the servers managed by a plugin when the plugin reports a new version
+}
+
+// DiscoveryConf holds the configurations used while discovering the resources of a server
+type DiscoveryConf struct {
+	RootInfoWorkerCount             int                   `json:"RootInfoWorkerCount"`             // holds the number of collection members discovered in parallel under a root resource
+	DiscoverVirtualMedia            bool                  `json:"DiscoverVirtualMedia"`            // holds the flag to explicitly discover the VirtualMedia under managers
+	DiscoverChassisAssembly         bool                  `json:"DiscoverChassisAssembly"`         // holds the flag to explicitly discover the Assembly under chassis
+	DiscoverPCIeDevices             bool                  `json:"DiscoverPCIeDevices"`             // holds the flag to explicitly discover the PCIeDevices and PCIeFunctions under chassis
+	DiscoverNetworkProtocol         bool                  `json:"DiscoverNetworkProtocol"`         // holds the flag to explicitly discover the NetworkProtocol under managers
+	DiscoverSerialInterfaces        bool                  `json:"DiscoverSerialInterfaces"`        // holds the flag to explicitly discover the SerialInterfaces under managers
+	SubResourceErrorPolicy          string                `json:"SubResourceErrorPolicy"`          // holds the policy(Warn or Fail) for the 5xx errors from plugin while discovering the sub resources
+	LanguagelessRegistries          *bool                 `json:"LanguagelessRegistries"`          // holds the flag to fetch the registry files from the locations without Language, it is enabled when not configured
+	RegistryLanguages               []string              `json:"RegistryLanguages"`               // holds the languages of the registry files in the order of preference
+	AuditPluginResponses            bool                  `json:"AuditPluginResponses"`            // holds the flag to store the raw responses of the plugins for troubleshooting
+	AuditResponseMaxBytes           int                   `json:"AuditResponseMaxBytes"`           // holds the maximum size of a raw plugin response stored for audit
+	ErrorBodyMaxBytes               int                   `json:"ErrorBodyMaxBytes"`               // holds the maximum size of a plugin response body included in the error messages and logs
+	MaxJSONDepth                    int                   `json:"MaxJSONDepth"`                    // holds the maximum nesting depth of the plugin responses decoded while discovering the resources
+	MaxLinksPerResource             int                   `json:"MaxLinksPerResource"`             // holds the maximum number of links collected from a resource while discovering the resources
+	StorageCapacityUnit             string                `json:"StorageCapacityUnit"`             // holds the unit(GB, GiB or Bytes) of the drive capacity in the search index of the systems
+	TelemetryWildCards              []WildCardConf        `json:"TelemetryWildCards"`              // holds the wildcards used to collapse the resource ids in the telemetry metric properties
+	ActiveMetricRequestMaxAgeInSecs int                   `json:"ActiveMetricRequestMaxAgeInSecs"` // holds the age after which the active metric requests are considered stale and deleted
+	BalancePluginReplicas           bool                  `json:"BalancePluginReplicas"`           // holds the flag to spread the servers added across the identical plugins
+	PluginWeights                   map[string]int        `json:"PluginWeights"`                   // holds the weight of the plugins used while spreading the servers across the identical plugins
+	MaxConcurrentAddsPerPluginType  map[string]int        `json:"MaxConcurrentAddsPerPluginType"`  // holds the maximum number of servers added concurrently for each plugin type
+	MaskSerialNumbers               bool                  `json:"MaskSerialNumbers"`               // holds the flag to mask the serial numbers of the systems in the search index
+	IndexMemoryProcessorDetails     bool                  `json:"IndexMemoryProcessorDetails"`     // holds the flag to index the details of the memory modules and the processors of the systems
+	SkipPluginSessionTeardown       bool                  `json:"SkipPluginSessionTeardown"`       // holds the flag to keep the plugin sessions created while adding the aggregation source
+	VerifyPluginEMBConsumption      bool                  `json:"VerifyPluginEMBConsumption"`      // holds the flag to verify the events service is consuming the EMB queues of a plugin after adding it
+	ReportLinkIntegrity             bool                  `json:"ReportLinkIntegrity"`             // holds the flag to report the links advertised by a server which could not be fetched while discovering it
+	TelemetryCollectionWorkerCount  int                   `json:"TelemetryCollectionWorkerCount"`  // holds the number of telemetry collections discovered in parallel
+	PluginRequestsPerSecond         int                   `json:"PluginRequestsPerSecond"`         // holds the maximum number of discovery calls made to a plugin per second, 0 doesn't limit the calls
+	ThrottleHintHeader              string                `json:"ThrottleHintHeader"`              // holds the header of the plugin responses carrying the load of the device in percentage
+	MaxThrottleDelayInMs            int                   `json:"MaxThrottleDelayInMs"`            // holds the delay added between the discovery calls to a plugin when the device reports full load
+	AllowFirmwareMinorMismatch      bool                  `json:"AllowFirmwareMinorMismatch"`      // holds the flag to accept a plugin whose firmware version differs from the connection method variant only after the major version
+	KeyPrefixParents                []KeyPrefixParentConf `json:"KeyPrefixParents"`                // holds the collections whose members are prefixed with the device UUID in the DB keys
+	MaxInFlightPluginCalls          int                   `json:"MaxInFlightPluginCalls"`          // holds the maximum number of plugin calls in flight while discovering a server
+	MaxTraversalDepth               int                   `json:"MaxTraversalDepth"`               // holds the maximum number of links followed from a resource to reach the resources under it while discovering a server
+	MaxPluginResponseBytes          int                   `json:"MaxPluginResponseBytes"`          // holds the maximum size of the plugin response body accepted, the larger responses are failed
+	StreamPluginResponseBytes       int                   `json:"StreamPluginResponseBytes"`       // holds the size of the plugin response body beyond which it is translated while being read
+}
+
+// KeyPrefixParentConf holds a collection whose members are prefixed with the device UUID in the DB keys
+type KeyPrefixParentConf struct {
+	Name           string `json:"Name"`           // holds the name of the collection in the URI, e.g. Systems
+	ResourceIDOnly bool   `json:"ResourceIDOnly"` // holds the flag to prefix the member only when it is the ID of the resource the key is formed for
+}
+
+// WildCardConf holds the name of a telemetry wildcard and the URI keyword which triggers it
+type WildCardConf struct {
+	Name       string `json:"Name"`       // holds the name of the wildcard, e.g. SystemID
+	URIKeyword string `json:"URIKeyword"` // holds the collection name in the metric property URI, e.g. Systems
+}
+
+// PluginTaskConf holds the configurations used while monitoring the long running tasks of the plugins
+type PluginTaskConf struct {
+	PollingIntervalInSecs int `json:"PollingIntervalInSecs"` // holds the interval in which the status of the plugin task is polled
+	StallTimeoutInSecs    int `json:"StallTimeoutInSecs"`    // holds the time after which the task is failed when its PercentComplete doesn't change
+	TimeoutInSecs         int `json:"TimeoutInSecs"`         // holds the maximum time the task is monitored irrespective of its progress
+}
+
+// PluginTimeoutConf holds the timeouts of the plugin calls for each type of operation
+type PluginTimeoutConf struct {
+	StatusTimeoutInSecs    int `json:"StatusTimeoutInSecs"`    // holds the timeout of the status and session calls made while adding a plugin
+	DiscoveryTimeoutInSecs int `json:"DiscoveryTimeoutInSecs"` // holds the timeout of the GET calls made to discover the resources
+	ActionTimeoutInSecs    int `json:"ActionTimeoutInSecs"`    // holds the timeout of the actions, like reset or firmware update, posted to the plugin
+}
+
+// PluginConnPoolConf holds the configurations of the connections reused for the calls to each of the plugins
+type PluginConnPoolConf struct {
+	MaxIdleConnsPerHost   int `json:"MaxIdleConnsPerHost"`   // holds the maximum number of idle connections kept open to a plugin
+	IdleConnTimeoutInSecs int `json:"IdleConnTimeoutInSecs"` // holds the time after which an idle connection to a plugin is closed
+}
+
+// TaskCreationConf holds the configurations used while creating the tasks in the task service
+type TaskCreationConf struct {
+	MaxRetryAttempts         int `json:"MaxRetryAttempts"`         // holds the number of times the task creation is retried after a failure
+	RetryIntervalInMillisecs int `json:"RetryIntervalInMillisecs"` // holds the interval before the first retry, the interval is doubled for every retry
 }
 
 // ExecPriorityDelayConf holds priority and delay configurations for exec actions
@@ -194,8 +288,14 @@ type ConnectionMethodConf struct {
 
 // EventConf stores all inforamtion related to event delivery configurations
 type EventConf struct {
-	DeliveryRetryAttempts        int `json:"DeliveryRetryAttempts"`        // holds value of retrying event posting to destination
-	DeliveryRetryIntervalSeconds int `json:"DeliveryRetryIntervalSeconds"` // holds value of retrying events posting in interval
+	DeliveryRetryAttempts         int      `json:"DeliveryRetryAttempts"`         // holds value of retrying event posting to destination
+	DeliveryRetryIntervalSeconds  int      `json:"DeliveryRetryIntervalSeconds"`  // holds value of retrying events posting in interval
+	ConsumerWorkerCount           int      `json:"ConsumerWorkerCount"`           // holds value of number of consumers started for each EMB topic
+	ResumeFromStoredOffset        bool     `json:"ResumeFromStoredOffset"`        // holds the flag to resume the consumption of EMB topics from the stored offset
+	OffsetPersistIntervalSecs     int      `json:"OffsetPersistIntervalSecs"`     // holds value of interval in which the offset of EMB topics are persisted
+	ConsumerMonitorIntervalSecs   int      `json:"ConsumerMonitorIntervalSecs"`   // holds value of interval in which the exited consumers of EMB topics are restarted
+	DefaultSubscriptionMessageIDs []string `json:"DefaultSubscriptionMessageIDs"` // holds the MessageIds subscribed by the default subscriptions, all the MessageIds are subscribed when empty
+	DeniedMessageIDs              []string `json:"DeniedMessageIDs"`              // holds the MessageIds excluded from the default subscriptions
 }
 
 // SetConfiguration will extract the config data from file
@@ -257,6 +357,11 @@ func ValidateConfiguration() (WarningList, error) {
 	checkURLTranslation(warningList)
 	checkPluginStatusPolling(warningList)
 	checkExecPriorityDelayConf(warningList)
+	checkDiscoveryConf(warningList)
+	checkPluginTaskConf(warningList)
+	checkPluginTimeoutConf(warningList)
+	checkPluginConnPoolConf(warningList)
+	checkTaskCreationConf(warningList)
 
 	return *warningList, nil
 }
@@ -323,6 +428,12 @@ func checkDBConf(wl *WarningList) error {
 			return err
 		}
 	}
+	for table, dbType := range Data.DBConf.TableDBType {
+		if dbType != InMemoryDBType && dbType != OnDiskDBType {
+			return fmt.Errorf("error: invalid DB type %s configured for the table %s in TableDBType, allowed values are %s and %s",
+				dbType, table, InMemoryDBType, OnDiskDBType)
+		}
+	}
 	var err error
 	if Data.DBConf.RedisInMemoryPasswordFilePath != "" && Data.KeyCertConf.RSAPrivateKeyPath != "" {
 		if Data.DBConf.RedisInMemoryPassword, err = decryptRSAOAEPEncryptedPasswords(Data.DBConf.RedisInMemoryPasswordFilePath); err != nil {
@@ -567,6 +678,7 @@ func checkPluginStatusPolling(wl *WarningList) {
 			RetryIntervalInMins:     DefaultRetryIntervalInMins,
 			ResponseTimeoutInSecs:   DefaultResponseTimeoutInSecs,
 			StartUpResouceBatchSize: DefaultStartUpResouceBatchSize,
+			MaxRetryAfterInSecs:     DefaultMaxRetryAfterInSecs,
 		}
 		return
 	}
@@ -590,6 +702,10 @@ func checkPluginStatusPolling(wl *WarningList) {
 		wl.add("No value found for StartUpResouceBatchSize, setting default value")
 		Data.PluginStatusPolling.StartUpResouceBatchSize = DefaultStartUpResouceBatchSize
 	}
+	if Data.PluginStatusPolling.MaxRetryAfterInSecs <= 0 {
+		wl.add("No value found for MaxRetryAfterInSecs, setting default value")
+		Data.PluginStatusPolling.MaxRetryAfterInSecs = DefaultMaxRetryAfterInSecs
+	}
 }
 
 func checkExecPriorityDelayConf(wl *WarningList) {
@@ -617,6 +733,250 @@ func checkExecPriorityDelayConf(wl *WarningList) {
 	}
 }
 
+// boolPtr returns the pointer to the value, it is used to set the default of the optional flags
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func checkDiscoveryConf(wl *WarningList) {
+	if Data.DiscoveryConf == nil {
+		wl.add("DiscoveryConf not provided, setting default value")
+		Data.DiscoveryConf = &DiscoveryConf{
+			RootInfoWorkerCount:             DefaultRootInfoWorkerCount,
+			SubResourceErrorPolicy:          DefaultSubResourceErrorPolicy,
+			LanguagelessRegistries:          boolPtr(DefaultLanguagelessRegistries),
+			RegistryLanguages:               getDefaultRegistryLanguages(),
+			AuditResponseMaxBytes:           DefaultAuditResponseMaxBytes,
+			ErrorBodyMaxBytes:               DefaultErrorBodyMaxBytes,
+			MaxJSONDepth:                    DefaultMaxJSONDepth,
+			MaxLinksPerResource:             DefaultMaxLinksPerResource,
+			StorageCapacityUnit:             DefaultStorageCapacityUnit,
+			TelemetryWildCards:              getDefaultTelemetryWildCards(),
+			ActiveMetricRequestMaxAgeInSecs: DefaultActiveMetricRequestMaxAgeInSecs,
+			TelemetryCollectionWorkerCount:  DefaultTelemetryCollectionWorkerCount,
+			MaxThrottleDelayInMs:            DefaultMaxThrottleDelayInMs,
+			KeyPrefixParents:                getDefaultKeyPrefixParents(),
+			MaxInFlightPluginCalls:          DefaultMaxInFlightPluginCalls,
+			MaxTraversalDepth:               DefaultMaxTraversalDepth,
+			MaxPluginResponseBytes:          DefaultMaxPluginResponseBytes,
+			StreamPluginResponseBytes:       DefaultStreamPluginResponseBytes,
+		}
+		return
+	}
+	if Data.DiscoveryConf.RootInfoWorkerCount <= 0 {
+		wl.add("No value found for RootInfoWorkerCount, setting default value")
+		Data.DiscoveryConf.RootInfoWorkerCount = DefaultRootInfoWorkerCount
+	}
+	if Data.DiscoveryConf.LanguagelessRegistries == nil {
+		wl.add("No value found for LanguagelessRegistries, setting default value")
+		Data.DiscoveryConf.LanguagelessRegistries = boolPtr(DefaultLanguagelessRegistries)
+	}
+	if Data.DiscoveryConf.TelemetryCollectionWorkerCount <= 0 {
+		wl.add("No value found for TelemetryCollectionWorkerCount, setting default value")
+		Data.DiscoveryConf.TelemetryCollectionWorkerCount = DefaultTelemetryCollectionWorkerCount
+	}
+	if Data.DiscoveryConf.PluginRequestsPerSecond < 0 {
+		wl.add("Invalid value configured for PluginRequestsPerSecond, discovery calls to the plugins are not limited")
+		Data.DiscoveryConf.PluginRequestsPerSecond = 0
+	}
+	if Data.DiscoveryConf.MaxThrottleDelayInMs <= 0 {
+		wl.add("No value found for MaxThrottleDelayInMs, setting default value")
+		Data.DiscoveryConf.MaxThrottleDelayInMs = DefaultMaxThrottleDelayInMs
+	}
+	if Data.DiscoveryConf.MaxInFlightPluginCalls <= 0 {
+		wl.add("No value found for MaxInFlightPluginCalls, setting default value")
+		Data.DiscoveryConf.MaxInFlightPluginCalls = DefaultMaxInFlightPluginCalls
+	}
+	if Data.DiscoveryConf.MaxTraversalDepth <= 0 {
+		wl.add("No value found for MaxTraversalDepth, setting default value")
+		Data.DiscoveryConf.MaxTraversalDepth = DefaultMaxTraversalDepth
+	}
+	if Data.DiscoveryConf.MaxPluginResponseBytes <= 0 {
+		wl.add("No value found for MaxPluginResponseBytes, setting default value")
+		Data.DiscoveryConf.MaxPluginResponseBytes = DefaultMaxPluginResponseBytes
+	}
+	if Data.DiscoveryConf.StreamPluginResponseBytes <= 0 {
+		wl.add("No value found for StreamPluginResponseBytes, setting default value")
+		Data.DiscoveryConf.StreamPluginResponseBytes = DefaultStreamPluginResponseBytes
+	}
+	if Data.DiscoveryConf.SubResourceErrorPolicy != SubResourceErrorPolicyWarn && Data.DiscoveryConf.SubResourceErrorPolicy != SubResourceErrorPolicyFail {
+		wl.add("Invalid value configured for SubResourceErrorPolicy, setting default value")
+		Data.DiscoveryConf.SubResourceErrorPolicy = DefaultSubResourceErrorPolicy
+	}
+	if Data.DiscoveryConf.AuditResponseMaxBytes <= 0 {
+		wl.add("No value found for AuditResponseMaxBytes, setting default value")
+		Data.DiscoveryConf.AuditResponseMaxBytes = DefaultAuditResponseMaxBytes
+	}
+	if Data.DiscoveryConf.ErrorBodyMaxBytes <= 0 {
+		wl.add("No value found for ErrorBodyMaxBytes, setting default value")
+		Data.DiscoveryConf.ErrorBodyMaxBytes = DefaultErrorBodyMaxBytes
+	}
+	if Data.DiscoveryConf.MaxJSONDepth <= 0 {
+		wl.add("No value found for MaxJSONDepth, setting default value")
+		Data.DiscoveryConf.MaxJSONDepth = DefaultMaxJSONDepth
+	}
+	if len(Data.DiscoveryConf.RegistryLanguages) == 0 {
+		wl.add("No value found for RegistryLanguages, setting default value")
+		Data.DiscoveryConf.RegistryLanguages = getDefaultRegistryLanguages()
+	}
+	if Data.DiscoveryConf.MaxLinksPerResource <= 0 {
+		wl.add("No value found for MaxLinksPerResource, setting default value")
+		Data.DiscoveryConf.MaxLinksPerResource = DefaultMaxLinksPerResource
+	}
+	switch Data.DiscoveryConf.StorageCapacityUnit {
+	case StorageCapacityUnitGB, StorageCapacityUnitGiB, StorageCapacityUnitBytes:
+	default:
+		wl.add("Invalid value configured for StorageCapacityUnit, setting default value")
+		Data.DiscoveryConf.StorageCapacityUnit = DefaultStorageCapacityUnit
+	}
+	if Data.DiscoveryConf.ActiveMetricRequestMaxAgeInSecs <= 0 {
+		wl.add("No value found for ActiveMetricRequestMaxAgeInSecs, setting default value")
+		Data.DiscoveryConf.ActiveMetricRequestMaxAgeInSecs = DefaultActiveMetricRequestMaxAgeInSecs
+	}
+	var wildCards []WildCardConf
+	for _, wildCard := range Data.DiscoveryConf.TelemetryWildCards {
+		if wildCard.Name == "" || wildCard.URIKeyword == "" {
+			wl.add("Name or URIKeyword not provided for an entry in TelemetryWildCards, ignoring the entry")
+			continue
+		}
+		wildCards = append(wildCards, wildCard)
+	}
+	if len(wildCards) == 0 {
+		wl.add("No value found for TelemetryWildCards, setting default value")
+		wildCards = getDefaultTelemetryWildCards()
+	}
+	Data.DiscoveryConf.TelemetryWildCards = wildCards
+	var keyPrefixParents []KeyPrefixParentConf
+	for _, parent := range Data.DiscoveryConf.KeyPrefixParents {
+		if parent.Name == "" {
+			wl.add("Name not provided for an entry in KeyPrefixParents, ignoring the entry")
+			continue
+		}
+		keyPrefixParents = append(keyPrefixParents, parent)
+	}
+	if len(keyPrefixParents) == 0 {
+		wl.add("No value found for KeyPrefixParents, setting default value")
+		keyPrefixParents = getDefaultKeyPrefixParents()
+	}
+	Data.DiscoveryConf.KeyPrefixParents = keyPrefixParents
+}
+
+// getDefaultRegistryLanguages returns the default languages of the registry files in the order of preference
+func getDefaultRegistryLanguages() []string {
+	return []string{"en", "en-US"}
+}
+
+// getDefaultTelemetryWildCards returns the default SystemID and ChassisID telemetry wildcards
+func getDefaultTelemetryWildCards() []WildCardConf {
+	return []WildCardConf{
+		{Name: DefaultSystemWildCardName, URIKeyword: "Systems"},
+		{Name: DefaultChassisWildCardName, URIKeyword: "Chassis"},
+	}
+}
+
+// getDefaultKeyPrefixParents returns the collections whose members are prefixed with the device UUID by default,
+// the members of the Licenses are always prefixed and the members of the rest only when they are the resource saved
+func getDefaultKeyPrefixParents() []KeyPrefixParentConf {
+	return []KeyPrefixParentConf{
+		{Name: "Systems", ResourceIDOnly: true},
+		{Name: "Chassis", ResourceIDOnly: true},
+		{Name: "Managers", ResourceIDOnly: true},
+		{Name: "FirmwareInventory", ResourceIDOnly: true},
+		{Name: "SoftwareInventory", ResourceIDOnly: true},
+		{Name: "Licenses"},
+	}
+}
+
+func checkPluginTaskConf(wl *WarningList) {
+	if Data.PluginTaskConf == nil {
+		wl.add("PluginTaskConf not provided, setting default value")
+		Data.PluginTaskConf = &PluginTaskConf{
+			PollingIntervalInSecs: DefaultPluginTaskPollingIntervalInSecs,
+			StallTimeoutInSecs:    DefaultPluginTaskStallTimeoutInSecs,
+			TimeoutInSecs:         DefaultPluginTaskTimeoutInSecs,
+		}
+		return
+	}
+	if Data.PluginTaskConf.PollingIntervalInSecs <= 0 {
+		wl.add("No value found for PollingIntervalInSecs, setting default value")
+		Data.PluginTaskConf.PollingIntervalInSecs = DefaultPluginTaskPollingIntervalInSecs
+	}
+	if Data.PluginTaskConf.StallTimeoutInSecs <= 0 {
+		wl.add("No value found for StallTimeoutInSecs, setting default value")
+		Data.PluginTaskConf.StallTimeoutInSecs = DefaultPluginTaskStallTimeoutInSecs
+	}
+	if Data.PluginTaskConf.TimeoutInSecs <= 0 {
+		wl.add("No value found for TimeoutInSecs, setting default value")
+		Data.PluginTaskConf.TimeoutInSecs = DefaultPluginTaskTimeoutInSecs
+	}
+	if Data.PluginTaskConf.StallTimeoutInSecs > Data.PluginTaskConf.TimeoutInSecs {
+		wl.add("StallTimeoutInSecs is greater than TimeoutInSecs, setting it to TimeoutInSecs")
+		Data.PluginTaskConf.StallTimeoutInSecs = Data.PluginTaskConf.TimeoutInSecs
+	}
+}
+
+func checkPluginTimeoutConf(wl *WarningList) {
+	if Data.PluginTimeoutConf == nil {
+		wl.add("PluginTimeoutConf not provided, setting default value")
+		Data.PluginTimeoutConf = &PluginTimeoutConf{
+			StatusTimeoutInSecs:    DefaultPluginStatusTimeoutInSecs,
+			DiscoveryTimeoutInSecs: DefaultPluginDiscoveryTimeoutInSecs,
+			ActionTimeoutInSecs:    DefaultPluginActionTimeoutInSecs,
+		}
+		return
+	}
+	if Data.PluginTimeoutConf.StatusTimeoutInSecs <= 0 {
+		wl.add("No value found for StatusTimeoutInSecs, setting default value")
+		Data.PluginTimeoutConf.StatusTimeoutInSecs = DefaultPluginStatusTimeoutInSecs
+	}
+	if Data.PluginTimeoutConf.DiscoveryTimeoutInSecs <= 0 {
+		wl.add("No value found for DiscoveryTimeoutInSecs, setting default value")
+		Data.PluginTimeoutConf.DiscoveryTimeoutInSecs = DefaultPluginDiscoveryTimeoutInSecs
+	}
+	if Data.PluginTimeoutConf.ActionTimeoutInSecs <= 0 {
+		wl.add("No value found for ActionTimeoutInSecs, setting default value")
+		Data.PluginTimeoutConf.ActionTimeoutInSecs = DefaultPluginActionTimeoutInSecs
+	}
+}
+
+func checkPluginConnPoolConf(wl *WarningList) {
+	if Data.PluginConnPoolConf == nil {
+		wl.add("PluginConnPoolConf not provided, setting default value")
+		Data.PluginConnPoolConf = &PluginConnPoolConf{
+			MaxIdleConnsPerHost:   DefaultPluginMaxIdleConnsPerHost,
+			IdleConnTimeoutInSecs: DefaultPluginIdleConnTimeoutInSecs,
+		}
+		return
+	}
+	if Data.PluginConnPoolConf.MaxIdleConnsPerHost <= 0 {
+		wl.add("No value found for MaxIdleConnsPerHost, setting default value")
+		Data.PluginConnPoolConf.MaxIdleConnsPerHost = DefaultPluginMaxIdleConnsPerHost
+	}
+	if Data.PluginConnPoolConf.IdleConnTimeoutInSecs <= 0 {
+		wl.add("No value found for IdleConnTimeoutInSecs, setting default value")
+		Data.PluginConnPoolConf.IdleConnTimeoutInSecs = DefaultPluginIdleConnTimeoutInSecs
+	}
+}
+
+func checkTaskCreationConf(wl *WarningList) {
+	if Data.TaskCreationConf == nil {
+		wl.add("TaskCreationConf not provided, setting default value")
+		Data.TaskCreationConf = &TaskCreationConf{
+			MaxRetryAttempts:         DefaultTaskCreationMaxRetryAttempts,
+			RetryIntervalInMillisecs: DefaultTaskCreationRetryIntervalInMillisecs,
+		}
+		return
+	}
+	if Data.TaskCreationConf.MaxRetryAttempts < 0 {
+		wl.add("Invalid value found for MaxRetryAttempts of TaskCreationConf, setting default value")
+		Data.TaskCreationConf.MaxRetryAttempts = DefaultTaskCreationMaxRetryAttempts
+	}
+	if Data.TaskCreationConf.RetryIntervalInMillisecs <= 0 {
+		wl.add("No value found for RetryIntervalInMillisecs, setting default value")
+		Data.TaskCreationConf.RetryIntervalInMillisecs = DefaultTaskCreationRetryIntervalInMillisecs
+	}
+}
+
 func checkTLSConf(wl *WarningList) error {
 	if Data.TLSConf == nil {
 		wl.add("TLSConf not provided, setting default values")
@@ -662,6 +1022,9 @@ func checkEventConf(wl *WarningList) error {
 		Data.EventConf = &EventConf{
 			DeliveryRetryAttempts:        DefaultDeliveryRetryAttempts,
 			DeliveryRetryIntervalSeconds: DefaultDeliveryRetryIntervalSeconds,
+			ConsumerWorkerCount:          DefaultConsumerWorkerCount,
+			OffsetPersistIntervalSecs:    DefaultOffsetPersistIntervalSecs,
+			ConsumerMonitorIntervalSecs:  DefaultConsumerMonitorIntervalSecs,
 		}
 		return nil
 	}
@@ -673,7 +1036,40 @@ func checkEventConf(wl *WarningList) error {
 		wl.add("No value found for DeliveryRetryIntervalSeconds, setting default value")
 		Data.EventConf.DeliveryRetryIntervalSeconds = DefaultDeliveryRetryIntervalSeconds
 	}
-	return nil
+	if Data.EventConf.ConsumerWorkerCount <= 0 {
+		wl.add("No value found for ConsumerWorkerCount, setting default value")
+		Data.EventConf.ConsumerWorkerCount = DefaultConsumerWorkerCount
+	}
+	if Data.EventConf.OffsetPersistIntervalSecs <= 0 {
+		wl.add("No value found for OffsetPersistIntervalSecs, setting default value")
+		Data.EventConf.OffsetPersistIntervalSecs = DefaultOffsetPersistIntervalSecs
+	}
+	if Data.EventConf.ConsumerMonitorIntervalSecs <= 0 {
+		wl.add("No value found for ConsumerMonitorIntervalSecs, setting default value")
+		Data.EventConf.ConsumerMonitorIntervalSecs = DefaultConsumerMonitorIntervalSecs
+	}
+	return checkDeniedMessageIDs()
+}
+
+// checkDeniedMessageIDs checks the DeniedMessageIDs leave some of the DefaultSubscriptionMessageIDs
+// to be subscribed. The denylist can't be applied to the default subscriptions of all the MessageIds
+func checkDeniedMessageIDs() error {
+	if len(Data.EventConf.DeniedMessageIDs) == 0 {
+		return nil
+	}
+	if len(Data.EventConf.DefaultSubscriptionMessageIDs) == 0 {
+		return fmt.Errorf("DeniedMessageIDs can't be applied without DefaultSubscriptionMessageIDs, as all the MessageIds are subscribed")
+	}
+	denied := make(map[string]bool, len(Data.EventConf.DeniedMessageIDs))
+	for _, messageID := range Data.EventConf.DeniedMessageIDs {
+		denied[messageID] = true
+	}
+	for _, messageID := range Data.EventConf.DefaultSubscriptionMessageIDs {
+		if !denied[messageID] {
+			return nil
+		}
+	}
+	return fmt.Errorf("all the DefaultSubscriptionMessageIDs are denied by DeniedMessageIDs")
 }
 
 func checkResourceRateLimit() error {
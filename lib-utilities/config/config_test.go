@@ -16,6 +16,7 @@
 package config
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -621,3 +622,67 @@ func TestValidateConfigurationForEventConf(t *testing.T) {
 	}
 	os.Remove(sampleFileForTest)
 }
+
+func TestCheckEventConfDeniedMessageIDs(t *testing.T) {
+	defer func() {
+		Data.EventConf = nil
+	}()
+	tests := []struct {
+		name      string
+		messageID []string
+		denied    []string
+		wantErr   bool
+	}{
+		{name: "nothing denied", wantErr: false},
+		{name: "some MessageIds denied", messageID: []string{"Alert.1.0.LanDisconnect", "Alert.1.0.TemperatureHigh"}, denied: []string{"Alert.1.0.LanDisconnect"}, wantErr: false},
+		{name: "denied without MessageIds", denied: []string{"Alert.1.0.LanDisconnect"}, wantErr: true},
+		{name: "all MessageIds denied", messageID: []string{"Alert.1.0.LanDisconnect"}, denied: []string{"Alert.1.0.LanDisconnect"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Data.EventConf = &EventConf{
+				DefaultSubscriptionMessageIDs: tt.messageID,
+				DeniedMessageIDs:              tt.denied,
+			}
+			var wl WarningList
+			if err := checkEventConf(&wl); (err != nil) != tt.wantErr {
+				t.Errorf("checkEventConf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDiscoveryConfLanguagelessRegistries(t *testing.T) {
+	defer func() {
+		Data.DiscoveryConf = nil
+	}()
+	tests := []struct {
+		name string
+		conf string
+		want bool
+	}{
+		{name: "not configured", conf: `{"RootInfoWorkerCount":2}`, want: DefaultLanguagelessRegistries},
+		{name: "disabled", conf: `{"LanguagelessRegistries":false}`, want: false},
+		{name: "enabled", conf: `{"LanguagelessRegistries":true}`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Data.DiscoveryConf = &DiscoveryConf{}
+			if err := json.Unmarshal([]byte(tt.conf), Data.DiscoveryConf); err != nil {
+				t.Fatalf("error while trying to unmarshal DiscoveryConf: %v", err)
+			}
+			var wl WarningList
+			checkDiscoveryConf(&wl)
+			if got := Data.DiscoveryConf.LanguagelessRegistries; got == nil || *got != tt.want {
+				t.Errorf("checkDiscoveryConf() LanguagelessRegistries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	Data.DiscoveryConf = nil
+	var wl WarningList
+	checkDiscoveryConf(&wl)
+	if got := Data.DiscoveryConf.LanguagelessRegistries; got == nil || *got != DefaultLanguagelessRegistries {
+		t.Errorf("checkDiscoveryConf() LanguagelessRegistries = %v, want %v", got, DefaultLanguagelessRegistries)
+	}
+}
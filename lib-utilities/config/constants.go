@@ -38,6 +38,10 @@ const (
 	DefaultExpiredSessionCleanUpTimeInMins = 15
 	// DefaultDBProtocol - default Protocol value
 	DefaultDBProtocol = "tcp"
+	// InMemoryDBType - value of TableDBType to store the table in InMemory DB
+	InMemoryDBType = "InMemory"
+	// OnDiskDBType - value of TableDBType to store the table in OnDisk DB
+	OnDiskDBType = "OnDisk"
 	// DefaultDBMaxActiveConns - default MaxActiveConns value
 	DefaultDBMaxActiveConns = 120
 	// DefaultDBMaxIdleConns - default MaxIdleConns value
@@ -66,6 +70,72 @@ const (
 	DefaultResponseTimeoutInSecs = 3
 	// DefaultStartUpResouceBatchSize - default StartUpResouceBatchSize value
 	DefaultStartUpResouceBatchSize = 10
+	// DefaultMaxRetryAfterInSecs - default MaxRetryAfterInSecs value
+	DefaultMaxRetryAfterInSecs = 300
+	// DefaultRootInfoWorkerCount - default RootInfoWorkerCount value
+	DefaultRootInfoWorkerCount = 5
+	// DefaultTelemetryCollectionWorkerCount - default TelemetryCollectionWorkerCount value
+	DefaultTelemetryCollectionWorkerCount = 4
+	// DefaultMaxThrottleDelayInMs - default MaxThrottleDelayInMs value
+	DefaultMaxThrottleDelayInMs = 1000
+	// DefaultMaxInFlightPluginCalls - default MaxInFlightPluginCalls value
+	DefaultMaxInFlightPluginCalls = 16
+	// DefaultLanguagelessRegistries - default LanguagelessRegistries value
+	DefaultLanguagelessRegistries = true
+	// DefaultMaxTraversalDepth - default MaxTraversalDepth value
+	DefaultMaxTraversalDepth = 32
+	// DefaultMaxPluginResponseBytes - default MaxPluginResponseBytes value
+	DefaultMaxPluginResponseBytes = 67108864
+	// DefaultStreamPluginResponseBytes - default StreamPluginResponseBytes value
+	DefaultStreamPluginResponseBytes = 1048576
+	// DefaultAuditResponseMaxBytes - default AuditResponseMaxBytes value
+	DefaultAuditResponseMaxBytes = 65536
+	// DefaultErrorBodyMaxBytes - default ErrorBodyMaxBytes value
+	DefaultErrorBodyMaxBytes = 4096
+	// DefaultMaxJSONDepth - default MaxJSONDepth value
+	DefaultMaxJSONDepth = 64
+	// DefaultMaxLinksPerResource - default MaxLinksPerResource value
+	DefaultMaxLinksPerResource = 1000
+	// DefaultActiveMetricRequestMaxAgeInSecs - default ActiveMetricRequestMaxAgeInSecs value
+	DefaultActiveMetricRequestMaxAgeInSecs = 900
+	// DefaultPluginTaskPollingIntervalInSecs - default PollingIntervalInSecs value of PluginTaskConf
+	DefaultPluginTaskPollingIntervalInSecs = 5
+	// DefaultPluginTaskStallTimeoutInSecs - default StallTimeoutInSecs value of PluginTaskConf
+	DefaultPluginTaskStallTimeoutInSecs = 1800
+	// DefaultPluginTaskTimeoutInSecs - default TimeoutInSecs value of PluginTaskConf
+	DefaultPluginTaskTimeoutInSecs = 7200
+	// DefaultPluginStatusTimeoutInSecs - default StatusTimeoutInSecs value of PluginTimeoutConf
+	DefaultPluginStatusTimeoutInSecs = 30
+	// DefaultPluginDiscoveryTimeoutInSecs - default DiscoveryTimeoutInSecs value of PluginTimeoutConf
+	DefaultPluginDiscoveryTimeoutInSecs = 300
+	// DefaultPluginActionTimeoutInSecs - default ActionTimeoutInSecs value of PluginTimeoutConf
+	DefaultPluginActionTimeoutInSecs = 900
+	// DefaultPluginMaxIdleConnsPerHost - default MaxIdleConnsPerHost value of PluginConnPoolConf
+	DefaultPluginMaxIdleConnsPerHost = 16
+	// DefaultPluginIdleConnTimeoutInSecs - default IdleConnTimeoutInSecs value of PluginConnPoolConf
+	DefaultPluginIdleConnTimeoutInSecs = 90
+	// DefaultTaskCreationMaxRetryAttempts - default MaxRetryAttempts value of TaskCreationConf
+	DefaultTaskCreationMaxRetryAttempts = 3
+	// DefaultTaskCreationRetryIntervalInMillisecs - default RetryIntervalInMillisecs value of TaskCreationConf
+	DefaultTaskCreationRetryIntervalInMillisecs = 500
+	// DefaultSystemWildCardName - name of the default telemetry wildcard for the system ids
+	DefaultSystemWildCardName = "SystemID"
+	// DefaultChassisWildCardName - name of the default telemetry wildcard for the chassis ids
+	DefaultChassisWildCardName = "ChassisID"
+	// SubResourceErrorPolicyWarn - SubResourceErrorPolicy value to record the sub resource 5xx errors as warnings and continue
+	SubResourceErrorPolicyWarn = "Warn"
+	// SubResourceErrorPolicyFail - SubResourceErrorPolicy value to fail the discovery for the sub resource 5xx errors
+	SubResourceErrorPolicyFail = "Fail"
+	// DefaultSubResourceErrorPolicy - default SubResourceErrorPolicy value
+	DefaultSubResourceErrorPolicy = SubResourceErrorPolicyWarn
+	// StorageCapacityUnitGB - StorageCapacityUnit value to index the drive capacity in decimal gigabytes
+	StorageCapacityUnitGB = "GB"
+	// StorageCapacityUnitGiB - StorageCapacityUnit value to index the drive capacity in binary gibibytes
+	StorageCapacityUnitGiB = "GiB"
+	// StorageCapacityUnitBytes - StorageCapacityUnit value to index the drive capacity in bytes
+	StorageCapacityUnitBytes = "Bytes"
+	// DefaultStorageCapacityUnit - default StorageCapacityUnit value
+	DefaultStorageCapacityUnit = StorageCapacityUnitGB
 	// DefaultMinResetPriority - default MinResetPriority value
 	DefaultMinResetPriority = 1
 	// DefaultMaxResetDelay - maximum delay in seconds a reset action can wait
@@ -94,6 +164,12 @@ const (
 	DefaultDeliveryRetryAttempts = 3
 	// DefaultDeliveryRetryIntervalSeconds - default DeliveryRetryIntervalSeconds value
 	DefaultDeliveryRetryIntervalSeconds = 60
+	// DefaultConsumerWorkerCount - default ConsumerWorkerCount value
+	DefaultConsumerWorkerCount = 1
+	// DefaultOffsetPersistIntervalSecs - default OffsetPersistIntervalSecs value
+	DefaultOffsetPersistIntervalSecs = 5
+	// DefaultConsumerMonitorIntervalSecs - default ConsumerMonitorIntervalSecs value
+	DefaultConsumerMonitorIntervalSecs = 60
 )
 
 var (
@@ -221,6 +221,7 @@ func SetUpMockConfig(t *testing.T) error {
 	Data.RootServiceUUID = "3bd1f589-117a-4cf9-89f2-da44ee8e012b"
 	Data.FirmwareVersion = "1.0"
 	Data.SouthBoundRequestTimeoutInSecs = 10
+	Data.PluginTenantHeader = ""
 	Data.ServerRediscoveryBatchSize = 10
 	path := strings.SplitAfter(workingDir, "ODIM")
 	var basePath string
@@ -308,6 +309,72 @@ func SetUpMockConfig(t *testing.T) error {
 		ResponseTimeoutInSecs:   1,
 		StartUpResouceBatchSize: 1,
 		PollingFrequencyInMins:  1,
+		MaxRetryAfterInSecs:     2,
+	}
+	Data.DiscoveryConf = &DiscoveryConf{
+		RootInfoWorkerCount:      2,
+		DiscoverVirtualMedia:     true,
+		DiscoverChassisAssembly:  true,
+		DiscoverPCIeDevices:      true,
+		DiscoverNetworkProtocol:  true,
+		DiscoverSerialInterfaces: true,
+		SubResourceErrorPolicy:   SubResourceErrorPolicyWarn,
+		LanguagelessRegistries:   boolPtr(true),
+		RegistryLanguages:        []string{"en", "en-US"},
+		AuditPluginResponses:     false,
+		AuditResponseMaxBytes:    1024,
+		ErrorBodyMaxBytes:        1024,
+		MaxJSONDepth:             64,
+		MaxLinksPerResource:      1000,
+		StorageCapacityUnit:      StorageCapacityUnitGB,
+		TelemetryWildCards: []WildCardConf{
+			{Name: "SystemID", URIKeyword: "Systems"},
+			{Name: "ChassisID", URIKeyword: "Chassis"},
+		},
+		ActiveMetricRequestMaxAgeInSecs: 60,
+		BalancePluginReplicas:           false,
+		PluginWeights:                   map[string]int{},
+		MaxConcurrentAddsPerPluginType:  map[string]int{},
+		MaskSerialNumbers:               false,
+		IndexMemoryProcessorDetails:     false,
+		SkipPluginSessionTeardown:       false,
+		VerifyPluginEMBConsumption:      false,
+		ReportLinkIntegrity:             false,
+		TelemetryCollectionWorkerCount:  2,
+		PluginRequestsPerSecond:         0,
+		ThrottleHintHeader:              "",
+		MaxThrottleDelayInMs:            1000,
+		AllowFirmwareMinorMismatch:      false,
+		KeyPrefixParents: []KeyPrefixParentConf{
+			{Name: "Systems", ResourceIDOnly: true},
+			{Name: "Chassis", ResourceIDOnly: true},
+			{Name: "Managers", ResourceIDOnly: true},
+			{Name: "FirmwareInventory", ResourceIDOnly: true},
+			{Name: "SoftwareInventory", ResourceIDOnly: true},
+			{Name: "Licenses"},
+		},
+		MaxInFlightPluginCalls:    16,
+		MaxTraversalDepth:         32,
+		MaxPluginResponseBytes:    1048576,
+		StreamPluginResponseBytes: 65536,
+	}
+	Data.PluginTaskConf = &PluginTaskConf{
+		PollingIntervalInSecs: 1,
+		StallTimeoutInSecs:    60,
+		TimeoutInSecs:         120,
+	}
+	Data.PluginTimeoutConf = &PluginTimeoutConf{
+		StatusTimeoutInSecs:    5,
+		DiscoveryTimeoutInSecs: 10,
+		ActionTimeoutInSecs:    20,
+	}
+	Data.PluginConnPoolConf = &PluginConnPoolConf{
+		MaxIdleConnsPerHost:   4,
+		IdleConnTimeoutInSecs: 30,
+	}
+	Data.TaskCreationConf = &TaskCreationConf{
+		MaxRetryAttempts:         2,
+		RetryIntervalInMillisecs: 10,
 	}
 	Data.ExecPriorityDelayConf = &ExecPriorityDelayConf{
 		MinResetPriority:    1,
@@ -340,6 +407,9 @@ func SetUpMockConfig(t *testing.T) error {
 	Data.EventConf = &EventConf{
 		DeliveryRetryAttempts:        1,
 		DeliveryRetryIntervalSeconds: 1,
+		ConsumerWorkerCount:          1,
+		OffsetPersistIntervalSecs:    1,
+		ConsumerMonitorIntervalSecs:  1,
 	}
 	Data.TaskQueueConf = &TaskQueueConf{
 		QueueSize:        1000,
@@ -39,6 +39,27 @@ func SubscribeToEMB(pluginID string, queueList []string) error {
 	return nil
 }
 
+// VerifyEMBConsumption checks with the event service whether the event queues of the plugin are being consumed
+func VerifyEMBConsumption(pluginID string, queueList []string) error {
+	conn, errConn := ODIMService.Client(Events)
+	if errConn != nil {
+		return fmt.Errorf("Failed to create client connection: %s", errConn.Error())
+	}
+	defer conn.Close()
+	events := eventsproto.NewEventsClient(conn)
+	resp, err := events.IsEMBConsumed(context.TODO(), &eventsproto.SubscribeEMBRequest{
+		PluginID:     pluginID,
+		EMBQueueName: queueList,
+	})
+	if err != nil {
+		return fmt.Errorf("error verifying the EMB consumption %s", err.Error())
+	}
+	if !resp.Status {
+		return fmt.Errorf("EMB queues %v of the plugin %s are not being consumed", queueList, pluginID)
+	}
+	return nil
+}
+
 // DeleteSubscription  calls the event service and delete all subscription realated to that server
 func DeleteSubscription(uuid string) (*eventsproto.EventSubResponse, error) {
 	var resp eventsproto.EventSubResponse
@@ -119,8 +119,10 @@ func routers() *iris.Application {
 	{
 		pluginRoutes.Post("/validate", rfpmiddleware.BasicAuth, rfphandler.Validate)
 		pluginRoutes.Post("/Sessions", rfphandler.CreateSession)
+		pluginRoutes.Delete("/Sessions", rfphandler.DeleteSession)
 		pluginRoutes.Post("/Subscriptions", rfpmiddleware.BasicAuth, rfphandler.CreateEventSubscription)
 		pluginRoutes.Delete("/Subscriptions", rfpmiddleware.BasicAuth, rfphandler.DeleteEventSubscription)
+		pluginRoutes.Get("", rfpmiddleware.BasicAuth, rfphandler.GetResource)
 
 		//Adding routes related to all system gets
 		systems := pluginRoutes.Party("/Systems", rfpmiddleware.BasicAuth)
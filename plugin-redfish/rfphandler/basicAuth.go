@@ -32,6 +32,8 @@ import (
 func TokenValidation(token string) bool {
 	var flag bool
 	flag = false
+	tokenLock.RLock()
+	defer tokenLock.RUnlock()
 	for _, v := range tokenDetails {
 		if token == v.Token {
 			flag = true
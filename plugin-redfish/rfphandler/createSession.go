@@ -26,6 +26,7 @@ import (
 	"golang.org/x/crypto/sha3"
 	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -36,7 +37,9 @@ type TokenMap struct {
 }
 
 var tokenDetails []TokenMap
-var tokenSpec TokenMap
+
+// tokenLock guards tokenDetails, as the sessions are created, deleted and validated concurrently
+var tokenLock sync.RWMutex
 
 //CreateSession is used to create session for odimra to interact with plugin
 func CreateSession(ctx iris.Context) {
@@ -68,14 +71,48 @@ func CreateSession(ctx iris.Context) {
 	}
 	//Create token
 	token := createToken()
-	currentTime := time.Now()
-	tokenSpec.Token = token
-	tokenSpec.LastUsed = currentTime
-	tokenDetails = append(tokenDetails, tokenSpec)
+	addToken(token)
 	ctx.StatusCode(http.StatusCreated)
 	ctx.Header("X-Auth-Token", token)
 }
 
+//DeleteSession is used to delete the session of the X-Auth-Token once odimra is done with it
+func DeleteSession(ctx iris.Context) {
+	token := ctx.GetHeader("X-Auth-Token")
+	if token != "" && removeToken(token) {
+		ctx.StatusCode(http.StatusNoContent)
+		return
+	}
+	errorMessage := "Invalid/Expired X-Auth-Token"
+	log.Error(errorMessage)
+	ctx.StatusCode(http.StatusUnauthorized)
+	ctx.WriteString(errorMessage)
+}
+
+// addToken adds the token to the plugin generated tokens
+func addToken(token string) {
+	tokenLock.Lock()
+	defer tokenLock.Unlock()
+	tokenDetails = append(tokenDetails, TokenMap{
+		Token:    token,
+		LastUsed: time.Now(),
+	})
+}
+
+// removeToken removes the token from the plugin generated tokens,
+// false is returned when the token is not present
+func removeToken(token string) bool {
+	tokenLock.Lock()
+	defer tokenLock.Unlock()
+	for i, v := range tokenDetails {
+		if token == v.Token {
+			tokenDetails = append(tokenDetails[:i], tokenDetails[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 func validate(userName, password string) bool {
 	//var err error
 	username := pluginConfig.Data.PluginConf.UserName
@@ -44,19 +44,19 @@ var (
 // CreateUser connects to the persistencemgr and creates a user in db
 func CreateUser(user User) *errors.Error {
 
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	//Create a header for data entry
+	const table string = "User"
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
-	//Create a header for data entry
-	const table string = "User"
 	//Save data into Database
 	return conn.Create(table, user.UserName, user)
 }
 
 //GetAllUsers gets all the accounts from the db
 func GetAllUsers() ([]User, *errors.Error) {
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("User", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +85,7 @@ func GetAllUsers() ([]User, *errors.Error) {
 func GetUserDetails(userName string) (User, *errors.Error) {
 	var user User
 
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("User", common.OnDisk))
 	if err != nil {
 		return user, err
 	}
@@ -102,7 +102,7 @@ func GetUserDetails(userName string) (User, *errors.Error) {
 
 //DeleteUser will delete the user entry from the database based on the uuid
 func DeleteUser(key string) *errors.Error {
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("User", common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -115,12 +115,12 @@ func DeleteUser(key string) *errors.Error {
 // UpdateUserDetails will modify the current details to given changes
 func UpdateUserDetails(user, newData User) *errors.Error {
 
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	//Create a header for data entry
+	const table string = "User"
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
-	//Create a header for data entry
-	const table string = "User"
 	//Save data into Database
 
 	if newData.Password != "" {
@@ -233,6 +233,7 @@ func (phc *PluginHealthCheckInterface) DupPluginConf() {
 	phc.PluginConfig.RetryIntervalInMins = config.Data.PluginStatusPolling.RetryIntervalInMins
 	phc.PluginConfig.ResponseTimeoutInSecs = config.Data.PluginStatusPolling.ResponseTimeoutInSecs
 	phc.PluginConfig.StartUpResouceBatchSize = config.Data.PluginStatusPolling.StartUpResouceBatchSize
+	phc.PluginConfig.RediscoverOnVersionChange = config.Data.PluginStatusPolling.RediscoverOnVersionChange
 	phc.RootCA = make([]byte, len(config.Data.KeyCertConf.RootCACertificate))
 	copy(phc.RootCA, config.Data.KeyCertConf.RootCACertificate)
 	return
@@ -309,6 +310,13 @@ func GetAllPlugins(ctx context.Context) ([]agmodel.Plugin, error) {
 
 // GetPluginStatus is for checking the status of a plugin
 func (phc *PluginHealthCheckInterface) GetPluginStatus(ctx context.Context, plugin agmodel.Plugin) (bool, []string) {
+	status, _, topics := phc.GetPluginStatusDetailed(ctx, plugin)
+	return status, topics
+}
+
+// GetPluginStatusDetailed is for checking the status of a plugin, along with the
+// status it returns the version reported by the plugin
+func (phc *PluginHealthCheckInterface) GetPluginStatusDetailed(ctx context.Context, plugin agmodel.Plugin) (bool, string, []string) {
 	var pluginStatus = common.PluginStatus{
 		Method: http.MethodGet,
 		RequestBody: common.StatusRequest{
@@ -321,16 +329,16 @@ func (phc *PluginHealthCheckInterface) GetPluginStatus(ctx context.Context, plug
 		PluginPort:              plugin.Port,
 		PluginUsername:          plugin.Username,
 		PluginUserPassword:      string(plugin.Password),
-		PluginPrefferedAuthType: plugin.PreferredAuthType,
+		PluginPrefferedAuthType: plugin.AuthType(),
 		CACertificate:           &phc.RootCA,
 	}
 	status, _, topics, err := pluginStatus.CheckStatus()
 	if err != nil {
 		l.LogWithFields(ctx).Error("failed to get the status of plugin " + plugin.ID + err.Error())
-		return false, nil
+		return false, "", nil
 	}
 	l.LogWithFields(ctx).Info("Status of plugin " + plugin.ID + " is " + strconv.FormatBool(status))
-	return status, topics
+	return status, pluginStatus.Version, topics
 }
 
 // GetPluginManagedServers is for fetching the list of servers managed by a plugin
@@ -342,7 +350,12 @@ func (phc *PluginHealthCheckInterface) GetPluginManagedServers(plugin agmodel.Pl
 	return serversList
 }
 
+// maxParallelPasswordDecryptions bounds the number of server passwords decrypted in parallel
+const maxParallelPasswordDecryptions = 16
+
 // getAllServers is for fetching the list of all servers added.
+// Passwords of the servers are decrypted in parallel, since the decryption could be slow,
+// and the servers whose password can't be decrypted are skipped.
 func (phc *PluginHealthCheckInterface) getAllServers(pluginID string) ([]agmodel.Target, error) {
 	var matchedServers []agmodel.Target
 	allServers, err := GetAllSystemsFunc()
@@ -350,17 +363,30 @@ func (phc *PluginHealthCheckInterface) getAllServers(pluginID string) ([]agmodel
 		l.Log.Error("failed to get the list of all managed servers " + err.Error())
 		return matchedServers, err
 	}
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	workers := make(chan struct{}, maxParallelPasswordDecryptions)
 	for _, server := range allServers {
-		if server.PluginID == pluginID {
+		if server.PluginID != pluginID {
+			continue
+		}
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(server agmodel.Target) {
+			defer wg.Done()
+			defer func() { <-workers }()
 			decryptedPasswordByte, err := phc.DecryptPassword(server.Password)
 			if err != nil {
 				l.Log.Error("failed to decrypt device password of the host: " + server.ManagerAddress + ":" + err.Error())
-				continue
+				return
 			}
 			server.Password = decryptedPasswordByte
+			lock.Lock()
 			matchedServers = append(matchedServers, server)
-		}
+			lock.Unlock()
+		}(server)
 	}
+	wg.Wait()
 	return matchedServers, nil
 }
 
@@ -369,7 +395,7 @@ func ContactPlugin(ctx context.Context, req agmodel.PluginContactRequest, server
 	req.LoginCredential = map[string]string{}
 	//ToDo: Variable "LoginCredentials" to be changed
 	req.LoginCredential["ServerName"] = serverName
-	if strings.EqualFold(req.Plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(req.Plugin.AuthType(), "XAuthToken") {
 		payload := map[string]interface{}{
 			"Username": req.Plugin.Username,
 			"Password": string(req.Plugin.Password),
@@ -497,6 +523,17 @@ func SetPluginStatusRecord(plugin string, count int) {
 	return
 }
 
+// GetAllPluginStatusRecords returns a copy of the status records of all the plugins
+func GetAllPluginStatusRecords() map[string]int {
+	PSRecord.Lock.Lock()
+	defer PSRecord.Lock.Unlock()
+	records := make(map[string]int, len(PSRecord.InactiveCount))
+	for plugin, count := range PSRecord.InactiveCount {
+		records[plugin] = count
+	}
+	return records
+}
+
 func CreateContext(transactionId, actionId, actionName, threadId, threadName, ProcessName string) context.Context {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, common.TransactionID, transactionId)
@@ -23,7 +23,10 @@ import (
 	"net"
 	"net/http"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
 	"github.com/ODIM-Project/ODIM/lib-utilities/config"
@@ -390,6 +393,68 @@ func TestGetPluginManagedServers(t *testing.T) {
 	}
 }
 
+func mockManagedServers(count int) func() ([]agmodel.Target, *errors.Error) {
+	return func() ([]agmodel.Target, *errors.Error) {
+		var servers []agmodel.Target
+		for i := 0; i < count; i++ {
+			pluginID := "GRF"
+			if i%2 == 1 {
+				pluginID = "ILO"
+			}
+			servers = append(servers, agmodel.Target{
+				ManagerAddress: fmt.Sprintf("10.0.0.%d", i),
+				Password:       []byte(fmt.Sprintf("password%d", i)),
+				PluginID:       pluginID,
+			})
+		}
+		return servers, nil
+	}
+}
+
+func TestGetAllServersParallelDecryption(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(orig func() ([]agmodel.Target, *errors.Error)) { GetAllSystemsFunc = orig }(GetAllSystemsFunc)
+	GetAllSystemsFunc = mockManagedServers(8)
+	phc := &PluginHealthCheckInterface{
+		DecryptPassword: func(password []byte) ([]byte, error) {
+			if string(password) == "password2" {
+				return nil, fmt.Errorf("decryption failed")
+			}
+			return append([]byte("decrypted-"), password...), nil
+		},
+	}
+	servers, err := phc.getAllServers("GRF")
+	assert.Nil(t, err)
+	var addresses []string
+	for _, server := range servers {
+		assert.Equal(t, "GRF", server.PluginID, "servers of the other plugins should be filtered")
+		assert.Equal(t, "decrypted-password"+strings.TrimPrefix(server.ManagerAddress, "10.0.0."), string(server.Password))
+		addresses = append(addresses, server.ManagerAddress)
+	}
+	sort.Strings(addresses)
+	assert.Equal(t, []string{"10.0.0.0", "10.0.0.4", "10.0.0.6"}, addresses, "server failed to decrypt should be skipped")
+}
+
+func BenchmarkGetAllServers(b *testing.B) {
+	defer func(orig func() ([]agmodel.Target, *errors.Error)) { GetAllSystemsFunc = orig }(GetAllSystemsFunc)
+	const serverCount = 200
+	const decryptDelay = 2 * time.Millisecond
+	GetAllSystemsFunc = mockManagedServers(serverCount)
+	phc := &PluginHealthCheckInterface{
+		DecryptPassword: func(password []byte) ([]byte, error) {
+			// emulates the latency of a KMS backed decryption
+			time.Sleep(decryptDelay)
+			return password, nil
+		},
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		phc.getAllServers("GRF")
+	}
+	// the wall-clock of decrypting the passwords of the servers serially
+	b.ReportMetric(float64(serverCount/2*decryptDelay.Nanoseconds()), "serial-ns/op")
+}
+
 func TestLookupHost(t *testing.T) {
 	config.SetUpMockConfig(t)
 
@@ -97,3 +97,15 @@ func PublishCtrlMsg(msgType common.ControlMessage, msg interface{}) error {
 	}
 	return nil
 }
+
+// CheckQueueAvailability checks whether the given queue exists on the message bus
+func CheckQueueAvailability(queueName string) error {
+	exists, err := dc.PipeExists(config.Data.MessageBusConf.MessageBusType, queueName)
+	if err != nil {
+		return fmt.Errorf("failed to check the queue %s on %s: %s", queueName, config.Data.MessageBusConf.MessageBusType, err.Error())
+	}
+	if !exists {
+		return fmt.Errorf("queue %s is not available on %s", queueName, config.Data.MessageBusConf.MessageBusType)
+	}
+	return nil
+}
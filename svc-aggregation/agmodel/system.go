@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	dmtfmodel "github.com/ODIM-Project/ODIM/lib-dmtf/model"
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
@@ -34,6 +35,9 @@ const (
 	// aggregateHostIndex is a index name which required for indexing
 	// aggregateHost of device
 	aggregateHostIndex = common.AggregateSubscriptionIndex
+	// ResourceTypeTable is the table which indexes the @odata.type
+	// of the discovered resources with the key of the resource
+	ResourceTypeTable = "ResourceType"
 )
 
 // Schema model is used to iterate throgh the schema json for search/filter
@@ -50,6 +54,21 @@ type SaveSystem struct {
 	UserName       string
 	DeviceUUID     string
 	PluginID       string
+	DiscoveredAt   string `json:",omitempty"`
+	// RedfishVersion and ServiceCapabilities are recorded from the ServiceRoot of the device
+	RedfishVersion      string   `json:",omitempty"`
+	ServiceCapabilities []string `json:",omitempty"`
+	// EventServiceCapabilities are recorded from the EventService of the device,
+	// it is nil for the devices without EventService
+	EventServiceCapabilities *EventServiceCapabilities `json:",omitempty"`
+}
+
+// EventServiceCapabilities is the model for the capabilities of the EventService of the device
+type EventServiceCapabilities struct {
+	DeliveryRetryAttempts        int      `json:",omitempty"`
+	DeliveryRetryIntervalSeconds int      `json:",omitempty"`
+	EventTypesForSubscription    []string `json:",omitempty"`
+	SSESupported                 bool     `json:",omitempty"`
 }
 
 // Plugin is the model for plugin information
@@ -62,6 +81,18 @@ type Plugin struct {
 	PluginType        string
 	PreferredAuthType string
 	ManagerUUID       string
+	Capabilities      []string
+	ForceBasicAuth    bool
+	Version           string
+}
+
+// AuthType returns the auth type used to contact the plugin, BasicAuth is used
+// when it is forced for the plugin irrespective of the PreferredAuthType
+func (p Plugin) AuthType() string {
+	if p.ForceBasicAuth {
+		return "BasicAuth"
+	}
+	return p.PreferredAuthType
 }
 
 // Target is for sending the requst to south bound/plugin
@@ -72,6 +103,7 @@ type Target struct {
 	PostBody       []byte `json:"PostBody"`
 	DeviceUUID     string `json:"DeviceUUID"`
 	PluginID       string `json:"PluginID"`
+	DiscoveredAt   string `json:"DiscoveredAt,omitempty"`
 }
 
 // SystemOperation hold the value system operation(InventoryRediscovery or Delete)
@@ -174,7 +206,7 @@ type PluginContactRequest struct {
 
 // GetResource fetches a resource from database using table and key
 func GetResource(Table, key string) (string, *errors.Error) {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType(Table, common.InMemory))
 	if err != nil {
 		return "", errors.PackError(err.ErrNo(), err)
 	}
@@ -192,13 +224,13 @@ func GetResource(Table, key string) (string, *errors.Error) {
 // Create connects to the persistencemgr and creates a system in db
 func (system *SaveSystem) Create(ctx context.Context, systemID string) *errors.Error {
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	//Create a header for data entry
+	const table string = "System"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		l.LogWithFields(ctx).Error("error while trying to get Db connection : " + err.Error())
 		return err
 	}
-	//Create a header for data entry
-	const table string = "System"
 	//Save data into Database
 	if err = conn.Create(table, systemID, system); err != nil {
 		l.LogWithFields(ctx).Error("error while trying to save system data in DB : " + err.Error())
@@ -211,7 +243,7 @@ func (system *SaveSystem) Create(ctx context.Context, systemID string) *errors.E
 func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 	var plugin Plugin
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return plugin, errors.PackError(err.ErrNo(), "error while trying to connect to DB: ", err.Error())
 	}
@@ -234,11 +266,32 @@ func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 	return plugin, nil
 }
 
+// GetPluginCapabilities will fetch the capabilities the plugin reported while it was added
+func GetPluginCapabilities(pluginID string) ([]string, *errors.Error) {
+	conn, err := common.GetDBConnection(common.GetTableDBType("Plugin", common.OnDisk))
+	if err != nil {
+		return nil, errors.PackError(err.ErrNo(), "error while trying to connect to DB: ", err.Error())
+	}
+
+	plugindata, err := conn.Read("Plugin", pluginID)
+	if err != nil {
+		return nil, errors.PackError(err.ErrNo(), "error while trying to fetch plugin data: ", err.Error())
+	}
+
+	var plugin struct {
+		Capabilities []string
+	}
+	if err := json.Unmarshal([]byte(plugindata), &plugin); err != nil {
+		return nil, errors.PackError(errors.JSONUnmarshalFailed, err)
+	}
+	return plugin.Capabilities, nil
+}
+
 // GetComputeSystem will fetch the compute resource details
 func GetComputeSystem(ctx context.Context, deviceUUID string) (dmtfmodel.ComputerSystem, error) {
 	var compute dmtfmodel.ComputerSystem
 
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ComputerSystem", common.InMemory))
 	if err != nil {
 		l.LogWithFields(ctx).Error("GetComputeSystem : error while trying to get db conenction : " + err.Error())
 		return compute, err
@@ -284,7 +337,7 @@ func SaveChassis(ctx context.Context, chassis dmtfmodel.Chassis, deviceUUID stri
 // GenericSave will save any resource data into the database
 func GenericSave(body []byte, table string, key string) error {
 
-	connPool, err := common.GetDBConnection(common.InMemory)
+	connPool, err := common.GetDBConnection(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -298,7 +351,7 @@ func GenericSave(body []byte, table string, key string) error {
 // SaveRegistryFile will save any Registry file in database OnDisk DB
 func SaveRegistryFile(ctx context.Context, body []byte, table string, key string) error {
 
-	connPool, err := common.GetDBConnection(common.OnDisk)
+	connPool, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -314,7 +367,7 @@ func SaveRegistryFile(ctx context.Context, body []byte, table string, key string
 
 // GetRegistryFile from InMemory DB
 func GetRegistryFile(Table, key string) (string, *errors.Error) {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType(Table, common.InMemory))
 	if err != nil {
 		return "", errors.PackError(err.ErrNo(), err)
 	}
@@ -331,7 +384,7 @@ func GetRegistryFile(Table, key string) (string, *errors.Error) {
 
 // DeleteComputeSystem will delete the compute system
 func DeleteComputeSystem(index int, key string) *errors.Error {
-	connPool, err := common.GetDBConnection(common.InMemory)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("ComputerSystem", common.InMemory))
 	if err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to connecting to DB: ", err.Error())
 	}
@@ -340,7 +393,7 @@ func DeleteComputeSystem(index int, key string) *errors.Error {
 	if _, err = connPool.Read("ComputerSystem", key); err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to get compute details: ", err.Error())
 	}
-	var computeData, inventoryData []string
+	var computeData []string
 	editedKeyList := strings.Split(key, "/")
 	editedKey := editedKeyList[len(editedKeyList)-1]
 	systemID := strings.SplitN(editedKey, ".", 2)[0]
@@ -348,29 +401,33 @@ func DeleteComputeSystem(index int, key string) *errors.Error {
 		return errors.PackError(err.ErrNo(), "error while trying to get ComputerSystem details: ", err.Error())
 	}
 	if len(computeData) == 1 {
-		if inventoryData, err = connPool.GetAllMatchingDetails("FirmwareInventory", systemID); err != nil {
-			return errors.PackError(err.ErrNo(), "error while trying to get compute details: ", err.Error())
-		}
-		for _, value := range inventoryData {
-			if err = connPool.Delete("FirmwareInventory", value); err != nil {
-				return errors.PackError(err.ErrNo(), "error while trying to delete compute details: ", err.Error())
-			}
-		}
-		if inventoryData, err = connPool.GetAllMatchingDetails("SoftwareInventory", systemID); err != nil {
-			return errors.PackError(err.ErrNo(), "error while trying to get compute details: ", err.Error())
-		}
-		for _, value := range inventoryData {
-			if err = connPool.Delete("SoftwareInventory", value); err != nil {
-				return errors.PackError(err.ErrNo(), "error while trying to delete compute details: ", err.Error())
+		for _, table := range []string{"FirmwareInventory", "SoftwareInventory"} {
+			if err = deleteMatchingKeys(table, systemID); err != nil {
+				return err
 			}
 		}
 	}
 
 	//Delete All resources
+	inMemoryConn, err := common.GetDBConnection(common.InMemory)
+	if err != nil {
+		return errors.PackError(err.ErrNo(), "error while trying to connecting to DB: ", err.Error())
+	}
 	deleteKey := "*" + systemID + "*"
-	if err = connPool.DeleteServer(deleteKey); err != nil {
+	if err = inMemoryConn.DeleteServer(deleteKey); err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to delete compute system: ", err.Error())
 	}
+	// the tables mapped to another DB are not covered by the DeleteServer of the InMemory DB
+	if config.Data.DBConf != nil {
+		for table := range config.Data.DBConf.TableDBType {
+			if common.GetTableDBType(table, common.InMemory) == common.InMemory {
+				continue
+			}
+			if err = deleteMatchingKeys(table, systemID); err != nil {
+				return err
+			}
+		}
+	}
 	if errs := deletefilteredkeys(key); errs != nil {
 		return errors.PackError(errors.UndefinedErrorType, errs)
 	}
@@ -378,6 +435,24 @@ func DeleteComputeSystem(index int, key string) *errors.Error {
 	return nil
 }
 
+// deleteMatchingKeys deletes the keys of the table matching the pattern from the DB of the table
+func deleteMatchingKeys(table, pattern string) *errors.Error {
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.InMemory))
+	if err != nil {
+		return errors.PackError(err.ErrNo(), "error while trying to connecting to DB: ", err.Error())
+	}
+	keys, err := conn.GetAllMatchingDetails(table, pattern)
+	if err != nil {
+		return errors.PackError(err.ErrNo(), "error while trying to get compute details: ", err.Error())
+	}
+	for _, key := range keys {
+		if err = conn.Delete(table, key); err != nil {
+			return errors.PackError(err.ErrNo(), "error while trying to delete compute details: ", err.Error())
+		}
+	}
+	return nil
+}
+
 func deletefilteredkeys(key string) error {
 	var sf Schema
 	schemaFile, ioErr := ioutil.ReadFile(config.Data.SearchAndFilterSchemaPath)
@@ -426,7 +501,7 @@ func deletefilteredkeys(key string) error {
 
 // DeleteSystem will delete the system from OnDisk
 func DeleteSystem(key string) *errors.Error {
-	connPool, err := common.GetDBConnection(common.OnDisk)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to connecting to DB: ", err.Error())
 	}
@@ -442,6 +517,9 @@ func DeleteSystem(key string) *errors.Error {
 		return errors.PackError(err.ErrNo(), "error while trying to delete compute system: ", err.Error())
 	}
 	//Added logic to remove simpleupdate key from inmemory after removing the server
+	if connPool, err = common.GetDBConnection(common.GetTableDBType("SimpleUpdate", common.OnDisk)); err != nil {
+		return errors.PackError(err.ErrNo(), "error while trying to connecting to DB: ", err.Error())
+	}
 	var simleUpdateData []string
 	if simleUpdateData, err = connPool.GetAllMatchingDetails("SimpleUpdate", key); err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to get simleUpdate details: ", err.Error())
@@ -458,7 +536,7 @@ func DeleteSystem(key string) *errors.Error {
 // GetTarget fetches the System(Target Device Credentials) table details
 func GetTarget(deviceUUID string) (*Target, error) {
 	var target Target
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -494,11 +572,11 @@ func SaveIndex(searchForm map[string]interface{}, table, uuid, bmcAddress string
 // SavePluginData will saves plugin on disk
 func SavePluginData(plugin Plugin) *errors.Error {
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "Plugin"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
-	const table string = "Plugin"
 	if err := conn.Create(table, plugin.ID, plugin); err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to save plugin data: ", err.Error())
 	}
@@ -508,7 +586,7 @@ func SavePluginData(plugin Plugin) *errors.Error {
 
 // GetAllSystems extracts all the computer systems saved in ondisk
 func GetAllSystems() ([]Target, *errors.Error) {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -534,7 +612,7 @@ func GetAllSystems() ([]Target, *errors.Error) {
 
 // DeletePluginData will delete the plugin entry from the database based on the uuid
 func DeletePluginData(key, table string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -546,7 +624,7 @@ func DeletePluginData(key, table string) *errors.Error {
 
 // DeleteManagersData will delete the table entry from the database based on the uuid
 func DeleteManagersData(key, table string) *errors.Error {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return err
 	}
@@ -575,7 +653,7 @@ func UpdateIndex(searchForm map[string]interface{}, table, uuid, bmcAddress stri
 
 // UpdateComputeSystem is used for updating ComputerSystem table
 func UpdateComputeSystem(key string, computeData interface{}) error {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ComputerSystem", common.InMemory))
 	if err != nil {
 		return err
 	}
@@ -624,18 +702,40 @@ func GetString(index, match string) ([]string, error) {
 	return list, nil
 }
 
+// GetUUIDIndexEntries returns the keys of the systems indexed under each UUID
+func GetUUIDIndexEntries() (map[string][]string, error) {
+	conn, dberr := common.GetDBConnection(common.InMemory)
+	if dberr != nil {
+		return nil, fmt.Errorf("error while trying to connecting to DB: %v", dberr.Error())
+	}
+	list, err := conn.GetString("UUID", 0, "*", true)
+	if err != nil && err.Error() != "no data with ID found" {
+		return nil, fmt.Errorf("error while trying to get the UUID index: %v", err)
+	}
+	var entries = make(map[string][]string)
+	for _, entry := range list {
+		// entries are in the format <UUID>::<system key>
+		uuidKey := strings.SplitN(entry, "::", 2)
+		if len(uuidKey) != 2 {
+			continue
+		}
+		entries[uuidKey[0]] = append(entries[uuidKey[0]], uuidKey[1])
+	}
+	return entries, nil
+}
+
 // AddSystemOperationInfo connects to the persistencemgr and Add the system operation info to db
 /* Inputs:
 1.systemURI: computer system uri for which system operation is maintained
 */
 func (system *SystemOperation) AddSystemOperationInfo(systemID string) *errors.Error {
 
-	conn, err := common.GetDBConnection(common.InMemory)
+	//Create a header for data entry
+	const table string = "SystemOperation"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return err
 	}
-	//Create a header for data entry
-	const table string = "SystemOperation"
 	//Save data into Database
 	if err = conn.AddResourceData(table, systemID, system); err != nil {
 		return err
@@ -650,7 +750,7 @@ func (system *SystemOperation) AddSystemOperationInfo(systemID string) *errors.E
 func GetSystemOperationInfo(systemURI string) (SystemOperation, *errors.Error) {
 	var systemOperation SystemOperation
 
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("SystemOperation", common.InMemory))
 	if err != nil {
 		return systemOperation, err
 	}
@@ -668,7 +768,7 @@ func GetSystemOperationInfo(systemURI string) (SystemOperation, *errors.Error) {
 
 // DeleteSystemOperationInfo will delete the system operation entry from the database based on the systemURI
 func DeleteSystemOperationInfo(systemURI string) *errors.Error {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("SystemOperation", common.InMemory))
 	if err != nil {
 		return err
 	}
@@ -685,12 +785,12 @@ func DeleteSystemOperationInfo(systemURI string) *errors.Error {
 */
 func AddSystemResetInfo(systemID, resetType string) *errors.Error {
 
-	conn, err := common.GetDBConnection(common.InMemory)
+	//Create a header for data entry
+	const table string = "SystemReset"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return err
 	}
-	//Create a header for data entry
-	const table string = "SystemReset"
 	//Save data into Database
 	if err = conn.AddResourceData(table, systemID, map[string]string{
 		"ResetType": resetType,
@@ -707,7 +807,7 @@ func AddSystemResetInfo(systemID, resetType string) *errors.Error {
 func GetSystemResetInfo(systemURI string) (map[string]string, *errors.Error) {
 	var resetInfo map[string]string
 
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("SystemReset", common.InMemory))
 	if err != nil {
 		return resetInfo, err
 	}
@@ -725,7 +825,7 @@ func GetSystemResetInfo(systemURI string) (map[string]string, *errors.Error) {
 
 // DeleteSystemResetInfo will delete the system reset entry from the database based on the systemURI
 func DeleteSystemResetInfo(systemURI string) *errors.Error {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("SystemReset", common.InMemory))
 	if err != nil {
 		return err
 	}
@@ -741,12 +841,12 @@ func DeleteSystemResetInfo(systemURI string) *errors.Error {
 2.aggregationSourceURI : uri of AggregationSource
 */
 func AddAggregationSource(req AggregationSource, aggregationSourceURI string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	//Create a header for data entry
+	const table string = "AggregationSource"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
-	//Create a header for data entry
-	const table string = "AggregationSource"
 	//Save data into Database
 	if err = conn.Create(table, aggregationSourceURI, req); err != nil {
 		return err
@@ -758,7 +858,7 @@ func AddAggregationSource(req AggregationSource, aggregationSourceURI string) *e
 func GetAggregationSourceInfo(aggregationSourceURI string) (AggregationSource, *errors.Error) {
 	var aggregationSource AggregationSource
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("AggregationSource", common.OnDisk))
 	if err != nil {
 		return aggregationSource, err
 	}
@@ -776,7 +876,7 @@ func GetAggregationSourceInfo(aggregationSourceURI string) (AggregationSource, *
 
 // UpdateSystemData updates the bmc details
 func UpdateSystemData(system SaveSystem, key string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -788,7 +888,7 @@ func UpdateSystemData(system SaveSystem, key string) *errors.Error {
 
 // UpdatePluginData updates the plugin details
 func UpdatePluginData(plugin Plugin, key string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -798,9 +898,31 @@ func UpdatePluginData(plugin Plugin, key string) *errors.Error {
 	return nil
 }
 
+// UpdatePluginVersion updates the version of the plugin in the stored plugin details,
+// the stored plugin is updated as is to retain its encrypted password
+func UpdatePluginVersion(pluginID, version string) *errors.Error {
+	conn, err := common.GetDBConnection(common.GetTableDBType("Plugin", common.OnDisk))
+	if err != nil {
+		return err
+	}
+	plugindata, err := conn.Read("Plugin", pluginID)
+	if err != nil {
+		return errors.PackError(err.ErrNo(), "error while trying to fetch plugin data: ", err.Error())
+	}
+	var plugin Plugin
+	if err := json.Unmarshal([]byte(plugindata), &plugin); err != nil {
+		return errors.PackError(errors.JSONUnmarshalFailed, err)
+	}
+	plugin.Version = version
+	if _, err := conn.Update("Plugin", pluginID, plugin); err != nil {
+		return err
+	}
+	return nil
+}
+
 // UpdateAggregtionSource updates the aggregation details
 func UpdateAggregtionSource(aggregationSource AggregationSource, key string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("AggregationSource", common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -812,7 +934,7 @@ func UpdateAggregtionSource(aggregationSource AggregationSource, key string) *er
 
 // GetAllMatchingDetails accepts the table name ,pattern and DB type and return all the keys which mathces the pattern
 func GetAllMatchingDetails(table, pattern string, dbtype common.DbType) ([]string, *errors.Error) {
-	conn, err := common.GetDBConnection(dbtype)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, dbtype))
 	if err != nil {
 		return []string{}, err
 	}
@@ -821,7 +943,7 @@ func GetAllMatchingDetails(table, pattern string, dbtype common.DbType) ([]strin
 
 // DeleteAggregationSource will delete the AggregationSource entry from the database based on the aggregtionSourceURI
 func DeleteAggregationSource(aggregtionSourceURI string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("AggregationSource", common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -834,7 +956,7 @@ func DeleteAggregationSource(aggregtionSourceURI string) *errors.Error {
 // GetComputerSystem fetches computer system details by UUID from database
 func GetComputerSystem(systemid string) (string, *errors.Error) {
 	var system string
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ComputerSystem", common.InMemory))
 	if err != nil {
 		// connection error
 		return system, err
@@ -852,11 +974,11 @@ func GetComputerSystem(systemid string) (string, *errors.Error) {
 // CreateAggregate will create aggregate on disk
 func CreateAggregate(aggregate Aggregate, aggregateURI string) *errors.Error {
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "Aggregate"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
-	const table string = "Aggregate"
 	if err := conn.Create(table, aggregateURI, aggregate); err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to create aggregate: ", err.Error())
 	}
@@ -868,11 +990,11 @@ func CreateAggregate(aggregate Aggregate, aggregateURI string) *errors.Error {
 func GetAggregate(aggregateURI string) (Aggregate, *errors.Error) {
 	var aggregate Aggregate
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "Aggregate"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return aggregate, err
 	}
-	const table string = "Aggregate"
 	data, err := conn.Read(table, aggregateURI)
 	if err != nil {
 		return aggregate, errors.PackError(err.ErrNo(), "error: while trying to fetch connection method data: ", err.Error())
@@ -886,11 +1008,11 @@ func GetAggregate(aggregateURI string) (Aggregate, *errors.Error) {
 
 // DeleteAggregate will delete the aggregate
 func DeleteAggregate(key string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "Aggregate"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
-	const table string = "Aggregate"
 	if err = conn.Delete(table, key); err != nil {
 		return err
 	}
@@ -899,7 +1021,7 @@ func DeleteAggregate(key string) *errors.Error {
 
 // GetAllKeysFromTable retrun all matching data give table name
 func GetAllKeysFromTable(table string) ([]string, error) {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -912,7 +1034,8 @@ func GetAllKeysFromTable(table string) ([]string, error) {
 
 // AddElementsToAggregate add elements to the aggregate
 func AddElementsToAggregate(aggregate Aggregate, aggregateURL string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "Aggregate"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -921,7 +1044,6 @@ func AddElementsToAggregate(aggregate Aggregate, aggregateURL string) *errors.Er
 		return err
 	}
 	aggregate.Elements = append(aggregate.Elements, agg.Elements...)
-	const table string = "Aggregate"
 	if _, err := conn.Update(table, aggregateURL, aggregate); err != nil {
 		return err
 	}
@@ -930,7 +1052,8 @@ func AddElementsToAggregate(aggregate Aggregate, aggregateURL string) *errors.Er
 
 // RemoveElementsFromAggregate remove elements from an aggregate
 func RemoveElementsFromAggregate(aggregate Aggregate, aggregateURL string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "Aggregate"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -940,7 +1063,6 @@ func RemoveElementsFromAggregate(aggregate Aggregate, aggregateURL string) *erro
 	}
 	aggregate.Elements = removeElements(aggregate.Elements, agg.Elements)
 
-	const table string = "Aggregate"
 	if _, err := conn.Update(table, aggregateURL, aggregate); err != nil {
 		return err
 	}
@@ -970,11 +1092,11 @@ func removeElements(requestElements, presentElements []OdataID) []OdataID {
 
 // AddConnectionMethod will add connection methods on disk
 func AddConnectionMethod(connectionMethod ConnectionMethod, connectionMethodURI string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "ConnectionMethod"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return err
 	}
-	const table string = "ConnectionMethod"
 	if err := conn.Create(table, connectionMethodURI, connectionMethod); err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to create aggregate: ", err.Error())
 	}
@@ -986,11 +1108,11 @@ func AddConnectionMethod(connectionMethod ConnectionMethod, connectionMethodURI
 func GetConnectionMethod(connectionMethodURI string) (ConnectionMethod, *errors.Error) {
 	var connectionMethod ConnectionMethod
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	const table string = "ConnectionMethod"
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return connectionMethod, err
 	}
-	const table string = "ConnectionMethod"
 	data, err := conn.Read(table, connectionMethodURI)
 	if err != nil {
 		return connectionMethod, errors.PackError(err.ErrNo(), "error: while trying to fetch connection method data: ", err.Error())
@@ -1004,7 +1126,7 @@ func GetConnectionMethod(connectionMethodURI string) (ConnectionMethod, *errors.
 
 // Delete will delete the data from the provided db with the provided table and key data
 func Delete(table, key string, dbtype common.DbType) *errors.Error {
-	conn, err := common.GetDBConnection(dbtype)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, dbtype))
 	if err != nil {
 		return err
 	}
@@ -1016,7 +1138,7 @@ func Delete(table, key string, dbtype common.DbType) *errors.Error {
 
 // UpdateConnectionMethod updates the Connection Method details
 func UpdateConnectionMethod(connectionMethod ConnectionMethod, key string) *errors.Error {
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ConnectionMethod", common.OnDisk))
 	if err != nil {
 		return err
 	}
@@ -1026,11 +1148,15 @@ func UpdateConnectionMethod(connectionMethod ConnectionMethod, key string) *erro
 	return nil
 }
 
+// CancelledActiveRequestSuffix is the suffix of the key of the active request which marks the request
+// as cancelled, the ongoing add polls the marker to stop the discovery
+const CancelledActiveRequestSuffix = ":Cancelled"
+
 // CheckActiveRequest will check the DB to see whether there are any active requests for the given key
 // It will return true if there is an active request or false if not
 // It will also through an error if any DB connection issues arise
 func CheckActiveRequest(key string) (bool, *errors.Error) {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ActiveAddBMCRequest", common.InMemory))
 	if err != nil {
 		return false, errors.PackError(err.ErrNo(), "error: while trying to create connection with DB: ", err.Error())
 	}
@@ -1046,7 +1172,7 @@ func CheckActiveRequest(key string) (bool, *errors.Error) {
 
 // DeleteActiveRequest deletes the active request key from the DB, return error if any
 func DeleteActiveRequest(key string) *errors.Error {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ActiveAddBMCRequest", common.InMemory))
 	if err != nil {
 		return errors.PackError(err.ErrNo(), "error: while trying to create connection with DB: ", err.Error())
 	}
@@ -1057,10 +1183,39 @@ func DeleteActiveRequest(key string) *errors.Error {
 	return nil
 }
 
+// GetActiveRequests returns the keys of all the active add requests
+func GetActiveRequests() ([]string, *errors.Error) {
+	conn, err := common.GetDBConnection(common.GetTableDBType("ActiveAddBMCRequest", common.InMemory))
+	if err != nil {
+		return nil, errors.PackError(err.ErrNo(), "error: while trying to create connection with DB: ", err.Error())
+	}
+	keys, err := conn.GetAllDetails("ActiveAddBMCRequest")
+	if err != nil {
+		return nil, errors.PackError(err.ErrNo(), "error: while trying to fetch active requests: ", err.Error())
+	}
+	activeKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		// the cancellation markers are not the requests by themselves
+		if !strings.HasSuffix(key, CancelledActiveRequestSuffix) {
+			activeKeys = append(activeKeys, key)
+		}
+	}
+	return activeKeys, nil
+}
+
+// PingDB checks the reachability of the given DB
+func PingDB(dbType common.DbType) error {
+	conn, err := common.GetDBConnection(dbType)
+	if err != nil {
+		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
+	}
+	return conn.Ping()
+}
+
 // SavePluginManagerInfo will save plugin manager  data into the database
 func SavePluginManagerInfo(body []byte, table string, key string) error {
 
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return fmt.Errorf("Unable to save the plugin data with SavePluginManagerInfo: %v", err.Error())
 	}
@@ -1130,7 +1285,7 @@ func UpdateDeviceSubscription(devSubscription common.DeviceSubscription) error {
 // It will return true if there is an active request or false if not
 // It will also through an error if any DB connection issues arise
 func CheckMetricRequest(key string) (bool, *errors.Error) {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ActiveMetricRequest", common.InMemory))
 	if err != nil {
 		return false, errors.PackError(err.ErrNo(), "error: while trying to create connection with DB: ", err.Error())
 	}
@@ -1146,7 +1301,7 @@ func CheckMetricRequest(key string) (bool, *errors.Error) {
 
 // DeleteMetricRequest deletes the active request key from the DB, return error if any
 func DeleteMetricRequest(key string) *errors.Error {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType("ActiveMetricRequest", common.InMemory))
 	if err != nil {
 		return errors.PackError(err.ErrNo(), "error: while trying to create connection with DB: ", err.Error())
 	}
@@ -1157,6 +1312,34 @@ func DeleteMetricRequest(key string) *errors.Error {
 	return nil
 }
 
+// GetActiveMetricRequests returns the keys of the active metric requests along with the time
+// they were created, the time is zero for the requests saved without it
+func GetActiveMetricRequests() (map[string]time.Time, *errors.Error) {
+	conn, err := common.GetDBConnection(common.GetTableDBType("ActiveMetricRequest", common.InMemory))
+	if err != nil {
+		return nil, errors.PackError(err.ErrNo(), "error: while trying to create connection with DB: ", err.Error())
+	}
+	keys, err := conn.GetAllDetails("ActiveMetricRequest")
+	if err != nil {
+		return nil, errors.PackError(err.ErrNo(), "error: while trying to fetch active metric requests: ", err.Error())
+	}
+	requests := make(map[string]time.Time, len(keys))
+	for _, key := range keys {
+		data, err := conn.Read("ActiveMetricRequest", key)
+		if err != nil {
+			// request could be completed while reading the others
+			if errors.DBKeyNotFound == err.ErrNo() {
+				continue
+			}
+			return nil, errors.PackError(err.ErrNo(), "error: while trying to fetch active metric request details: ", err.Error())
+		}
+		var createdTime string
+		json.Unmarshal([]byte(data), &createdTime)
+		requests[key], _ = time.Parse(time.RFC3339, createdTime)
+	}
+	return requests, nil
+}
+
 // AddAggregateHostIndex add aggregate hosts
 func AddAggregateHostIndex(uuid string, hostIP []string) error {
 	conn, err := common.GetDBConnection(common.OnDisk)
@@ -1247,14 +1430,63 @@ func DeleteAggregateHostIndex(uuid string) error {
 	return nil
 }
 
-// SaveBMCInventory function save all bmc inventory data togeter using the transaction model
+// SaveBMCInventory function save all bmc inventory data togeter using the transaction model.
+// The keys of the data are prefixed with their table, the data of the tables mapped to
+// another DB in DBConf.TableDBType is saved in a transaction of that DB.
 func SaveBMCInventory(data map[string]interface{}) error {
-	connPool, err := common.GetDBConnection(common.InMemory)
-	if err != nil {
-		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
+	dataByDBType := make(map[common.DbType]map[string]interface{})
+	for key, value := range data {
+		table := strings.SplitN(key, ":", 2)[0]
+		dbType := common.GetTableDBType(table, common.InMemory)
+		if dataByDBType[dbType] == nil {
+			dataByDBType[dbType] = make(map[string]interface{})
+		}
+		dataByDBType[dbType][key] = value
 	}
-	if err = connPool.SaveBMCInventory(data); err != nil {
-		return fmt.Errorf("error while trying to save BMC inventory: %v", err.Error())
+	for dbType, dbData := range dataByDBType {
+		connPool, err := common.GetDBConnection(dbType)
+		if err != nil {
+			return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
+		}
+		if err = connPool.SaveBMCInventory(dbData); err != nil {
+			return fmt.Errorf("error while trying to save BMC inventory: %v", err.Error())
+		}
 	}
 	return nil
 }
+
+// GetResourcesByType returns the keys of the resources indexed with the given @odata.type.
+// odataType can either be the complete type like #Processor.v1_0_0.Processor or
+// only the namespace like Processor to match all the versions of the type
+func GetResourcesByType(odataType string) ([]string, *errors.Error) {
+	dbType := common.GetTableDBType(ResourceTypeTable, common.InMemory)
+	conn, err := common.GetDBConnection(dbType)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := conn.GetAllMatchingDetails(ResourceTypeTable, "")
+	if err != nil {
+		return nil, err
+	}
+	// the types are read in batches, as the table has the type of every resource of every server
+	types, err := conn.ReadMultipleKeys(ResourceTypeTable, keys)
+	if err != nil {
+		return nil, err
+	}
+	var resources []string
+	for _, key := range keys {
+		data, ok := types[key]
+		if !ok {
+			continue
+		}
+		var resourceType string
+		if jsonErr := json.Unmarshal([]byte(data), &resourceType); jsonErr != nil {
+			continue
+		}
+		namespace := strings.Split(strings.TrimPrefix(resourceType, "#"), ".")[0]
+		if resourceType == odataType || namespace == odataType {
+			resources = append(resources, key)
+		}
+	}
+	return resources, nil
+}
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	dmtfmodel "github.com/ODIM-Project/ODIM/lib-dmtf/model"
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
@@ -685,6 +686,60 @@ func TestSavePluginData(t *testing.T) {
 	}
 }
 
+func TestGetPluginCapabilities(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		common.TruncateDB(common.OnDisk)
+		common.TruncateDB(common.InMemory)
+	}()
+
+	capabilities := []string{"Telemetry", "FirmwareUpdate"}
+	if err := SavePluginData(Plugin{ID: "GRF", PluginType: "RF-GENERIC", Capabilities: capabilities}); err != nil {
+		t.Fatalf("SavePluginData() error = %v", err)
+	}
+	if err := SavePluginData(Plugin{ID: "ILO", PluginType: "ILO"}); err != nil {
+		t.Fatalf("SavePluginData() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		pluginID string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "plugin with capabilities",
+			pluginID: "GRF",
+			want:     capabilities,
+			wantErr:  false,
+		},
+		{
+			name:     "plugin without capabilities",
+			pluginID: "ILO",
+			want:     nil,
+			wantErr:  false,
+		},
+		{
+			name:     "non-existent plugin",
+			pluginID: "notFound",
+			want:     nil,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPluginCapabilities(tt.pluginID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPluginCapabilities() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPluginCapabilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetAllSystems(t *testing.T) {
 	config.SetUpMockConfig(t)
 	mockData(t, common.OnDisk, "System", "someID", Target{DeviceUUID: "someID"})
@@ -1436,6 +1491,28 @@ func TestCheckMetricRequest(t *testing.T) {
 	assert.NotNil(t, err, "There should be error")
 }
 
+func TestGetActiveMetricRequests(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		err := common.TruncateDB(common.InMemory)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}()
+	createdTime := time.Now().UTC().Truncate(time.Second)
+	if err := GenericSave([]byte(createdTime.Format(time.RFC3339)), "ActiveMetricRequest", "/redfish/v1/TelemetryService/MetricDefinitions/1"); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := GenericSave(nil, "ActiveMetricRequest", "/redfish/v1/TelemetryService/MetricDefinitions/2"); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	requests, err := GetActiveMetricRequests()
+	assert.Nil(t, err, "There should be no error")
+	assert.Len(t, requests, 2, "should be same")
+	assert.True(t, createdTime.Equal(requests["/redfish/v1/TelemetryService/MetricDefinitions/1"]), "created time should be same")
+	assert.True(t, requests["/redfish/v1/TelemetryService/MetricDefinitions/2"].IsZero(), "created time should be zero when not saved")
+}
+
 func TestAggregateHostIndex(t *testing.T) {
 	config.SetUpMockConfig(t)
 	defer func() {
@@ -1529,3 +1606,97 @@ func TestGetDeviceSubscriptions(t *testing.T) {
 	_, err := GetDeviceSubscriptions(hostIP)
 	assert.NotNil(t, err, "There should be error")
 }
+
+func TestGenericSaveWithRemappedTable(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DBConf.TableDBType = map[string]string{
+		"MetricReports": config.OnDiskDBType,
+	}
+	defer func() {
+		config.Data.DBConf.TableDBType = nil
+		common.TruncateDB(common.OnDisk)
+		common.TruncateDB(common.InMemory)
+	}()
+	if err := GenericSave([]byte("someReport"), "MetricReports", "someKey"); err != nil {
+		t.Fatalf("GenericSave() error = %v", err)
+	}
+	// data should be written to the configured store and not to the default one
+	onDiskConn, _ := common.GetDBConnection(common.OnDisk)
+	if _, err := onDiskConn.Read("MetricReports", "someKey"); err != nil {
+		t.Errorf("expected remapped table data in OnDisk DB, got error: %v", err)
+	}
+	inMemoryConn, _ := common.GetDBConnection(common.InMemory)
+	if _, err := inMemoryConn.Read("MetricReports", "someKey"); err == nil {
+		t.Errorf("remapped table data should not be written to InMemory DB")
+	}
+
+	data, err := GetResource("MetricReports", "someKey")
+	if err != nil || data != "someReport" {
+		t.Errorf("GetResource() = %v, error = %v, want someReport", data, err)
+	}
+	if err := Delete("MetricReports", "someKey", common.InMemory); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if _, err := onDiskConn.Read("MetricReports", "someKey"); err == nil {
+		t.Errorf("remapped table data should be deleted from OnDisk DB")
+	}
+}
+
+func TestSaveBMCInventoryWithRemappedTable(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DBConf.TableDBType = map[string]string{
+		"MetricReports": config.OnDiskDBType,
+	}
+	defer func() {
+		config.Data.DBConf.TableDBType = nil
+		common.TruncateDB(common.OnDisk)
+		common.TruncateDB(common.InMemory)
+	}()
+	data := map[string]interface{}{
+		"ComputerSystem:/redfish/v1/Systems/someuuid.1": "someSystem",
+		"MetricReports:/redfish/v1/Systems/someuuid.1":  "someReport",
+	}
+	if err := SaveBMCInventory(data); err != nil {
+		t.Fatalf("SaveBMCInventory() error = %v", err)
+	}
+	onDiskConn, _ := common.GetDBConnection(common.OnDisk)
+	if _, err := onDiskConn.Read("MetricReports", "/redfish/v1/Systems/someuuid.1"); err != nil {
+		t.Errorf("expected remapped table data in OnDisk DB, got error: %v", err)
+	}
+	inMemoryConn, _ := common.GetDBConnection(common.InMemory)
+	if _, err := inMemoryConn.Read("MetricReports", "/redfish/v1/Systems/someuuid.1"); err == nil {
+		t.Errorf("remapped table data should not be written to InMemory DB")
+	}
+	if _, err := inMemoryConn.Read("ComputerSystem", "/redfish/v1/Systems/someuuid.1"); err != nil {
+		t.Errorf("expected the table data in InMemory DB, got error: %v", err)
+	}
+
+	if err := DeleteComputeSystem(0, "/redfish/v1/Systems/someuuid.1"); err != nil {
+		t.Errorf("DeleteComputeSystem() error = %v", err)
+	}
+	if _, err := onDiskConn.Read("MetricReports", "/redfish/v1/Systems/someuuid.1"); err == nil {
+		t.Errorf("remapped table data should be deleted from OnDisk DB")
+	}
+}
+
+func TestGetResourcesByType(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		common.TruncateDB(common.InMemory)
+	}()
+	inventory := map[string]interface{}{
+		ResourceTypeTable + ":/redfish/v1/Systems/uuid.1":              "#ComputerSystem.v1_10_0.ComputerSystem",
+		ResourceTypeTable + ":/redfish/v1/Systems/uuid.1/Processors/1": "#Processor.v1_0_0.Processor",
+		ResourceTypeTable + ":/redfish/v1/Systems/uuid.1/Processors/2": "#Processor.v1_3_0.Processor",
+	}
+	if err := SaveBMCInventory(inventory); err != nil {
+		t.Fatalf("SaveBMCInventory() error = %v", err)
+	}
+	resources, err := GetResourcesByType("Processor")
+	assert.Nil(t, err, "There should be no error")
+	assert.ElementsMatch(t, []string{"/redfish/v1/Systems/uuid.1/Processors/1", "/redfish/v1/Systems/uuid.1/Processors/2"}, resources)
+
+	resources, err = GetResourcesByType("#Processor.v1_0_0.Processor")
+	assert.Nil(t, err, "There should be no error")
+	assert.Equal(t, []string{"/redfish/v1/Systems/uuid.1/Processors/1"}, resources)
+}
@@ -14,8 +14,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/sirupsen/logrus"
 
@@ -99,7 +102,7 @@ func main() {
 	// Rediscover the Resources by looking in OnDisk DB, populate the resources in InMemory DB
 	//This happens only if the InMemory DB lost it contents due to DB reboot or host VM reboot.
 	p := system.ExternalInterface{
-		ContactClient:   pmbhandle.ContactPlugin,
+		ContactClient:   pmbhandle.ContactPluginPooled,
 		Auth:            services.IsAuthorized,
 		PublishEventMB:  agmessagebus.Publish,
 		GetPluginStatus: agcommon.GetPluginStatus,
@@ -110,6 +113,10 @@ func main() {
 
 	go p.RediscoverResources()
 
+	system.RediscoverPluginServersFunc = p.RediscoverPluginServers
+
+	go interruptTasksOnShutdown(p)
+
 	agcommon.ConfigFilePath = os.Getenv("CONFIG_FILE_PATH")
 	if agcommon.ConfigFilePath == "" {
 		log.Fatal("error: no value get the environment variable CONFIG_FILE_PATH")
@@ -118,7 +125,19 @@ func main() {
 
 	go system.PerformPluginHealthCheck()
 
+	go system.PruneActiveMetricRequests()
+
 	if err := services.ODIMService.Run(); err != nil {
 		log.Fatal("failed to run a service: " + err.Error())
 	}
 }
+
+// interruptTasksOnShutdown waits for the service to be stopped and marks the tasks
+// which are still in flight as interrupted
+func interruptTasksOnShutdown(p system.ExternalInterface) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	sig := <-sigs
+	logs.Log.Warn(fmt.Sprintf("received %v, marking the tasks in flight as interrupted", sig))
+	p.InterruptInFlightTasks(context.Background())
+}
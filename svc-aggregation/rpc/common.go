@@ -37,16 +37,17 @@ type Aggregator struct {
 func GetAggregator() *Aggregator {
 	return &Aggregator{
 		connector: &system.ExternalInterface{
-			ContactClient:            pmbhandle.ContactPlugin,
+			ContactClient:            pmbhandle.ContactPluginPooled,
 			Auth:                     services.IsAuthorized,
 			GetSessionUserName:       services.GetSessionUserName,
-			CreateTask:               services.CreateTask,
-			CreateChildTask:          services.CreateChildTask,
+			CreateTask:               system.WithCreateTaskRetry(services.CreateTask),
+			CreateChildTask:          system.WithCreateChildTaskRetry(services.CreateChildTask),
 			UpdateTask:               system.UpdateTaskData,
 			CreateSubcription:        system.CreateDefaultEventSubscription,
 			PublishEvent:             system.PublishEvent,
 			GetPluginStatus:          agcommon.GetPluginStatus,
 			SubscribeToEMB:           services.SubscribeToEMB,
+			VerifyEMBConsumption:     services.VerifyEMBConsumption,
 			EncryptPassword:          common.EncryptWithPublicKey,
 			DecryptPassword:          common.DecryptWithPrivateKey,
 			DeleteComputeSystem:      agmodel.DeleteComputeSystem,
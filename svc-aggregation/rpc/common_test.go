@@ -159,7 +159,8 @@ func mockContactClientForDelete(url, method, token string, odataID string, body
 	return nil, fmt.Errorf("InvalidRequest")
 }
 
-func EventFunctionsForTesting(ctx context.Context, s []string) {}
+func EventFunctionsForTesting(ctx context.Context, s []string, capabilities *agmodel.EventServiceCapabilities) {
+}
 
 func PostEventFunctionForTesting(ctx context.Context, s []string, name string) {}
 
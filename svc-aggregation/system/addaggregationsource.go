@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"net/http"
 
+	dmtf "github.com/ODIM-Project/ODIM/lib-dmtf/model"
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
 	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
 	aggregatorproto "github.com/ODIM-Project/ODIM/lib-utilities/proto/aggregator"
 	"github.com/ODIM-Project/ODIM/lib-utilities/response"
@@ -70,7 +72,14 @@ func (e *ExternalInterface) AddAggregationSource(ctx context.Context, taskID str
 		l.LogWithFields(ctx).Error(errMsg)
 		return common.GeneralError(http.StatusBadRequest, response.PropertyMissing, errMsg, []interface{}{"ConnectionMethod"}, taskInfo)
 	}
-	return e.addAggregationSource(ctx, taskID, targetURI, string(req.RequestBody), percentComplete, aggregationSourceRequest, taskInfo)
+	resp = e.addAggregationSource(ctx, taskID, targetURI, string(req.RequestBody), percentComplete, aggregationSourceRequest, taskInfo)
+	// the inventory is not changed by a dry run
+	if aggregationSourceRequest.Oem != nil && aggregationSourceRequest.Oem.DryRun {
+		return resp
+	}
+	auditInventoryChange(context.WithValue(ctx, common.SessionUserID, sessionUserName), inventoryAuditAdd, resp.Header["Location"],
+		aggregationSourceRequest.HostName, aggregationSourceRequest.Links.ConnectionMethod.OdataID, resp)
+	return resp
 }
 
 func (e *ExternalInterface) addAggregationSource(ctx context.Context, taskID, targetURI, reqBody string, percentComplete int32, aggregationSourceRequest AggregationSource, taskInfo *common.TaskUpdateInfo) response.RPC {
@@ -81,6 +90,17 @@ func (e *ExternalInterface) addAggregationSource(ctx context.Context, taskID, ta
 		Password:         aggregationSourceRequest.Password,
 		ConnectionMethod: aggregationSourceRequest.Links.ConnectionMethod,
 	}
+	if aggregationSourceRequest.Oem != nil {
+		addResourceRequest.ForceBasicAuth = aggregationSourceRequest.Oem.ForceBasicAuth
+		addResourceRequest.DryRun = aggregationSourceRequest.Oem.DryRun
+		addResourceRequest.ForceRegistryRefresh = aggregationSourceRequest.Oem.ForceRegistryRefresh
+		addResourceRequest.PreviewResources = aggregationSourceRequest.Oem.PreviewResources
+	}
+	if validationResp, err := ValidateAddResourceRequest(addResourceRequest); err != nil {
+		l.LogWithFields(ctx).Error(err.Error())
+		e.UpdateTask(ctx, fillTaskData(taskID, targetURI, reqBody, validationResp, common.Exception, common.Critical, 100, http.MethodPost))
+		return validationResp
+	}
 
 	ipAddr := getKeyFromManagerAddress(addResourceRequest.ManagerAddress)
 	indexList, err := agmodel.GetString("BMCAddress", ipAddr)
@@ -114,19 +134,28 @@ func (e *ExternalInterface) addAggregationSource(ctx context.Context, taskID, ta
 		e.UpdateTask(ctx, fillTaskData(taskID, targetURI, reqBody, resp, common.Exception, common.Warning, percentComplete, http.MethodPost))
 		return resp
 	}
-	err = e.GenericSave(nil, "ActiveAddBMCRequest", ipAddr)
-	if err != nil {
-		errMsg := fmt.Sprintf("Unable to save the active request details from DB: %v", err.Error())
-		l.LogWithFields(ctx).Errorln(errMsg)
-		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo)
-	}
-
-	defer func() {
-		err := e.DeleteActiveRequest(ipAddr)
+	// the dry run doesn't save anything in DB, so it isn't tracked as an active request
+	if !addResourceRequest.DryRun {
+		err = e.GenericSave(nil, "ActiveAddBMCRequest", ipAddr)
 		if err != nil {
-			l.LogWithFields(ctx).Infof("Unable to collect the active request details from DB: %v", err.Error())
+			errMsg := fmt.Sprintf("Unable to save the active request details from DB: %v", err.Error())
+			l.LogWithFields(ctx).Errorln(errMsg)
+			return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo)
 		}
-	}()
+
+		defer func() {
+			err := e.DeleteActiveRequest(ipAddr)
+			if err != nil {
+				l.LogWithFields(ctx).Infof("Unable to collect the active request details from DB: %v", err.Error())
+			}
+			// the cancellation marker is left behind when the add is cancelled
+			if cancelled, _ := e.CheckActiveRequest(getCancelRequestKey(ipAddr)); cancelled {
+				if err := e.DeleteActiveRequest(getCancelRequestKey(ipAddr)); err != nil {
+					l.LogWithFields(ctx).Infof("Unable to delete the cancellation of the active request from DB: %v", err.Error())
+				}
+			}
+		}()
+	}
 
 	connectionMethod, err1 := e.GetConnectionMethod(addResourceRequest.ConnectionMethod.OdataID)
 	if err1 != nil {
@@ -134,21 +163,47 @@ func (e *ExternalInterface) addAggregationSource(ctx context.Context, taskID, ta
 		l.LogWithFields(ctx).Error(errMsg)
 		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"connectionmethod id", addResourceRequest.ConnectionMethod.OdataID}, taskInfo)
 	}
-	cmVariants := getConnectionMethodVariants(connectionMethod.ConnectionMethodVariant)
+	cmVariants, err := getConnectionMethodVariants(connectionMethod.ConnectionMethodVariant)
+	if err != nil {
+		errMsg := "Unable to get the connection method variant: " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo)
+	}
+	// adds exceeding the limit of the plugin type are queued till the ongoing adds complete
+	release, err := addLimiter.acquire(ctx, cmVariants.PluginType)
+	if err != nil {
+		errMsg := "Unable to start adding the aggregation source while waiting for the ongoing " + cmVariants.PluginType + " adds: " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusServiceUnavailable, response.InternalError, errMsg, nil, taskInfo)
+	}
+	defer release()
 	var pluginContactRequest getResourceRequest
 	pluginContactRequest.ContactClient = e.ContactClient
 	pluginContactRequest.GetPluginStatus = e.GetPluginStatus
 	pluginContactRequest.TargetURI = targetURI
 	pluginContactRequest.UpdateTask = e.UpdateTask
 	pluginContactRequest.TaskRequest = reqBody
+	// the XAuthToken sessions are reused for all the plugin contacts of the add
+	pluginContactRequest.Sessions = newPluginToken()
+	if !config.Data.DiscoveryConf.SkipPluginSessionTeardown {
+		defer deletePluginSessions(ctx, pluginContactRequest)
+	}
 	var aggregationSourceUUID string
 	var cipherText []byte
+	var discoveryProblems []discoveryProblem
+	// the EMB consumption is verified only for the plugins, when it is enabled
+	var embConsumptionVerified bool
+	var embConsumptionErr error
 
 	// check status will do call on the URI /ODIM/v1/Status to the requested manager address
 	// if its success then add the plugin, else if its not found then add BMC
 	// else return the response
-	statusResp, statusCode, queueList := checkStatus(ctx, pluginContactRequest, addResourceRequest, cmVariants, taskInfo)
-	if statusCode == http.StatusOK {
+	statusResult := checkStatus(ctx, pluginContactRequest, addResourceRequest, cmVariants, taskInfo)
+	if addResourceRequest.DryRun {
+		return e.dryRunAggregationSource(ctx, taskID, targetURI, addResourceRequest, pluginContactRequest, connectionMethod, cmVariants, statusResult, taskInfo)
+	}
+	if statusResult.StatusCode == http.StatusOK {
+		l.LogWithFields(ctx).Info("Version of the plugin " + cmVariants.PluginID + " is " + statusResult.PluginVersion)
 
 		// check if AggregationSource has any values, if its there means its managing the bmcs
 		if len(connectionMethod.Links.AggregationSources) > 0 {
@@ -156,11 +211,22 @@ func (e *ExternalInterface) addAggregationSource(ctx context.Context, taskID, ta
 			l.LogWithFields(ctx).Error(errMsg)
 			return common.GeneralError(http.StatusConflict, response.ResourceInUse, errMsg, nil, taskInfo)
 		}
-		resp, aggregationSourceUUID, cipherText = e.addPluginData(ctx, addResourceRequest, taskID, targetURI, pluginContactRequest, queueList, cmVariants)
-	} else if statusCode == http.StatusNotFound {
-		resp, aggregationSourceUUID, cipherText = e.addCompute(ctx, taskID, targetURI, cmVariants.PluginID, percentComplete, addResourceRequest, pluginContactRequest)
+		resp, aggregationSourceUUID, cipherText = e.addPluginData(ctx, addResourceRequest, taskID, targetURI, pluginContactRequest, statusResult.QueueList, statusResult.Capabilities, cmVariants)
+		if resp.StatusMessage == "" && config.Data.DiscoveryConf.VerifyPluginEMBConsumption {
+			embConsumptionVerified = true
+			embConsumptionErr = e.verifyPluginEMBConsumption(ctx, cmVariants.PluginID, statusResult.QueueList)
+		}
+	} else if statusResult.StatusCode == http.StatusNotFound {
+		if config.Data.DiscoveryConf.BalancePluginReplicas {
+			replica := e.selectPluginReplica(ctx, addResourceRequest.ConnectionMethod.OdataID, connectionMethod, cmVariants)
+			// the aggregation source is linked with the connection method of the selected plugin
+			addResourceRequest.ConnectionMethod.OdataID = replica.ConnectionMethodURI
+			connectionMethod = replica.ConnectionMethod
+			cmVariants = replica.Variants
+		}
+		resp, aggregationSourceUUID, cipherText, discoveryProblems = e.addCompute(ctx, taskID, targetURI, cmVariants.PluginID, percentComplete, addResourceRequest, pluginContactRequest)
 	} else {
-		return statusResp
+		return statusResult.Response
 	}
 	if resp.StatusMessage != "" {
 		return resp
@@ -202,15 +268,52 @@ func (e *ExternalInterface) addAggregationSource(ctx context.Context, taskID, ta
 	commonResponse.Message = ""
 	commonResponse.MessageID = ""
 	commonResponse.Severity = ""
-	resp.Body = agresponse.AggregationSourceResponse{
+	aggregationSourceResponse := agresponse.AggregationSourceResponse{
 		Response: commonResponse,
 		HostName: aggregationSourceRequest.HostName,
 		UserName: aggregationSourceRequest.UserName,
 		Links:    aggregationSourceRequest.Links,
 	}
+	var oem dmtf.Oem = map[string]interface{}{}
+	// capabilities are reported only by the plugins, not by the BMCs
+	if len(statusResult.Capabilities) > 0 {
+		oem["PluginCapabilities"] = statusResult.Capabilities
+	}
+	// the resources which couldn't be discovered while adding the BMC are reported
+	if len(discoveryProblems) > 0 {
+		oem["DiscoveryProblems"] = discoveryProblems
+	}
+	// the links which could not be fetched are reported only for the servers discovered
+	if config.Data.DiscoveryConf.ReportLinkIntegrity && statusResult.StatusCode == http.StatusNotFound {
+		linkIntegrity := getLinkIntegrityReport(discoveryProblems)
+		if len(linkIntegrity.BrokenLinks) > 0 {
+			l.LogWithFields(ctx).Warnf("%d broken links are advertised by the server %s: %v",
+				len(linkIntegrity.BrokenLinks), aggregationSourceRequest.HostName, linkIntegrity.BrokenLinks)
+		}
+		oem["LinkIntegrity"] = linkIntegrity
+	}
+	// the task completes with warning when the events of the plugin won't be delivered
+	taskStatus := common.OK
+	if len(statusResult.UnavailableQueues) > 0 {
+		l.LogWithFields(ctx).Errorf("EMB queues %v of the plugin %s are unavailable, its events won't be delivered",
+			statusResult.UnavailableQueues, cmVariants.PluginID)
+		oem["UnavailableEMBQueues"] = statusResult.UnavailableQueues
+		taskStatus = common.Warning
+	}
+	if embConsumptionVerified {
+		oem["EventDeliveryReady"] = embConsumptionErr == nil
+		if embConsumptionErr != nil {
+			oem["EventDeliveryProblem"] = embConsumptionErr.Error()
+			taskStatus = common.Warning
+		}
+	}
+	if len(oem) > 0 {
+		aggregationSourceResponse.Oem = &oem
+	}
+	resp.Body = aggregationSourceResponse
 	resp.StatusCode = http.StatusCreated
 	percentComplete = 100
-	task := fillTaskData(taskID, targetURI, reqBody, resp, common.Completed, common.OK, percentComplete, http.MethodPost)
+	task := fillTaskData(taskID, targetURI, reqBody, resp, common.Completed, taskStatus, percentComplete, http.MethodPost)
 	e.UpdateTask(ctx, task)
 	return resp
 }
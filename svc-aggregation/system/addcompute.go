@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
 	"github.com/ODIM-Project/ODIM/lib-utilities/config"
@@ -34,7 +35,7 @@ import (
 // AddCompute is the handler for adding system
 // Discovers Computersystem, Manager & Chassis and its top level odata.ID links and store them in inmemory db.
 // Upon successfull operation this api returns Systems root UUID in the response body with 200 OK.
-func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, pluginID string, percentComplete int32, addResourceRequest AddResourceRequest, pluginContactRequest getResourceRequest) (response.RPC, string, []byte) {
+func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, pluginID string, percentComplete int32, addResourceRequest AddResourceRequest, pluginContactRequest getResourceRequest) (response.RPC, string, []byte, []discoveryProblem) {
 	var resp response.RPC
 	l.LogWithFields(ctx).Info("started adding system with manager address " + addResourceRequest.ManagerAddress +
 		" using plugin id: " + pluginID)
@@ -47,7 +48,7 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	if errs != nil {
 		errMsg := "error while getting plugin data: " + errs.Error()
 		l.LogWithFields(ctx).Error(errMsg)
-		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"plugin", pluginID}, taskInfo), "", nil
+		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"plugin", pluginID}, taskInfo), "", nil, nil
 	}
 
 	var saveSystem agmodel.SaveSystem
@@ -59,19 +60,13 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 
 	pluginContactRequest.Plugin = plugin
 	pluginContactRequest.StatusPoll = true
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
-		var err error
-		pluginContactRequest.HTTPMethodType = http.MethodPost
-		pluginContactRequest.DeviceInfo = map[string]interface{}{
-			"UserName": plugin.Username,
-			"Password": string(plugin.Password),
-		}
-		pluginContactRequest.OID = "/ODIM/v1/Sessions"
-		_, token, getResponse, err := contactPlugin(ctx, pluginContactRequest, "error while getting the details "+pluginContactRequest.OID+": ")
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
+		// the session is reused for the whole discovery of the system
+		token, getResponse, err := getPluginSessionToken(ctx, pluginContactRequest)
 		if err != nil {
 			errMsg := err.Error()
 			l.LogWithFields(ctx).Error(errMsg)
-			return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo), "", nil
+			return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo), "", nil, nil
 		}
 		pluginContactRequest.Token = token
 	} else {
@@ -90,7 +85,7 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	if err != nil {
 		errMsg := err.Error()
 		l.LogWithFields(ctx).Error(errMsg)
-		return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo), "", nil
+		return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo), "", nil, nil
 	}
 
 	var commonError errors.CommonError
@@ -98,7 +93,7 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	if err != nil {
 		errMsg := err.Error()
 		l.LogWithFields(ctx).Error(errMsg)
-		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo), "", nil
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo), "", nil, nil
 	}
 
 	commonError.Error.Code = errors.PropertyValueFormatError
@@ -107,6 +102,8 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	resp.StatusMessage = getResponse.StatusMessage
 
 	saveSystem.DeviceUUID = uuid.NewV4().String()
+	ctx, metrics := withDiscoveryMetrics(ctx)
+	defer metrics.log(ctx, saveSystem.DeviceUUID, saveSystem.ManagerAddress)
 	getSystemBody := map[string]interface{}{
 		"ManagerAddress": saveSystem.ManagerAddress,
 		"UserName":       saveSystem.UserName,
@@ -120,32 +117,24 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	pluginContactRequest.HTTPMethodType = http.MethodGet
 	pluginContactRequest.CreateSubcription = e.CreateSubcription
 	pluginContactRequest.PublishEvent = e.PublishEvent
+	pluginContactRequest.DiscoveryMetricHook = e.DiscoveryMetricHook
+	pluginContactRequest.InFlightCalls = newDiscoveryCallLimiter(config.Data.DiscoveryConf.MaxInFlightPluginCalls)
 	pluginContactRequest.BMCAddress = saveSystem.ManagerAddress
 
 	var h respHolder
 	h.TraversedLinks = make(map[string]bool)
 	h.InventoryData = make(map[string]interface{})
+	h.cancelRequestKey = getCancelRequestKey(getKeyFromManagerAddress(addResourceRequest.ManagerAddress))
+	h.checkActiveRequest = e.CheckActiveRequest
 	progress := percentComplete
 	systemsEstimatedWork := int32(60)
 	var computeSystemID, resourceURI string
 	if computeSystemID, resourceURI, progress, err = h.getAllSystemInfo(ctx, taskID, progress, systemsEstimatedWork, pluginContactRequest); err != nil {
 		errMsg := "error while trying to add compute: " + err.Error()
 		l.LogWithFields(ctx).Error(errMsg)
-		var msgArg = make([]interface{}, 0)
-		var skipFlag bool
-		switch h.StatusMessage {
-		case response.ResourceAlreadyExists:
-			msgArg = append(msgArg, addResourceRequest.ManagerAddress, pluginID, "ComputerSystem")
-		case response.ActionParameterNotSupported:
-			msgArg = append(msgArg, addResourceRequest.ManagerAddress, pluginID)
-		case response.ResourceAtURIUnauthorized, response.CouldNotEstablishConnection:
-			msgArg = append(msgArg, addResourceRequest.ManagerAddress)
-		default:
-			skipFlag = true
-		}
-		if !skipFlag {
-			go e.rollbackInMemory(resourceURI)
-			return common.GeneralError(h.StatusCode, h.StatusMessage, errMsg, msgArg, taskInfo), "", nil
+		if msgArg, fatal := getSystemErrorArgs(h.StatusMessage, addResourceRequest.ManagerAddress, pluginID); fatal {
+			go e.rollbackInMemory(ctx, resourceURI)
+			return common.GeneralError(h.StatusCode, h.StatusMessage, errMsg, msgArg, taskInfo), "", nil, nil
 		}
 	}
 	percentComplete = progress
@@ -153,6 +142,15 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	e.UpdateTask(ctx, task)
 	h.InventoryData = make(map[string]interface{})
 
+	// ServiceRoot of the device is discovered once, to record its capabilities
+	pluginContactRequest.DeviceInfo = getSystemBody
+	pluginContactRequest.DeviceUUID = saveSystem.DeviceUUID
+	h.getServiceRootInfo(ctx, pluginContactRequest)
+	saveSystem.RedfishVersion = h.ServiceRoot.RedfishVersion
+	saveSystem.ServiceCapabilities = h.ServiceRoot.Services
+	h.getEventServiceInfo(ctx, pluginContactRequest)
+	saveSystem.EventServiceCapabilities = h.EventService
+
 	// Populate the resource Firmware inventory for update service
 	pluginContactRequest.DeviceInfo = getSystemBody
 	pluginContactRequest.OID = "/redfish/v1/UpdateService/FirmwareInventory"
@@ -202,6 +200,7 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	pluginContactRequest.OID = "/redfish/v1/Registries"
 	pluginContactRequest.DeviceUUID = saveSystem.DeviceUUID
 	pluginContactRequest.HTTPMethodType = http.MethodGet
+	pluginContactRequest.ForceRegistryRefresh = addResourceRequest.ForceRegistryRefresh
 
 	progress = percentComplete
 	registriesEstimatedWork := int32(5)
@@ -210,8 +209,8 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	task = fillTaskData(taskID, targetURI, pluginContactRequest.TaskRequest, resp, common.Running, common.OK, percentComplete, http.MethodPost)
 	err = e.UpdateTask(ctx, task)
 	if err != nil && (err.Error() == common.Cancelling) {
-		go e.rollbackInMemory(resourceURI)
-		return resp, "", nil
+		go e.rollbackInMemory(ctx, resourceURI)
+		return resp, "", nil, nil
 	}
 
 	// End of Registry files Discovery
@@ -231,13 +230,15 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	progress = percentComplete
 	chassisEstimatedWork := int32(15)
 	progress = h.getAllRootInfo(ctx, taskID, progress, chassisEstimatedWork, pluginContactRequest, config.Data.AddComputeSkipResources.SkipResourceListUnderChassis)
+	// Assembly and PCIeDevices are accounted in the estimated work of the chassis
+	progress = h.getChassisAssetInfo(ctx, taskID, progress, 0, pluginContactRequest)
 
 	percentComplete = progress
 	task = fillTaskData(taskID, targetURI, pluginContactRequest.TaskRequest, resp, common.Running, common.OK, percentComplete, http.MethodPost)
 	err = e.UpdateTask(ctx, task)
 	if err != nil && (err.Error() == common.Cancelling) {
-		go e.rollbackInMemory(resourceURI)
-		return resp, "", nil
+		go e.rollbackInMemory(ctx, resourceURI)
+		return resp, "", nil, nil
 	}
 
 	//Logic for getting the manager information
@@ -256,40 +257,58 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	progress = percentComplete
 	managerEstimatedWork := int32(15)
 	progress = h.getAllRootInfo(ctx, taskID, progress, managerEstimatedWork, pluginContactRequest, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+	// VirtualMedia, NetworkProtocol and SerialInterfaces are accounted in the estimated work of the managers
+	progress = h.getVirtualMediaInfo(ctx, taskID, progress, 0, pluginContactRequest)
+	progress = h.getManagerProtocolInfo(ctx, taskID, progress, 0, pluginContactRequest)
+	h.crossCheckServiceRootUUID(ctx)
 
 	percentComplete = progress
 	task = fillTaskData(taskID, targetURI, pluginContactRequest.TaskRequest, resp, common.Running, common.OK, percentComplete, http.MethodPost)
 	err = e.UpdateTask(ctx, task)
 	if err != nil && (err.Error() == common.Cancelling) {
-		go e.rollbackInMemory(resourceURI)
-		return resp, "", nil
+		go e.rollbackInMemory(ctx, resourceURI)
+		return resp, "", nil, nil
 	}
-	if h.ErrorMessage != "" && h.StatusCode != http.StatusServiceUnavailable && h.StatusCode != http.StatusNotFound && h.StatusCode != http.StatusInternalServerError && h.StatusCode != http.StatusBadRequest {
-		go e.rollbackInMemory(resourceURI)
+	if h.hasFatalError() {
+		go e.rollbackInMemory(ctx, resourceURI)
 		l.LogWithFields(ctx).Error(h.ErrorMessage)
-		return common.GeneralError(h.StatusCode, h.StatusMessage, h.ErrorMessage, h.MsgArgs, taskInfo), "", nil
+		resp = common.GeneralError(h.StatusCode, h.StatusMessage, h.ErrorMessage, h.MsgArgs, nil)
+		// the errors of all the resources are reported along with the one failed the discovery
+		if commonError, ok := resp.Body.(response.CommonError); ok {
+			resp.Body = problemReportResponse{CommonError: commonError, DiscoveryProblems: h.Problems}
+		}
+		task = fillTaskData(taskID, targetURI, pluginContactRequest.TaskRequest, resp, common.Exception, common.Critical, 100, http.MethodPost)
+		if h.cancelRequested {
+			l.LogWithFields(ctx).Info("adding system with manager address " + addResourceRequest.ManagerAddress + " is cancelled")
+			task = fillTaskData(taskID, targetURI, pluginContactRequest.TaskRequest, resp, common.Cancelled, common.OK, 100, http.MethodPost)
+		}
+		e.UpdateTask(ctx, task)
+		return resp, "", nil, h.Problems
 	}
+	h.findMissingRegistries(ctx)
 	err = agmodel.SaveBMCInventory(h.InventoryData)
 	if err != nil {
 		errorMessage := "GenericSave : error while trying to add resource data to DB: " + err.Error()
 		l.LogWithFields(ctx).Error(errorMessage)
 		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errorMessage,
-			nil, nil), "", nil
+			nil, nil), "", nil, nil
 	}
+	h.indexSensorSummary(ctx, pluginContactRequest.BMCAddress)
 	ciphertext, err := e.EncryptPassword([]byte(addResourceRequest.Password))
 	if err != nil {
-		go e.rollbackInMemory(resourceURI)
+		go e.rollbackInMemory(ctx, resourceURI)
 		errMsg := "error while trying to encrypt: " + err.Error()
 		l.LogWithFields(ctx).Error(errMsg)
-		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo), "", nil
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo), "", nil, nil
 	}
 	saveSystem.Password = ciphertext
+	saveSystem.DiscoveredAt = time.Now().UTC().Format(time.RFC3339)
 	aggregationSourceID := saveSystem.DeviceUUID + "." + computeSystemID
 	if err := saveSystem.Create(ctx, saveSystem.DeviceUUID); err != nil {
-		go e.rollbackInMemory(resourceURI)
+		go e.rollbackInMemory(ctx, resourceURI)
 		errMsg := "error while trying to add compute: " + err.Error()
 		l.LogWithFields(ctx).Error(errMsg)
-		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo), "", nil
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo), "", nil, nil
 	}
 	aggSourceIDChassisAndManager := saveSystem.DeviceUUID + "."
 	chassisList, _ := agmodel.GetAllMatchingDetails("Chassis", aggSourceIDChassisAndManager, common.InMemory)
@@ -297,7 +316,7 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 	urlList := h.SystemURL
 	urlList = append(urlList, chassisList...)
 	urlList = append(urlList, managersList...)
-	pluginContactRequest.CreateSubcription(ctx, urlList)
+	pluginContactRequest.CreateSubcription(ctx, urlList, h.EventService)
 
 	pluginContactRequest.PublishEvent(ctx, h.SystemURL, "SystemsCollection")
 
@@ -342,7 +361,7 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 		errorMessage := "error getting manager details: " + jerr.Error()
 		l.LogWithFields(ctx).Error(errorMessage)
 		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errorMessage,
-			nil, nil), "", nil
+			nil, nil), "", nil, nil
 	}
 
 	err = json.Unmarshal([]byte(data), &managerData)
@@ -350,7 +369,7 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 		errorMessage := "error unmarshalling manager details: " + err.Error()
 		l.LogWithFields(ctx).Error(errorMessage)
 		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errorMessage,
-			nil, nil), "", nil
+			nil, nil), "", nil, nil
 	}
 
 	for _, val := range chassisList {
@@ -383,15 +402,29 @@ func (e *ExternalInterface) addCompute(ctx context.Context, taskID, targetURI, p
 		errorMessage := "unable to marshal data while updating managers detail: " + err.Error()
 		l.LogWithFields(ctx).Error(errorMessage)
 		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errorMessage,
-			nil, nil), "", nil
+			nil, nil), "", nil, nil
 	}
 	err = agmodel.GenericSave([]byte(mgrData), "Managers", managerURI)
 	if err != nil {
 		errorMessage := "GenericSave : error while trying to add resource date to DB: " + err.Error()
 		l.LogWithFields(ctx).Error(errorMessage)
 		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errorMessage,
-			nil, nil), "", nil
+			nil, nil), "", nil, nil
 	}
 
-	return resp, aggregationSourceID, ciphertext
+	return resp, aggregationSourceID, ciphertext, h.Problems
+}
+
+// getSystemErrorArgs returns the message args of the error which failed the discovery of the systems.
+// The errors other than these don't fail the add, the rest of the server is still discovered.
+func getSystemErrorArgs(statusMessage, managerAddress, pluginID string) ([]interface{}, bool) {
+	switch statusMessage {
+	case response.ResourceAlreadyExists:
+		return []interface{}{managerAddress, pluginID, "ComputerSystem"}, true
+	case response.ActionParameterNotSupported:
+		return []interface{}{managerAddress, pluginID}, true
+	case response.ResourceAtURIUnauthorized, response.CouldNotEstablishConnection:
+		return []interface{}{managerAddress}, true
+	}
+	return nil, false
 }
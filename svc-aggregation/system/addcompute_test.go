@@ -31,7 +31,8 @@ import (
 	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
 )
 
-func EventFunctionsForTesting(ctx context.Context, s []string)                 {}
+func EventFunctionsForTesting(ctx context.Context, s []string, capabilities *agmodel.EventServiceCapabilities) {
+}
 func PostEventFunctionForTesting(ctx context.Context, s []string, name string) {}
 func GetPluginStatusForTesting(ctx context.Context, plugin agmodel.Plugin) bool {
 	return true
@@ -71,7 +72,7 @@ func mockContactClientForDuplicate(ctx context.Context, url, method, token strin
 			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
 		}, nil
 	} else if strings.Contains(url, "SomeRegistry.json") {
-		body := `{"MessageId": "` + response.Success + `"}`
+		body := `{"Id":"SomeRegistry.1.0.0","RegistryPrefix":"SomeRegistry","RegistryVersion":"1.0.0","Messages":{}}`
 		return &http.Response{
 			StatusCode: http.StatusOK,
 			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
@@ -351,7 +352,7 @@ func TestExternalInterface_addcompute(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _, _ := tt.p.addCompute(ctx, tt.args.taskID, targetURI, tt.args.pluginID, percentComplete, tt.args.req, pluginContactRequest); !reflect.DeepEqual(got.StatusCode, tt.want.StatusCode) {
+			if got, _, _, _ := tt.p.addCompute(ctx, tt.args.taskID, targetURI, tt.args.pluginID, percentComplete, tt.args.req, pluginContactRequest); !reflect.DeepEqual(got.StatusCode, tt.want.StatusCode) {
 				t.Errorf("ExternalInterface.addCompute = %v, want %v", got, tt.want)
 			}
 		})
@@ -0,0 +1,102 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+)
+
+// pluginTypeLimiter limits the number of servers added concurrently for each plugin type,
+// it holds a semaphore for each of the plugin types having a limit configured
+type pluginTypeLimiter struct {
+	lock       sync.Mutex
+	semaphores map[string]chan struct{}
+	// queued holds the number of adds waiting for each of the plugin types
+	queued map[string]int
+}
+
+// limiterState is the state of the limiter of a plugin type
+type limiterState struct {
+	Limit      int `json:"Limit"`
+	InProgress int `json:"InProgress"`
+	Queued     int `json:"Queued"`
+}
+
+var addLimiter = &pluginTypeLimiter{
+	semaphores: make(map[string]chan struct{}),
+}
+
+// getSemaphore returns the semaphore of the plugin type sized as per the configured limit,
+// nil is returned when there is no limit for the plugin type
+func (p *pluginTypeLimiter) getSemaphore(pluginType string) chan struct{} {
+	limit := config.Data.DiscoveryConf.MaxConcurrentAddsPerPluginType[pluginType]
+	if limit <= 0 {
+		return nil
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	semaphore, ok := p.semaphores[pluginType]
+	// limit could be changed in the config, the adds holding the previous
+	// semaphore are released to it
+	if !ok || cap(semaphore) != limit {
+		semaphore = make(chan struct{}, limit)
+		p.semaphores[pluginType] = semaphore
+	}
+	return semaphore
+}
+
+// acquire waits till an add of the plugin type can be started and returns the function to be
+// called once the add is completed. It returns the error of the context if it is done while waiting.
+func (p *pluginTypeLimiter) acquire(ctx context.Context, pluginType string) (func(), error) {
+	semaphore := p.getSemaphore(pluginType)
+	if semaphore == nil {
+		return func() {}, nil
+	}
+	p.setQueued(pluginType, 1)
+	defer p.setQueued(pluginType, -1)
+	select {
+	case semaphore <- struct{}{}:
+		return func() { <-semaphore }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *pluginTypeLimiter) setQueued(pluginType string, delta int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.queued == nil {
+		p.queued = make(map[string]int)
+	}
+	p.queued[pluginType] += delta
+}
+
+// state returns the state of the limiters of all the plugin types having a limit
+func (p *pluginTypeLimiter) state() map[string]limiterState {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	states := make(map[string]limiterState, len(p.semaphores))
+	for pluginType, semaphore := range p.semaphores {
+		states[pluginType] = limiterState{
+			Limit:      cap(semaphore),
+			InProgress: len(semaphore),
+			Queued:     p.queued[pluginType],
+		}
+	}
+	return states
+}
@@ -0,0 +1,83 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pluginTypeLimiter(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.MaxConcurrentAddsPerPluginType = map[string]int{"Compute": 2}
+	limiter := &pluginTypeLimiter{semaphores: make(map[string]chan struct{})}
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.acquire(context.Background(), "Compute")
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer release()
+			current := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), maxRunning, "concurrent Compute adds should be capped at the limit")
+
+	// adds of the plugin types without a limit are not queued
+	release1, err := limiter.acquire(context.Background(), "Storage")
+	assert.Nil(t, err)
+	release2, err := limiter.acquire(context.Background(), "Storage")
+	assert.Nil(t, err)
+	release1()
+	release2()
+}
+
+func Test_pluginTypeLimiterContextDone(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.MaxConcurrentAddsPerPluginType = map[string]int{"Compute": 1}
+	limiter := &pluginTypeLimiter{semaphores: make(map[string]chan struct{})}
+
+	release, err := limiter.acquire(context.Background(), "Compute")
+	assert.Nil(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "Compute")
+	assert.Equal(t, context.DeadlineExceeded, err, "queued add should stop when the context is done")
+
+	release()
+	release, err = limiter.acquire(context.Background(), "Compute")
+	assert.Nil(t, err, "add should start once the ongoing add is completed")
+	release()
+}
@@ -32,7 +32,7 @@ import (
 	"github.com/ODIM-Project/ODIM/svc-aggregation/agresponse"
 )
 
-func (e *ExternalInterface) addPluginData(ctx context.Context, req AddResourceRequest, taskID, targetURI string, pluginContactRequest getResourceRequest, queueList []string, cmVariants connectionMethodVariants) (response.RPC, string, []byte) {
+func (e *ExternalInterface) addPluginData(ctx context.Context, req AddResourceRequest, taskID, targetURI string, pluginContactRequest getResourceRequest, queueList, capabilities []string, cmVariants connectionMethodVariants) (response.RPC, string, []byte) {
 	var resp response.RPC
 	taskInfo := &common.TaskUpdateInfo{Context: ctx, TaskID: taskID, TargetURI: targetURI, UpdateTask: e.UpdateTask, TaskRequest: pluginContactRequest.TaskRequest}
 
@@ -115,17 +115,15 @@ func (e *ExternalInterface) addPluginData(ctx context.Context, req AddResourceRe
 		ID:                cmVariants.PluginID,
 		PluginType:        cmVariants.PluginType,
 		PreferredAuthType: cmVariants.PreferredAuthType,
+		Capabilities:      capabilities,
+		ForceBasicAuth:    req.ForceBasicAuth,
+		Version:           cmVariants.FirmwareVersion,
 	}
 	pluginContactRequest.Plugin = plugin
 	pluginContactRequest.StatusPoll = true
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
-		pluginContactRequest.HTTPMethodType = http.MethodPost
-		pluginContactRequest.DeviceInfo = map[string]interface{}{
-			"Username": plugin.Username,
-			"Password": string(plugin.Password),
-		}
-		pluginContactRequest.OID = "/ODIM/v1/Sessions"
-		_, token, getResponse, err := contactPlugin(ctx, pluginContactRequest, "error while creating the session: ")
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
+		// the session created while checking the status of the plugin is reused
+		token, getResponse, err := getPluginSessionToken(ctx, pluginContactRequest)
 		if err != nil {
 			errMsg := err.Error()
 			l.LogWithFields(ctx).Error(errMsg)
@@ -316,3 +314,15 @@ func (e *ExternalInterface) addPluginData(ctx context.Context, req AddResourceRe
 
 	return resp, managerUUID, ciphertext
 }
+
+// verifyPluginEMBConsumption checks whether the events service is consuming the EMB queues
+// of the added plugin, so that its events are delivered. The events service publishes a probe
+// on each queue and waits for a bounded time for it to be consumed
+func (e *ExternalInterface) verifyPluginEMBConsumption(ctx context.Context, pluginID string, queueList []string) error {
+	if err := e.VerifyEMBConsumption(pluginID, queueList); err != nil {
+		l.LogWithFields(ctx).Warn("events of the plugin " + pluginID + " won't be delivered: " + err.Error())
+		return err
+	}
+	l.LogWithFields(ctx).Info("EMB queues of the plugin " + pluginID + " are being consumed")
+	return nil
+}
@@ -89,6 +89,11 @@ func mockDupMgrAddrPluginData(t *testing.T, pluginID string) error {
 	return nil
 }
 
+func getTestConnectionMethodVariants(connectionMethodVariant string) connectionMethodVariants {
+	cmVariants, _ := getConnectionMethodVariants(connectionMethodVariant)
+	return cmVariants
+}
+
 func TestExternalInterface_Plugin(t *testing.T) {
 	config.SetUpMockConfig(t)
 	addComputeRetrieval := config.AddComputeSkipResources{
@@ -201,7 +206,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqSuccess,
-				cmVariants: getConnectionMethodVariants("Compute:BasicAuth:GRF_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:BasicAuth:GRF_v2.0.0"),
 			},
 			want: response.RPC{
 				StatusCode: http.StatusCreated,
@@ -213,7 +218,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqExistingPlugin,
-				cmVariants: getConnectionMethodVariants("Compute:BasicAuth:ILO_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:BasicAuth:ILO_v2.0.0"),
 			},
 			want: response.RPC{
 				StatusCode: http.StatusConflict,
@@ -225,7 +230,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqInvalidAuthType,
-				cmVariants: getConnectionMethodVariants("Compute:BasicAuthentication:ILO_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:BasicAuthentication:ILO_v2.0.0"),
 			},
 			want: response.RPC{
 				StatusCode: http.StatusBadRequest,
@@ -237,7 +242,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqInvalidPluginType,
-				cmVariants: getConnectionMethodVariants("plugin:BasicAuth:ILO_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("plugin:BasicAuth:ILO_v2.0.0"),
 			},
 			want: response.RPC{
 				StatusCode: http.StatusBadRequest,
@@ -249,7 +254,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqExistingPluginBadPassword,
-				cmVariants: getConnectionMethodVariants("Compute:BasicAuth:PluginWithBadPassword_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:BasicAuth:PluginWithBadPassword_v2.0.0"),
 			},
 			want: response.RPC{
 				StatusCode: http.StatusConflict,
@@ -261,7 +266,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqExistingPluginBadData,
-				cmVariants: getConnectionMethodVariants("Compute:BasicAuth:PluginWithBadData_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:BasicAuth:PluginWithBadData_v2.0.0"),
 			},
 
 			want: response.RPC{
@@ -275,7 +280,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqPluginWithDuplciateUUID,
-				cmVariants: getConnectionMethodVariants("Compute:BasicAuth:STGtest_v1.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:BasicAuth:STGtest_v1.0.0"),
 			},
 			want: response.RPC{
 				StatusCode: http.StatusConflict,
@@ -284,7 +289,7 @@ func TestExternalInterface_Plugin(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _, _ := tt.p.addPluginData(ctx, tt.args.req, tt.args.taskID, targetURI, pluginContactRequest, queueList, tt.args.cmVariants); !reflect.DeepEqual(got.StatusCode, tt.want.StatusCode) {
+			if got, _, _ := tt.p.addPluginData(ctx, tt.args.req, tt.args.taskID, targetURI, pluginContactRequest, queueList, nil, tt.args.cmVariants); !reflect.DeepEqual(got.StatusCode, tt.want.StatusCode) {
 				t.Errorf("ExternalInterface.addPluginData = %v, want %v", got, tt.want)
 			}
 		})
@@ -376,7 +381,7 @@ func TestExternalInterface_PluginXAuth(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqXAuthSuccess,
-				cmVariants: getConnectionMethodVariants("Compute:XAuthToken:GRF_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:XAuthToken:GRF_v2.0.0"),
 			},
 
 			want: response.RPC{
@@ -389,7 +394,7 @@ func TestExternalInterface_PluginXAuth(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqXAuthFail,
-				cmVariants: getConnectionMethodVariants("Compute:XAuthToken:ILO_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:XAuthToken:ILO_v2.0.0"),
 			},
 
 			want: response.RPC{
@@ -402,7 +407,7 @@ func TestExternalInterface_PluginXAuth(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqManagerGetFail,
-				cmVariants: getConnectionMethodVariants("Compute:XAuthToken:ILO_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:XAuthToken:ILO_v2.0.0"),
 			},
 
 			want: response.RPC{
@@ -415,7 +420,7 @@ func TestExternalInterface_PluginXAuth(t *testing.T) {
 			args: args{
 				taskID:     "123",
 				req:        reqInvalidManagerBody,
-				cmVariants: getConnectionMethodVariants("Compute:XAuthToken:ILO_v2.0.0"),
+				cmVariants: getTestConnectionMethodVariants("Compute:XAuthToken:ILO_v2.0.0"),
 			},
 			want: response.RPC{
 				StatusCode: http.StatusInternalServerError,
@@ -424,9 +429,30 @@ func TestExternalInterface_PluginXAuth(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _, _ := tt.p.addPluginData(ctx, tt.args.req, tt.args.taskID, targetURI, pluginContactRequest, queueList, tt.args.cmVariants); !reflect.DeepEqual(got.StatusCode, tt.want.StatusCode) {
+			if got, _, _ := tt.p.addPluginData(ctx, tt.args.req, tt.args.taskID, targetURI, pluginContactRequest, queueList, nil, tt.args.cmVariants); !reflect.DeepEqual(got.StatusCode, tt.want.StatusCode) {
 				t.Errorf("ExternalInterface.addPluginData = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestExternalInterface_verifyPluginEMBConsumption(t *testing.T) {
+	consumedQueues := map[string]bool{"GRF-EVENTS": true}
+	e := ExternalInterface{
+		VerifyEMBConsumption: func(pluginID string, queueList []string) error {
+			for _, queue := range queueList {
+				if !consumedQueues[queue] {
+					return fmt.Errorf("EMB queues %v of the plugin %s are not being consumed", queueList, pluginID)
+				}
+			}
+			return nil
+		},
+	}
+	ctx := mockContext()
+	if err := e.verifyPluginEMBConsumption(ctx, "GRF", []string{"GRF-EVENTS"}); err != nil {
+		t.Errorf("verifyPluginEMBConsumption() for the consumed queue returned error: %v", err)
+	}
+	if err := e.verifyPluginEMBConsumption(ctx, "ILO", []string{"ILO-EVENTS"}); err == nil {
+		t.Errorf("verifyPluginEMBConsumption() for the queue which is not consumed returned no error")
+	}
+}
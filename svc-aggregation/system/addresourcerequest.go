@@ -0,0 +1,101 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+)
+
+const connectionMethodURIPrefix = "/redfish/v1/AggregationService/ConnectionMethods/"
+
+var hostNamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+
+// ValidateAddResourceRequest validates the required properties of the add request, the format of
+// the manager address and the connection method link, before the plugin or the device is contacted.
+// On failure, it returns the error response with an entry for each of the offending properties.
+func ValidateAddResourceRequest(req AddResourceRequest) (response.RPC, error) {
+	var errArgs []response.ErrArgs
+	addMissing := func(property string) {
+		errArgs = append(errArgs, response.ErrArgs{
+			StatusMessage: response.PropertyMissing,
+			ErrorMessage:  "error: mandatory property " + property + " is missing in the request",
+			MessageArgs:   []interface{}{property},
+		})
+	}
+	addFormatError := func(property, value string, err error) {
+		errArgs = append(errArgs, response.ErrArgs{
+			StatusMessage: response.PropertyValueFormatError,
+			ErrorMessage:  err.Error(),
+			MessageArgs:   []interface{}{value, property},
+		})
+	}
+
+	if req.ManagerAddress == "" {
+		addMissing("HostName")
+	} else if err := validateManagerAddressFormat(req.ManagerAddress); err != nil {
+		addFormatError("HostName", req.ManagerAddress, err)
+	}
+	if req.UserName == "" {
+		addMissing("UserName")
+	}
+	if req.Password == "" {
+		addMissing("Password")
+	}
+	if req.ConnectionMethod == nil || req.ConnectionMethod.OdataID == "" {
+		addMissing("ConnectionMethod")
+	} else if !strings.HasPrefix(req.ConnectionMethod.OdataID, connectionMethodURIPrefix) ||
+		len(req.ConnectionMethod.OdataID) == len(connectionMethodURIPrefix) {
+		addFormatError("ConnectionMethod", req.ConnectionMethod.OdataID,
+			fmt.Errorf("error: ConnectionMethod should be a link to a member of %s", strings.TrimSuffix(connectionMethodURIPrefix, "/")))
+	}
+	if len(errArgs) == 0 {
+		return response.RPC{}, nil
+	}
+
+	args := response.Args{
+		Code:      response.GeneralError,
+		Message:   "",
+		ErrorArgs: errArgs,
+	}
+	resp := response.RPC{
+		StatusCode:    http.StatusBadRequest,
+		StatusMessage: errArgs[0].StatusMessage,
+		Body:          args.CreateGenericErrorResponse(),
+	}
+	return resp, fmt.Errorf("error: %d properties of the add request are not valid", len(errArgs))
+}
+
+// validateManagerAddressFormat checks if the manager address is an IP or host name with an optional port,
+// unlike validateManagerAddress the host name is not resolved
+func validateManagerAddressFormat(managerAddress string) error {
+	host, _ := getIPAndPortFromAddress(managerAddress)
+	if _, port, err := net.SplitHostPort(managerAddress); err == nil {
+		portNumber, err := strconv.Atoi(port)
+		if err != nil || portNumber < 1 || portNumber > 65535 {
+			return fmt.Errorf("error: invalid port %s in the manager address", port)
+		}
+	}
+	if isIPAddress(host) || hostNamePattern.MatchString(host) {
+		return nil
+	}
+	return fmt.Errorf("error: %s is not a valid IP address or host name", host)
+}
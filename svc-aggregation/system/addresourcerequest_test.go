@@ -0,0 +1,135 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddResourceRequest(t *testing.T) {
+	validRequest := func() AddResourceRequest {
+		return AddResourceRequest{
+			ManagerAddress: "100.0.0.1:443",
+			UserName:       "admin",
+			Password:       "password",
+			ConnectionMethod: &ConnectionMethod{
+				OdataID: "/redfish/v1/AggregationService/ConnectionMethods/7ff3bd97-c41c-5de0-937d-85d390691b73",
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		modify     func(req *AddResourceRequest)
+		wantErrors []response.ErrArgs
+	}{
+		{
+			name:   "valid request",
+			modify: func(req *AddResourceRequest) {},
+		},
+		{
+			name:   "valid request with host name",
+			modify: func(req *AddResourceRequest) { req.ManagerAddress = "bmc-1.odim.local" },
+		},
+		{
+			name:   "valid request with IPv6 address",
+			modify: func(req *AddResourceRequest) { req.ManagerAddress = "[fd00::1]:443" },
+		},
+		{
+			name:   "valid request with IPv6 link-local address with zone",
+			modify: func(req *AddResourceRequest) { req.ManagerAddress = "[fe80::1%eth0]:443" },
+		},
+		{
+			name:   "valid request with bare IPv6 address with zone",
+			modify: func(req *AddResourceRequest) { req.ManagerAddress = "fe80::1%eth0" },
+		},
+		{
+			name:       "missing HostName",
+			modify:     func(req *AddResourceRequest) { req.ManagerAddress = "" },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyMissing, MessageArgs: []interface{}{"HostName"}}},
+		},
+		{
+			name:       "invalid host in HostName",
+			modify:     func(req *AddResourceRequest) { req.ManagerAddress = "https://100.0.0.1" },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyValueFormatError, MessageArgs: []interface{}{"https://100.0.0.1", "HostName"}}},
+		},
+		{
+			name:       "invalid port in HostName",
+			modify:     func(req *AddResourceRequest) { req.ManagerAddress = "100.0.0.1:99999" },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyValueFormatError, MessageArgs: []interface{}{"100.0.0.1:99999", "HostName"}}},
+		},
+		{
+			name:       "missing UserName",
+			modify:     func(req *AddResourceRequest) { req.UserName = "" },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyMissing, MessageArgs: []interface{}{"UserName"}}},
+		},
+		{
+			name:       "missing Password",
+			modify:     func(req *AddResourceRequest) { req.Password = "" },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyMissing, MessageArgs: []interface{}{"Password"}}},
+		},
+		{
+			name:       "missing ConnectionMethod",
+			modify:     func(req *AddResourceRequest) { req.ConnectionMethod = nil },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyMissing, MessageArgs: []interface{}{"ConnectionMethod"}}},
+		},
+		{
+			name:       "empty ConnectionMethod link",
+			modify:     func(req *AddResourceRequest) { req.ConnectionMethod.OdataID = "" },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyMissing, MessageArgs: []interface{}{"ConnectionMethod"}}},
+		},
+		{
+			name:   "invalid ConnectionMethod link",
+			modify: func(req *AddResourceRequest) { req.ConnectionMethod.OdataID = "/redfish/v1/Systems/1" },
+			wantErrors: []response.ErrArgs{{StatusMessage: response.PropertyValueFormatError,
+				MessageArgs: []interface{}{"/redfish/v1/Systems/1", "ConnectionMethod"}}},
+		},
+		{
+			name: "multiple invalid properties",
+			modify: func(req *AddResourceRequest) {
+				req.ManagerAddress = "100.0.0.1:port"
+				req.Password = ""
+			},
+			wantErrors: []response.ErrArgs{
+				{StatusMessage: response.PropertyValueFormatError, MessageArgs: []interface{}{"100.0.0.1:port", "HostName"}},
+				{StatusMessage: response.PropertyMissing, MessageArgs: []interface{}{"Password"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.modify(&req)
+			resp, err := ValidateAddResourceRequest(req)
+			if len(tt.wantErrors) == 0 {
+				assert.Nil(t, err, "request should be valid")
+				return
+			}
+			assert.NotNil(t, err, "request should not be valid")
+			assert.Equal(t, int32(http.StatusBadRequest), resp.StatusCode)
+			assert.Equal(t, tt.wantErrors[0].StatusMessage, resp.StatusMessage)
+			body, ok := resp.Body.(response.CommonError)
+			if assert.True(t, ok, "response body should be an error") && assert.Len(t, body.Error.MessageExtendedInfo, len(tt.wantErrors)) {
+				for i, wantError := range tt.wantErrors {
+					assert.Equal(t, wantError.StatusMessage, body.Error.MessageExtendedInfo[i].MessageID)
+					assert.Equal(t, wantError.MessageArgs, body.Error.MessageExtendedInfo[i].MessageArgs)
+				}
+			}
+		})
+	}
+}
@@ -661,7 +661,7 @@ func (e *ExternalInterface) resetSystem(ctx context.Context, taskID, reqBody str
 	pluginContactRequest.StatusPoll = true
 	pluginContactRequest.TaskRequest = reqBody
 
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		var err error
 		pluginContactRequest.HTTPMethodType = http.MethodPost
 		pluginContactRequest.DeviceInfo = map[string]interface{}{
@@ -702,8 +702,10 @@ func (e *ExternalInterface) resetSystem(ctx context.Context, taskID, reqBody str
 		common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo)
 		return
 	}
+	successMessage := "Request completed successfully."
 	if getResponse.StatusCode == http.StatusAccepted {
-		getResponse, err = e.monitorPluginTask(ctx, subTaskChan, &monitorTaskRequest{
+		var taskResult monitorTaskResult
+		taskResult, err = e.monitorPluginTask(ctx, subTaskChan, &monitorTaskRequest{
 			subTaskID:         subTaskID,
 			serverURI:         targetURI,
 			updateRequestBody: reqBody,
@@ -718,12 +720,16 @@ func (e *ExternalInterface) resetSystem(ctx context.Context, taskID, reqBody str
 		if err != nil {
 			return
 		}
+		getResponse = taskResult.responseStatus
+		if msg := taskResult.finalMessage(); msg != "" {
+			successMessage = msg
+		}
 	}
 
 	resp.StatusMessage = response.Success
 	resp.Body = response.ErrorClass{
 		Code:    resp.StatusMessage,
-		Message: "Request completed successfully.",
+		Message: successMessage,
 	}
 	resp.Header = map[string]string{
 		"Location": element,
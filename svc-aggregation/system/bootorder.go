@@ -216,7 +216,7 @@ func (e *ExternalInterface) collectAndSetDefaultOrder(ctx context.Context, taskI
 	pluginContactRequest.StatusPoll = true
 	pluginContactRequest.TaskRequest = reqJSON
 
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		pluginContactRequest.HTTPMethodType = http.MethodPost
 		pluginContactRequest.DeviceInfo = map[string]interface{}{
 			"UserName": plugin.Username,
@@ -0,0 +1,53 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+)
+
+// CancelAdd cancels the in-progress add of the aggregation source with the manager address.
+// The cancellation is marked in the active-request store, the discovery of the add polls it
+// and stops, the partially added data is rolled back and the task of the add is marked Cancelled
+func (e *ExternalInterface) CancelAdd(ctx context.Context, managerAddress string) response.RPC {
+	ipAddr := getKeyFromManagerAddress(managerAddress)
+	exist, err := e.CheckActiveRequest(ipAddr)
+	if err != nil {
+		errMsg := fmt.Sprintf("Unable to collect the active request details from DB: %v", err.Error())
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+	if !exist {
+		errMsg := "no active request exists for adding aggregation source " + managerAddress
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"ActiveAddBMCRequest", managerAddress}, nil)
+	}
+	if err := e.GenericSave(nil, "ActiveAddBMCRequest", getCancelRequestKey(ipAddr)); err != nil {
+		errMsg := fmt.Sprintf("Unable to save the cancellation of the active request in DB: %v", err.Error())
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+	l.LogWithFields(ctx).Info("cancellation of adding aggregation source " + managerAddress + " is requested")
+	return response.RPC{
+		StatusCode:    http.StatusAccepted,
+		StatusMessage: response.Success,
+	}
+}
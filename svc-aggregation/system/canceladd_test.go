@@ -0,0 +1,153 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalInterface_CancelAdd(t *testing.T) {
+	config.SetUpMockConfig(t)
+	tests := []struct {
+		name       string
+		active     bool
+		wantStatus int32
+	}{
+		{
+			name:       "active add is cancelled",
+			active:     true,
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "no active add",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var savedKeys []string
+			p := getMockExternalInterface()
+			p.CheckActiveRequest = func(key string) (bool, *errors.Error) {
+				return tt.active && key == "100.0.0.1", nil
+			}
+			p.GenericSave = func(data []byte, table, key string) error {
+				savedKeys = append(savedKeys, table+":"+key)
+				return nil
+			}
+			resp := p.CancelAdd(mockContext(), "100.0.0.1")
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.active {
+				assert.Equal(t, []string{"ActiveAddBMCRequest:100.0.0.1:Cancelled"}, savedKeys)
+			} else {
+				assert.Empty(t, savedKeys)
+			}
+		})
+	}
+}
+
+func TestExternalInterface_addComputeCancelled(t *testing.T) {
+	common.MuxLock.Lock()
+	config.SetUpMockConfig(t)
+	common.MuxLock.Unlock()
+	defer func() {
+		if err := common.TruncateDB(common.OnDisk); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		if err := common.TruncateDB(common.InMemory); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}()
+	// the cancellation marker is polled for every resource, so that no plugin call follows the cancel
+	defer func(interval time.Duration) {
+		cancelPollInterval = interval
+	}(cancelPollInterval)
+	cancelPollInterval = 0
+	mockPluginData(t, "GRF")
+	mockManagersData("/redfish/v1/Managers/1s7sda8asd-asdas8as0", map[string]interface{}{
+		"Name": "GRF_v2.0.0",
+		"UUID": "1s7sda8asd-asdas8as0",
+	})
+
+	var lock sync.Mutex
+	var cancelled bool
+	var contactedAfterCancel []string
+	var tasks []common.TaskData
+	rolledBack := make(chan string, 1)
+	p := getMockExternalInterface()
+	// the add is cancelled once the discovery of the chassis starts
+	p.ContactClient = func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		lock.Lock()
+		if cancelled {
+			contactedAfterCancel = append(contactedAfterCancel, url)
+		}
+		if strings.HasSuffix(url, "/ODIM/v1/Chassis") {
+			cancelled = true
+		}
+		lock.Unlock()
+		return mockContactClient(ctx, url, method, token, odataID, body, credentials)
+	}
+	p.CheckActiveRequest = func(key string) (bool, *errors.Error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return cancelled && key == getCancelRequestKey("100.0.0.1"), nil
+	}
+	p.UpdateTask = func(ctx context.Context, task common.TaskData) error {
+		lock.Lock()
+		tasks = append(tasks, task)
+		lock.Unlock()
+		return nil
+	}
+	p.DeleteComputeSystem = func(index int, key string) *errors.Error {
+		rolledBack <- key
+		return nil
+	}
+	req := AddResourceRequest{
+		ManagerAddress: "100.0.0.1",
+		UserName:       "admin",
+		Password:       "password",
+	}
+	var pluginContactRequest getResourceRequest
+	pluginContactRequest.ContactClient = p.ContactClient
+	pluginContactRequest.GetPluginStatus = p.GetPluginStatus
+	pluginContactRequest.TargetURI = "/redfish/v1/AggregationService/AggregationSource"
+	pluginContactRequest.UpdateTask = p.UpdateTask
+
+	resp, aggregationSourceUUID, _, _ := p.addCompute(mockContext(), "123", pluginContactRequest.TargetURI, "GRF", 0, req, pluginContactRequest)
+	assert.Equal(t, int32(discoveryCancelledStatus.StatusCode), resp.StatusCode)
+	assert.Empty(t, aggregationSourceUUID)
+	select {
+	case key := <-rolledBack:
+		assert.True(t, strings.HasPrefix(key, "/redfish/v1/Systems/"), "the added system should be rolled back")
+	case <-time.After(5 * time.Second):
+		t.Fatal("partially added system is not rolled back after the cancellation")
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Empty(t, contactedAfterCancel, "the discovery should stop once the add is cancelled")
+	if assert.NotEmpty(t, tasks) {
+		assert.Equal(t, common.Cancelled, tasks[len(tasks)-1].TaskState)
+	}
+}
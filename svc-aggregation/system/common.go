@@ -21,8 +21,9 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
-	"reflect"
+	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -58,6 +59,27 @@ const (
 	LogServices = "LogServices"
 	//EntriesCollection is used to replace with table id EntriesCollection
 	EntriesCollection = "EntriesCollection"
+	// telemetryServiceURI is the URI of the TelemetryService resource
+	telemetryServiceURI = "/redfish/v1/TelemetryService"
+)
+
+// firmwareVersionPattern matches the semantic versions and the vendor versions
+// having two to four numeric components, like 1.0.0, 2.1 or 1.0.0-beta
+var firmwareVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+){1,3}([-+][0-9A-Za-z.+-]+)?$`)
+
+// resourceLinkPattern matches the links of the systems, managers and chassis only at the start of
+// a JSON string, so that the URIs in the free text and the URIs of the external systems are not
+// updated with the device UUID
+var resourceLinkPattern = regexp.MustCompile(`"/redfish/v1/(?:Systems|systems|Managers|managers|Chassis|chassis)/`)
+
+var (
+	// rollbackRetryCount is the number of times the delete is attempted while rolling back a failed add
+	rollbackRetryCount = 3
+	// rollbackRetryInterval is the wait between the delete attempts while rolling back a failed add
+	rollbackRetryInterval = 2 * time.Second
+	// cancelPollInterval is the minimum wait between the polls of the cancellation marker of the add,
+	// so that the active-request store isn't queried for every discovered resource
+	cancelPollInterval = time.Second
 )
 
 // WildCard is used to reduce the size the of list of metric properties
@@ -81,11 +103,12 @@ type ExternalInterface struct {
 	CreateChildTask          func(context.Context, string, string) (string, error)
 	CreateTask               func(context.Context, string) (string, error)
 	UpdateTask               func(context.Context, common.TaskData) error
-	CreateSubcription        func(context.Context, []string)
+	CreateSubcription        func(context.Context, []string, *agmodel.EventServiceCapabilities)
 	PublishEvent             func(context.Context, []string, string)
 	PublishEventMB           func(context.Context, string, string, string)
 	GetPluginStatus          func(context.Context, agmodel.Plugin) bool
 	SubscribeToEMB           func(string, []string) error
+	VerifyEMBConsumption     func(string, []string) error
 	EncryptPassword          func([]byte) ([]byte, error)
 	DecryptPassword          func([]byte) ([]byte, error)
 	DeleteComputeSystem      func(int, string) *errors.Error
@@ -105,6 +128,8 @@ type ExternalInterface struct {
 	DeleteMetricRequest      func(string) *errors.Error
 	GetResource              func(string, string) (string, *errors.Error)
 	Delete                   func(string, string, common.DbType) *errors.Error
+	// DiscoveryMetricHook receives the timing data of each resource discovered, it is optional
+	DiscoveryMetricHook func(context.Context, DiscoveryMetric)
 }
 
 type responseStatus struct {
@@ -130,13 +155,71 @@ type getResourceRequest struct {
 	HTTPMethodType    string
 	Token             string
 	StatusPoll        bool
-	CreateSubcription func(context.Context, []string)
+	CreateSubcription func(context.Context, []string, *agmodel.EventServiceCapabilities)
 	PublishEvent      func(context.Context, []string, string)
 	GetPluginStatus   func(context.Context, agmodel.Plugin) bool
 	UpdateFlag        bool
 	TargetURI         string
 	UpdateTask        func(context.Context, common.TaskData) error
 	BMCAddress        string
+	// NorthBoundHeaders holds the headers of the northbound request, only the ones
+	// in the HeaderAllowList are forwarded to the plugin
+	NorthBoundHeaders map[string]string
+	HeaderAllowList   []string
+	// TenantID holds the tenant of the request, it is taken from the context when it is empty
+	TenantID string
+	// Sessions holds the XAuthToken sessions of the discovery run, the session of the plugin is
+	// preferred over the Token and it is re-authenticated when the plugin responds with 401
+	Sessions *pluginToken
+	// Operation selects the timeout of the plugin call
+	Operation pluginOperation
+	// DiscoveryMetricHook receives the timing data of each resource discovered, it is optional
+	DiscoveryMetricHook func(context.Context, DiscoveryMetric)
+	// DryRun stops the discovery of a system once it is checked for the duplicates, nothing is saved in DB
+	DryRun bool
+	// InFlightCalls limits the plugin calls in flight during the discovery run, it is shared by
+	// all the copies of the request and the calls are not limited when it is nil
+	InFlightCalls *discoveryCallLimiter
+	// Depth is the number of links followed by getResourceDetails to reach the resource,
+	// the links beyond the MaxTraversalDepth are not followed
+	Depth int
+	// ForceRegistryRefresh downloads the registries even when they exist in the registry store or in DB
+	ForceRegistryRefresh bool
+}
+
+// pluginOperation is the type of the operation done with a plugin call
+type pluginOperation int
+
+const (
+	// defaultOperation is a discovery for the GET calls and an action for the rest
+	defaultOperation pluginOperation = iota
+	statusOperation
+	discoveryOperation
+	actionOperation
+)
+
+// pluginOperation returns the operation done with the plugin call of the request
+func (req getResourceRequest) pluginOperation() pluginOperation {
+	if req.Operation != defaultOperation {
+		return req.Operation
+	}
+	if req.HTTPMethodType == http.MethodGet {
+		return discoveryOperation
+	}
+	return actionOperation
+}
+
+// getPluginTimeout returns the configured timeout of the operation done with the plugin call
+func getPluginTimeout(req getResourceRequest) time.Duration {
+	timeouts := config.Data.PluginTimeoutConf
+	switch req.pluginOperation() {
+	case statusOperation:
+		return time.Duration(timeouts.StatusTimeoutInSecs) * time.Second
+	case discoveryOperation:
+		return time.Duration(timeouts.DiscoveryTimeoutInSecs) * time.Second
+	default:
+		return time.Duration(timeouts.ActionTimeoutInSecs) * time.Second
+	}
 }
 
 type respHolder struct {
@@ -149,14 +232,291 @@ type respHolder struct {
 	PluginResponse string
 	TraversedLinks map[string]bool
 	InventoryData  map[string]interface{}
+	Warnings       []string
+	Problems       []discoveryProblem
+	ServiceRoot    serviceRootInfo
+	// EventService holds the capabilities of the EventService of the device, nil when absent
+	EventService *agmodel.EventServiceCapabilities
+	// cancelled is set once the discovery is stopped for the context of the request
+	cancelled bool
+	// scope limits the link traversal to the resource with the OID and the resources under it,
+	// all the links are traversed when it is empty
+	scope string
+	// cancelRequestKey is the key of the cancellation marker of the add in the active-request store,
+	// it is polled with checkActiveRequest so that the discovery stops once the add is cancelled
+	cancelRequestKey   string
+	checkActiveRequest func(string) (bool, *errors.Error)
+	// cancelRequested is set once the cancellation marker of the add is found
+	cancelRequested bool
+	// cancelPolledAt is the time of the last poll of the cancellation marker
+	cancelPolledAt time.Time
+	// fetchesInProgress holds the resources being fetched by getResourceDetails,
+	// the channel of the resource is closed once its fetch is finished
+	fetchesInProgress map[string]chan struct{}
+}
+
+// serviceRootInfo holds the metadata of the ServiceRoot of the device
+type serviceRootInfo struct {
+	UUID           string
+	RedfishVersion string
+	Vendor         string
+	// Services are the names of the services linked with the ServiceRoot
+	Services []string
+}
+
+// discoveryProblem is a structured record of an error while discovering a resource, so that
+// the orchestration tools can decide the remediation for each of the resources
+type discoveryProblem struct {
+	OID        string `json:"OID"`
+	StatusCode int32  `json:"StatusCode"`
+	Message    string `json:"Message"`
+	Retryable  bool   `json:"Retryable"`
+}
+
+// problemReportResponse is the error response of the add along with the problem report
+type problemReportResponse struct {
+	response.CommonError
+	DiscoveryProblems []discoveryProblem `json:"DiscoveryProblems,omitempty"`
+}
+
+// addProblem records the error while discovering the resource. Caller should hold the lock.
+func (h *respHolder) addProblem(oid string, statusCode int32, message string) {
+	h.Problems = append(h.Problems, discoveryProblem{
+		OID:        oid,
+		StatusCode: statusCode,
+		Message:    message,
+		Retryable:  isRetryableStatus(statusCode),
+	})
+}
+
+// isRetryableStatus checks if the discovery of a resource failed with the status code could succeed on a retry
+func isRetryableStatus(statusCode int32) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// linkIntegrityReport summarizes the links advertised by the server which could not be fetched
+// while discovering it, the links not found are reported separately as they are usually the
+// bugs in the firmware of the server
+type linkIntegrityReport struct {
+	BrokenLinks      []string           `json:"BrokenLinks"`
+	UnreachableLinks []discoveryProblem `json:"UnreachableLinks"`
+}
+
+// getLinkIntegrityReport builds the link integrity report from the problems recorded while
+// discovering the server. The resources not discovered because the discovery is stopped are
+// not reported, as the server was never contacted for them
+func getLinkIntegrityReport(problems []discoveryProblem) linkIntegrityReport {
+	report := linkIntegrityReport{
+		BrokenLinks:      []string{},
+		UnreachableLinks: []discoveryProblem{},
+	}
+	reported := make(map[string]bool)
+	for _, problem := range problems {
+		if reported[problem.OID] || problem.StatusCode == discoveryCancelledStatus.StatusCode {
+			continue
+		}
+		reported[problem.OID] = true
+		if problem.StatusCode == http.StatusNotFound {
+			report.BrokenLinks = append(report.BrokenLinks, problem.OID)
+			continue
+		}
+		report.UnreachableLinks = append(report.UnreachableLinks, problem)
+	}
+	return report
+}
+
+// recordSubResourceError records the error while discovering a sub resource of the system.
+// 5xx errors are recorded as warnings when SubResourceErrorPolicy is Warn, so that the
+// discovery can continue, and true is returned for the same.
+func (h *respHolder) recordSubResourceError(ctx context.Context, oid string, getResponse responseStatus, err error) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.addProblem(oid, getResponse.StatusCode, err.Error())
+	if getResponse.StatusCode >= http.StatusInternalServerError &&
+		config.Data.DiscoveryConf.SubResourceErrorPolicy == config.SubResourceErrorPolicyWarn {
+		l.LogWithFields(ctx).Warn(err.Error())
+		h.Warnings = append(h.Warnings, err.Error())
+		return true
+	}
+	h.ErrorMessage = err.Error()
+	h.StatusMessage = getResponse.StatusMessage
+	h.MsgArgs = getResponse.MsgArgs
+	h.StatusCode = getResponse.StatusCode
+	return false
+}
+
+// discoveryCancelledStatus is the status of the discovery stopped when the context of the
+// request is done, it is not retryable and fails the discovery so that the partial data is rolled back
+var discoveryCancelledStatus = responseStatus{
+	StatusCode:    http.StatusRequestTimeout,
+	StatusMessage: response.GeneralError,
+}
+
+// errAddCancelled is the reason of the discovery stopped when the add is cancelled by the user
+var errAddCancelled = fmt.Errorf("add is cancelled")
+
+// checkCancelled returns the error when the context of the request is cancelled or its deadline
+// is exceeded, or the add is cancelled, so that the discovery stops before contacting the plugin
+// for the resource. The error is recorded for the first resource which is not discovered.
+func (h *respHolder) checkCancelled(ctx context.Context, oid string) error {
+	reason := ctx.Err()
+	if reason == nil && h.isCancelRequested() {
+		reason = errAddCancelled
+	}
+	if reason == nil {
+		return nil
+	}
+	err := fmt.Errorf("discovery is stopped at %s: %v", oid, reason)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if !h.cancelled {
+		h.cancelled = true
+		h.addProblem(oid, discoveryCancelledStatus.StatusCode, err.Error())
+		h.ErrorMessage = err.Error()
+		h.StatusMessage = discoveryCancelledStatus.StatusMessage
+		h.StatusCode = discoveryCancelledStatus.StatusCode
+		h.MsgArgs = nil
+	}
+	return err
+}
+
+// isCancelRequested polls the active-request store for the cancellation marker of the add,
+// at most once in cancelPollInterval. The marker isn't polled again once it is found
+func (h *respHolder) isCancelRequested() bool {
+	if h.cancelRequestKey == "" || h.checkActiveRequest == nil {
+		return false
+	}
+	h.lock.Lock()
+	if h.cancelRequested {
+		h.lock.Unlock()
+		return true
+	}
+	if time.Since(h.cancelPolledAt) < cancelPollInterval {
+		h.lock.Unlock()
+		return false
+	}
+	h.cancelPolledAt = time.Now()
+	h.lock.Unlock()
+	cancelRequested, err := h.checkActiveRequest(h.cancelRequestKey)
+	if err != nil || !cancelRequested {
+		return false
+	}
+	h.lock.Lock()
+	h.cancelRequested = true
+	h.lock.Unlock()
+	return true
+}
+
+// recordMalformedResponse records the error for the response of the resource which doesn't
+// have the expected structure, so that the resource is skipped instead of crashing the service
+func (h *respHolder) recordMalformedResponse(oid, message string) error {
+	err := fmt.Errorf("malformed response of %s from plugin: %s", oid, message)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.addProblem(oid, http.StatusInternalServerError, err.Error())
+	h.ErrorMessage = err.Error()
+	h.StatusMessage = response.InternalError
+	h.StatusCode = http.StatusInternalServerError
+	return err
+}
+
+// describeInvalidProperty describes why the value of the property isn't a valid string
+func describeInvalidProperty(name string, value interface{}) string {
+	if value == nil {
+		return name + " is missing"
+	}
+	if str, ok := value.(string); ok && str == "" {
+		return name + " is empty"
+	}
+	return name + " is not a string"
+}
+
+// inScope checks if the resource with the OID is to be discovered in the scope of the discovery
+func (h *respHolder) inScope(oid string) bool {
+	if h.scope == "" {
+		return true
+	}
+	oid = strings.TrimSuffix(oid, "/")
+	return oid == h.scope || strings.HasPrefix(oid, h.scope+"/")
+}
+
+// addResourceTypeIndex adds the @odata.type of the resource to the inventory data, so that it
+// is indexed with the key of the resource. Caller should hold the lock when required.
+func (h *respHolder) addResourceTypeIndex(resource map[string]interface{}, oidKey string) {
+	if odataType, ok := resource["@odata.type"].(string); ok && odataType != "" {
+		h.InventoryData[agmodel.ResourceTypeTable+":"+oidKey] = odataType
+	}
+}
+
+// startFetch marks the resource as traversed and being fetched. When the resource is already
+// being fetched, false is returned along with the channel closed once that fetch is finished,
+// so that the caller reuses its result instead of contacting the plugin again.
+// Caller should hold the lock.
+func (h *respHolder) startFetch(oid string) (<-chan struct{}, bool) {
+	if done, ok := h.fetchesInProgress[oid]; ok {
+		return done, false
+	}
+	if h.fetchesInProgress == nil {
+		h.fetchesInProgress = make(map[string]chan struct{})
+	}
+	h.fetchesInProgress[oid] = make(chan struct{})
+	h.TraversedLinks[oid] = true
+	return nil, true
+}
+
+// exceedsTraversalDepth checks if the link of the resource of the request is beyond the
+// MaxTraversalDepth, a warning is recorded for the link which is not followed.
+// The link isn't marked traversed, so that it is still discovered when reached by a shorter path.
+// Caller should hold the lock.
+func (h *respHolder) exceedsTraversalDepth(ctx context.Context, oid string, req getResourceRequest) bool {
+	maxDepth := config.Data.DiscoveryConf.MaxTraversalDepth
+	if maxDepth <= 0 || req.Depth < maxDepth {
+		return false
+	}
+	warning := fmt.Sprintf("link %s of %s is not discovered, since it is beyond the maximum traversal depth %d", oid, req.OID, maxDepth)
+	l.LogWithFields(ctx).Warn(warning)
+	h.Warnings = append(h.Warnings, warning)
+	return true
+}
+
+// finishFetch marks the fetch of the resource started with startFetch as finished
+func (h *respHolder) finishFetch(oid string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if done, ok := h.fetchesInProgress[oid]; ok {
+		close(done)
+		delete(h.fetchesInProgress, oid)
+	}
+}
+
+// hasFatalError checks if the error recorded while discovering the resources should fail the discovery
+func (h *respHolder) hasFatalError() bool {
+	if h.ErrorMessage == "" {
+		return false
+	}
+	if h.StatusCode >= http.StatusInternalServerError &&
+		config.Data.DiscoveryConf.SubResourceErrorPolicy == config.SubResourceErrorPolicyFail {
+		return true
+	}
+	return h.StatusCode != http.StatusServiceUnavailable && h.StatusCode != http.StatusNotFound &&
+		h.StatusCode != http.StatusInternalServerError && h.StatusCode != http.StatusBadRequest
 }
 
 // AddResourceRequest is payload of adding a  resource
 type AddResourceRequest struct {
-	ManagerAddress   string            `json:"ManagerAddress"`
-	UserName         string            `json:"UserName"`
-	Password         string            `json:"Password"`
-	ConnectionMethod *ConnectionMethod `json:"ConnectionMethod"`
+	ManagerAddress       string            `json:"ManagerAddress"`
+	UserName             string            `json:"UserName"`
+	Password             string            `json:"Password"`
+	ConnectionMethod     *ConnectionMethod `json:"ConnectionMethod"`
+	ForceBasicAuth       bool              `json:"ForceBasicAuth,omitempty"`
+	DryRun               bool              `json:"DryRun,omitempty"`
+	ForceRegistryRefresh bool              `json:"ForceRegistryRefresh,omitempty"`
+	PreviewResources     bool              `json:"PreviewResources,omitempty"`
 }
 
 // ConnectionMethod struct definition for @odata.id
@@ -183,15 +543,48 @@ type ActiveRequestsSet struct {
 	UpdateMu sync.Mutex
 }
 
+// statusStates and statusHealthValues are the Redfish defined values of
+// Status/State and Status/Health(Rollup) used while indexing the systems
+var statusStates = []string{"Enabled", "Disabled", "StandbyOffline", "StandbySpare", "InTest", "Starting", "Absent", "UnavailableOffline", "Deferring", "Quiesced", "Updating", "Qualified"}
+var statusHealthValues = []string{"OK", "Warning", "Critical"}
+
+// authHeaders are the headers which are never forwarded from the northbound request to the plugin
+var authHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"X-Auth-Token":        true,
+	"Cookie":              true,
+}
+
 var southBoundURL = "southboundurl"
 var northBoundURL = "northboundurl"
 
+// CheckEMBQueueAvailability function pointer for the agmessagebus.CheckQueueAvailability
+var CheckEMBQueueAvailability = agmessagebus.CheckQueueAvailability
+
 // AggregationSource  payload of adding a  AggregationSource
 type AggregationSource struct {
-	HostName string `json:"HostName"`
-	UserName string `json:"UserName"`
-	Password string `json:"Password"`
-	Links    *Links `json:"Links,omitempty"`
+	HostName string                `json:"HostName"`
+	UserName string                `json:"UserName"`
+	Password string                `json:"Password"`
+	Links    *Links                `json:"Links,omitempty"`
+	Oem      *AggregationSourceOem `json:"Oem,omitempty"`
+}
+
+// AggregationSourceOem holds the Oem properties of the aggregation source request
+type AggregationSourceOem struct {
+	// ForceBasicAuth forces the plugin added to be contacted with BasicAuth even when
+	// the PreferredAuthType is XAuthToken, to work around the buggy session handling
+	ForceBasicAuth bool `json:"ForceBasicAuth,omitempty"`
+	// DryRun only validates the aggregation source, nothing is saved in DB
+	DryRun bool `json:"DryRun,omitempty"`
+	// ForceRegistryRefresh downloads the registries of the server again and overwrites the
+	// ones in DB, so that a registry fixed by the vendor is taken. The existing registries
+	// are skipped by default
+	ForceRegistryRefresh bool `json:"ForceRegistryRefresh,omitempty"`
+	// PreviewResources returns the tree of the resources exposed by the server in the summary
+	// of the dry run, it is honored only along with DryRun
+	PreviewResources bool `json:"PreviewResources,omitempty"`
 }
 
 // Links holds information of Oem
@@ -219,25 +612,128 @@ type monitorTaskRequest struct {
 	resp              response.RPC
 }
 
+// monitorTaskResult holds the final status and the final task body of the plugin task
+type monitorTaskResult struct {
+	responseStatus
+	taskBody map[string]interface{}
+}
+
+// finalMessage returns the messages reported by the device in the final task body
+func (r monitorTaskResult) finalMessage() string {
+	messages, _ := r.taskBody["Messages"].([]interface{})
+	var finalMessages []string
+	for _, message := range messages {
+		messageInfo, _ := message.(map[string]interface{})
+		if msg, _ := messageInfo["Message"].(string); msg != "" {
+			finalMessages = append(finalMessages, msg)
+		}
+	}
+	return strings.Join(finalMessages, " ")
+}
+
+// getIPAndPortFromAddress splits the manager address into the host and the port. The address can be
+// an IPv4 address or a host name with an optional port, a bare IPv6 address, or an IPv6 address in
+// the brackets with an optional port. The IPv6 addresses can have a zone, like fe80::1%eth0.
+// The host is returned without the brackets, and the port is empty when the address doesn't have one.
 func getIPAndPortFromAddress(address string) (string, string) {
-	ip, port, err := net.SplitHostPort(address)
-	if err != nil {
-		ip = address
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "[") {
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			return host, port
+		}
+		return strings.TrimSuffix(strings.TrimPrefix(address, "["), "]"), ""
 	}
-	return ip, port
+	// the last group of a bare IPv6 address is not a port
+	if strings.Count(address, ":") > 1 {
+		return address, ""
+	}
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		return host, port
+	}
+	return address, ""
 }
 
-func getKeyFromManagerAddress(managerAddress string) string {
-	ipAddr, host, port, err := agcommon.LookupHost(managerAddress)
-	if err != nil {
-		ipAddr = host
+// isIPAddress checks the host is an IPv4 or an IPv6 address, the zone of the IPv6 address is ignored
+func isIPAddress(host string) bool {
+	if index := strings.Index(host, "%"); index > 0 {
+		host = host[:index]
+	}
+	return net.ParseIP(host) != nil
+}
+
+// getURLHost returns the host in the form used in the URLs, the IPv6 addresses are enclosed
+// in the brackets and the % of their zone is escaped
+func getURLHost(host string) string {
+	if !strings.Contains(host, ":") {
+		return host
 	}
+	return "[" + strings.Replace(host, "%", "%25", 1) + "]"
+}
+
+// getKeyFromManagerAddress returns the key of the manager address used in DB. The addresses with
+// the port are keyed with the host and the port, the rest with the IP address of the host.
+func getKeyFromManagerAddress(managerAddress string) string {
+	host, port := getIPAndPortFromAddress(managerAddress)
 	if port != "" {
 		return net.JoinHostPort(host, port)
 	}
+	// the IP addresses are used as they are, so that the zone of the IPv6 address is retained
+	if isIPAddress(host) {
+		return host
+	}
+	ipAddr, _, _, err := agcommon.LookupHost(host)
+	if err != nil {
+		return host
+	}
 	return ipAddr
 }
 
+// getCancelRequestKey returns the key of the cancellation marker of the active add request
+func getCancelRequestKey(activeRequestKey string) string {
+	return activeRequestKey + agmodel.CancelledActiveRequestSuffix
+}
+
+// workShares splits the alotted work of a collection among its members. The remainder of the
+// division is handed out to the members, so that the shares always add up to the alotted work
+// even when the collection has more members than the alotted work
+type workShares struct {
+	alottedWork int64
+	count       int64
+	index       int64
+}
+
+func newWorkShares(alottedWork int32, count int) *workShares {
+	return &workShares{alottedWork: int64(alottedWork), count: int64(count)}
+}
+
+// next returns the share of the next member, it is zero once all the members got their share
+func (w *workShares) next() int32 {
+	if w.count <= 0 || w.index >= w.count {
+		return 0
+	}
+	w.index++
+	return int32(w.alottedWork*w.index/w.count - w.alottedWork*(w.index-1)/w.count)
+}
+
+// rest returns the work which is not handed out yet, it is the whole alotted work for an empty collection
+func (w *workShares) rest() int32 {
+	if w.count <= 0 {
+		return int32(w.alottedWork)
+	}
+	return int32(w.alottedWork - w.alottedWork*w.index/w.count)
+}
+
+// clampProgress bounds the progress of the task to the range of 0 to 100 percent
+func clampProgress(percentComplete int32) int32 {
+	if percentComplete < 0 {
+		return 0
+	}
+	if percentComplete > 100 {
+		return 100
+	}
+	return percentComplete
+}
+
 func fillTaskData(taskID, targetURI, request string, resp response.RPC, taskState string, taskStatus string, percentComplete int32, httpMethod string) common.TaskData {
 	return common.TaskData{
 		TaskID:          taskID,
@@ -246,7 +742,7 @@ func fillTaskData(taskID, targetURI, request string, resp response.RPC, taskStat
 		Response:        resp,
 		TaskState:       taskState,
 		TaskStatus:      taskStatus,
-		PercentComplete: percentComplete,
+		PercentComplete: clampProgress(percentComplete),
 		HTTPMethod:      httpMethod,
 	}
 }
@@ -262,6 +758,12 @@ func genError(ctx context.Context, errorMessage string, respPtr *response.RPC, h
 
 // UpdateTaskData update the task with the given data
 func UpdateTaskData(ctx context.Context, taskData common.TaskData) error {
+	taskData.PercentComplete = clampProgress(taskData.PercentComplete)
+	taskData, ok := inFlightTasks.track(taskData)
+	if !ok {
+		l.LogWithFields(ctx).Debug("ignoring the update of the interrupted task " + taskData.TaskID)
+		return nil
+	}
 	var res map[string]interface{}
 	if taskData.TaskRequest != "" {
 		r := strings.NewReader(taskData.TaskRequest)
@@ -299,29 +801,53 @@ func UpdateTaskData(ctx context.Context, taskData common.TaskData) error {
 
 func contactPlugin(ctx context.Context, req getResourceRequest, errorMessage string) ([]byte, string, responseStatus, error) {
 	var resp responseStatus
-	pluginResp, err := callPlugin(ctx, req)
-	if err != nil {
-		if req.StatusPoll {
-			if req.GetPluginStatus(ctx, req.Plugin) {
-				pluginResp, err = callPlugin(ctx, req)
-			}
-		}
-		if err != nil {
+	usedToken := req.sessionToken()
+	// the discovery calls are paced to back off when the device is under pressure
+	discoveryCall := req.pluginOperation() == discoveryOperation
+	if discoveryCall {
+		if err := discoveryRateLimiter.wait(ctx, req.Plugin); err != nil {
 			errorMessage = errorMessage + err.Error()
 			resp.StatusCode = http.StatusServiceUnavailable
 			resp.StatusMessage = response.CouldNotEstablishConnection
 			resp.MsgArgs = []interface{}{"https://" + req.Plugin.IP + ":" + req.Plugin.Port + req.OID}
-			return nil, "", resp, fmt.Errorf(errorMessage)
+			return nil, "", resp, newPluginError(errorMessage, resp, err, ErrPluginUnreachable)
 		}
 	}
-
-	defer pluginResp.Body.Close()
-	body, err := ioutil.ReadAll(pluginResp.Body)
-	if err != nil {
-		errorMessage := "error while trying to read plugin response body: " + err.Error()
-		resp.StatusCode = http.StatusInternalServerError
-		resp.StatusMessage = response.InternalError
-		return nil, "", resp, fmt.Errorf(errorMessage)
+	if err := req.InFlightCalls.acquire(ctx); err != nil {
+		errorMessage = errorMessage + err.Error()
+		resp.StatusCode = http.StatusServiceUnavailable
+		resp.StatusMessage = response.CouldNotEstablishConnection
+		resp.MsgArgs = []interface{}{"https://" + req.Plugin.IP + ":" + req.Plugin.Port + req.OID}
+		return nil, "", resp, newPluginError(errorMessage, resp, err, ErrPluginUnreachable)
+	}
+	// the slot is released as soon as the response is read, so that the plugin contacted
+	// again for the session refresh and the links of the response don't wait on it
+	released := false
+	releaseCall := func() {
+		if !released {
+			released = true
+			req.InFlightCalls.release()
+		}
+	}
+	defer releaseCall()
+	translations := getTranslationURL(northBoundURL, req.Plugin.ID)
+	pluginResp, body, translated, contactErr := callAndReadPlugin(ctx, req, errorMessage, translations)
+	releaseCall()
+	if contactErr != nil {
+		return nil, "", contactErr.status(), contactErr
+	}
+	auditPluginResponse(ctx, req, pluginResp.StatusCode, body)
+
+	// the session expired during the discovery run is re-authenticated and the plugin is contacted again
+	if pluginResp.StatusCode == http.StatusUnauthorized && req.canRefreshSession() {
+		token, err := req.Sessions.refreshToken(ctx, req, usedToken)
+		if err == nil {
+			l.LogWithFields(ctx).Info("session with the plugin " + req.Plugin.ID + " is re-authenticated while contacting " + req.OID)
+			req.Token = token
+			req.Sessions = nil
+			return contactPlugin(ctx, req, errorMessage)
+		}
+		l.LogWithFields(ctx).Warn("error while re-authenticating the session with the plugin " + req.Plugin.ID + ": " + err.Error())
 	}
 
 	if pluginResp.StatusCode != http.StatusCreated && pluginResp.StatusCode != http.StatusOK && pluginResp.StatusCode != http.StatusAccepted {
@@ -330,29 +856,38 @@ func contactPlugin(ctx context.Context, req getResourceRequest, errorMessage str
 			resp.StatusCode = int32(pluginResp.StatusCode)
 			resp.StatusMessage = response.ResourceAtURIUnauthorized
 			resp.MsgArgs = []interface{}{"https://" + req.Plugin.IP + ":" + req.Plugin.Port + req.OID}
-			return nil, "", resp, fmt.Errorf(errorMessage)
+			return nil, "", resp, newPluginError(errorMessage, resp, nil, ErrAuth)
 		}
-		errorMessage += string(body)
+		errorMessage += getLoggableBody(body)
 		resp.StatusCode = int32(pluginResp.StatusCode)
 		resp.StatusMessage = response.InternalError
-		return body, "", resp, fmt.Errorf(errorMessage)
+		kinds := []error{ErrDeviceError}
+		if strings.Contains(string(body), errors.SystemNotSupportedErrString) {
+			kinds = append(kinds, ErrSystemNotSupported)
+		}
+		return body, "", resp, newPluginError(errorMessage, resp, nil, kinds...)
+	}
+	if req.HTTPMethodType == http.MethodGet {
+		getDiscoveryMetrics(ctx).addResource(len(body))
 	}
 
-	data := string(body)
-	//replacing the resposne with north bound translation URL
-	for key, value := range getTranslationURL(northBoundURL) {
-		data = strings.Replace(data, key, value, -1)
+	// the large responses are translated while being read
+	if !translated {
+		//replacing the resposne with north bound translation URL
+		body = []byte(translateURL(string(body), translations))
 	}
 	// Get location from the header if status code is status accepted
 	if pluginResp.StatusCode == http.StatusAccepted {
-		return []byte(data), pluginResp.Header.Get("Location"), resp, nil
+		return body, pluginResp.Header.Get("Location"), resp, nil
 	}
 
 	resp.StatusCode = int32(pluginResp.StatusCode)
-	return []byte(data), pluginResp.Header.Get("X-Auth-Token"), resp, nil
+	return body, pluginResp.Header.Get("X-Auth-Token"), resp, nil
 }
 
 // keyFormation is to form the key to insert in DB
+// The member following a collection in the KeyPrefixParents is prefixed with the device UUID,
+// only when it is the resource ID for the collections with ResourceIDOnly set
 func keyFormation(oid, systemID, DeviceUUID string) string {
 	if oid[len(oid)-1:] == "/" {
 		oid = oid[:len(oid)-1]
@@ -360,11 +895,7 @@ func keyFormation(oid, systemID, DeviceUUID string) string {
 	str := strings.Split(oid, "/")
 	var key []string
 	for i, id := range str {
-		if id == systemID && (strings.EqualFold(str[i-1], "Systems") || strings.EqualFold(str[i-1], "Chassis") || strings.EqualFold(str[i-1], "Managers") || strings.EqualFold(str[i-1], "FirmwareInventory") || strings.EqualFold(str[i-1], "SoftwareInventory")) {
-			key = append(key, DeviceUUID+"."+id)
-			continue
-		}
-		if i != 0 && strings.EqualFold(str[i-1], "Licenses") {
+		if i != 0 && isKeyPrefixParent(str[i-1], id == systemID) {
 			key = append(key, DeviceUUID+"."+id)
 			continue
 		}
@@ -373,8 +904,22 @@ func keyFormation(oid, systemID, DeviceUUID string) string {
 	return strings.Join(key, "/")
 }
 
+// isKeyPrefixParent checks the collection is configured in the KeyPrefixParents for the member,
+// isResourceID tells the member is the ID of the resource the key is formed for
+func isKeyPrefixParent(collection string, isResourceID bool) bool {
+	for _, parent := range config.Data.DiscoveryConf.KeyPrefixParents {
+		if strings.EqualFold(collection, parent.Name) && (isResourceID || !parent.ResourceIDOnly) {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *respHolder) getAllSystemInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) (string, string, int32, error) {
 	var computeSystemID, resourceURI string
+	if err := h.checkCancelled(ctx, req.OID); err != nil {
+		return computeSystemID, resourceURI, progress, err
+	}
 	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get system collection details: ")
 	if err != nil {
 		h.lock.Lock()
@@ -390,7 +935,7 @@ func (h *respHolder) getAllSystemInfo(ctx context.Context, taskID string, progre
 	h.SystemURL = make([]string, 0)
 	h.PluginResponse = string(body)
 	systemsMap := make(map[string]interface{})
-	err = json.Unmarshal([]byte(body), &systemsMap)
+	err = decodeJSON(body, &systemsMap)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal systems collection: " + err.Error()
@@ -400,14 +945,32 @@ func (h *respHolder) getAllSystemInfo(ctx context.Context, taskID string, progre
 		l.LogWithFields(ctx).Error("error while trying unmarshal systems collection: " + err.Error())
 		return computeSystemID, resourceURI, progress, err
 	}
-	systemMembers := systemsMap["Members"]
+	// the collection without the Members is treated as the one without any systems
+	systemMembers, ok := getCollectionMembers(systemsMap)
+	if !ok {
+		err = h.recordMalformedResponse(req.OID, "Members is not an array")
+		l.LogWithFields(ctx).Error(err)
+		return computeSystemID, resourceURI, progress, err
+	}
+	if len(systemMembers) == 0 {
+		l.LogWithFields(ctx).Warn("no systems found in the system collection of the server")
+		return computeSystemID, resourceURI, progress + alottedWork, nil
+	}
 	// Loop through System collection members and discover all of them
 	errorMessage := "error : get system collection members failed for ["
 	foundErr := false
-	for _, object := range systemMembers.([]interface{}) {
-		estimatedWork := alottedWork / int32(len(systemMembers.([]interface{})))
-		oDataID := object.(map[string]interface{})["@odata.id"].(string)
-		oDataID = strings.TrimSuffix(oDataID, "/")
+	shares := newWorkShares(alottedWork, len(systemMembers))
+	for _, object := range systemMembers {
+		estimatedWork := shares.next()
+		oDataID, ok := getMemberODataID(object)
+		if !ok {
+			l.LogWithFields(ctx).Error(h.recordMalformedResponse(req.OID, fmt.Sprintf("skipping the member %v without @odata.id", object)))
+			progress = progress + estimatedWork
+			continue
+		}
+		if err := h.checkCancelled(ctx, oDataID); err != nil {
+			return computeSystemID, resourceURI, progress, err
+		}
 		req.OID = oDataID
 		if computeSystemID, resourceURI, progress, err = h.getSystemInfo(ctx, taskID, progress, estimatedWork, req); err != nil {
 			errorMessage += oDataID + ":err-" + err.Error() + "; "
@@ -420,8 +983,147 @@ func (h *respHolder) getAllSystemInfo(ctx context.Context, taskID string, progre
 	return computeSystemID, resourceURI, progress, nil
 }
 
+// getServiceRootInfo discovers the ServiceRoot of the device and adds it to the inventory data.
+// The ServiceRoot is optional for the discovery, so the failure is only recorded as a problem
+// and the properties absent in the minimal ServiceRoots are left empty.
+func (h *respHolder) getServiceRootInfo(ctx context.Context, req getResourceRequest) {
+	req.OID = "/redfish/v1"
+	req.HTTPMethodType = http.MethodGet
+	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get the service root: ")
+	if err != nil {
+		l.LogWithFields(ctx).Warn(err.Error())
+		h.lock.Lock()
+		h.addProblem(req.OID, getResponse.StatusCode, err.Error())
+		h.lock.Unlock()
+		return
+	}
+	var serviceRoot map[string]interface{}
+	if err := decodeJSON(body, &serviceRoot); err != nil {
+		l.LogWithFields(ctx).Warn("error while trying to unmarshal the service root: " + err.Error())
+		return
+	}
+	var info serviceRootInfo
+	info.UUID, _ = serviceRoot["UUID"].(string)
+	info.RedfishVersion, _ = serviceRoot["RedfishVersion"].(string)
+	info.Vendor, _ = serviceRoot["Vendor"].(string)
+	for name, value := range serviceRoot {
+		if link, ok := value.(map[string]interface{}); ok && link["@odata.id"] != nil {
+			info.Services = append(info.Services, name)
+		}
+	}
+	sort.Strings(info.Services)
+	h.lock.Lock()
+	h.ServiceRoot = info
+	h.InventoryData["ServiceRoot:"+serviceRootKey(req.DeviceUUID)] = string(body)
+	h.lock.Unlock()
+}
+
+// getEventServiceInfo discovers the EventService of the device to record its capabilities,
+// so that the default subscriptions can be tailored for the device. The EventService is optional,
+// the default subscriptions are created with the defaults when it is absent.
+func (h *respHolder) getEventServiceInfo(ctx context.Context, req getResourceRequest) {
+	req.OID = "/redfish/v1/EventService"
+	req.HTTPMethodType = http.MethodGet
+	body, _, _, err := contactPlugin(ctx, req, "error while trying to get the event service: ")
+	if err != nil {
+		l.LogWithFields(ctx).Info("EventService of the device is not discovered, default subscriptions are created with the defaults: " + err.Error())
+		return
+	}
+	var eventService map[string]interface{}
+	if err := decodeJSON(body, &eventService); err != nil {
+		l.LogWithFields(ctx).Warn("error while trying to unmarshal the event service: " + err.Error())
+		return
+	}
+	var capabilities agmodel.EventServiceCapabilities
+	if attempts, ok := toFloat64(eventService["DeliveryRetryAttempts"]); ok {
+		capabilities.DeliveryRetryAttempts = int(attempts)
+	}
+	if interval, ok := toFloat64(eventService["DeliveryRetryIntervalSeconds"]); ok {
+		capabilities.DeliveryRetryIntervalSeconds = int(interval)
+	}
+	if eventTypes, ok := eventService["EventTypesForSubscription"].([]interface{}); ok {
+		for _, eventType := range eventTypes {
+			if eventType, ok := eventType.(string); ok {
+				capabilities.EventTypesForSubscription = append(capabilities.EventTypesForSubscription, eventType)
+			}
+		}
+	}
+	sseURI, _ := eventService["ServerSentEventUri"].(string)
+	capabilities.SSESupported = sseURI != ""
+	h.lock.Lock()
+	h.EventService = &capabilities
+	h.lock.Unlock()
+}
+
+// serviceRootKey returns the key of the ServiceRoot of the device in the inventory
+func serviceRootKey(deviceUUID string) string {
+	return "/redfish/v1/ServiceRoot/" + deviceUUID
+}
+
+// crossCheckServiceRootUUID verifies the UUID of the ServiceRoot against the ServiceEntryPointUUID
+// of the managers discovered, a mismatch is recorded as a warning since the device answering the
+// ServiceRoot may not be the one managing the resources discovered
+func (h *respHolder) crossCheckServiceRootUUID(ctx context.Context) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.ServiceRoot.UUID == "" {
+		return
+	}
+	var entryPointUUIDs []string
+	for key, data := range h.InventoryData {
+		memberData, ok := data.(string)
+		if !strings.HasPrefix(key, "Managers:") || !ok {
+			continue
+		}
+		var manager map[string]interface{}
+		if err := json.Unmarshal([]byte(memberData), &manager); err != nil {
+			continue
+		}
+		if entryPointUUID, _ := manager["ServiceEntryPointUUID"].(string); entryPointUUID != "" {
+			if strings.EqualFold(entryPointUUID, h.ServiceRoot.UUID) {
+				return
+			}
+			entryPointUUIDs = append(entryPointUUIDs, entryPointUUID)
+		}
+	}
+	if len(entryPointUUIDs) == 0 {
+		return
+	}
+	warning := fmt.Sprintf("UUID %s of the service root doesn't match the ServiceEntryPointUUID %v of the managers",
+		h.ServiceRoot.UUID, entryPointUUIDs)
+	l.LogWithFields(ctx).Warn(warning)
+	h.Warnings = append(h.Warnings, warning)
+}
+
+// getMemberODataID returns the @odata.id of a collection member without the trailing slash.
+// false is returned if the member is not an object or the @odata.id is absent, null or not a string.
+func getMemberODataID(member interface{}) (string, bool) {
+	memberMap, ok := member.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	oDataID, ok := memberMap["@odata.id"].(string)
+	if !ok || oDataID == "" {
+		return "", false
+	}
+	return strings.TrimSuffix(oDataID, "/"), true
+}
+
+// getCollectionMembers returns the Members of a collection. The collection without
+// Members or with null Members has no members, false is returned if Members is not an array.
+func getCollectionMembers(collection map[string]interface{}) ([]interface{}, bool) {
+	if collection["Members"] == nil {
+		return nil, true
+	}
+	members, ok := collection["Members"].([]interface{})
+	return members, ok
+}
+
 // Registries Discovery function
 func (h *respHolder) getAllRegistries(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) int32 {
+	if h.checkCancelled(ctx, req.OID) != nil {
+		return progress
+	}
 
 	// Get all available file names in the registry store directory in a list
 	registryStore := config.Data.RegistryStorePath
@@ -447,7 +1149,7 @@ func (h *respHolder) getAllRegistries(ctx context.Context, taskID string, progre
 		return progress
 	}
 	registriesMap := make(map[string]interface{})
-	err = json.Unmarshal([]byte(body), &registriesMap)
+	err = decodeJSON(body, &registriesMap)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal Registries collection: " + err.Error()
@@ -458,20 +1160,22 @@ func (h *respHolder) getAllRegistries(ctx context.Context, taskID string, progre
 		return progress
 
 	}
-	registriesMembers := registriesMap["Members"]
+	registriesMembers, ok := registriesMap["Members"].([]interface{})
+	if !ok {
+		// the registries are not needed for the discovery of the server, so they are skipped
+		l.LogWithFields(ctx).Warn("skipping the registries of " + req.OID + ", Members is not an array")
+		return progress + alottedWork
+	}
 	// Loop through all the registry members collection and discover all of them
-	for _, object := range registriesMembers.([]interface{}) {
-		estimatedWork := alottedWork / int32(len(registriesMembers.([]interface{})))
-		if object == nil {
-			progress = progress + estimatedWork
-			continue
-		}
-		oDataIDInterface := object.(map[string]interface{})["@odata.id"]
-		if oDataIDInterface == nil {
+	shares := newWorkShares(alottedWork, len(registriesMembers))
+	for _, object := range registriesMembers {
+		estimatedWork := shares.next()
+		oDataID, ok := getMemberODataID(object)
+		if !ok {
+			l.LogWithFields(ctx).Warn(fmt.Sprintf("skipping the registry member %v of %s without @odata.id", object, req.OID))
 			progress = progress + estimatedWork
 			continue
 		}
-		oDataID := oDataIDInterface.(string)
 		req.OID = oDataID
 		progress = h.getRegistriesInfo(ctx, taskID, progress, estimatedWork, standardFiles, req)
 	}
@@ -489,7 +1193,7 @@ func (h *respHolder) getRegistriesInfo(ctx context.Context, taskID string, progr
 		return progress
 	}
 	var registryFileInfo map[string]interface{}
-	err = json.Unmarshal(body, &registryFileInfo)
+	err = decodeJSON(body, &registryFileInfo)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal response body: " + err.Error()
@@ -498,7 +1202,6 @@ func (h *respHolder) getRegistriesInfo(ctx context.Context, taskID string, progr
 		h.lock.Unlock()
 		return progress
 	}
-	uri := ""
 	/* '#' charactor in the begining of the registryfile name is giving some issue
 	* during api routing. So getting Id instead of Registry name if it has '#' char as a
 	* prefix.
@@ -508,35 +1211,36 @@ func (h *respHolder) getRegistriesInfo(ctx context.Context, taskID string, progr
 	if registryNameInterface == nil {
 		return progress + allotedWork
 	}
-	registryName := registryNameInterface.(string)
-	if strings.HasPrefix(registryName, "#") {
-		registryName = registryFileInfo["Id"].(string)
-	}
-	// Check if file not exist go get ut and store in DB
-	if isFileExist(standardFiles, registryName+".json") == true {
+	registryName, ok := registryNameInterface.(string)
+	if !ok {
+		l.LogWithFields(ctx).Warn(fmt.Sprintf("skipping the registry %s, Registry %v is not a string", req.OID, registryNameInterface))
 		return progress + allotedWork
 	}
-	locations := registryFileInfo["Location"]
-	for _, location := range locations.([]interface{}) {
-		if location == nil {
-			continue
+	if strings.HasPrefix(registryName, "#") {
+		registryName, ok = registryFileInfo["Id"].(string)
+		if !ok || registryName == "" {
+			l.LogWithFields(ctx).Warn("skipping the registry " + req.OID + ", " + describeInvalidProperty("Id", registryFileInfo["Id"]))
+			return progress + allotedWork
 		}
-		languageInterface := location.(map[string]interface{})["Language"]
-		if languageInterface == nil {
-			continue
+	}
+	if req.ForceRegistryRefresh {
+		// the registry refreshed by a discovery in progress is not downloaded again
+		done, started := registryRefreshes.start(registryName + ".json")
+		if !started {
+			<-done
+			return progress + allotedWork
 		}
-		language := languageInterface.(string)
-		if language == "en" {
-			uriInterface := location.(map[string]interface{})["Uri"]
-			//if  Uri object type is map then we skip, as we dont know how to proceed
-			// with processing the document.
-			if reflect.ValueOf(uriInterface).Kind() == reflect.Map {
-				continue
-			}
-			if uriInterface != nil {
-				uri = uriInterface.(string)
-			}
-			break
+		defer registryRefreshes.finish(registryName + ".json")
+	} else if isFileExist(standardFiles, registryName+".json") == true {
+		// Check if file not exist go get ut and store in DB
+		return progress + allotedWork
+	}
+	locations, _ := registryFileInfo["Location"].([]interface{})
+	uri, languageFound := getRegistryLocationURI(locations, config.Data.DiscoveryConf.RegistryLanguages)
+	if uri == "" && !languageFound && *config.Data.DiscoveryConf.LanguagelessRegistries {
+		uri = getLanguagelessRegistryURI(locations)
+		if uri != "" {
+			l.LogWithFields(ctx).Info("Language is not available in the locations of the registry " + registryName + ", taking the registry file from " + uri)
 		}
 	}
 	if uri == "" {
@@ -550,12 +1254,75 @@ func (h *respHolder) getRegistriesInfo(ctx context.Context, taskID string, progr
 		return progress + allotedWork
 	}
 	req.OID = uri
+	// the refreshed registry is staged in the inventory data and overwrites
+	// the existing one once the inventory is saved
 	h.getRegistryFile(ctx, registryName, req)
 	// File already exist retrun progress here
 	return progress + allotedWork
 
 }
 
+// getRegistryLocationURI returns the Uri of the location of the registry in the most preferred language.
+// A language matches the location with the same language or with a more specific one, i.e. "en" matches
+// "en-US". The first location with a language is taken when none of them is in the preferred languages.
+// The locations whose Uri is a map are skipped, as the document can't be processed. languageFound is
+// false when none of the locations has the Language
+func getRegistryLocationURI(locations []interface{}, languages []string) (uri string, languageFound bool) {
+	var fallbackURI string
+	uris := make(map[string]string)
+	var locationLanguages []string
+	for _, location := range locations {
+		locationMap, ok := location.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		language, ok := locationMap["Language"].(string)
+		if !ok || language == "" {
+			continue
+		}
+		languageFound = true
+		locationURI, ok := locationMap["Uri"].(string)
+		if !ok || locationURI == "" {
+			continue
+		}
+		if fallbackURI == "" {
+			fallbackURI = locationURI
+		}
+		language = strings.ToLower(language)
+		if _, exists := uris[language]; !exists {
+			uris[language] = locationURI
+			locationLanguages = append(locationLanguages, language)
+		}
+	}
+	for _, preferred := range languages {
+		preferred = strings.ToLower(preferred)
+		if locationURI, ok := uris[preferred]; ok {
+			return locationURI, languageFound
+		}
+		for _, language := range locationLanguages {
+			if strings.HasPrefix(language, preferred+"-") {
+				return uris[language], languageFound
+			}
+		}
+	}
+	return fallbackURI, languageFound
+}
+
+// getLanguagelessRegistryURI returns the Uri of the first location of the registry
+// which is not a map, it is used when none of the locations has the Language
+func getLanguagelessRegistryURI(locations []interface{}) string {
+	for _, location := range locations {
+		locationMap, ok := location.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if uri, ok := locationMap["Uri"].(string); ok && uri != "" {
+			return uri
+		}
+	}
+	return ""
+}
+
 func (h *respHolder) getRegistryFile(ctx context.Context, registryName string, req getResourceRequest) {
 	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get Registry file: ")
 	if err != nil {
@@ -566,8 +1333,81 @@ func (h *respHolder) getRegistryFile(ctx context.Context, registryName string, r
 		h.lock.Unlock()
 		return
 	}
+	// the registry which is not complete is not saved, so that it is downloaded
+	// again by the next discovery instead of being considered present
+	if err := validateRegistryFile(body); err != nil {
+		warning := "registry " + registryName + " downloaded from " + req.OID + " is skipped: " + err.Error()
+		l.LogWithFields(ctx).Warn(warning)
+		h.lock.Lock()
+		h.Warnings = append(h.Warnings, warning)
+		h.lock.Unlock()
+		return
+	}
 
+	h.lock.Lock()
 	h.InventoryData["Registries:"+registryName+".json"] = string(body)
+	h.lock.Unlock()
+}
+
+// registryRefresher tracks the forced refreshes of the registries in progress, so that the
+// discoveries refreshing a registry concurrently don't overwrite each other
+type registryRefresher struct {
+	lock       sync.Mutex
+	inProgress map[string]chan struct{}
+}
+
+var registryRefreshes = &registryRefresher{
+	inProgress: make(map[string]chan struct{}),
+}
+
+// start marks the registry file as being refreshed. When the registry is already being refreshed,
+// false is returned along with the channel closed once that refresh is finished.
+func (r *registryRefresher) start(registryFile string) (<-chan struct{}, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if done, ok := r.inProgress[registryFile]; ok {
+		return done, false
+	}
+	r.inProgress[registryFile] = make(chan struct{})
+	return nil, true
+}
+
+// finish marks the refresh of the registry file started with start as finished
+func (r *registryRefresher) finish(registryFile string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if done, ok := r.inProgress[registryFile]; ok {
+		close(done)
+		delete(r.inProgress, registryFile)
+	}
+}
+
+// validateRegistryFile checks the registry file is a JSON object with the required properties
+// of its schema, the MessageRegistry or the AttributeRegistry, so that an empty or a partially
+// downloaded registry is not saved
+func validateRegistryFile(body []byte) error {
+	if strings.TrimSpace(string(body)) == "" {
+		return fmt.Errorf("registry file is empty")
+	}
+	var registry map[string]interface{}
+	if err := decodeJSON(body, &registry); err != nil {
+		return fmt.Errorf("registry file is not a valid JSON object: %v", err)
+	}
+	requiredProperties := []string{"Id", "RegistryPrefix", "RegistryVersion"}
+	entriesProperty := "Messages"
+	if odataType, _ := registry["@odata.type"].(string); strings.Contains(odataType, "AttributeRegistry") {
+		requiredProperties = []string{"Id", "RegistryVersion"}
+		entriesProperty = "RegistryEntries"
+	}
+	for _, property := range requiredProperties {
+		if value, ok := registry[property].(string); !ok || value == "" {
+			return fmt.Errorf("registry file doesn't have the %s", property)
+		}
+	}
+	if _, ok := registry[entriesProperty].(map[string]interface{}); !ok {
+		return fmt.Errorf("registry file doesn't have the %s", entriesProperty)
+	}
+	return nil
 }
 
 func isFileExist(existingFiles []string, substr string) bool {
@@ -579,9 +1419,9 @@ func isFileExist(existingFiles []string, substr string) bool {
 			return true
 		}
 	}
-	// Check if the file is present in DB
-	_, err := agmodel.GetRegistryFile("Registries", substr)
-	if err == nil {
+	// Check if the file is present in DB, the invalid registry saved earlier is downloaded again
+	registry, err := GetRegistryFileFunc("Registries", substr)
+	if err == nil && validateRegistryFile([]byte(registry)) == nil {
 		fileExist = true
 	}
 	return fileExist
@@ -589,9 +1429,13 @@ func isFileExist(existingFiles []string, substr string) bool {
 
 func (h *respHolder) getAllRootInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest, resourceList []string) int32 {
 	resourceName := req.OID
+	if h.checkCancelled(ctx, req.OID) != nil {
+		return progress
+	}
 	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get the"+resourceName+"collection details: ")
 	if err != nil {
 		h.lock.Lock()
+		h.addProblem(req.OID, getResponse.StatusCode, err.Error())
 		h.ErrorMessage = err.Error()
 		h.StatusMessage = getResponse.StatusMessage
 		h.StatusCode = getResponse.StatusCode
@@ -602,7 +1446,7 @@ func (h *respHolder) getAllRootInfo(ctx context.Context, taskID string, progress
 	}
 
 	resourceMap := make(map[string]interface{})
-	err = json.Unmarshal([]byte(body), &resourceMap)
+	err = decodeJSON(body, &resourceMap)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal " + resourceName + " " + err.Error()
@@ -614,38 +1458,179 @@ func (h *respHolder) getAllRootInfo(ctx context.Context, taskID string, progress
 
 	}
 
-	resourceMembers := resourceMap["Members"]
-	if resourceMembers != nil {
+	resourceMembers, ok := getCollectionMembers(resourceMap)
+	if !ok {
+		l.LogWithFields(ctx).Error(h.recordMalformedResponse(req.OID, "Members is not an array"))
+		return progress + alottedWork
+	}
+	if len(resourceMembers) > 0 {
+		var wg sync.WaitGroup
+		var memberErrors []string
+		startProgress := progress
+		shares := newWorkShares(alottedWork, len(resourceMembers))
+		// workers bounds the number of members discovered in parallel
+		workers := make(chan struct{}, config.Data.DiscoveryConf.RootInfoWorkerCount)
 		// Loop through all the resource members collection and discover all of them
-		for _, object := range resourceMembers.([]interface{}) {
-			estimatedWork := alottedWork / int32(len(resourceMembers.([]interface{})))
-			oDataID := object.(map[string]interface{})["@odata.id"].(string)
-			oDataID = strings.TrimSuffix(oDataID, "/")
-			req.OID = oDataID
-			progress = h.getIndivdualInfo(ctx, taskID, progress, estimatedWork, req, resourceList)
+		for _, object := range resourceMembers {
+			estimatedWork := shares.next()
+			oDataID, ok := getMemberODataID(object)
+			if !ok {
+				l.LogWithFields(ctx).Error(h.recordMalformedResponse(req.OID, fmt.Sprintf("skipping the member %v without @odata.id", object)))
+				h.lock.Lock()
+				progress = progress + estimatedWork
+				h.lock.Unlock()
+				continue
+			}
+			memberReq := req
+			memberReq.OID = oDataID
+			workers <- struct{}{}
+			// the members waiting for a worker are not discovered once the discovery is cancelled
+			if h.checkCancelled(ctx, oDataID) != nil {
+				<-workers
+				break
+			}
+			wg.Add(1)
+			go func(memberReq getResourceRequest, estimatedWork int32) {
+				defer wg.Done()
+				defer func() { <-workers }()
+				memberProgress, err := h.getIndivdualInfo(ctx, taskID, startProgress, estimatedWork, memberReq, resourceList)
+				h.lock.Lock()
+				progress += memberProgress - startProgress
+				if err != nil {
+					memberErrors = append(memberErrors, memberReq.OID+": "+err.Error())
+				}
+				h.lock.Unlock()
+			}(memberReq, estimatedWork)
+		}
+		wg.Wait()
+		if len(memberErrors) > 0 {
+			l.LogWithFields(ctx).Error(fmt.Sprintf("failed to discover %d of %d members of %s: %s",
+				len(memberErrors), len(resourceMembers), resourceName, strings.Join(memberErrors, "; ")))
+		}
+	}
+	return progress
+}
+
+// getVirtualMediaInfo discovers the VirtualMedia collection and its members of the managers
+// already discovered, irrespective of the resources skipped under managers. The managers
+// which don't expose VirtualMedia are skipped.
+func (h *respHolder) getVirtualMediaInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) int32 {
+	if !config.Data.DiscoveryConf.DiscoverVirtualMedia {
+		return progress + alottedWork
+	}
+	return h.getLinkedResourceInfo(ctx, taskID, progress, alottedWork, req, "Managers", []string{"VirtualMedia"})
+}
+
+// getManagerProtocolInfo discovers the NetworkProtocol and the SerialInterfaces, along with
+// their members, of the managers already discovered, irrespective of the resources skipped
+// under managers. The managers which don't expose them are skipped.
+func (h *respHolder) getManagerProtocolInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) int32 {
+	var properties []string
+	if config.Data.DiscoveryConf.DiscoverNetworkProtocol {
+		properties = append(properties, "NetworkProtocol")
+	}
+	if config.Data.DiscoveryConf.DiscoverSerialInterfaces {
+		properties = append(properties, "SerialInterfaces")
+	}
+	if len(properties) == 0 {
+		return progress + alottedWork
+	}
+	return h.getLinkedResourceInfo(ctx, taskID, progress, alottedWork, req, "Managers", properties)
+}
+
+// getChassisAssetInfo discovers the Assembly and the PCIeDevices, along with the PCIeFunctions
+// under them, of the chassis already discovered, irrespective of the resources skipped under
+// chassis. The chassis which don't expose them are skipped.
+func (h *respHolder) getChassisAssetInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) int32 {
+	var properties []string
+	if config.Data.DiscoveryConf.DiscoverChassisAssembly {
+		properties = append(properties, "Assembly")
+	}
+	if config.Data.DiscoveryConf.DiscoverPCIeDevices {
+		properties = append(properties, "PCIeDevices")
+	}
+	if len(properties) == 0 {
+		return progress + alottedWork
+	}
+	return h.getLinkedResourceInfo(ctx, taskID, progress, alottedWork, req, "Chassis", properties)
+}
+
+// getLinkedResourceInfo discovers the resources linked with the properties of the members of
+// the root collection already discovered, the links which are already traversed are skipped
+func (h *respHolder) getLinkedResourceInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest, rootName string, properties []string) int32 {
+	if h.checkCancelled(ctx, req.OID) != nil {
+		return progress
+	}
+	links := make(map[string]string)
+	h.lock.Lock()
+	for key, data := range h.InventoryData {
+		if !strings.HasPrefix(key, rootName+":") {
+			continue
+		}
+		memberData, ok := data.(string)
+		if !ok {
+			continue
 		}
+		var member map[string]interface{}
+		if err := json.Unmarshal([]byte(memberData), &member); err != nil {
+			continue
+		}
+		oid, ok := member["@odata.id"].(string)
+		if !ok {
+			continue
+		}
+		// the links are saved with the device UUID, so removing it before contacting the plugin
+		rootPrefix := "/redfish/v1/" + rootName + "/"
+		oid = strings.Replace(oid, rootPrefix+req.DeviceUUID+".", rootPrefix, -1)
+		for _, property := range properties {
+			link, ok := getMemberODataID(member[property])
+			if !ok {
+				l.LogWithFields(ctx).Debug(property + " is not available for " + oid)
+				continue
+			}
+			link = strings.Replace(link, rootPrefix+req.DeviceUUID+".", rootPrefix, -1)
+			if !h.TraversedLinks[link] {
+				links[link] = oid
+			}
+		}
+	}
+	h.lock.Unlock()
+	if len(links) == 0 {
+		return progress + alottedWork
 	}
+	shares := newWorkShares(alottedWork, len(links))
+	for link, parentOID := range links {
+		req.OID = link
+		req.ParentOID = parentOID
+		req.OemFlag = false
+		progress = h.getResourceDetails(ctx, taskID, progress, shares.next(), req)
+	}
+	progress += shares.rest()
 	return progress
 }
 
 func (h *respHolder) getSystemInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) (string, string, int32, error) {
 	var computeSystemID, oidKey string
+	if err := h.checkCancelled(ctx, req.OID); err != nil {
+		return computeSystemID, oidKey, progress, err
+	}
+	metric := newResourceMetric(req, "ComputerSystem")
+	defer metric.report(ctx)
+	startTime := time.Now()
 	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get system collection details: ")
+	metric.pluginCallDone(startTime, getResponse)
 	if err != nil {
+		status := getDiscoveryErrorStatus(err)
 		h.lock.Lock()
 		h.ErrorMessage = err.Error()
-		if strings.Contains(h.ErrorMessage, errors.SystemNotSupportedErrString) {
-			h.StatusMessage = response.ActionNotSupported
-		} else {
-			h.StatusMessage = getResponse.StatusMessage
-		}
-		h.StatusCode = getResponse.StatusCode
+		h.StatusMessage = status.StatusMessage
+		h.StatusCode = status.StatusCode
 		h.lock.Unlock()
 		return computeSystemID, oidKey, progress, err
 	}
 
 	var computeSystem map[string]interface{}
-	err = json.Unmarshal(body, &computeSystem)
+	err = decodeJSON(body, &computeSystem)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal response body: " + err.Error()
@@ -655,9 +1640,18 @@ func (h *respHolder) getSystemInfo(ctx context.Context, taskID string, progress
 		return computeSystemID, oidKey, progress, err
 	}
 
-	oid := computeSystem["@odata.id"].(string)
-	computeSystemID = computeSystem["Id"].(string)
-	computeSystemUUID := computeSystem["UUID"].(string)
+	// the systems of the partially initialized BMCs can be without these properties
+	oid, ok := computeSystem["@odata.id"].(string)
+	if !ok {
+		return computeSystemID, oidKey, progress, h.recordMalformedResponse(req.OID, describeInvalidProperty("@odata.id of the system", computeSystem["@odata.id"]))
+	}
+	if computeSystemID, ok = computeSystem["Id"].(string); !ok || computeSystemID == "" {
+		return computeSystemID, oidKey, progress, h.recordMalformedResponse(req.OID, describeInvalidProperty("Id of the system", computeSystem["Id"]))
+	}
+	computeSystemUUID, ok := computeSystem["UUID"].(string)
+	if !ok || computeSystemUUID == "" {
+		return computeSystemID, oidKey, progress, h.recordMalformedResponse(req.OID, describeInvalidProperty("UUID of the system", computeSystem["UUID"]))
+	}
 	oidKey = keyFormation(oid, computeSystemID, req.DeviceUUID)
 	if !req.UpdateFlag {
 		indexList, err := agmodel.GetString("UUID", computeSystemUUID)
@@ -676,30 +1670,54 @@ func (h *respHolder) getSystemInfo(ctx context.Context, taskID string, progress
 			h.ErrorMessage = "Resource already exists"
 			h.MsgArgs = []interface{}{"ComputerSystem", "ComputerSystem", "ComputerSystem"}
 			h.lock.Unlock()
-			return computeSystemID, oidKey, progress, fmt.Errorf(h.ErrorMessage)
+			return computeSystemID, oidKey, progress, fmt.Errorf("Resource already exists")
 		}
 
 	}
+	if req.DryRun {
+		// the system is not given an ID in ODIM, so it is reported with the URI of the device
+		h.lock.Lock()
+		h.SystemURL = append(h.SystemURL, oid)
+		h.lock.Unlock()
+		return computeSystemID, oidKey, progress + alottedWork, nil
+	}
 	updatedResourceData := updateResourceDataWithUUID(string(body), req.DeviceUUID)
-
+	h.lock.Lock()
 	h.InventoryData["ComputerSystem:"+oidKey] = updatedResourceData
+	h.addResourceTypeIndex(computeSystem, oidKey)
 	h.TraversedLinks[req.OID] = true
 	h.SystemURL = append(h.SystemURL, oidKey)
+	h.lock.Unlock()
 	var retrievalLinks = make(map[string]bool)
 
-	getLinks(computeSystem, retrievalLinks, false)
+	collectLinks(ctx, oid, computeSystem, retrievalLinks, false)
+	h.lock.Lock()
 	removeRetrievalLinks(retrievalLinks, oid, config.Data.AddComputeSkipResources.SkipResourceListUnderSystem, h.TraversedLinks)
+	h.lock.Unlock()
+	metric.ChildLinks = len(retrievalLinks)
 	req.SystemID = computeSystemID
 	req.ParentOID = oid
+	shares := newWorkShares(alottedWork, len(retrievalLinks))
 	for resourceOID, oemFlag := range retrievalLinks {
-		estimatedWork := alottedWork / int32(len(retrievalLinks))
 		resourceOID = strings.TrimSuffix(resourceOID, "/")
 		req.OID = resourceOID
 		req.OemFlag = oemFlag
-		progress = h.getResourceDetails(ctx, taskID, progress, estimatedWork, req)
+		progress = h.getResourceDetails(ctx, taskID, progress, shares.next(), req)
+	}
+	progress += shares.rest()
+	// Controllers and Volumes of the storage are accounted in the estimated work of the system
+	req.OID = oid + "/Storage"
+	progress = h.getStorageDepthInfo(ctx, taskID, progress, 0, req)
+	// the partial inventory of the system is not saved once the discovery is cancelled
+	if err := h.checkCancelled(ctx, oid); err != nil {
+		return computeSystemID, oidKey, progress, err
 	}
-	json.Unmarshal([]byte(updatedResourceData), &computeSystem)
+	decodeJSON([]byte(updatedResourceData), &computeSystem)
+	startTime = time.Now()
+	h.lock.Lock()
 	err = agmodel.SaveBMCInventory(h.InventoryData)
+	h.lock.Unlock()
+	metric.DBSaveLatency = time.Since(startTime)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying to save data: " + err.Error()
@@ -711,38 +1729,143 @@ func (h *respHolder) getSystemInfo(ctx context.Context, taskID string, progress
 
 	searchForm := createServerSearchIndex(ctx, computeSystem, oidKey, req.DeviceUUID)
 	//save the final search form here
+	startTime = time.Now()
 	if req.UpdateFlag {
 		err = agmodel.UpdateIndex(searchForm, oidKey, computeSystemUUID, req.BMCAddress)
 	} else {
 		err = agmodel.SaveIndex(searchForm, oidKey, computeSystemUUID, req.BMCAddress)
 	}
+	metric.DBSaveLatency += time.Since(startTime)
 	if err != nil {
+		h.lock.Lock()
 		h.ErrorMessage = "error while trying save index values: " + err.Error()
 		h.StatusMessage = response.InternalError
 		h.StatusCode = http.StatusInternalServerError
+		h.lock.Unlock()
 		return computeSystemID, oidKey, progress, err
 	}
 	return computeSystemID, oidKey, progress, nil
 }
 
+// storageDepthProperties are the collections of the storage members discovered explicitly
+var storageDepthProperties = []string{"Controllers", "Volumes"}
+
+// getStorageDepthInfo discovers the storage members of the system along with their Controllers and
+// Volumes collections, irrespective of the link traversal and the resources skipped under system.
+// The systems without storage and the storage members without these collections are skipped.
+func (h *respHolder) getStorageDepthInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) int32 {
+	req.OID = strings.TrimSuffix(req.OID, "/")
+	if h.checkCancelled(ctx, req.OID) != nil {
+		return progress
+	}
+	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get the "+req.OID+" details: ")
+	if err != nil {
+		l.LogWithFields(ctx).Debug("storage is not available for " + req.ParentOID + ": " + err.Error())
+		if getResponse.StatusCode != http.StatusNotFound {
+			h.lock.Lock()
+			h.addProblem(req.OID, getResponse.StatusCode, err.Error())
+			h.lock.Unlock()
+		}
+		return progress + alottedWork
+	}
+	var collection map[string]interface{}
+	if err := decodeJSON(body, &collection); err != nil {
+		l.LogWithFields(ctx).Warn("error while trying to unmarshal " + req.OID + ": " + err.Error())
+		return progress + alottedWork
+	}
+	h.saveStorageResource(req, "StorageCollection", body, collection)
+	members, _ := collection["Members"].([]interface{})
+	if len(members) == 0 {
+		return progress + alottedWork
+	}
+	shares := newWorkShares(alottedWork, len(members))
+	for _, object := range members {
+		estimatedWork := shares.next()
+		memberOID, ok := getMemberODataID(object)
+		if !ok {
+			progress += estimatedWork
+			continue
+		}
+		if h.checkCancelled(ctx, memberOID) != nil {
+			return progress
+		}
+		memberReq := req
+		memberReq.OID = memberOID
+		body, _, getResponse, err := contactPlugin(ctx, memberReq, "error while trying to get the "+memberOID+" details: ")
+		if err != nil {
+			h.recordSubResourceError(ctx, memberOID, getResponse, err)
+			progress += estimatedWork
+			continue
+		}
+		var storage map[string]interface{}
+		if err := decodeJSON(body, &storage); err != nil {
+			l.LogWithFields(ctx).Warn("error while trying to unmarshal " + memberOID + ": " + err.Error())
+			progress += estimatedWork
+			continue
+		}
+		h.saveStorageResource(memberReq, "Storage", body, storage)
+		var links []string
+		h.lock.Lock()
+		for _, property := range storageDepthProperties {
+			link, ok := getMemberODataID(storage[property])
+			if !ok {
+				l.LogWithFields(ctx).Debug(property + " is not available for " + memberOID)
+				continue
+			}
+			link = strings.Replace(link, "/redfish/v1/Systems/"+req.DeviceUUID+".", "/redfish/v1/Systems/", -1)
+			if !h.TraversedLinks[link] {
+				links = append(links, link)
+			}
+		}
+		h.lock.Unlock()
+		if len(links) == 0 {
+			progress += estimatedWork
+			continue
+		}
+		linkShares := newWorkShares(estimatedWork, len(links))
+		for _, link := range links {
+			linkReq := memberReq
+			linkReq.OID = link
+			linkReq.ParentOID = memberOID
+			linkReq.OemFlag = false
+			progress = h.getResourceDetails(ctx, taskID, progress, linkShares.next(), linkReq)
+		}
+	}
+	return progress
+}
+
+// saveStorageResource adds the storage resource to the inventory data, unless it is already
+// discovered by the link traversal
+func (h *respHolder) saveStorageResource(req getResourceRequest, resourceName string, body []byte, resource map[string]interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.TraversedLinks[req.OID] {
+		return
+	}
+	oidKey := keyFormation(req.OID, req.SystemID, req.DeviceUUID)
+	h.InventoryData[resourceName+":"+oidKey] = updateResourceDataWithUUID(string(body), req.DeviceUUID)
+	h.addResourceTypeIndex(resource, oidKey)
+	h.TraversedLinks[req.OID] = true
+}
+
 // getStorageInfo is used to rediscover storage data from a system
 func (h *respHolder) getStorageInfo(ctx context.Context, progress int32, alottedWork int32, req getResourceRequest) (string, int32, error) {
-	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get system storage collection details: ")
+	if err := h.checkCancelled(ctx, req.OID); err != nil {
+		return "", progress, err
+	}
+	body, _, _, err := contactPlugin(ctx, req, "error while trying to get system storage collection details: ")
 	if err != nil {
+		status := getDiscoveryErrorStatus(err)
 		h.lock.Lock()
 		h.ErrorMessage = err.Error()
-		if strings.Contains(h.ErrorMessage, errors.SystemNotSupportedErrString) {
-			h.StatusMessage = response.ActionNotSupported
-		} else {
-			h.StatusMessage = getResponse.StatusMessage
-		}
-		h.StatusCode = getResponse.StatusCode
+		h.StatusMessage = status.StatusMessage
+		h.StatusCode = status.StatusCode
 		h.lock.Unlock()
 		return "", progress, err
 	}
 
 	var computeSystem map[string]interface{}
-	err = json.Unmarshal(body, &computeSystem)
+	err = decodeJSON(body, &computeSystem)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal response body of system storage: " + err.Error()
@@ -767,9 +1890,19 @@ func (h *respHolder) getStorageInfo(ctx context.Context, progress int32, alotted
 		return "", progress, err
 	}
 
-	oid := computeSystem["@odata.id"].(string)
-	computeSystemID := systemData["Id"].(string)
-	computeSystemUUID := systemData["UUID"].(string)
+	oid, ok := computeSystem["@odata.id"].(string)
+	if !ok {
+		return "", progress, h.recordMalformedResponse(req.OID, describeInvalidProperty("@odata.id of the storage", computeSystem["@odata.id"]))
+	}
+	// the system saved by an earlier discovery can be without these properties
+	computeSystemID, ok := systemData["Id"].(string)
+	if !ok || computeSystemID == "" {
+		return "", progress, h.recordInvalidSystemData(systemURI, describeInvalidProperty("Id of the system", systemData["Id"]))
+	}
+	computeSystemUUID, ok := systemData["UUID"].(string)
+	if !ok || computeSystemUUID == "" {
+		return "", progress, h.recordInvalidSystemData(systemURI, describeInvalidProperty("UUID of the system", systemData["UUID"]))
+	}
 	oidKey := keyFormation(oid, computeSystemID, req.DeviceUUID)
 
 	updatedResourceData := updateResourceDataWithUUID(string(body), req.DeviceUUID)
@@ -788,53 +1921,335 @@ func (h *respHolder) getStorageInfo(ctx context.Context, progress int32, alotted
 	h.SystemURL = append(h.SystemURL, oidKey)
 	var retrievalLinks = make(map[string]bool)
 
-	getLinks(computeSystem, retrievalLinks, false)
+	collectLinks(ctx, oid, computeSystem, retrievalLinks, false)
 	removeRetrievalLinks(retrievalLinks, oid, config.Data.AddComputeSkipResources.SkipResourceListUnderSystem, h.TraversedLinks)
 	req.SystemID = computeSystemID
 	req.ParentOID = oid
+	shares := newWorkShares(alottedWork, len(retrievalLinks))
 	for resourceOID, oemFlag := range retrievalLinks {
-		estimatedWork := alottedWork / int32(len(retrievalLinks))
 		req.OID = resourceOID
 		req.OemFlag = oemFlag
 		// Passing taskid as empty string
-		progress = h.getResourceDetails(ctx, "", progress, estimatedWork, req)
+		progress = h.getResourceDetails(ctx, "", progress, shares.next(), req)
 	}
-	json.Unmarshal([]byte(updatedResourceData), &computeSystem)
+	progress += shares.rest()
+	decodeJSON([]byte(updatedResourceData), &computeSystem)
 	searchForm := createServerSearchIndex(ctx, computeSystem, systemURI, req.DeviceUUID)
 	//save the final search form here
 	if req.UpdateFlag {
 		err = agmodel.SaveIndex(searchForm, systemURI, computeSystemUUID, req.BMCAddress)
 	}
-	if err != nil {
-		h.ErrorMessage = "error while trying save index values: " + err.Error()
-		h.StatusMessage = response.InternalError
-		h.StatusCode = http.StatusInternalServerError
-		return oidKey, progress, err
+	if err != nil {
+		h.ErrorMessage = "error while trying save index values: " + err.Error()
+		h.StatusMessage = response.InternalError
+		h.StatusCode = http.StatusInternalServerError
+		return oidKey, progress, err
+	}
+	return oidKey, progress, nil
+}
+
+// recordInvalidSystemData records the error for the system in DB which doesn't have the
+// expected properties, so that its storage is skipped instead of crashing the service
+func (h *respHolder) recordInvalidSystemData(systemURI, message string) error {
+	err := fmt.Errorf("invalid data of the system %s: %s", systemURI, message)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.ErrorMessage = err.Error()
+	h.StatusMessage = response.InternalError
+	h.StatusCode = http.StatusInternalServerError
+	return err
+}
+
+// getTrustedModulesIndex returns the search index of the TPM presence and version of the system,
+// the modules with the Absent state are not considered present. Nothing is indexed when the
+// system doesn't report the TrustedModules
+func getTrustedModulesIndex(computeSystem map[string]interface{}) map[string]interface{} {
+	modules, ok := computeSystem["TrustedModules"].([]interface{})
+	if !ok {
+		return nil
+	}
+	present := false
+	var interfaceTypes, firmwareVersions []string
+	for _, module := range modules {
+		moduleData, ok := module.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if status, ok := moduleData["Status"].(map[string]interface{}); ok && status["State"] == "Absent" {
+			continue
+		}
+		present = true
+		if interfaceType, ok := moduleData["InterfaceType"].(string); ok && interfaceType != "" {
+			interfaceTypes = append(interfaceTypes, interfaceType)
+		}
+		if firmwareVersion, ok := moduleData["FirmwareVersion"].(string); ok && firmwareVersion != "" {
+			firmwareVersions = append(firmwareVersions, firmwareVersion)
+		}
+	}
+	index := map[string]interface{}{
+		"TrustedModules/Present": strconv.FormatBool(present),
+	}
+	if len(interfaceTypes) > 0 {
+		index["TrustedModules/InterfaceType"] = interfaceTypes
+	}
+	if len(firmwareVersions) > 0 {
+		index["TrustedModules/FirmwareVersion"] = firmwareVersions
+	}
+	return index
+}
+
+// getSensorSummaryIndex returns the search index of the number of sensors in the Sensors collections
+// of the chassis linked with the system and the worst health among them. The chassis and the sensors
+// are read from the saved inventory, nothing is indexed when none of the chassis reports the Sensors
+func getSensorSummaryIndex(computeSystem map[string]interface{}) map[string]interface{} {
+	links, _ := computeSystem["Links"].(map[string]interface{})
+	chassisLinks, _ := links["Chassis"].([]interface{})
+	found := false
+	count := 0
+	worstHealth := -1
+	for _, chassisLink := range chassisLinks {
+		chassisOID, ok := getMemberODataID(chassisLink)
+		if !ok {
+			continue
+		}
+		chassis, ok := getSavedResource(chassisOID)
+		if !ok {
+			continue
+		}
+		sensorsOID, ok := getMemberODataID(chassis["Sensors"])
+		if !ok {
+			continue
+		}
+		sensors, ok := getSavedResource(sensorsOID)
+		if !ok {
+			continue
+		}
+		found = true
+		members, _ := getCollectionMembers(sensors)
+		for _, member := range members {
+			sensorOID, ok := getMemberODataID(member)
+			if !ok {
+				continue
+			}
+			count++
+			sensor, ok := getSavedResource(sensorOID)
+			if !ok {
+				continue
+			}
+			status, _ := sensor["Status"].(map[string]interface{})
+			health, _ := status["Health"].(string)
+			for i, value := range statusHealthValues {
+				if strings.EqualFold(health, value) && i > worstHealth {
+					worstHealth = i
+				}
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	index := map[string]interface{}{
+		"Sensors/Count": float64(count),
+	}
+	if worstHealth >= 0 {
+		index["Sensors/Status/Health"] = statusHealthValues[worstHealth]
+	}
+	return index
+}
+
+// getDetailedSummaryIndex returns the search index derived from the members of the Memory and
+// Processors collections of the system: the minimum and maximum size of the memory modules and the
+// distinct models and steppings of the processors. The members are read from the saved inventory,
+// the fields are indexed only when the members report them
+func getDetailedSummaryIndex(computeSystem map[string]interface{}) map[string]interface{} {
+	index := make(map[string]interface{})
+	var minSize, maxSize float64
+	for _, memory := range getSavedCollectionMembers(computeSystem["Memory"]) {
+		size, ok := toFloat64(memory["CapacityMiB"])
+		if !ok || size <= 0 {
+			continue
+		}
+		if minSize == 0 || size < minSize {
+			minSize = size
+		}
+		if size > maxSize {
+			maxSize = size
+		}
+	}
+	if maxSize > 0 {
+		index["Memory/CapacityMiB/Min"] = minSize
+		index["Memory/CapacityMiB/Max"] = maxSize
+	}
+	var models, steppings []string
+	for _, processor := range getSavedCollectionMembers(computeSystem["Processors"]) {
+		if model, ok := processor["Model"].(string); ok && model != "" {
+			models = appendDistinct(models, model)
+		}
+		processorID, _ := processor["ProcessorId"].(map[string]interface{})
+		if step, ok := processorID["Step"].(string); ok && step != "" {
+			steppings = appendDistinct(steppings, step)
+		}
+	}
+	if len(models) > 0 {
+		index["Processors/Model"] = models
+	}
+	if len(steppings) > 0 {
+		index["Processors/ProcessorId/Step"] = steppings
+	}
+	return index
+}
+
+// getSavedCollectionMembers reads the members of the collection with the link from the saved inventory,
+// the members which are not saved are skipped
+func getSavedCollectionMembers(collectionLink interface{}) []map[string]interface{} {
+	collectionOID, ok := getMemberODataID(collectionLink)
+	if !ok {
+		return nil
+	}
+	collection, ok := getSavedResource(collectionOID)
+	if !ok {
+		return nil
+	}
+	members, _ := getCollectionMembers(collection)
+	var resources []map[string]interface{}
+	for _, member := range members {
+		memberOID, ok := getMemberODataID(member)
+		if !ok {
+			continue
+		}
+		if resource, ok := getSavedResource(memberOID); ok {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// appendDistinct appends the value to the list if it is not present in the list
+func appendDistinct(list []string, value string) []string {
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// getSavedResource reads the resource saved in the inventory, false is returned
+// if the resource is not saved yet or its data is not valid
+func getSavedResource(oid string) (map[string]interface{}, bool) {
+	data, err := agcommon.GetResourceDetailsFunc(oid)
+	if err != nil {
+		return nil, false
+	}
+	var resource map[string]interface{}
+	if err := decodeJSON([]byte(data), &resource); err != nil {
+		return nil, false
+	}
+	return resource, true
+}
+
+// indexSensorSummary updates the sensor summary in the search index of the discovered systems.
+// The chassis are discovered after the systems, so the summary is indexed once they are saved.
+func (h *respHolder) indexSensorSummary(ctx context.Context, bmcAddress string) {
+	for _, systemURI := range h.SystemURL {
+		computeSystem, ok := getSavedResource(systemURI)
+		if !ok {
+			continue
+		}
+		searchForm := getSensorSummaryIndex(computeSystem)
+		if len(searchForm) == 0 {
+			continue
+		}
+		if err := agmodel.UpdateIndex(searchForm, systemURI, "", bmcAddress); err != nil {
+			l.LogWithFields(ctx).Warn("unable to index the sensor summary of the system " + systemURI + ": " + err.Error())
+		}
+	}
+}
+
+// maskSerialNumber masks all the characters of the serial number except the last 4
+func maskSerialNumber(serialNumber string) string {
+	const visibleChars = 4
+	if len(serialNumber) <= visibleChars {
+		return strings.Repeat("*", len(serialNumber))
 	}
-	return oidKey, progress, nil
+	return strings.Repeat("*", len(serialNumber)-visibleChars) + serialNumber[len(serialNumber)-visibleChars:]
 }
 
 func createServerSearchIndex(ctx context.Context, computeSystem map[string]interface{}, oidKey, deviceUUID string) map[string]interface{} {
 	var searchForm = make(map[string]interface{})
 
-	if val, ok := computeSystem["MemorySummary"]; ok {
-		memSum := val.(map[string]interface{})
-		searchForm["MemorySummary/TotalSystemMemoryGiB"] = memSum["TotalSystemMemoryGiB"].(float64)
-		if _, ok := memSum["TotalSystemPersistentMemoryGiB"]; ok {
-			searchForm["MemorySummary/TotalSystemPersistentMemoryGiB"] = memSum["TotalSystemPersistentMemoryGiB"].(float64)
+	// the numbers are either float64 or json.Number, based on how the system is decoded
+	// the properties which are absent or of an unexpected type are not indexed
+	if memSum, ok := computeSystem["MemorySummary"].(map[string]interface{}); ok {
+		if memory, ok := toFloat64(memSum["TotalSystemMemoryGiB"]); ok {
+			searchForm["MemorySummary/TotalSystemMemoryGiB"] = memory
+		}
+		if memory, ok := toFloat64(memSum["TotalSystemPersistentMemoryGiB"]); ok {
+			searchForm["MemorySummary/TotalSystemPersistentMemoryGiB"] = memory
+		}
+	}
+	if systemType, ok := computeSystem["SystemType"].(string); ok {
+		searchForm["SystemType"] = systemType
+	}
+	if procSum, ok := computeSystem["ProcessorSummary"].(map[string]interface{}); ok {
+		if count, ok := toFloat64(procSum["Count"]); ok {
+			searchForm["ProcessorSummary/Count"] = count
+			searchForm["ProcessorSummary/sockets"] = count
+		}
+		if model, ok := procSum["Model"].(string); ok {
+			searchForm["ProcessorSummary/Model"] = model
+		}
+	}
+	if powerState, ok := computeSystem["PowerState"].(string); ok {
+		searchForm["PowerState"] = powerState
+	}
+	if val, ok := computeSystem["Status"].(map[string]interface{}); ok {
+		if state, ok := val["State"].(string); ok {
+			searchForm["Status/State"] = canonicalStatusValue(state, statusStates)
 		}
+		if health, ok := val["Health"].(string); ok {
+			searchForm["Status/Health"] = canonicalStatusValue(health, statusHealthValues)
+		}
+		if healthRollup, ok := val["HealthRollup"].(string); ok {
+			searchForm["Status/HealthRollup"] = canonicalStatusValue(healthRollup, statusHealthValues)
+		}
+	}
+	// the boot override settings and the number of boot options in the BootOrder
+	// are indexed, the Boot object itself is saved along with the system
+	if boot, ok := computeSystem["Boot"].(map[string]interface{}); ok {
+		if target, ok := boot["BootSourceOverrideTarget"].(string); ok {
+			searchForm["Boot/BootSourceOverrideTarget"] = target
+		}
+		if enabled, ok := boot["BootSourceOverrideEnabled"].(string); ok {
+			searchForm["Boot/BootSourceOverrideEnabled"] = enabled
+		}
+		if bootOrder, ok := boot["BootOrder"].([]interface{}); ok {
+			searchForm["Boot/BootOptions/Count"] = float64(len(bootOrder))
+		}
+	}
+	// the identity properties are indexed for the asset management, the serial number
+	// is masked in the index when configured, the system is saved with the actual value
+	for _, property := range []string{"AssetTag", "SKU", "SerialNumber", "Manufacturer"} {
+		value, ok := computeSystem[property].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if property == "SerialNumber" && config.Data.DiscoveryConf.MaskSerialNumbers {
+			value = maskSerialNumber(value)
+		}
+		searchForm[property] = value
 	}
-	if _, ok := computeSystem["SystemType"]; ok {
-		searchForm["SystemType"] = computeSystem["SystemType"].(string)
+	for key, value := range getTrustedModulesIndex(computeSystem) {
+		searchForm[key] = value
 	}
-	if val, ok := computeSystem["ProcessorSummary"]; ok {
-		procSum := val.(map[string]interface{})
-		searchForm["ProcessorSummary/Count"] = procSum["Count"].(float64)
-		searchForm["ProcessorSummary/sockets"] = procSum["Count"].(float64)
-		searchForm["ProcessorSummary/Model"] = procSum["Model"].(string)
+	for key, value := range getSensorSummaryIndex(computeSystem) {
+		searchForm[key] = value
 	}
-	if _, ok := computeSystem["PowerState"]; ok {
-		searchForm["PowerState"] = computeSystem["PowerState"].(string)
+	// walking the Memory and Processors collections is costly, so they are indexed only when enabled
+	if config.Data.DiscoveryConf.IndexMemoryProcessorDetails {
+		for key, value := range getDetailedSummaryIndex(computeSystem) {
+			searchForm[key] = value
+		}
 	}
 
 	// saving the firmware version
@@ -843,44 +2258,48 @@ func createServerSearchIndex(ctx context.Context, computeSystem map[string]inter
 		searchForm["FirmwareVersion"] = firmwareVersion
 	}
 
+	for key, value := range getNetworkInterfacesIndex(ctx, computeSystem) {
+		searchForm[key] = value
+	}
+
 	// saving storage drive quantity/capacity/type
-	if val, ok := computeSystem["Storage"]; ok || strings.Contains(oidKey, "/Storage") {
-		var storageCollectionOdataID string
-		if strings.Contains(oidKey, "/Storage") {
-			storageCollectionOdataID = oidKey
-		} else {
-			storage := val.(map[string]interface{})
-			storageCollectionOdataID = storage["@odata.id"].(string)
-		}
+	storageCollectionOdataID, ok := getMemberODataID(computeSystem["Storage"])
+	if strings.Contains(oidKey, "/Storage") {
+		storageCollectionOdataID, ok = oidKey, true
+	}
+	if ok {
 		storageCollection := agcommon.GetStorageResources(ctx, strings.TrimSuffix(storageCollectionOdataID, "/"))
-		storageMembers := storageCollection["Members"]
-		if storageMembers != nil {
+		if storageMembers, ok := storageCollection["Members"].([]interface{}); ok {
 			var capacity []float64
 			var types []string
 			var quantity int
+			capacityUnit := config.Data.DiscoveryConf.StorageCapacityUnit
 			// Loop through all the storage members collection and discover all of them
-			for _, object := range storageMembers.([]interface{}) {
-				storageODataID := object.(map[string]interface{})["@odata.id"].(string)
+			for _, object := range storageMembers {
+				storageODataID, ok := getMemberODataID(object)
+				if !ok {
+					continue
+				}
 				storageRes := agcommon.GetStorageResources(ctx, strings.TrimSuffix(storageODataID, "/"))
-				drives := storageRes["Drives"]
-				if drives != nil {
-					quantity += len(drives.([]interface{}))
-					for _, drive := range drives.([]interface{}) {
-						driveODataID := drive.(map[string]interface{})["@odata.id"].(string)
+				if drives, ok := storageRes["Drives"].([]interface{}); ok {
+					quantity += len(drives)
+					for _, drive := range drives {
+						driveODataID, ok := getMemberODataID(drive)
+						if !ok {
+							continue
+						}
 						driveRes := agcommon.GetStorageResources(ctx, strings.TrimSuffix(driveODataID, "/"))
-						capInBytes := driveRes["CapacityBytes"]
-						// convert bytes to gb in decimal format
-						if capInBytes != nil {
-							capInGbs := capInBytes.(float64) / 1000000000
-							capacity = append(capacity, capInGbs)
+						// convert bytes to the configured unit
+						if driveCapacity, ok := bytesToCapacity(driveRes["CapacityBytes"], capacityUnit); ok {
+							capacity = append(capacity, driveCapacity)
 						}
-						mediaType := driveRes["MediaType"]
-						if mediaType != nil {
-							types = append(types, mediaType.(string))
+						if mediaType, ok := driveRes["MediaType"].(string); ok {
+							types = append(types, mediaType)
 						}
 					}
 					searchForm["Storage/Drives/Quantity"] = quantity
 					searchForm["Storage/Drives/Capacity"] = capacity
+					searchForm["Storage/Drives/CapacityUnit"] = capacityUnit
 					searchForm["Storage/Drives/Type"] = types
 				}
 			}
@@ -888,66 +2307,150 @@ func createServerSearchIndex(ctx context.Context, computeSystem map[string]inter
 	}
 	return searchForm
 }
-func (h *respHolder) getIndivdualInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest, resourceList []string) int32 {
+
+// getNetworkInterfacesIndex returns the search index of the number of EthernetInterfaces of the
+// system along with their MAC addresses and speeds. The interfaces without the MACAddress or the
+// SpeedMbps are counted, nothing is indexed when the system doesn't report the EthernetInterfaces
+func getNetworkInterfacesIndex(ctx context.Context, computeSystem map[string]interface{}) map[string]interface{} {
+	collectionOID, ok := getMemberODataID(computeSystem["EthernetInterfaces"])
+	if !ok {
+		return nil
+	}
+	collection := agcommon.GetStorageResources(ctx, strings.TrimSuffix(collectionOID, "/"))
+	if len(collection) == 0 {
+		return nil
+	}
+	members, ok := getCollectionMembers(collection)
+	if !ok {
+		return nil
+	}
+	macAddresses := []string{}
+	speeds := []float64{}
+	var quantity int
+	for _, member := range members {
+		interfaceOID, ok := getMemberODataID(member)
+		if !ok {
+			continue
+		}
+		quantity++
+		ethernetInterface := agcommon.GetStorageResources(ctx, strings.TrimSuffix(interfaceOID, "/"))
+		if macAddress, ok := ethernetInterface["MACAddress"].(string); ok && macAddress != "" {
+			macAddresses = append(macAddresses, macAddress)
+		}
+		if speed, ok := toFloat64(ethernetInterface["SpeedMbps"]); ok {
+			speeds = append(speeds, speed)
+		}
+	}
+	return map[string]interface{}{
+		"NetworkInterfaces/Quantity":     quantity,
+		"NetworkInterfaces/MACAddresses": macAddresses,
+		"NetworkInterfaces/SpeedMbps":    speeds,
+	}
+}
+
+// canonicalStatusValue returns the Redfish defined casing of the Status value
+// if it is one of the allowed values, else the value is returned as it is
+func canonicalStatusValue(value string, allowedValues []string) string {
+	for _, allowedValue := range allowedValues {
+		if strings.EqualFold(value, allowedValue) {
+			return allowedValue
+		}
+	}
+	return value
+}
+
+// getIndivdualInfo discovers a member of a root collection and the resources linked to it.
+// It is safe to be called concurrently for different members, the error returned is
+// for the member itself and not for the resources linked to it.
+func (h *respHolder) getIndivdualInfo(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest, resourceList []string) (int32, error) {
 	resourceName := getResourceName(req.OID, false)
+	if err := h.checkCancelled(ctx, req.OID); err != nil {
+		return progress, err
+	}
 	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get "+resourceName+" details: ")
 	if err != nil {
-		h.lock.Lock()
-		h.ErrorMessage = err.Error()
-		h.StatusMessage = getResponse.StatusMessage
-		h.StatusCode = getResponse.StatusCode
-		h.lock.Unlock()
-		return progress
+		h.recordSubResourceError(ctx, req.OID, getResponse, err)
+		return progress, err
 	}
 	var resource map[string]interface{}
-	err = json.Unmarshal(body, &resource)
+	err = decodeJSON(body, &resource)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal response body: " + err.Error()
 		h.StatusMessage = response.InternalError
 		h.StatusCode = http.StatusInternalServerError
 		h.lock.Unlock()
-		return progress
+		return progress, err
+	}
+	oid, ok := resource["@odata.id"].(string)
+	if !ok {
+		return progress, h.recordMalformedResponse(req.OID, "@odata.id of the "+resourceName+" is not a string")
+	}
+	resourceID, ok := resource["Id"].(string)
+	if !ok {
+		return progress, h.recordMalformedResponse(req.OID, "Id of the "+resourceName+" is not a string")
 	}
-	oid := resource["@odata.id"].(string)
-	resourceID := resource["Id"].(string)
 
 	oidKey := keyFormation(oid, resourceID, req.DeviceUUID)
 
 	//replacing the uuid while saving the data
 	updatedResourceData := updateResourceDataWithUUID(string(body), req.DeviceUUID)
+	h.lock.Lock()
 	h.InventoryData[resourceName+":"+oidKey] = updatedResourceData
+	h.addResourceTypeIndex(resource, oidKey)
 	h.TraversedLinks[req.OID] = true
+	h.lock.Unlock()
 	var retrievalLinks = make(map[string]bool)
 
-	getLinks(resource, retrievalLinks, false)
+	collectLinks(ctx, oid, resource, retrievalLinks, false)
+	h.lock.Lock()
 	removeRetrievalLinks(retrievalLinks, oid, resourceList, h.TraversedLinks)
+	h.lock.Unlock()
 	req.SystemID = resourceID
 	req.ParentOID = oid
+	shares := newWorkShares(alottedWork, len(retrievalLinks))
 	for resourceOID, oemFlag := range retrievalLinks {
-		estimatedWork := alottedWork / int32(len(retrievalLinks))
 		resourceOID = strings.TrimSuffix(resourceOID, "/")
 		req.OID = resourceOID
 		req.OemFlag = oemFlag
-		progress = h.getResourceDetails(ctx, taskID, progress, estimatedWork, req)
+		progress = h.getResourceDetails(ctx, taskID, progress, shares.next(), req)
 	}
-	return progress
+	progress += shares.rest()
+	return progress, nil
 }
 
 func (h *respHolder) getResourceDetails(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) int32 {
-	h.TraversedLinks[req.OID] = true
+	if h.checkCancelled(ctx, req.OID) != nil {
+		return progress
+	}
+	h.lock.Lock()
+	done, started := h.startFetch(req.OID)
+	h.lock.Unlock()
+	if !started {
+		// the resource is saved by the fetch in progress
+		<-done
+		return progress + alottedWork
+	}
+	return h.fetchResourceDetails(ctx, taskID, progress, alottedWork, req)
+}
+
+// fetchResourceDetails gets the resource and the resources linked with it.
+// The fetch of the resource should be started with startFetch by the caller.
+func (h *respHolder) fetchResourceDetails(ctx context.Context, taskID string, progress int32, alottedWork int32, req getResourceRequest) int32 {
+	defer h.finishFetch(req.OID)
+	metric := newResourceMetric(req, getResourceName(req.OID, false))
+	defer metric.report(ctx)
+	startTime := time.Now()
 	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get the "+req.OID+" details: ")
+	metric.pluginCallDone(startTime, getResponse)
 	if err != nil {
-		h.lock.Lock()
-		h.ErrorMessage = err.Error()
-		h.StatusMessage = getResponse.StatusMessage
-		h.MsgArgs = getResponse.MsgArgs
-		h.StatusCode = getResponse.StatusCode
-		h.lock.Unlock()
+		if h.recordSubResourceError(ctx, req.OID, getResponse, err) {
+			return progress + alottedWork
+		}
 		return progress
 	}
 	var resourceData map[string]interface{}
-	err = json.Unmarshal(body, &resourceData)
+	err = decodeJSON(body, &resourceData)
 	if err != nil {
 		h.lock.Lock()
 		h.ErrorMessage = "error while trying unmarshal : " + err.Error()
@@ -968,24 +2471,24 @@ func (h *respHolder) getResourceDetails(ctx context.Context, taskID string, prog
 	var memberFlag bool
 	if _, ok := resourceData["Members"]; ok {
 		memberFlag = true
+		members, ok := getCollectionMembers(resourceData)
+		if !ok {
+			l.LogWithFields(ctx).Error(h.recordMalformedResponse(req.OID, "Members is not an array"))
+			return progress + alottedWork
+		}
+		// the members without a valid @odata.id are not discovered by the link traversal
+		for _, member := range members {
+			if _, ok := getMemberODataID(member); !ok {
+				l.LogWithFields(ctx).Error(h.recordMalformedResponse(req.OID, fmt.Sprintf("skipping the member %v without @odata.id", member)))
+			}
+		}
 	}
 	resourceName := getResourceName(req.OID, memberFlag)
+	metric.ResourceType = resourceName
 	if memberFlag && strings.Contains(resourceName, "VolumesCollection") {
 		CollectionCapabilities := dmtf.CollectionCapabilities{
-			OdataType: "#CollectionCapabilities.v1_4_0.CollectionCapabilities",
-			Capabilities: []*dmtf.Capabilities{
-				&dmtf.Capabilities{
-					CapabilitiesObject: &dmtf.Link{
-						Oid: req.OID + "/Capabilities",
-					},
-					Links: dmtf.CapLinks{
-						TargetCollection: &dmtf.Link{
-							Oid: req.OID,
-						},
-					},
-					UseCase: "VolumeCreation",
-				},
-			},
+			OdataType:    "#CollectionCapabilities.v1_4_0.CollectionCapabilities",
+			Capabilities: getVolumeCapabilities(req.OID, resourceData),
 		}
 		resourceData["@Redfish.CollectionCapabilities"] = CollectionCapabilities
 		body, _ = json.Marshal(resourceData)
@@ -994,26 +2497,127 @@ func (h *respHolder) getResourceDetails(ctx context.Context, taskID string, prog
 	//replacing the uuid while saving the data
 	updatedResourceData := updateResourceDataWithUUID(string(body), req.DeviceUUID)
 
+	h.lock.Lock()
 	h.InventoryData[resourceName+":"+oidKey] = updatedResourceData
+	h.addResourceTypeIndex(resourceData, oidKey)
+	// the resource is marked traversed with its own @odata.id as well, so that a cycle
+	// linking it back in another form isn't fetched again
+	if selfOID, ok := resourceData["@odata.id"].(string); ok && selfOID != "" {
+		h.TraversedLinks[strings.TrimSuffix(selfOID, "/")] = true
+	}
+	h.lock.Unlock()
 	var retrievalLinks = make(map[string]bool)
 
-	getLinks(resourceData, retrievalLinks, req.OemFlag)
+	collectLinks(ctx, req.OID, resourceData, retrievalLinks, req.OemFlag)
+	metric.ChildLinks = childLinkCount(retrievalLinks, req.OID)
+	// the alotted work is shared by the links, the share of the links which are not
+	// retrieved is accounted as done along with the resource
+	shares := newWorkShares(alottedWork, len(retrievalLinks))
 	/* Loop through  Collection members and discover all of them*/
 	for oid, oemFlag := range retrievalLinks {
-		// skipping the Retrieval if oid mathches the parent oid
-		if checkRetrieval(oid, req.OID, h.TraversedLinks) {
-			estimatedWork := alottedWork / int32(len(retrievalLinks))
+		// skipping the Retrieval if oid mathches the parent oid, the link is checked and
+		// its fetch is started atomically so that the links shared by the resources being
+		// discovered concurrently are fetched once
+		h.lock.Lock()
+		retrieve := checkRetrieval(oid, req.OID, h.TraversedLinks) && h.inScope(oid)
+		oid = strings.TrimSuffix(oid, "/")
+		if retrieve && h.exceedsTraversalDepth(ctx, oid, req) {
+			retrieve = false
+		}
+		if retrieve {
+			_, retrieve = h.startFetch(oid)
+		}
+		h.lock.Unlock()
+		if retrieve {
+			if h.checkCancelled(ctx, oid) != nil {
+				h.finishFetch(oid)
+				continue
+			}
 			childReq := req
-			oid = strings.TrimSuffix(oid, "/")
 			childReq.OID = oid
 			childReq.ParentOID = req.OID
 			childReq.OemFlag = oemFlag
-			progress = h.getResourceDetails(ctx, taskID, progress, estimatedWork, childReq)
+			childReq.Depth = req.Depth + 1
+			progress = h.fetchResourceDetails(ctx, taskID, progress, shares.next(), childReq)
 		}
 	}
-	progress = progress + alottedWork
+	progress = progress + shares.rest()
 	return progress
 }
+
+// volumeActionUseCases maps the volume actions reported by the device to
+// the use case advertised in the collection capabilities
+var volumeActionUseCases = []struct {
+	action  string
+	useCase string
+}{
+	{action: "Delete", useCase: "VolumeDeletion"},
+	{action: "SecureErase", useCase: "VolumeSecureErase"},
+	{action: "Sanitize", useCase: "VolumeSanitize"},
+}
+
+// getVolumeCapabilities builds the capabilities of the volumes collection.
+// VolumeCreation is always advertised, the deletion and sanitize use cases are added
+// only when the collection or its members report the corresponding actions.
+func getVolumeCapabilities(collectionOID string, resourceData map[string]interface{}) []*dmtf.Capabilities {
+	actions := getReportedActions(resourceData)
+	if members, ok := resourceData["Members"].([]interface{}); ok {
+		for _, member := range members {
+			if memberData, ok := member.(map[string]interface{}); ok {
+				actions = append(actions, getReportedActions(memberData)...)
+			}
+		}
+	}
+	useCases := []string{"VolumeCreation"}
+	for _, actionUseCase := range volumeActionUseCases {
+		for _, action := range actions {
+			if strings.EqualFold(action, actionUseCase.action) {
+				useCases = append(useCases, actionUseCase.useCase)
+				break
+			}
+		}
+	}
+	var capabilities []*dmtf.Capabilities
+	for _, useCase := range useCases {
+		capabilities = append(capabilities, &dmtf.Capabilities{
+			CapabilitiesObject: &dmtf.Link{
+				Oid: collectionOID + "/Capabilities",
+			},
+			Links: dmtf.CapLinks{
+				TargetCollection: &dmtf.Link{
+					Oid: collectionOID,
+				},
+			},
+			UseCase: useCase,
+		})
+	}
+	return capabilities
+}
+
+// getReportedActions returns the names of the actions in the Actions and Actions/Oem
+// of the resource, "#Volume.SecureErase" is returned as "SecureErase"
+func getReportedActions(resourceData map[string]interface{}) []string {
+	actionsData, ok := resourceData["Actions"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var actions []string
+	for key, value := range actionsData {
+		if key == "Oem" {
+			if oemActions, ok := value.(map[string]interface{}); ok {
+				for oemKey := range oemActions {
+					actions = append(actions, oemKey[strings.LastIndex(oemKey, ".")+1:])
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(key, "#") {
+			actions = append(actions, key[strings.LastIndex(key, ".")+1:])
+		}
+	}
+	return actions
+}
+
 func getResourceName(oDataID string, memberFlag bool) string {
 	str := strings.Split(oDataID, "/")
 	if memberFlag {
@@ -1025,8 +2629,20 @@ func getResourceName(oDataID string, memberFlag bool) string {
 	return str[len(str)-2]
 }
 
-// getLinks recursively finds and stores all the  @odata.id whcih is present in the request
-func getLinks(data map[string]interface{}, retrievalLinks map[string]bool, oemFlag bool) {
+// collectLinks finds the links of the resource to be retrieved. The links beyond the configured
+// MaxLinksPerResource are dropped, so that the resources with enormous embedded arrays of links
+// don't blow up the memory and the time of the discovery
+func collectLinks(ctx context.Context, oid string, data map[string]interface{}, retrievalLinks map[string]bool, oemFlag bool) {
+	if dropped := getLinks(data, retrievalLinks, oemFlag); dropped > 0 {
+		l.LogWithFields(ctx).Warn(fmt.Sprintf("%d links of %s are not discovered, since it has more than %d links",
+			dropped, oid, config.Data.DiscoveryConf.MaxLinksPerResource))
+	}
+}
+
+// getLinks recursively finds and stores all the  @odata.id whcih is present in the request.
+// The number of links which are not stored for exceeding the MaxLinksPerResource is returned
+func getLinks(data map[string]interface{}, retrievalLinks map[string]bool, oemFlag bool) int {
+	dropped := 0
 	for key, value := range data {
 		switch value.(type) {
 		// condition to validate the map data
@@ -1034,7 +2650,7 @@ func getLinks(data map[string]interface{}, retrievalLinks map[string]bool, oemFl
 			if strings.EqualFold(key, "Oem") {
 				oemFlag = true
 			}
-			getLinks(value.(map[string]interface{}), retrievalLinks, oemFlag)
+			dropped += getLinks(value.(map[string]interface{}), retrievalLinks, oemFlag)
 		// condition to validate the array data
 		case []interface{}:
 			memberData := value.([]interface{})
@@ -1044,18 +2660,24 @@ func getLinks(data map[string]interface{}, retrievalLinks map[string]bool, oemFl
 					if strings.EqualFold(key, "Oem") {
 						oemFlag = true
 					}
-					getLinks(v.(map[string]interface{}), retrievalLinks, oemFlag)
+					dropped += getLinks(v.(map[string]interface{}), retrievalLinks, oemFlag)
 				}
 			}
 		default:
-			// stores value of @odata.id
-			if key == "@odata.id" {
-				link := strings.TrimSuffix(value.(string), "/")
+			// stores value of @odata.id, the links which are not strings can't be retrieved
+			if link, ok := value.(string); ok && key == "@odata.id" {
+				link = strings.TrimSuffix(link, "/")
+				maxLinks := config.Data.DiscoveryConf.MaxLinksPerResource
+				if _, exists := retrievalLinks[link]; !exists && maxLinks > 0 && len(retrievalLinks) >= maxLinks {
+					dropped++
+					continue
+				}
 				retrievalLinks[link] = oemFlag
 			}
 		}
 
 	}
+	return dropped
 }
 
 func checkRetrieval(oid, parentoid string, traversedLinks map[string]bool) bool {
@@ -1100,15 +2722,63 @@ func removeRetrievalLinks(retrievalLinks map[string]bool, parentoid string, reso
 }
 
 func callPlugin(ctx context.Context, req getResourceRequest) (*http.Response, error) {
-	var oid string
-	for key, value := range getTranslationURL(southBoundURL) {
-		oid = strings.Replace(req.OID, key, value, -1)
-	}
+	oid := translateURL(req.OID, getTranslationURL(southBoundURL, req.Plugin.ID))
 	var reqURL = "https://" + req.Plugin.IP + ":" + req.Plugin.Port + oid
-	if strings.EqualFold(req.Plugin.PreferredAuthType, "BasicAuth") {
+	if headers := getForwardedHeaders(ctx, req); len(headers) > 0 {
+		ctx = context.WithValue(ctx, common.ForwardedHeaders, headers)
+	}
+	if config.Data.PluginTimeoutConf != nil {
+		ctx = context.WithValue(ctx, common.PluginTimeout, getPluginTimeout(req))
+	}
+	if strings.EqualFold(req.Plugin.AuthType(), "BasicAuth") {
 		return req.ContactClient(ctx, reqURL, req.HTTPMethodType, "", oid, req.DeviceInfo, req.LoginCredentials)
 	}
-	return req.ContactClient(ctx, reqURL, req.HTTPMethodType, req.Token, oid, req.DeviceInfo, nil)
+	return req.ContactClient(ctx, reqURL, req.HTTPMethodType, req.sessionToken(), oid, req.DeviceInfo, nil)
+}
+
+// getForwardedHeaders returns the northbound request headers which are in the allow list of the
+// request. The authentication headers are never forwarded, even if they are allowed.
+// The tenant ID of the request is added in the configured PluginTenantHeader.
+func getForwardedHeaders(ctx context.Context, req getResourceRequest) map[string]string {
+	tenantHeader, tenantID := config.Data.PluginTenantHeader, getTenantID(ctx, req)
+	if tenantHeader != "" && tenantID != "" {
+		headers := getAllowedHeaders(req)
+		if headers == nil {
+			headers = make(map[string]string, 1)
+		}
+		headers[http.CanonicalHeaderKey(tenantHeader)] = tenantID
+		return headers
+	}
+	return getAllowedHeaders(req)
+}
+
+// getTenantID returns the tenant ID of the request, or the one of the authenticated context
+func getTenantID(ctx context.Context, req getResourceRequest) string {
+	if req.TenantID != "" {
+		return req.TenantID
+	}
+	tenantID, _ := ctx.Value(common.TenantID).(string)
+	return tenantID
+}
+
+// getAllowedHeaders returns the northbound request headers which are in the allow list of the request
+func getAllowedHeaders(req getResourceRequest) map[string]string {
+	if len(req.NorthBoundHeaders) == 0 || len(req.HeaderAllowList) == 0 {
+		return nil
+	}
+	allowList := make(map[string]bool, len(req.HeaderAllowList))
+	for _, header := range req.HeaderAllowList {
+		allowList[http.CanonicalHeaderKey(header)] = true
+	}
+	headers := make(map[string]string)
+	for key, value := range req.NorthBoundHeaders {
+		key = http.CanonicalHeaderKey(key)
+		if !allowList[key] || authHeaders[key] {
+			continue
+		}
+		headers[key] = value
+	}
+	return headers
 }
 
 func updateManagerName(data []byte, pluginID string) []byte {
@@ -1148,8 +2818,12 @@ func getFirmwareVersion(oid, deviceUUID string) (string, error) {
 	return firmwareVersion, nil
 }
 
-// CreateDefaultEventSubscription will create default events subscriptions
-func CreateDefaultEventSubscription(ctx context.Context, systemID []string) {
+// defaultSubscriptionEventTypes are the EventTypes of the default subscriptions
+var defaultSubscriptionEventTypes = []string{"Alert"}
+
+// CreateDefaultEventSubscription will create default events subscriptions,
+// the EventTypes are limited to the ones supported by the EventService of the device
+func CreateDefaultEventSubscription(ctx context.Context, systemID []string, capabilities *agmodel.EventServiceCapabilities) {
 	l.LogWithFields(ctx).Error("Creation of default subscriptions for " + strings.Join(systemID, ", ") + " are initiated.")
 
 	conn, connErr := services.ODIMService.Client(services.Events)
@@ -1164,8 +2838,8 @@ func CreateDefaultEventSubscription(ctx context.Context, systemID []string) {
 
 	_, err := events.CreateDefaultEventSubscription(reqCtx, &eventsproto.DefaultEventSubRequest{
 		SystemID:      systemID,
-		EventTypes:    []string{"Alert"},
-		MessageIDs:    []string{},
+		EventTypes:    getDefaultSubscriptionEventTypes(ctx, capabilities),
+		MessageIDs:    getDefaultSubscriptionMessageIDs(),
 		ResourceTypes: []string{},
 		Protocol:      "Redfish",
 	})
@@ -1175,6 +2849,47 @@ func CreateDefaultEventSubscription(ctx context.Context, systemID []string) {
 	}
 }
 
+// getDefaultSubscriptionEventTypes returns the default EventTypes supported by the EventService
+// of the device. The defaults are used when the device doesn't report the EventTypes supported,
+// or when none of the defaults are supported by it.
+func getDefaultSubscriptionEventTypes(ctx context.Context, capabilities *agmodel.EventServiceCapabilities) []string {
+	if capabilities == nil || len(capabilities.EventTypesForSubscription) == 0 {
+		return defaultSubscriptionEventTypes
+	}
+	supported := make(map[string]bool, len(capabilities.EventTypesForSubscription))
+	for _, eventType := range capabilities.EventTypesForSubscription {
+		supported[eventType] = true
+	}
+	eventTypes := []string{}
+	for _, eventType := range defaultSubscriptionEventTypes {
+		if supported[eventType] {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	if len(eventTypes) == 0 {
+		l.LogWithFields(ctx).Warn("none of the default EventTypes are supported by the device, default subscriptions will be created with " + strings.Join(defaultSubscriptionEventTypes, ", "))
+		return defaultSubscriptionEventTypes
+	}
+	return eventTypes
+}
+
+// getDefaultSubscriptionMessageIDs returns the MessageIds configured for the default subscriptions
+// excluding the denied ones. The denylist is validated with the configuration to leave some of the
+// configured MessageIds, so all the MessageIds are subscribed only when none are configured.
+func getDefaultSubscriptionMessageIDs() []string {
+	deniedMessageIDs := make(map[string]bool, len(config.Data.EventConf.DeniedMessageIDs))
+	for _, messageID := range config.Data.EventConf.DeniedMessageIDs {
+		deniedMessageIDs[messageID] = true
+	}
+	messageIDs := []string{}
+	for _, messageID := range config.Data.EventConf.DefaultSubscriptionMessageIDs {
+		if !deniedMessageIDs[messageID] {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+	return messageIDs
+}
+
 // PublishEvent will publish default events
 func PublishEvent(ctx context.Context, systemIDs []string, collectionName string) {
 	for i := 0; i < len(systemIDs); i++ {
@@ -1212,25 +2927,44 @@ func getIDsFromURI(uri string) (string, string, error) {
 // rollbackInMemory will delete all InMemory data with the resourceURI
 // passed. This function is used for rollback the InMemoryDB data
 // if any error happens while adding a server
-func (e *ExternalInterface) rollbackInMemory(resourceURI string) {
-	if resourceURI != "" {
-		index := strings.LastIndexAny(resourceURI, "/")
-		e.DeleteComputeSystem(index, resourceURI)
+func (e *ExternalInterface) rollbackInMemory(ctx context.Context, resourceURI string) {
+	if resourceURI == "" {
+		return
+	}
+	index := strings.LastIndexAny(resourceURI, "/")
+	err := retryRollbackDelete(ctx, resourceURI, func() *errors.Error {
+		return e.DeleteComputeSystem(index, resourceURI)
+	})
+	if err != nil {
+		l.LogWithFields(ctx).Error(fmt.Sprintf("rollback of %s failed after %d attempts, the partially added data has to be removed manually: %s",
+			resourceURI, rollbackRetryCount, err.Error()))
+	}
+}
+
+// retryRollbackDelete calls the delete till it succeeds or the retries are exhausted, so that
+// a brief unavailability of the DB doesn't leave the partially added data behind.
+// Data which is not found is considered as already deleted.
+func retryRollbackDelete(ctx context.Context, key string, deleteFunc func() *errors.Error) *errors.Error {
+	var err *errors.Error
+	for attempt := 1; attempt <= rollbackRetryCount; attempt++ {
+		if err = deleteFunc(); err == nil || err.ErrNo() == errors.DBKeyNotFound {
+			return nil
+		}
+		l.LogWithFields(ctx).Warn(fmt.Sprintf("attempt %d to delete %s during rollback failed: %s", attempt, key, err.Error()))
+		if attempt < rollbackRetryCount {
+			time.Sleep(rollbackRetryInterval)
+		}
 	}
+	return err
 }
 
 func updateResourceDataWithUUID(resourceData, uuid string) string {
 	//replacing the uuid while saving the data
-	//to replace the id of system
-	var updatedResourceData = strings.Replace(resourceData, "/redfish/v1/Systems/", "/redfish/v1/Systems/"+uuid+".", -1)
-	updatedResourceData = strings.Replace(updatedResourceData, "/redfish/v1/systems/", "/redfish/v1/Systems/"+uuid+".", -1)
-	// to replace the id in managers
-	updatedResourceData = strings.Replace(updatedResourceData, "/redfish/v1/Managers/", "/redfish/v1/Managers/"+uuid+".", -1)
-	// to replace id in chassis
-	updatedResourceData = strings.Replace(updatedResourceData, "/redfish/v1/Chassis/", "/redfish/v1/Chassis/"+uuid+".", -1)
-
-	return strings.Replace(updatedResourceData, "/redfish/v1/chassis/", "/redfish/v1/Chassis/"+uuid+".", -1)
-
+	//to replace the id of the systems, managers and chassis
+	return resourceLinkPattern.ReplaceAllStringFunc(resourceData, func(link string) string {
+		collection := strings.TrimSuffix(strings.TrimPrefix(link, `"/redfish/v1/`), "/")
+		return `"/redfish/v1/` + strings.ToUpper(collection[:1]) + collection[1:] + "/" + uuid + "."
+	})
 }
 
 // check plugin type is supported
@@ -1243,59 +2977,132 @@ func isPluginTypeSupported(pluginType string) bool {
 	return false
 }
 
-func getTranslationURL(translationURL string) map[string]string {
+// getTranslationURL returns the translations of the plugin, the translations configured
+// for the plugin in the PluginOverrides are merged over the global translations
+func getTranslationURL(translationURL, pluginID string) map[string]string {
 	common.MuxLock.Lock()
 	defer common.MuxLock.Unlock()
+	translations, overrides := config.Data.URLTranslation.NorthBoundURL, config.Data.URLTranslation.PluginOverrides[pluginID].NorthBoundURL
 	if translationURL == southBoundURL {
-		return config.Data.URLTranslation.SouthBoundURL
+		translations, overrides = config.Data.URLTranslation.SouthBoundURL, config.Data.URLTranslation.PluginOverrides[pluginID].SouthBoundURL
+	}
+	if len(overrides) == 0 {
+		return translations
+	}
+	merged := make(map[string]string, len(translations)+len(overrides))
+	for key, value := range translations {
+		merged[key] = value
 	}
-	return config.Data.URLTranslation.NorthBoundURL
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
 }
 
-func checkStatus(ctx context.Context, pluginContactRequest getResourceRequest, req AddResourceRequest, cmVariants connectionMethodVariants, taskInfo *common.TaskUpdateInfo) (response.RPC, int32, []string) {
-	var queueList = make([]string, 0)
-	var ip, port string
-	if strings.Count(req.ManagerAddress, ":") > 2 {
-		if !strings.Contains(req.ManagerAddress, "[") {
-			ip = fmt.Sprintf("[%s]", req.ManagerAddress)
-
-		} else {
-			index := strings.LastIndex(req.ManagerAddress, ":")
-			ip = req.ManagerAddress[:index]
-			port = req.ManagerAddress[index+1:]
+// sortTranslationKeys returns the keys of the translations with the longer keys first, so that a key
+// which is the prefix of another key is replaced only where the longer key doesn't match.
+// The keys of the same length are sorted alphabetically, the empty keys are ignored.
+func sortTranslationKeys(translations map[string]string) []string {
+	keys := make([]string, 0, len(translations))
+	for key := range translations {
+		if key != "" {
+			keys = append(keys, key)
 		}
-	} else {
-		ipData := strings.Split(req.ManagerAddress, ":")
-		ip = ipData[0]
-		if len(ipData) > 1 {
-			port = ipData[1]
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
 		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// translateURL replaces the keys of the translations in the data in a single pass. The longest
+// key is replaced when the keys match at the same position, and the replaced text is not
+// translated again, so the result doesn't depend on the order of the translations.
+func translateURL(data string, translations map[string]string) string {
+	keys := sortTranslationKeys(translations)
+	if len(keys) == 0 {
+		return data
+	}
+	oldNew := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		oldNew = append(oldNew, key, translations[key])
+	}
+	return strings.NewReplacer(oldNew...).Replace(data)
+}
+
+// statusCheckResult holds the result of the plugin status check done while adding an aggregation source
+type statusCheckResult struct {
+	Response      response.RPC
+	StatusCode    int32
+	QueueList     []string
+	PluginVersion string
+	Capabilities  []string
+	// UnavailableQueues are the EMB queues advertised by the plugin which are not on the message bus
+	UnavailableQueues []string
+}
+
+// deletePluginSession deletes the session of the token in the request from the plugin,
+// the failures are only logged as the plugin expires the unused sessions anyway
+func deletePluginSession(ctx context.Context, req getResourceRequest) {
+	req.HTTPMethodType = http.MethodDelete
+	req.OID = "/ODIM/v1/Sessions"
+	req.DeviceInfo = nil
+	pluginResp, err := callPlugin(ctx, req)
+	if err != nil {
+		l.LogWithFields(ctx).Warn("error while deleting the session of the plugin " + req.Plugin.ID + ": " + err.Error())
+		return
+	}
+	defer pluginResp.Body.Close()
+	if pluginResp.StatusCode != http.StatusOK && pluginResp.StatusCode != http.StatusNoContent {
+		l.LogWithFields(ctx).Warn(fmt.Sprintf("error while deleting the session of the plugin %s: plugin responded with status code %d", req.Plugin.ID, pluginResp.StatusCode))
+	}
+}
+
+// checkStatus calls the /ODIM/v1/Status of the requested manager address and validates
+// the version of the plugin with the connection method variant
+func checkStatus(ctx context.Context, pluginContactRequest getResourceRequest, req AddResourceRequest, cmVariants connectionMethodVariants, taskInfo *common.TaskUpdateInfo) statusCheckResult {
+	var result = statusCheckResult{
+		QueueList: make([]string, 0),
 	}
+	host, port := getIPAndPortFromAddress(req.ManagerAddress)
 	var plugin = agmodel.Plugin{
-		IP:                ip,
+		IP:                getURLHost(host),
 		Port:              port,
 		Username:          req.UserName,
 		Password:          []byte(req.Password),
 		ID:                cmVariants.PluginID,
 		PluginType:        cmVariants.PluginType,
 		PreferredAuthType: cmVariants.PreferredAuthType,
+		ForceBasicAuth:    req.ForceBasicAuth,
 	}
 	pluginContactRequest.Plugin = plugin
 	pluginContactRequest.StatusPoll = true
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
-		pluginContactRequest.HTTPMethodType = http.MethodPost
-		pluginContactRequest.DeviceInfo = map[string]interface{}{
-			"Username": plugin.Username,
-			"Password": string(plugin.Password),
-		}
-		pluginContactRequest.OID = "/ODIM/v1/Sessions"
-		_, token, getResponse, err := contactPlugin(ctx, pluginContactRequest, "error while creating the session: ")
+	pluginContactRequest.Operation = statusOperation
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
+		// the session is kept in the sessions of the discovery run, so that it is reused for adding the plugin
+		token, getResponse, err := getPluginSessionToken(ctx, pluginContactRequest)
 		if err != nil {
 			errMsg := err.Error()
 			l.LogWithFields(ctx).Error(errMsg)
-			return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo), getResponse.StatusCode, queueList
+			result.Response = common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo)
+			result.StatusCode = getResponse.StatusCode
+			return result
+		}
+		if token == "" {
+			errMsg := "error while creating the session: X-Auth-Token is not present in the session response of the plugin " + cmVariants.PluginID
+			l.LogWithFields(ctx).Error(errMsg)
+			result.Response = common.GeneralError(http.StatusUnauthorized, response.ResourceAtURIUnauthorized, errMsg, []interface{}{"https://" + plugin.IP + ":" + plugin.Port + pluginContactRequest.OID}, taskInfo)
+			result.StatusCode = http.StatusUnauthorized
+			return result
 		}
 		pluginContactRequest.Token = token
+		// without the sessions of the discovery run, the session is only used for the status check
+		if pluginContactRequest.Sessions == nil && !config.Data.DiscoveryConf.SkipPluginSessionTeardown {
+			defer deletePluginSession(ctx, pluginContactRequest)
+		}
 	} else {
 		pluginContactRequest.LoginCredentials = map[string]string{
 			"UserName": plugin.Username,
@@ -1310,10 +3117,13 @@ func checkStatus(ctx context.Context, pluginContactRequest getResourceRequest, r
 	if err != nil {
 		errMsg := err.Error()
 		l.LogWithFields(ctx).Error(errMsg)
+		result.StatusCode = getResponse.StatusCode
 		if getResponse.StatusCode == http.StatusNotFound {
-			return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, nil), getResponse.StatusCode, queueList
+			result.Response = common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, nil)
+			return result
 		}
-		return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo), getResponse.StatusCode, queueList
+		result.Response = common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo)
+		return result
 	}
 	// extracting the EMB Type and EMB Queue name
 	var statusResponse common.StatusResponse
@@ -1321,36 +3131,146 @@ func checkStatus(ctx context.Context, pluginContactRequest getResourceRequest, r
 	if err != nil {
 		errMsg := err.Error()
 		l.LogWithFields(ctx).Error(errMsg)
-		getResponse.StatusCode = http.StatusInternalServerError
-		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo), getResponse.StatusCode, queueList
+		result.StatusCode = http.StatusInternalServerError
+		result.Response = common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, taskInfo)
+		return result
 	}
+	result.PluginVersion = statusResponse.Version
+	result.Capabilities = statusResponse.Capabilities
 
 	// check the firmware version of plugin is matched with connection method variant version
-	if statusResponse.Version != cmVariants.FirmwareVersion {
-		errMsg := fmt.Sprintf("Provided firmware version %s does not match supported firmware version %s of the plugin %s", cmVariants.FirmwareVersion, statusResponse.Version, cmVariants.PluginID)
+	if pluginVersion, _ := normalizeFirmwareVersion(statusResponse.Version); !firmwareVersionMatches(cmVariants.FirmwareVersion, pluginVersion) {
+		errMsg := fmt.Sprintf("Firmware version %s of the plugin %s does not match the firmware version %s of the connection method variant",
+			statusResponse.Version, cmVariants.PluginID, cmVariants.FirmwareVersion)
 		l.LogWithFields(ctx).Error(errMsg)
-		getResponse.StatusCode = http.StatusBadRequest
-		return common.GeneralError(http.StatusBadRequest, response.PropertyValueNotInList, errMsg, []interface{}{"FirmwareVersion", statusResponse.Version}, taskInfo), getResponse.StatusCode, queueList
+		result.StatusCode = http.StatusBadRequest
+		result.Response = firmwareMismatchError(errMsg, cmVariants.FirmwareVersion, statusResponse.Version, taskInfo)
+		return result
 	}
 	if statusResponse.EventMessageBus != nil {
 		for i := 0; i < len(statusResponse.EventMessageBus.EmbQueue); i++ {
-			queueList = append(queueList, statusResponse.EventMessageBus.EmbQueue[i].QueueName)
+			result.QueueList = append(result.QueueList, statusResponse.EventMessageBus.EmbQueue[i].QueueName)
+		}
+	}
+	result.UnavailableQueues = validateEMBQueues(ctx, cmVariants.PluginID, result.QueueList)
+	result.StatusCode = getResponse.StatusCode
+	return result
+}
+
+// validateEMBQueues checks the EMB queues advertised by the plugin are available
+// on the message bus and returns the ones which are not. Unavailable queues don't
+// fail the add request, they are reported in the response of the plugin added,
+// so that a misconfigured plugin is noticed early.
+func validateEMBQueues(ctx context.Context, pluginID string, queueList []string) []string {
+	var unavailableQueues []string
+	for _, queueName := range queueList {
+		if err := CheckEMBQueueAvailability(queueName); err != nil {
+			l.LogWithFields(ctx).Warn("EMB queue " + queueName + " advertised by the plugin " + pluginID + " is unavailable: " + err.Error())
+			unavailableQueues = append(unavailableQueues, queueName)
 		}
 	}
-	return response.RPC{}, getResponse.StatusCode, queueList
+	return unavailableQueues
 }
 
-func getConnectionMethodVariants(connectionMethodVariant string) connectionMethodVariants {
+// connectionMethodVariantParts are the parts of the connection method variant separated by ":"
+var connectionMethodVariantParts = []string{"PluginType", "PreferredAuthType", "PluginID"}
+
+// getConnectionMethodVariants parses the connection method variant and validates its firmware version.
+// On an invalid firmware version, the error is returned along with the other parsed details, and
+// on a malformed variant the error is returned with empty details
+func getConnectionMethodVariants(connectionMethodVariant string) (connectionMethodVariants, error) {
 	// Split the connectionmethodvariant and get the PluginType, PreferredAuthType, PluginID and FirmwareVersion.
 	// Example: Compute:BasicAuth:GRF_v1.0.0
 	cm := strings.Split(connectionMethodVariant, ":")
-	firmwareVersion := strings.Split(cm[2], "_")
-	return connectionMethodVariants{
+	if len(cm) != len(connectionMethodVariantParts) {
+		return connectionMethodVariants{}, fmt.Errorf("invalid connection method variant %s: expected the format %s_FirmwareVersion",
+			connectionMethodVariant, strings.Join(connectionMethodVariantParts, ":"))
+	}
+	for i, part := range connectionMethodVariantParts {
+		if strings.TrimSpace(cm[i]) == "" {
+			return connectionMethodVariants{}, fmt.Errorf("invalid connection method variant %s: %s is not present", connectionMethodVariant, part)
+		}
+	}
+	cmVariants := connectionMethodVariants{
 		PluginType:        cm[0],
 		PreferredAuthType: cm[1],
 		PluginID:          cm[2],
-		FirmwareVersion:   firmwareVersion[1],
 	}
+	index := strings.LastIndex(cm[2], "_")
+	if index == -1 {
+		return cmVariants, fmt.Errorf("firmware version is not present in the connection method variant %s", connectionMethodVariant)
+	}
+	firmwareVersion, err := normalizeFirmwareVersion(cm[2][index+1:])
+	if err != nil {
+		return cmVariants, fmt.Errorf("invalid connection method variant %s: %s", connectionMethodVariant, err.Error())
+	}
+	cmVariants.FirmwareVersion = firmwareVersion
+	return cmVariants, nil
+}
+
+// normalizeFirmwareVersion trims the spaces and the leading v of the version
+// and validates it is either a semantic version or a vendor version
+func normalizeFirmwareVersion(version string) (string, error) {
+	normalized := strings.TrimSpace(version)
+	if len(normalized) > 0 && (normalized[0] == 'v' || normalized[0] == 'V') {
+		normalized = normalized[1:]
+	}
+	if !firmwareVersionPattern.MatchString(normalized) {
+		return "", fmt.Errorf("firmware version %s is malformed", version)
+	}
+	return normalized, nil
+}
+
+// firmwareVersionMatches checks the firmware version reported by the plugin against the firmware
+// version of the connection method variant. The versions must match exactly unless
+// AllowFirmwareMinorMismatch is set, in which case only their major versions are compared.
+func firmwareVersionMatches(expected, actual string) bool {
+	if actual == expected {
+		return true
+	}
+	if !config.Data.DiscoveryConf.AllowFirmwareMinorMismatch || actual == "" || expected == "" {
+		return false
+	}
+	return strings.SplitN(actual, ".", 2)[0] == strings.SplitN(expected, ".", 2)[0]
+}
+
+// firmwareMismatchError creates the error response for a plugin whose firmware version doesn't match
+// the connection method variant, carrying the expected and the actual versions in their own message args
+func firmwareMismatchError(errMsg, expected, actual string, t *common.TaskUpdateInfo) response.RPC {
+	args := response.Args{
+		Code:    response.GeneralError,
+		Message: "",
+		ErrorArgs: []response.ErrArgs{
+			{
+				StatusMessage: response.PropertyValueNotInList,
+				ErrorMessage:  errMsg,
+				MessageArgs:   []interface{}{expected, "FirmwareVersion"},
+			},
+			{
+				StatusMessage: response.PropertyValueNotInList,
+				ErrorMessage:  errMsg,
+				MessageArgs:   []interface{}{actual, "Version"},
+			},
+		},
+	}
+	resp := response.RPC{
+		StatusCode:    http.StatusBadRequest,
+		StatusMessage: response.PropertyValueNotInList,
+		Body:          args.CreateGenericErrorResponse(),
+	}
+	if t != nil && t.TaskID != "" && t.TargetURI != "" && t.UpdateTask != nil {
+		t.UpdateTask(t.Context, common.TaskData{
+			TaskID:          t.TaskID,
+			TargetURI:       t.TargetURI,
+			Response:        resp,
+			TaskRequest:     t.TaskRequest,
+			TaskState:       common.Exception,
+			TaskStatus:      common.Critical,
+			PercentComplete: 100,
+			HTTPMethod:      http.MethodPost,
+		})
+	}
+	return resp
 }
 
 func (e *ExternalInterface) getTelemetryService(ctx context.Context, taskID, targetURI string, percentComplete int32, pluginContactRequest getResourceRequest, resp response.RPC, saveSystem agmodel.SaveSystem) int32 {
@@ -1359,43 +3279,71 @@ func (e *ExternalInterface) getTelemetryService(ctx context.Context, taskID, tar
 		"UserName":       saveSystem.UserName,
 		"Password":       saveSystem.Password,
 	}
-	// Populate the resource MetricDefinitions for telemetry service
 	pluginContactRequest.DeviceInfo = deviceInfo
-	pluginContactRequest.OID = "/redfish/v1/TelemetryService/MetricDefinitions"
 	pluginContactRequest.DeviceUUID = saveSystem.DeviceUUID
 	pluginContactRequest.HTTPMethodType = http.MethodGet
 
-	// total estimated work for metric is 10 percent
-	var metricEstimatedWork = int32(3)
-	progress := percentComplete
-	progress, err := e.storeTelemetryCollectionInfo(ctx, "MetricDefinitionsCollection", taskID, progress, metricEstimatedWork, pluginContactRequest)
-	if err != nil {
+	// Populate the TelemetryService resource before its collections
+	if err := e.storeTelemetryService(ctx, pluginContactRequest); err != nil {
 		l.LogWithFields(ctx).Error(err)
 	}
 
-	// Populate the MetricReportDefinitions for telemetry service
-	pluginContactRequest.OID = "/redfish/v1/TelemetryService/MetricReportDefinitions"
-	progress, err = e.storeTelemetryCollectionInfo(ctx, "MetricReportDefinitionsCollection", taskID, progress, metricEstimatedWork, pluginContactRequest)
-	if err != nil {
-		l.LogWithFields(ctx).Error(err)
+	// The collections are independent of each other, so they are discovered in parallel
+	// with a bounded pool and started in the order below
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	progress := percentComplete
+	workers := make(chan struct{}, config.Data.DiscoveryConf.TelemetryCollectionWorkerCount)
+	for _, collection := range telemetryCollections {
+		collectionReq := pluginContactRequest
+		collectionReq.OID = collection.oid
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(resourceName string, estimatedWork int32, collectionReq getResourceRequest) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			collectionProgress, err := e.storeTelemetryCollectionInfo(ctx, resourceName, taskID, percentComplete, estimatedWork, collectionReq)
+			if err != nil {
+				l.LogWithFields(ctx).Error(err)
+			}
+			lock.Lock()
+			progress += collectionProgress - percentComplete
+			lock.Unlock()
+		}(collection.resourceName, collection.estimatedWork, collectionReq)
 	}
+	wg.Wait()
+	return progress
+}
 
-	// Populate the MetricReports for telemetry service
-	var metricReportEstimatedWork int32
-	pluginContactRequest.OID = "/redfish/v1/TelemetryService/MetricReports"
-	progress, err = e.storeTelemetryCollectionInfo(ctx, "MetricReportsCollection", taskID, progress, metricReportEstimatedWork, pluginContactRequest)
-	if err != nil {
-		l.LogWithFields(ctx).Error(err)
-	}
+// telemetryCollections are the collections of the telemetry service discovered while adding
+// a server, the total estimated work of them is 9 percent. The members of MetricReports are not
+// discovered, so no work is estimated for it.
+var telemetryCollections = []struct {
+	oid           string
+	resourceName  string
+	estimatedWork int32
+}{
+	{oid: "/redfish/v1/TelemetryService/MetricDefinitions", resourceName: "MetricDefinitionsCollection", estimatedWork: 3},
+	{oid: "/redfish/v1/TelemetryService/MetricReportDefinitions", resourceName: "MetricReportDefinitionsCollection", estimatedWork: 3},
+	{oid: "/redfish/v1/TelemetryService/MetricReports", resourceName: "MetricReportsCollection", estimatedWork: 0},
+	{oid: "/redfish/v1/TelemetryService/Triggers", resourceName: "TriggersCollection", estimatedWork: 3},
+}
 
-	// Populate the Triggers for telemetry service
-	pluginContactRequest.OID = "/redfish/v1/TelemetryService/Triggers"
-	progress, err = e.storeTelemetryCollectionInfo(ctx, "TriggersCollection", taskID, progress, metricEstimatedWork, pluginContactRequest)
+// storeTelemetryService stores the TelemetryService resource of the device, the devices
+// which doesn't support the TelemetryService are skipped without any error
+func (e *ExternalInterface) storeTelemetryService(ctx context.Context, req getResourceRequest) error {
+	req.OID = telemetryServiceURI
+	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get the "+req.OID+" details: ")
 	if err != nil {
-		l.LogWithFields(ctx).Error(err)
+		if getResponse.StatusCode == http.StatusNotFound {
+			l.LogWithFields(ctx).Debug("TelemetryService is not available for the device " + req.DeviceUUID)
+			return nil
+		}
+		return err
 	}
-
-	return progress
+	//replacing the uuid while saving the data
+	updatedResourceData := updateResourceDataWithUUID(string(body), req.DeviceUUID)
+	return e.GenericSave([]byte(updatedResourceData), "TelemetryService", req.OID)
 }
 
 func (e *ExternalInterface) storeTelemetryCollectionInfo(ctx context.Context, resourceName, taskID string, progress, alottedWork int32, req getResourceRequest) (int32, error) {
@@ -1407,7 +3355,7 @@ func (e *ExternalInterface) storeTelemetryCollectionInfo(ctx context.Context, re
 		return progress, fmt.Errorf(getResponse.StatusMessage)
 	}
 	var resourceData dmtf.Collection
-	err = json.Unmarshal(body, &resourceData)
+	err = decodeJSON(body, &resourceData)
 	if err != nil {
 		return progress, err
 	}
@@ -1462,12 +3410,12 @@ func getSuperSet(telemetryInfo, resourceData []*dmtf.Link) []*dmtf.Link {
 
 func (e *ExternalInterface) getIndividualTelemetryInfo(ctx context.Context, taskID string, progress, alottedWork int32, req getResourceRequest, resourceData dmtf.Collection) int32 {
 	// Loop through all the resource members collection and discover all of them
+	shares := newWorkShares(alottedWork, len(resourceData.Members))
 	for _, member := range resourceData.Members {
-		estimatedWork := alottedWork / int32(len(resourceData.Members))
 		req.OID = member.Oid
-		progress = e.getTeleInfo(ctx, taskID, progress, estimatedWork, req)
+		progress = e.getTeleInfo(ctx, taskID, progress, shares.next(), req)
 	}
-	return progress
+	return progress + shares.rest()
 }
 
 func (e *ExternalInterface) getTeleInfo(ctx context.Context, taskID string, progress, alottedWork int32, req getResourceRequest) int32 {
@@ -1496,7 +3444,8 @@ func (e *ExternalInterface) getTeleInfo(ctx context.Context, taskID string, prog
 		l.LogWithFields(ctx).Info("An active request already exists for metric request")
 		return progress
 	}
-	err = e.GenericSave(nil, "ActiveMetricRequest", req.OID)
+	// the created time is saved to prune the requests left behind if the service stops meanwhile
+	err = e.GenericSave([]byte(time.Now().UTC().Format(time.RFC3339)), "ActiveMetricRequest", req.OID)
 	if err != nil {
 		errMsg := fmt.Sprintf("Unable to save the active request details from DB: %v", err.Error())
 		l.LogWithFields(ctx).Error(errMsg)
@@ -1535,7 +3484,6 @@ func (e *ExternalInterface) createWildCard(resourceData, resourceName, oid strin
 // if the data not present in the db(means first time add server) then create empty wild and update it with metric properties
 // if the wild card data already present then update it with new properties
 func formWildCard(dbData string, resourceDataMap map[string]interface{}) (string, error) {
-	var systemID, chassisID string
 	var wildCards []WildCard
 	var dbMetricProperities []interface{}
 
@@ -1552,22 +3500,24 @@ func formWildCard(dbData string, resourceDataMap map[string]interface{}) (string
 		}
 		wildCards = getWildCard(dbDataMap["Wildcards"].([]interface{}))
 		dbMetricProperities = dbDataMap["MetricProperties"].([]interface{})
+		// wildcards configured after the metric definition was stored are added with empty values
+		for _, emptyWildCard := range getEmptyWildCard() {
+			if !isWildCardNamePresent(emptyWildCard.Name, wildCards) {
+				wildCards = append(wildCards, emptyWildCard)
+			}
+		}
 	}
+	wildCardKeywords := getWildCardKeywords()
 	metricProperties := resourceDataMap["MetricProperties"].([]interface{})
 	for _, mProperty := range metricProperties {
 		property := mProperty.(string)
 		for i, wCard := range wildCards {
-			if wCard.Name == SystemUUID && strings.Contains(property, "/Systems/") {
-				property, systemID = getUpdatedProperty(property, SystemUUID)
-				if !checkWildCardPresent(systemID, wildCards[i].Values) {
-					wildCards[i].Values = append(wildCards[i].Values, systemID)
-				}
-				break
-			}
-			if wCard.Name == ChassisUUID && strings.Contains(property, "/Chassis/") {
-				property, chassisID = getUpdatedProperty(property, ChassisUUID)
-				if !checkWildCardPresent(chassisID, wCard.Values) {
-					wildCards[i].Values = append(wildCards[i].Values, chassisID)
+			keyword, ok := wildCardKeywords[wCard.Name]
+			if ok && strings.Contains(property, "/"+keyword+"/") {
+				var id string
+				property, id = getUpdatedProperty(property, wCard.Name)
+				if !checkWildCardPresent(id, wildCards[i].Values) {
+					wildCards[i].Values = append(wildCards[i].Values, id)
 				}
 				break
 			}
@@ -1653,20 +3603,61 @@ func checkMetricPropertyPresent(val string, values []interface{}) bool {
 	return false
 }
 
-// getEmptyWildCard function is for create empty wild card field with default SystemID and ChassisID name and empty values
+// getEmptyWildCard function is for create empty wild card field with the configured wildcard names
+// (SystemID and ChassisID by default) and empty values
 func getEmptyWildCard() []WildCard {
 	var wildCards []WildCard
-	var w WildCard
-	w.Name = SystemUUID
-	w.Values = []string{}
-	wildCards = append(wildCards, w)
-	w.Name = ChassisUUID
-	w.Values = []string{}
-	wildCards = append(wildCards, w)
+	for _, wildCardConf := range getWildCardConf() {
+		wildCards = append(wildCards, WildCard{
+			Name:   wildCardConf.Name,
+			Values: []string{},
+		})
+	}
 	return wildCards
 }
 
-func (e *ExternalInterface) monitorPluginTask(ctx context.Context, subTaskChannel chan<- int32, monitorTaskData *monitorTaskRequest) (responseStatus, error) {
+// isWildCardNamePresent checks if a wildcard with the name is present in the wildcards
+func isWildCardNamePresent(name string, wildCards []WildCard) bool {
+	for _, wildCard := range wildCards {
+		if wildCard.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getWildCardKeywords returns the URI keyword of each configured wildcard mapped against the wildcard name
+func getWildCardKeywords() map[string]string {
+	keywords := make(map[string]string)
+	for _, wildCardConf := range getWildCardConf() {
+		keywords[wildCardConf.Name] = wildCardConf.URIKeyword
+	}
+	return keywords
+}
+
+// getWildCardConf returns the configured telemetry wildcards, SystemID and ChassisID are used
+// when the wildcards are not configured
+func getWildCardConf() []config.WildCardConf {
+	if config.Data.DiscoveryConf != nil && len(config.Data.DiscoveryConf.TelemetryWildCards) > 0 {
+		return config.Data.DiscoveryConf.TelemetryWildCards
+	}
+	return []config.WildCardConf{
+		{Name: SystemUUID, URIKeyword: "Systems"},
+		{Name: ChassisUUID, URIKeyword: "Chassis"},
+	}
+}
+
+// monitorPluginTask polls the task of the plugin till it completes. The task is failed when its
+// PercentComplete doesn't change for the configured stall timeout, or when it is still running
+// after the configured timeout, so that a hung task doesn't block the parent task forever.
+// The final task body of the plugin is returned along with the status on completion.
+func (e *ExternalInterface) monitorPluginTask(ctx context.Context, subTaskChannel chan<- int32, monitorTaskData *monitorTaskRequest) (monitorTaskResult, error) {
+	pollingInterval := time.Duration(config.Data.PluginTaskConf.PollingIntervalInSecs) * time.Second
+	stallTimeout := time.Duration(config.Data.PluginTaskConf.StallTimeoutInSecs) * time.Second
+	timeout := time.Duration(config.Data.PluginTaskConf.TimeoutInSecs) * time.Second
+	startTime := time.Now()
+	lastProgressTime := startTime
+	lastPercentComplete := int32(-1)
 	for {
 
 		var task common.TaskData
@@ -1675,7 +3666,25 @@ func (e *ExternalInterface) monitorPluginTask(ctx context.Context, subTaskChanne
 			errMsg := "Unable to parse the simple update respone" + err.Error()
 			l.LogWithFields(ctx).Warn(errMsg)
 			common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, monitorTaskData.taskInfo)
-			return monitorTaskData.getResponse, err
+			return monitorTaskResult{responseStatus: monitorTaskData.getResponse}, err
+		}
+		if task.PercentComplete != lastPercentComplete {
+			lastPercentComplete = task.PercentComplete
+			lastProgressTime = time.Now()
+		}
+		var errMsg string
+		if time.Since(lastProgressTime) >= stallTimeout {
+			errMsg = fmt.Sprintf("plugin task %s didn't progress from %d percent in %v", monitorTaskData.location, task.PercentComplete, stallTimeout)
+		} else if time.Since(startTime) >= timeout {
+			errMsg = fmt.Sprintf("plugin task %s didn't complete in %v", monitorTaskData.location, timeout)
+		}
+		if errMsg != "" {
+			subTaskChannel <- http.StatusInternalServerError
+			l.LogWithFields(ctx).Error(errMsg)
+			common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, monitorTaskData.taskInfo)
+			monitorTaskData.getResponse.StatusCode = http.StatusInternalServerError
+			monitorTaskData.getResponse.StatusMessage = response.InternalError
+			return monitorTaskResult{responseStatus: monitorTaskData.getResponse}, fmt.Errorf(errMsg)
 		}
 		var updatetask = fillTaskData(monitorTaskData.subTaskID, monitorTaskData.serverURI, monitorTaskData.updateRequestBody, monitorTaskData.resp, task.TaskState, task.TaskStatus, task.PercentComplete, http.MethodPost)
 		err := e.UpdateTask(ctx, updatetask)
@@ -1683,9 +3692,9 @@ func (e *ExternalInterface) monitorPluginTask(ctx context.Context, subTaskChanne
 			var updatetask = fillTaskData(monitorTaskData.subTaskID, monitorTaskData.serverURI, monitorTaskData.updateRequestBody, monitorTaskData.resp, common.Cancelled, common.Critical, 100, http.MethodPost)
 			subTaskChannel <- http.StatusInternalServerError
 			e.UpdateTask(ctx, updatetask)
-			return monitorTaskData.getResponse, err
+			return monitorTaskResult{responseStatus: monitorTaskData.getResponse}, err
 		}
-		time.Sleep(time.Second * 5)
+		time.Sleep(pollingInterval)
 		monitorTaskData.pluginRequest.OID = monitorTaskData.location
 		monitorTaskData.pluginRequest.HTTPMethodType = http.MethodGet
 		monitorTaskData.respBody, _, monitorTaskData.getResponse, err = contactPlugin(ctx, monitorTaskData.pluginRequest, "error while performing simple update action: ")
@@ -1694,11 +3703,16 @@ func (e *ExternalInterface) monitorPluginTask(ctx context.Context, subTaskChanne
 			errMsg := err.Error()
 			l.LogWithFields(ctx).Warn(errMsg)
 			common.GeneralError(monitorTaskData.getResponse.StatusCode, monitorTaskData.getResponse.StatusMessage, errMsg, monitorTaskData.getResponse.MsgArgs, monitorTaskData.taskInfo)
-			return monitorTaskData.getResponse, err
+			return monitorTaskResult{responseStatus: monitorTaskData.getResponse}, err
 		}
 		if monitorTaskData.getResponse.StatusCode == http.StatusOK {
 			break
 		}
 	}
-	return monitorTaskData.getResponse, nil
+	result := monitorTaskResult{responseStatus: monitorTaskData.getResponse}
+	// the final task body is only informational, so the task isn't failed when it can't be decoded
+	if err := decodeJSON(monitorTaskData.respBody, &result.taskBody); err != nil {
+		l.LogWithFields(ctx).Warn("unable to decode the final task body of " + monitorTaskData.location + ": " + err.Error())
+	}
+	return result, nil
 }
@@ -0,0 +1,2809 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agcommon"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+// contactClientFunc is the signature of the client contacting the plugin
+type contactClientFunc = func(context.Context, string, string, string, string, interface{}, map[string]string) (*http.Response, error)
+
+// newTestRespHolder returns the respHolder the discovery functions are run with in the tests
+func newTestRespHolder() *respHolder {
+	return &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+}
+
+// testPluginRequest returns the GET request of the oid to the mock plugin using the contact client
+func testPluginRequest(contactClient contactClientFunc, oid string) getResourceRequest {
+	return getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            oid,
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+}
+
+// stubResponse returns the plugin response with the status code and the body
+func stubResponse(statusCode int, body string) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	}, nil
+}
+
+func Test_validateEMBQueues(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(orig func(string) error) { CheckEMBQueueAvailability = orig }(CheckEMBQueueAvailability)
+	CheckEMBQueueAvailability = func(queueName string) error {
+		if queueName == "MissingQueue" {
+			return fmt.Errorf("queue %s is not available", queueName)
+		}
+		return nil
+	}
+	ctx := mockContext()
+
+	unavailable := validateEMBQueues(ctx, "GRF", []string{"GRF", "MissingQueue"})
+	assert.Equal(t, []string{"MissingQueue"}, unavailable, "advertised but missing queue should be reported")
+
+	unavailable = validateEMBQueues(ctx, "GRF", []string{"GRF"})
+	assert.Empty(t, unavailable, "all advertised queues are available")
+
+	unavailable = validateEMBQueues(ctx, "GRF", nil)
+	assert.Empty(t, unavailable, "no queues advertised")
+}
+
+func Test_createServerSearchIndexStatus(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	computeSystem := map[string]interface{}{
+		"PowerState": "On",
+		"Status": map[string]interface{}{
+			"State":        "enabled",
+			"Health":       "warning",
+			"HealthRollup": "Critical",
+		},
+	}
+	searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "Enabled", searchForm["Status/State"], "State should be indexed with canonical casing")
+	assert.Equal(t, "Warning", searchForm["Status/Health"], "Health should be indexed with canonical casing")
+	assert.Equal(t, "Critical", searchForm["Status/HealthRollup"], "HealthRollup should be indexed")
+
+	// Status with partial or missing data should not be indexed
+	computeSystem["Status"] = map[string]interface{}{
+		"State":  "Enabled",
+		"Health": nil,
+	}
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "Enabled", searchForm["Status/State"], "State should be indexed")
+	assert.NotContains(t, searchForm, "Status/Health", "null Health should not be indexed")
+	assert.NotContains(t, searchForm, "Status/HealthRollup", "absent HealthRollup should not be indexed")
+
+	delete(computeSystem, "Status")
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.NotContains(t, searchForm, "Status/State", "absent Status should not be indexed")
+}
+
+func Test_createServerSearchIndexMissingProperties(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	tests := []struct {
+		name       string
+		system     string
+		indexed    map[string]interface{}
+		notIndexed []string
+	}{
+		{
+			name:       "ProcessorSummary without Model",
+			system:     `{"ProcessorSummary":{"Count":2}}`,
+			indexed:    map[string]interface{}{"ProcessorSummary/Count": float64(2)},
+			notIndexed: []string{"ProcessorSummary/Model"},
+		},
+		{
+			name:       "null properties",
+			system:     `{"MemorySummary":null,"ProcessorSummary":null,"SystemType":null,"PowerState":null,"Storage":null}`,
+			notIndexed: []string{"MemorySummary/TotalSystemMemoryGiB", "ProcessorSummary/Count", "SystemType", "PowerState", "Storage/Drives/Quantity"},
+		},
+		{
+			name:       "properties of unexpected type",
+			system:     `{"MemorySummary":"16GiB","ProcessorSummary":{"Count":2,"Model":1},"SystemType":1,"PowerState":true,"Storage":{"@odata.id":1}}`,
+			indexed:    map[string]interface{}{"ProcessorSummary/Count": float64(2)},
+			notIndexed: []string{"MemorySummary/TotalSystemMemoryGiB", "ProcessorSummary/Model", "SystemType", "PowerState", "Storage/Drives/Quantity"},
+		},
+		{
+			name:       "Storage without @odata.id",
+			system:     `{"PowerState":"On","Storage":{}}`,
+			indexed:    map[string]interface{}{"PowerState": "On"},
+			notIndexed: []string{"Storage/Drives/Quantity"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var computeSystem map[string]interface{}
+			if err := decodeJSON([]byte(tt.system), &computeSystem); err != nil {
+				t.Fatalf("error while trying to decode the system: %v", err)
+			}
+			searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+			for key, value := range tt.indexed {
+				assert.Equal(t, value, searchForm[key], key+" should be indexed")
+			}
+			for _, key := range tt.notIndexed {
+				assert.NotContains(t, searchForm, key, key+" should not be indexed")
+			}
+		})
+	}
+}
+
+func Test_createServerSearchIndexBoot(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	computeSystem := map[string]interface{}{
+		"Boot": map[string]interface{}{
+			"BootSourceOverrideTarget":  "Pxe",
+			"BootSourceOverrideEnabled": "Once",
+			"BootOrder":                 []interface{}{"Boot0001", "Boot0002", "Boot0003"},
+			"BootOptions": map[string]interface{}{
+				"@odata.id": "/redfish/v1/Systems/1/BootOptions",
+			},
+		},
+	}
+	searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "Pxe", searchForm["Boot/BootSourceOverrideTarget"], "BootSourceOverrideTarget should be indexed")
+	assert.Equal(t, "Once", searchForm["Boot/BootSourceOverrideEnabled"], "BootSourceOverrideEnabled should be indexed")
+	assert.Equal(t, float64(3), searchForm["Boot/BootOptions/Count"], "number of boot options should be indexed")
+
+	// Boot with partial data indexes only the properties present
+	computeSystem["Boot"] = map[string]interface{}{
+		"BootSourceOverrideEnabled": "Disabled",
+		"BootSourceOverrideTarget":  nil,
+	}
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "Disabled", searchForm["Boot/BootSourceOverrideEnabled"], "BootSourceOverrideEnabled should be indexed")
+	assert.NotContains(t, searchForm, "Boot/BootSourceOverrideTarget", "null BootSourceOverrideTarget should not be indexed")
+	assert.NotContains(t, searchForm, "Boot/BootOptions/Count", "absent BootOrder should not be indexed")
+
+	delete(computeSystem, "Boot")
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.NotContains(t, searchForm, "Boot/BootSourceOverrideEnabled", "absent Boot should not be indexed")
+}
+
+func Test_createServerSearchIndexIdentity(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	computeSystem := map[string]interface{}{
+		"AssetTag":     "Rack12-Slot4",
+		"SKU":          "867959-B21",
+		"SerialNumber": "MXQ81104R3",
+		"Manufacturer": "HPE",
+	}
+	searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "Rack12-Slot4", searchForm["AssetTag"], "AssetTag should be indexed")
+	assert.Equal(t, "867959-B21", searchForm["SKU"], "SKU should be indexed")
+	assert.Equal(t, "MXQ81104R3", searchForm["SerialNumber"], "SerialNumber should be indexed")
+	assert.Equal(t, "HPE", searchForm["Manufacturer"], "Manufacturer should be indexed")
+
+	// masked serial number is indexed, the system itself is not changed
+	config.Data.DiscoveryConf.MaskSerialNumbers = true
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "******04R3", searchForm["SerialNumber"], "SerialNumber should be masked")
+	assert.Equal(t, "MXQ81104R3", computeSystem["SerialNumber"], "SerialNumber of the system should not be masked")
+
+	computeSystem = map[string]interface{}{
+		"AssetTag":     "",
+		"SerialNumber": nil,
+	}
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	for _, property := range []string{"AssetTag", "SKU", "SerialNumber", "Manufacturer"} {
+		assert.NotContains(t, searchForm, property, "empty or absent "+property+" should not be indexed")
+	}
+}
+
+func Test_createServerSearchIndexTrustedModules(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	computeSystem := map[string]interface{}{
+		"TrustedModules": []interface{}{
+			map[string]interface{}{
+				"InterfaceType":   "TPM2_0",
+				"FirmwareVersion": "7.2.1.0",
+				"Status":          map[string]interface{}{"State": "Enabled"},
+			},
+		},
+	}
+	searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "true", searchForm["TrustedModules/Present"], "TPM presence should be indexed")
+	assert.Equal(t, []string{"TPM2_0"}, searchForm["TrustedModules/InterfaceType"], "TPM version should be indexed")
+	assert.Equal(t, []string{"7.2.1.0"}, searchForm["TrustedModules/FirmwareVersion"], "TPM firmware version should be indexed")
+	assert.Contains(t, computeSystem, "TrustedModules", "TrustedModules of the system should not be changed")
+
+	// absent modules are not considered present
+	computeSystem["TrustedModules"] = []interface{}{
+		map[string]interface{}{
+			"InterfaceType": "TPM1_2",
+			"Status":        map[string]interface{}{"State": "Absent"},
+		},
+	}
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, "false", searchForm["TrustedModules/Present"], "absent TPM should not be indexed as present")
+	assert.NotContains(t, searchForm, "TrustedModules/InterfaceType")
+
+	delete(computeSystem, "TrustedModules")
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.NotContains(t, searchForm, "TrustedModules/Present", "TPM presence should not be indexed when the system doesn't report it")
+}
+
+func Test_getAllSystemInfoWithoutMembers(t *testing.T) {
+	config.SetUpMockConfig(t)
+	for _, collection := range []string{
+		`{"@odata.id":"/ODIM/v1/Systems","Name":"Computer System Collection"}`,
+		`{"@odata.id":"/ODIM/v1/Systems","Members":null}`,
+		`{"@odata.id":"/ODIM/v1/Systems","Members":[]}`,
+	} {
+		body := collection
+		req := getResourceRequest{
+			ContactClient: func(ctx context.Context, url, method, token string, odataID string, reqBody interface{}, credentials map[string]string) (*http.Response, error) {
+				return stubResponse(http.StatusOK, body)
+			},
+			OID:            "/redfish/v1/Systems",
+			HTTPMethodType: http.MethodGet,
+			Plugin: agmodel.Plugin{
+				IP:                "localhost",
+				Port:              "9091",
+				PreferredAuthType: "BasicAuth",
+			},
+		}
+		h := &respHolder{TraversedLinks: make(map[string]bool)}
+		var systemID, resourceURI string
+		var progress int32
+		var err error
+		assert.NotPanics(t, func() {
+			systemID, resourceURI, progress, err = h.getAllSystemInfo(mockContext(), "taskID", 10, 20, req)
+		}, "collection "+body+" should not panic")
+		assert.Nil(t, err, "collection without members should be treated as no systems")
+		assert.Equal(t, "", systemID)
+		assert.Equal(t, "", resourceURI)
+		assert.Equal(t, int32(30), progress, "progress should be advanced by the alotted work")
+	}
+}
+
+func Test_createServerSearchIndexSensors(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	inventory := map[string]string{
+		"/redfish/v1/Chassis/someuuid.1":                  `{"@odata.id":"/redfish/v1/Chassis/someuuid.1","Sensors":{"@odata.id":"/redfish/v1/Chassis/someuuid.1/Sensors"}}`,
+		"/redfish/v1/Chassis/someuuid.1/Sensors":          `{"Members":[{"@odata.id":"/redfish/v1/Chassis/someuuid.1/Sensors/CPU1Temp"},{"@odata.id":"/redfish/v1/Chassis/someuuid.1/Sensors/FAN1"}]}`,
+		"/redfish/v1/Chassis/someuuid.1/Sensors/CPU1Temp": `{"Id":"CPU1Temp","Reading":45,"Status":{"State":"Enabled","Health":"OK"}}`,
+		"/redfish/v1/Chassis/someuuid.1/Sensors/FAN1":     `{"Id":"FAN1","Reading":0,"Status":{"State":"Enabled","Health":"critical"}}`,
+		"/redfish/v1/Chassis/someuuid.2":                  `{"@odata.id":"/redfish/v1/Chassis/someuuid.2"}`,
+	}
+	defer func() { agcommon.GetResourceDetailsFunc = agmodel.GetResourceDetails }()
+	agcommon.GetResourceDetailsFunc = func(key string) (string, *errors.Error) {
+		if data, ok := inventory[key]; ok {
+			return data, nil
+		}
+		return "", errors.PackError(errors.DBKeyNotFound, "no data with the key ", key, " found")
+	}
+	computeSystem := map[string]interface{}{
+		"Links": map[string]interface{}{
+			"Chassis": []interface{}{
+				map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/someuuid.1"},
+				map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/someuuid.2"},
+			},
+		},
+	}
+	searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/someuuid.1", "someuuid")
+	assert.Equal(t, float64(2), searchForm["Sensors/Count"], "number of sensors should be indexed")
+	assert.Equal(t, "Critical", searchForm["Sensors/Status/Health"], "worst health of the sensors should be indexed")
+
+	// chassis without Sensors
+	computeSystem["Links"] = map[string]interface{}{
+		"Chassis": []interface{}{
+			map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/someuuid.2"},
+			map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/someuuid.3"},
+		},
+	}
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/someuuid.1", "someuuid")
+	assert.NotContains(t, searchForm, "Sensors/Count", "sensors should not be indexed when the chassis don't report them")
+	assert.NotContains(t, searchForm, "Sensors/Status/Health")
+}
+
+func Test_createServerSearchIndexNetworkInterfaces(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	inventory := map[string]string{
+		"/redfish/v1/Systems/someuuid.1/EthernetInterfaces":   `{"Members":[{"@odata.id":"/redfish/v1/Systems/someuuid.1/EthernetInterfaces/1"},{"@odata.id":"/redfish/v1/Systems/someuuid.1/EthernetInterfaces/2"},{"@odata.id":"/redfish/v1/Systems/someuuid.1/EthernetInterfaces/3"},{}]}`,
+		"/redfish/v1/Systems/someuuid.1/EthernetInterfaces/1": `{"Id":"1","MACAddress":"AA:BB:CC:DD:EE:01","SpeedMbps":10000}`,
+		"/redfish/v1/Systems/someuuid.1/EthernetInterfaces/2": `{"Id":"2","MACAddress":"AA:BB:CC:DD:EE:02","SpeedMbps":null}`,
+		"/redfish/v1/Systems/someuuid.2/EthernetInterfaces":   `{"Members":[]}`,
+	}
+	defer func() { agcommon.GetResourceDetailsFunc = agmodel.GetResourceDetails }()
+	agcommon.GetResourceDetailsFunc = func(key string) (string, *errors.Error) {
+		if data, ok := inventory[key]; ok {
+			return data, nil
+		}
+		return "", errors.PackError(errors.DBKeyNotFound, "no data with the key ", key, " found")
+	}
+
+	// the interface which is not saved and the member without @odata.id are tolerated
+	computeSystem := map[string]interface{}{
+		"EthernetInterfaces": map[string]interface{}{"@odata.id": "/redfish/v1/Systems/someuuid.1/EthernetInterfaces"},
+	}
+	searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/someuuid.1", "someuuid")
+	assert.Equal(t, 3, searchForm["NetworkInterfaces/Quantity"])
+	assert.Equal(t, []string{"AA:BB:CC:DD:EE:01", "AA:BB:CC:DD:EE:02"}, searchForm["NetworkInterfaces/MACAddresses"])
+	assert.Equal(t, []float64{10000}, searchForm["NetworkInterfaces/SpeedMbps"], "interfaces without the speed should not be indexed")
+
+	computeSystem["EthernetInterfaces"] = map[string]interface{}{"@odata.id": "/redfish/v1/Systems/someuuid.2/EthernetInterfaces"}
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/someuuid.2", "someuuid")
+	assert.Equal(t, 0, searchForm["NetworkInterfaces/Quantity"])
+	assert.Empty(t, searchForm["NetworkInterfaces/MACAddresses"])
+
+	// system without the EthernetInterfaces
+	searchForm = createServerSearchIndex(ctx, map[string]interface{}{}, "/redfish/v1/Systems/someuuid.3", "someuuid")
+	assert.NotContains(t, searchForm, "NetworkInterfaces/Quantity")
+	assert.NotContains(t, searchForm, "NetworkInterfaces/MACAddresses")
+}
+
+func Test_createServerSearchIndexDetails(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	inventory := map[string]string{
+		"/redfish/v1/Systems/someuuid.1/Memory":       `{"Members":[{"@odata.id":"/redfish/v1/Systems/someuuid.1/Memory/1"},{"@odata.id":"/redfish/v1/Systems/someuuid.1/Memory/2"},{"@odata.id":"/redfish/v1/Systems/someuuid.1/Memory/3"}]}`,
+		"/redfish/v1/Systems/someuuid.1/Memory/1":     `{"Id":"1","CapacityMiB":16384}`,
+		"/redfish/v1/Systems/someuuid.1/Memory/2":     `{"Id":"2","CapacityMiB":65536}`,
+		"/redfish/v1/Systems/someuuid.1/Memory/3":     `{"Id":"3","Status":{"State":"Absent"}}`,
+		"/redfish/v1/Systems/someuuid.1/Processors":   `{"Members":[{"@odata.id":"/redfish/v1/Systems/someuuid.1/Processors/1"},{"@odata.id":"/redfish/v1/Systems/someuuid.1/Processors/2"}]}`,
+		"/redfish/v1/Systems/someuuid.1/Processors/1": `{"Id":"1","Model":"Intel(R) Xeon(R) Gold 6230","ProcessorId":{"Step":"0x7"}}`,
+		"/redfish/v1/Systems/someuuid.1/Processors/2": `{"Id":"2","Model":"Intel(R) Xeon(R) Gold 6230","ProcessorId":{"Step":"0x6"}}`,
+	}
+	defer func() { agcommon.GetResourceDetailsFunc = agmodel.GetResourceDetails }()
+	agcommon.GetResourceDetailsFunc = func(key string) (string, *errors.Error) {
+		if data, ok := inventory[key]; ok {
+			return data, nil
+		}
+		return "", errors.PackError(errors.DBKeyNotFound, "no data with the key ", key, " found")
+	}
+	computeSystem := map[string]interface{}{
+		"Memory":     map[string]interface{}{"@odata.id": "/redfish/v1/Systems/someuuid.1/Memory"},
+		"Processors": map[string]interface{}{"@odata.id": "/redfish/v1/Systems/someuuid.1/Processors"},
+	}
+	searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/someuuid.1", "someuuid")
+	assert.NotContains(t, searchForm, "Memory/CapacityMiB/Min", "details should not be indexed by default")
+	assert.NotContains(t, searchForm, "Processors/Model", "details should not be indexed by default")
+
+	config.Data.DiscoveryConf.IndexMemoryProcessorDetails = true
+	searchForm = createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/someuuid.1", "someuuid")
+	assert.Equal(t, float64(16384), searchForm["Memory/CapacityMiB/Min"], "modules without the size should not be considered")
+	assert.Equal(t, float64(65536), searchForm["Memory/CapacityMiB/Max"])
+	assert.Equal(t, []string{"Intel(R) Xeon(R) Gold 6230"}, searchForm["Processors/Model"], "distinct models should be indexed")
+	assert.Equal(t, []string{"0x7", "0x6"}, searchForm["Processors/ProcessorId/Step"])
+
+	// system without the Memory and Processors collections
+	searchForm = createServerSearchIndex(ctx, map[string]interface{}{}, "/redfish/v1/Systems/someuuid.1", "someuuid")
+	assert.NotContains(t, searchForm, "Memory/CapacityMiB/Max")
+	assert.NotContains(t, searchForm, "Processors/ProcessorId/Step")
+}
+
+func Test_createServerSearchIndexStorageCapacity(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx := mockContext()
+	inventory := map[string]string{
+		"/redfish/v1/Systems/someuuid.1/Storage":            `{"Members":[{"@odata.id":"/redfish/v1/Systems/someuuid.1/Storage/1"}]}`,
+		"/redfish/v1/Systems/someuuid.1/Storage/1":          `{"Drives":[{"@odata.id":"/redfish/v1/Systems/someuuid.1/Storage/1/Drives/1"},{"@odata.id":"/redfish/v1/Systems/someuuid.1/Storage/1/Drives/2"},{"@odata.id":"/redfish/v1/Systems/someuuid.1/Storage/1/Drives/3"}]}`,
+		"/redfish/v1/Systems/someuuid.1/Storage/1/Drives/1": `{"CapacityBytes":480103981056,"MediaType":"SSD"}`,
+		"/redfish/v1/Systems/someuuid.1/Storage/1/Drives/2": `{"MediaType":"HDD"}`,
+		"/redfish/v1/Systems/someuuid.1/Storage/1/Drives/3": `{"CapacityBytes":0,"MediaType":"HDD"}`,
+	}
+	defer func() { agcommon.GetResourceDetailsFunc = agmodel.GetResourceDetails }()
+	agcommon.GetResourceDetailsFunc = func(key string) (string, *errors.Error) {
+		if data, ok := inventory[key]; ok {
+			return data, nil
+		}
+		return "", errors.PackError(errors.DBKeyNotFound, "no data with the key ", key, " found")
+	}
+	computeSystem := map[string]interface{}{
+		"Storage": map[string]interface{}{"@odata.id": "/redfish/v1/Systems/someuuid.1/Storage"},
+	}
+	tests := []struct {
+		unit string
+		want float64
+	}{
+		{unit: config.StorageCapacityUnitGB, want: 480.103981056},
+		{unit: config.StorageCapacityUnitGiB, want: 447.13167572021484},
+		{unit: config.StorageCapacityUnitBytes, want: 480103981056},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			config.Data.DiscoveryConf.StorageCapacityUnit = tt.unit
+			searchForm := createServerSearchIndex(ctx, computeSystem, "/redfish/v1/Systems/someuuid.1", "someuuid")
+			assert.Equal(t, 3, searchForm["Storage/Drives/Quantity"])
+			// the drives without the capacity are not indexed
+			capacity, _ := searchForm["Storage/Drives/Capacity"].([]float64)
+			if assert.Len(t, capacity, 1) {
+				assert.InDelta(t, tt.want, capacity[0], 1e-9)
+			}
+			assert.Equal(t, tt.unit, searchForm["Storage/Drives/CapacityUnit"], "unit of the capacity should be indexed")
+		})
+	}
+}
+
+func Test_getAllRootInfoParallel(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var activeCalls, maxActiveCalls int32
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		active := atomic.AddInt32(&activeCalls, 1)
+		defer atomic.AddInt32(&activeCalls, -1)
+		for {
+			max := atomic.LoadInt32(&maxActiveCalls)
+			if active <= max || atomic.CompareAndSwapInt32(&maxActiveCalls, max, active) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		var respBody string
+		statusCode := http.StatusOK
+		switch {
+		case strings.HasSuffix(url, "/ODIM/v1/Chassis"):
+			respBody = `{"Members":[{"@odata.id":"/ODIM/v1/Chassis/1"},{"@odata.id":"/ODIM/v1/Chassis/2"},{"@odata.id":"/ODIM/v1/Chassis/3"},{"@odata.id":"/ODIM/v1/Chassis/4"}]}`
+		case strings.HasSuffix(url, "/ODIM/v1/Chassis/4"):
+			statusCode = http.StatusInternalServerError
+			respBody = `{"error":"internal error"}`
+		case strings.HasSuffix(url, "/Thermal"):
+			respBody = `{"@odata.id":"` + odataID + `","Id":"Thermal"}`
+		default:
+			id := url[strings.LastIndex(url, "/")+1:]
+			respBody = `{"@odata.id":"/ODIM/v1/Chassis/` + id + `","Id":"` + id + `","Thermal":{"@odata.id":"/ODIM/v1/Chassis/` + id + `/Thermal"}}`
+		}
+		return stubResponse(statusCode, respBody)
+	}
+	h := newTestRespHolder()
+	req := testPluginRequest(contactClient, "/redfish/v1/Chassis")
+
+	progress := h.getAllRootInfo(mockContext(), "", 10, 40, req, config.Data.AddComputeSkipResources.SkipResourceListUnderChassis)
+	assert.Equal(t, int32(40), progress, "progress should be advanced for each discovered member")
+	assert.True(t, maxActiveCalls <= int32(config.Data.DiscoveryConf.RootInfoWorkerCount), "members should be discovered with a bounded pool")
+	for _, id := range []string{"1", "2", "3"} {
+		assert.Contains(t, h.InventoryData, "Chassis:/redfish/v1/Chassis/someuuid."+id, "chassis member should be discovered")
+		assert.Contains(t, h.InventoryData, "Thermal:/redfish/v1/Chassis/someuuid."+id+"/Thermal", "linked resource should be discovered")
+	}
+	assert.NotContains(t, h.InventoryData, "Chassis:/redfish/v1/Chassis/someuuid.4", "failed member should not be discovered")
+	assert.Equal(t, int32(http.StatusInternalServerError), h.StatusCode, "failure of a member should be reported")
+}
+
+// linkedResourceClient returns the contact client serving the resources with the links to the next
+// resources, the resources are fetched from the plugin in the order recorded in fetched
+func linkedResourceClient(next map[string][]string, fetched *[]string) func(context.Context, string, string, string, string, interface{}, map[string]string) (*http.Response, error) {
+	var lock sync.Mutex
+	return func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		oid := "/redfish/v1/" + url[strings.Index(url, "/ODIM/v1/")+len("/ODIM/v1/"):]
+		lock.Lock()
+		*fetched = append(*fetched, oid)
+		lock.Unlock()
+		var links []string
+		for _, link := range next[oid] {
+			links = append(links, `{"@odata.id":"`+link+`"}`)
+		}
+		respBody := `{"@odata.id":"` + oid + `","Id":"` + oid[strings.LastIndex(oid, "/")+1:] + `","Links":{"Next":[` + strings.Join(links, ",") + `]}}`
+		return stubResponse(http.StatusOK, respBody)
+	}
+}
+
+func Test_getResourceDetailsCycle(t *testing.T) {
+	config.SetUpMockConfig(t)
+	// A links B links C links A back, beyond the direct parent of A
+	var fetched []string
+	next := map[string][]string{
+		"/redfish/v1/Fabrics/1/Nodes/A": {"/redfish/v1/Fabrics/1/Nodes/B"},
+		"/redfish/v1/Fabrics/1/Nodes/B": {"/redfish/v1/Fabrics/1/Nodes/C"},
+		"/redfish/v1/Fabrics/1/Nodes/C": {"/redfish/v1/Fabrics/1/Nodes/A/", "/redfish/v1/Fabrics/1/Nodes/B"},
+	}
+	h := newTestRespHolder()
+	req := testPluginRequest(linkedResourceClient(next, &fetched), "/redfish/v1/Fabrics/1/Nodes/A")
+
+	done := make(chan struct{})
+	go func() {
+		h.getResourceDetails(mockContext(), "", 0, 30, req)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("discovery of the cycle should terminate")
+	}
+	assert.Equal(t, []string{"/redfish/v1/Fabrics/1/Nodes/A", "/redfish/v1/Fabrics/1/Nodes/B", "/redfish/v1/Fabrics/1/Nodes/C"}, fetched, "each resource of the cycle should be fetched once")
+	assert.Empty(t, h.Warnings, "cycle should not hit the traversal depth")
+	assert.Equal(t, int32(0), h.StatusCode, "cycle should not fail the discovery")
+}
+
+func Test_getResourceDetailsMaxTraversalDepth(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.MaxTraversalDepth = 3
+	var fetched []string
+	next := make(map[string][]string)
+	for i := 0; i < 6; i++ {
+		next[fmt.Sprintf("/redfish/v1/Fabrics/1/Nodes/N%d", i)] = []string{fmt.Sprintf("/redfish/v1/Fabrics/1/Nodes/N%d", i+1)}
+	}
+	h := newTestRespHolder()
+	req := testPluginRequest(linkedResourceClient(next, &fetched), "/redfish/v1/Fabrics/1/Nodes/N0")
+
+	progress := h.getResourceDetails(mockContext(), "", 0, 30, req)
+	assert.Equal(t, int32(30), progress, "work of the links not followed should be accounted as done")
+	assert.Equal(t, []string{
+		"/redfish/v1/Fabrics/1/Nodes/N0",
+		"/redfish/v1/Fabrics/1/Nodes/N1",
+		"/redfish/v1/Fabrics/1/Nodes/N2",
+		"/redfish/v1/Fabrics/1/Nodes/N3",
+	}, fetched, "resources beyond the maximum traversal depth should not be fetched")
+	if assert.Len(t, h.Warnings, 1, "link beyond the maximum traversal depth should be warned") {
+		assert.Contains(t, h.Warnings[0], "/redfish/v1/Fabrics/1/Nodes/N4")
+	}
+	assert.NotContains(t, h.TraversedLinks, "/redfish/v1/Fabrics/1/Nodes/N4", "link not followed should not be marked traversed")
+	assert.Equal(t, int32(0), h.StatusCode, "traversal depth should not fail the discovery")
+}
+
+func Test_getLinksMaxLinksPerResource(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.MaxLinksPerResource = 1000
+	var members []interface{}
+	for i := 0; i < 5000; i++ {
+		members = append(members, map[string]interface{}{"@odata.id": fmt.Sprintf("/redfish/v1/Chassis/1/Oem/Links/%d", i)})
+	}
+	resource := map[string]interface{}{
+		"@odata.id": "/redfish/v1/Chassis/1",
+		"Oem":       map[string]interface{}{"Links": members},
+	}
+	retrievalLinks := make(map[string]bool)
+	dropped := getLinks(resource, retrievalLinks, false)
+	assert.Len(t, retrievalLinks, 1000, "links beyond MaxLinksPerResource should not be collected")
+	assert.Equal(t, 4001, dropped, "number of links not collected should be returned")
+
+	// links already collected are not counted as dropped
+	assert.Equal(t, 0, getLinks(map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/1"}, retrievalLinks, false))
+
+	config.Data.DiscoveryConf.MaxLinksPerResource = 0
+	retrievalLinks = make(map[string]bool)
+	collectLinks(mockContext(), "/redfish/v1/Chassis/1", resource, retrievalLinks, false)
+	assert.Len(t, retrievalLinks, 5001, "links should not be limited when MaxLinksPerResource is not set")
+}
+
+func Test_getTranslationURL(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.URLTranslation.PluginOverrides = map[string]config.URLTranslationOverride{
+		"GRF": {
+			NorthBoundURL: map[string]string{"ODIM": "redfish", "/site-a": ""},
+			SouthBoundURL: map[string]string{"redfish": "site-a/ODIM"},
+		},
+	}
+	assert.Equal(t, map[string]string{"ODIM": "redfish", "/site-a": ""}, getTranslationURL(northBoundURL, "GRF"), "north bound override should be merged over the global translation")
+	assert.Equal(t, map[string]string{"redfish": "site-a/ODIM"}, getTranslationURL(southBoundURL, "GRF"), "south bound override should replace the global translation")
+	assert.Equal(t, map[string]string{"ODIM": "redfish"}, getTranslationURL(northBoundURL, "ILO"), "global translation should be used for the other plugins")
+	assert.Equal(t, map[string]string{"redfish": "ODIM"}, getTranslationURL(southBoundURL, "ILO"), "global translation should be used for the other plugins")
+
+	var contactedURL string
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			contactedURL = url
+			return stubResponse(http.StatusOK, `{}`)
+		},
+		OID:            "/redfish/v1/Systems/1",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			ID:                "GRF",
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	callPlugin(mockContext(), req)
+	assert.Equal(t, "https://localhost:9091/site-a/ODIM/v1/Systems/1", contactedURL, "override should be applied for the plugin")
+	req.Plugin.ID = "ILO"
+	callPlugin(mockContext(), req)
+	assert.Equal(t, "https://localhost:9091/ODIM/v1/Systems/1", contactedURL, "global translation should be applied for the other plugins")
+}
+
+func Test_translateURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		translations map[string]string
+		data         string
+		want         string
+	}{
+		{
+			name:         "longer key is preferred over its prefix",
+			translations: map[string]string{"/redfish/v1": "/ODIM/v1", "/redfish/v1/Systems": "/ODIM/v1/Compute", "redfish": "legacy"},
+			data:         `{"@odata.id":"/redfish/v1/Systems/1","Chassis":"/redfish/v1/Chassis/1","Name":"redfish"}`,
+			want:         `{"@odata.id":"/ODIM/v1/Compute/1","Chassis":"/ODIM/v1/Chassis/1","Name":"legacy"}`,
+		},
+		{
+			name:         "replaced text is not translated again",
+			translations: map[string]string{"ODIM": "redfish", "redfish": "ODIM"},
+			data:         "/ODIM/v1/redfish",
+			want:         "/redfish/v1/ODIM",
+		},
+		{
+			name:         "empty key is ignored",
+			translations: map[string]string{"": "x", "ODIM": "redfish"},
+			data:         "/ODIM/v1",
+			want:         "/redfish/v1",
+		},
+		{
+			name: "without translations",
+			data: "/ODIM/v1",
+			want: "/ODIM/v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the translations are applied in the same order irrespective of the map iteration
+			for i := 0; i < 20; i++ {
+				assert.Equal(t, tt.want, translateURL(tt.data, tt.translations))
+			}
+		})
+	}
+}
+
+func Test_contactPluginOverlappingTranslations(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.URLTranslation.NorthBoundURL = map[string]string{"ODIM": "redfish", "/ODIM/v1/Compute": "/redfish/v1/Systems"}
+	config.Data.URLTranslation.SouthBoundURL = map[string]string{"redfish": "ODIM", "/redfish/v1/Systems": "/ODIM/v1/Compute"}
+	var contactedURL string
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			contactedURL = url
+			respBody := `{"@odata.id":"/ODIM/v1/Compute/1","Links":{"Chassis":[{"@odata.id":"/ODIM/v1/Chassis/1"}]}}`
+			return stubResponse(http.StatusOK, respBody)
+		},
+		OID:            "/redfish/v1/Systems/1",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	for i := 0; i < 20; i++ {
+		body, _, _, err := contactPlugin(mockContext(), req, "")
+		assert.Nil(t, err)
+		assert.Equal(t, "https://localhost:9091/ODIM/v1/Compute/1", contactedURL, "south bound translation should prefer the longer key")
+		assert.Equal(t, `{"@odata.id":"/redfish/v1/Systems/1","Links":{"Chassis":[{"@odata.id":"/redfish/v1/Chassis/1"}]}}`, string(body),
+			"north bound translation should prefer the longer key")
+	}
+}
+
+func Test_callPluginSouthBoundTranslations(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.URLTranslation.SouthBoundURL = map[string]string{"redfish": "ODIM", "Systems": "Compute"}
+	var contactedURL, contactedOID string
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			contactedURL, contactedOID = url, odataID
+			return stubResponse(http.StatusOK, `{}`)
+		},
+		OID:            "/redfish/v1/Systems/1",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	// every south bound translation should be applied, whichever of them is iterated last
+	for i := 0; i < 20; i++ {
+		callPlugin(mockContext(), req)
+		assert.Equal(t, "https://localhost:9091/ODIM/v1/Compute/1", contactedURL, "all the south bound translations should be applied")
+		assert.Equal(t, "/ODIM/v1/Compute/1", contactedOID, "all the south bound translations should be applied")
+	}
+}
+
+func Test_getMemberODataID(t *testing.T) {
+	tests := []struct {
+		name   string
+		member interface{}
+		want   string
+		wantOk bool
+	}{
+		{name: "valid member", member: map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/1/"}, want: "/redfish/v1/Chassis/1", wantOk: true},
+		{name: "missing @odata.id", member: map[string]interface{}{"Id": "1"}},
+		{name: "null @odata.id", member: map[string]interface{}{"@odata.id": nil}},
+		{name: "non string @odata.id", member: map[string]interface{}{"@odata.id": 1.0}},
+		{name: "null member", member: nil},
+		{name: "non object member", member: "/redfish/v1/Chassis/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := getMemberODataID(tt.member)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
+
+func Test_getAllRootInfoMalformedMembers(t *testing.T) {
+	config.SetUpMockConfig(t)
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody := `{"Members":[{"@odata.id":"/ODIM/v1/Managers/1"},{"Id":"2"},{"@odata.id":null},null]}`
+		if strings.HasSuffix(url, "/ODIM/v1/Managers/1") {
+			respBody = `{"@odata.id":"/ODIM/v1/Managers/1","Id":"1"}`
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	h := newTestRespHolder()
+	req := testPluginRequest(contactClient, "/redfish/v1/Managers")
+
+	progress := h.getAllRootInfo(mockContext(), "", 0, 40, req, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+	assert.Equal(t, int32(40), progress, "progress should be advanced for the skipped members and the member without links")
+	assert.Contains(t, h.InventoryData, "Managers:/redfish/v1/Managers/someuuid.1", "valid member should be discovered")
+	assert.Len(t, h.InventoryData, 1, "malformed members should be skipped")
+}
+
+func Test_discoveryMalformedResponses(t *testing.T) {
+	config.SetUpMockConfig(t)
+	type discoverFunc func(h *respHolder, req getResourceRequest)
+	getAllSystemInfo := func(h *respHolder, req getResourceRequest) {
+		_, _, _, err := h.getAllSystemInfo(mockContext(), "", 0, 10, req)
+		assert.NotNil(t, err, "malformed system collection should fail the discovery")
+	}
+	getSystemInfo := func(h *respHolder, req getResourceRequest) {
+		_, _, _, err := h.getSystemInfo(mockContext(), "", 0, 10, req)
+		assert.NotNil(t, err, "malformed system should fail the discovery")
+	}
+	getAllRootInfo := func(h *respHolder, req getResourceRequest) {
+		h.getAllRootInfo(mockContext(), "", 0, 10, req, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+	}
+	getResourceDetails := func(h *respHolder, req getResourceRequest) {
+		h.getResourceDetails(mockContext(), "", 0, 10, req)
+	}
+	tests := []struct {
+		name     string
+		oid      string
+		body     string
+		discover discoverFunc
+	}{
+		{name: "system collection with object Members", oid: "/redfish/v1/Systems", body: `{"Members":{"@odata.id":"/ODIM/v1/Systems/1"}}`, discover: getAllSystemInfo},
+		{name: "system collection with string Members", oid: "/redfish/v1/Systems", body: `{"Members":"/ODIM/v1/Systems/1"}`, discover: getAllSystemInfo},
+		{name: "system with number @odata.id", oid: "/redfish/v1/Systems/1", body: `{"@odata.id":1,"Id":"1","UUID":"uuid"}`, discover: getSystemInfo},
+		{name: "system with number Id", oid: "/redfish/v1/Systems/1", body: `{"@odata.id":"/ODIM/v1/Systems/1","Id":1,"UUID":"uuid"}`, discover: getSystemInfo},
+		{name: "system without UUID", oid: "/redfish/v1/Systems/1", body: `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1"}`, discover: getSystemInfo},
+		{name: "manager collection with object Members", oid: "/redfish/v1/Managers", body: `{"Members":{"@odata.id":"/ODIM/v1/Managers/1"}}`, discover: getAllRootInfo},
+		{name: "manager collection with number member @odata.id", oid: "/redfish/v1/Managers", body: `{"Members":[{"@odata.id":1}]}`, discover: getAllRootInfo},
+		{name: "collection with object Members", oid: "/redfish/v1/Systems/1/Memory", body: `{"Members":{"@odata.id":"/ODIM/v1/Systems/1/Memory/1"}}`, discover: getResourceDetails},
+		{name: "collection with number member @odata.id", oid: "/redfish/v1/Systems/1/Memory", body: `{"Members":[{"@odata.id":1}]}`, discover: getResourceDetails},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+				return stubResponse(http.StatusOK, tt.body)
+			}
+			h := newTestRespHolder()
+			req := testPluginRequest(contactClient, tt.oid)
+			req.SystemID = "1"
+			assert.NotPanics(t, func() { tt.discover(h, req) }, "malformed response should not panic")
+			assert.Equal(t, int32(http.StatusInternalServerError), h.StatusCode)
+			assert.Equal(t, response.InternalError, h.StatusMessage)
+			assert.Contains(t, h.ErrorMessage, tt.oid, "error should have the OID of the malformed response")
+			if assert.NotEmpty(t, h.Problems, "malformed response should be reported as a problem") {
+				assert.Equal(t, tt.oid, h.Problems[0].OID)
+			}
+		})
+	}
+}
+
+func Test_getSystemInfoRequiredFields(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		err := common.TruncateDB(common.OnDisk)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		err = common.TruncateDB(common.InMemory)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}()
+	tests := []struct {
+		name       string
+		body       string
+		wantErr    string
+		wantID     string
+		wantOIDKey string
+	}{
+		{
+			name:    "system without UUID",
+			body:    `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1"}`,
+			wantErr: "UUID of the system is missing",
+		},
+		{
+			name:    "system with empty UUID",
+			body:    `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1","UUID":""}`,
+			wantErr: "UUID of the system is empty",
+		},
+		{
+			name:    "system without Id",
+			body:    `{"@odata.id":"/ODIM/v1/Systems/1","UUID":"b6c5c2ea-1a1f-4bd1-b4f7-4bd06f1a9e41"}`,
+			wantErr: "Id of the system is missing",
+		},
+		{
+			name:       "valid system",
+			body:       `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1","UUID":"b6c5c2ea-1a1f-4bd1-b4f7-4bd06f1a9e41"}`,
+			wantID:     "1",
+			wantOIDKey: "/redfish/v1/Systems/someuuid.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+				if strings.HasSuffix(url, "/ODIM/v1/Systems/1") {
+					return stubResponse(http.StatusOK, tt.body)
+				}
+				return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+			}
+			h := newTestRespHolder()
+			req := testPluginRequest(contactClient, "/redfish/v1/Systems/1")
+			req.BMCAddress = "10.0.0.1"
+			var (
+				systemID, oidKey string
+				err              error
+			)
+			assert.NotPanics(t, func() { systemID, oidKey, _, err = h.getSystemInfo(mockContext(), "", 0, 10, req) })
+			if tt.wantErr != "" {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), tt.wantErr)
+				}
+				assert.Equal(t, int32(http.StatusInternalServerError), h.StatusCode)
+				assert.Equal(t, response.InternalError, h.StatusMessage)
+				assert.Contains(t, h.ErrorMessage, "/redfish/v1/Systems/1", "error should have the OID of the system")
+				assert.Empty(t, h.InventoryData, "invalid system should not be saved")
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantID, systemID)
+			assert.Equal(t, tt.wantOIDKey, oidKey)
+			assert.Contains(t, h.SystemURL, tt.wantOIDKey)
+			indexList, err := agmodel.GetString("UUID", "b6c5c2ea-1a1f-4bd1-b4f7-4bd06f1a9e41")
+			assert.Nil(t, err)
+			assert.NotEmpty(t, indexList, "UUID of the valid system should be indexed")
+		})
+	}
+}
+
+func Test_getStorageInfoRequiredFields(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		err := common.TruncateDB(common.OnDisk)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		err = common.TruncateDB(common.InMemory)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}()
+	tests := []struct {
+		name        string
+		storageBody string
+		systemData  string
+		wantErr     string
+	}{
+		{
+			name:        "storage without @odata.id",
+			storageBody: `{"Members":[]}`,
+			systemData:  `{"Id":"1","UUID":"b6c5c2ea-1a1f-4bd1-b4f7-4bd06f1a9e41"}`,
+			wantErr:     "@odata.id of the storage is missing",
+		},
+		{
+			name:        "system without Id",
+			storageBody: `{"@odata.id":"/ODIM/v1/Systems/1/Storage","Members":[]}`,
+			systemData:  `{"UUID":"b6c5c2ea-1a1f-4bd1-b4f7-4bd06f1a9e41"}`,
+			wantErr:     "Id of the system is missing",
+		},
+		{
+			name:        "system without UUID",
+			storageBody: `{"@odata.id":"/ODIM/v1/Systems/1/Storage","Members":[]}`,
+			systemData:  `{"Id":"1"}`,
+			wantErr:     "UUID of the system is missing",
+		},
+		{
+			name:        "valid storage",
+			storageBody: `{"@odata.id":"/ODIM/v1/Systems/1/Storage","Members":[]}`,
+			systemData:  `{"Id":"1","UUID":"b6c5c2ea-1a1f-4bd1-b4f7-4bd06f1a9e41"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := agmodel.GenericSave([]byte(tt.systemData), "ComputerSystem", "/redfish/v1/Systems/someuuid.1")
+			assert.Nil(t, err, "system should be saved")
+			contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+				return stubResponse(http.StatusOK, tt.storageBody)
+			}
+			h := newTestRespHolder()
+			req := testPluginRequest(contactClient, "/redfish/v1/Systems/1/Storage")
+			var oidKey string
+			assert.NotPanics(t, func() { oidKey, _, err = h.getStorageInfo(mockContext(), 0, 10, req) })
+			if tt.wantErr != "" {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), tt.wantErr)
+				}
+				assert.Empty(t, oidKey)
+				assert.Equal(t, int32(http.StatusInternalServerError), h.StatusCode)
+				assert.Equal(t, response.InternalError, h.StatusMessage)
+				return
+			}
+			assert.Nil(t, err)
+			assert.NotEmpty(t, oidKey)
+		})
+	}
+}
+
+func Test_getAllRootInfoCancelled(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.RootInfoWorkerCount = 1
+	ctx, cancel := context.WithCancel(mockContext())
+	defer cancel()
+	var contactedURLs []string
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		contactedURLs = append(contactedURLs, url)
+		respBody := `{"Members":[{"@odata.id":"/ODIM/v1/Managers/1"},{"@odata.id":"/ODIM/v1/Managers/2"},{"@odata.id":"/ODIM/v1/Managers/3"}]}`
+		if strings.HasSuffix(url, "/ODIM/v1/Managers/1") {
+			// the request is cancelled while discovering the first member
+			cancel()
+			respBody = `{"@odata.id":"/ODIM/v1/Managers/1","Id":"1","EthernetInterfaces":{"@odata.id":"/ODIM/v1/Managers/1/EthernetInterfaces"}}`
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	h := newTestRespHolder()
+	req := testPluginRequest(contactClient, "/redfish/v1/Managers")
+
+	progress := h.getAllRootInfo(ctx, "", 0, 30, req, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+	assert.Len(t, contactedURLs, 2, "plugin should not be contacted after the discovery is cancelled")
+	assert.Less(t, progress, int32(30), "progress should stop where the discovery is cancelled")
+	assert.Equal(t, int32(http.StatusRequestTimeout), h.StatusCode)
+	assert.Contains(t, h.ErrorMessage, context.Canceled.Error())
+	assert.True(t, h.hasFatalError(), "cancelled discovery should fail, so that the partial data is rolled back")
+
+	// the discovery of the other roots is not started once the request is cancelled
+	req.OID = "/redfish/v1/Chassis"
+	h.getAllRootInfo(ctx, "", progress, 10, req, config.Data.AddComputeSkipResources.SkipResourceListUnderChassis)
+	assert.Len(t, contactedURLs, 2, "plugin should not be contacted after the discovery is cancelled")
+}
+
+func Test_checkCancelledPollInterval(t *testing.T) {
+	defer func(interval time.Duration) {
+		cancelPollInterval = interval
+	}(cancelPollInterval)
+	cancelPollInterval = time.Hour
+
+	var polls int
+	var cancelRequested bool
+	h := newTestRespHolder()
+	h.cancelRequestKey = getCancelRequestKey("100.0.0.1")
+	h.checkActiveRequest = func(key string) (bool, *errors.Error) {
+		polls++
+		return cancelRequested, nil
+	}
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, h.checkCancelled(mockContext(), "/redfish/v1/Systems/1"))
+	}
+	assert.Equal(t, 1, polls, "marker should be polled once in the poll interval")
+
+	// the marker is polled again once the interval is elapsed
+	cancelRequested = true
+	h.cancelPolledAt = time.Now().Add(-cancelPollInterval)
+	assert.NotNil(t, h.checkCancelled(mockContext(), "/redfish/v1/Systems/1"))
+	assert.Equal(t, 2, polls)
+	assert.Equal(t, int32(discoveryCancelledStatus.StatusCode), h.StatusCode)
+
+	// the marker isn't polled again once it is found
+	for i := 0; i < 10; i++ {
+		assert.NotNil(t, h.checkCancelled(mockContext(), "/redfish/v1/Systems/1"))
+	}
+	assert.Equal(t, 2, polls)
+}
+
+func Test_getLinkIntegrityReport(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.ReportLinkIntegrity = true
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		statusCode := http.StatusOK
+		respBody := `{"@odata.id":"/ODIM/v1/Managers/1","Id":"1","EthernetInterfaces":{"@odata.id":"/ODIM/v1/Managers/1/EthernetInterfaces"},"LogServices":{"@odata.id":"/ODIM/v1/Managers/1/LogServices"}}`
+		switch {
+		case strings.HasSuffix(url, "/EthernetInterfaces"):
+			// the device advertises the link which doesn't exist
+			statusCode = http.StatusNotFound
+			respBody = `{"error":"not found"}`
+		case strings.HasSuffix(url, "/LogServices"):
+			statusCode = http.StatusServiceUnavailable
+			respBody = `{"error":"busy"}`
+		}
+		return stubResponse(statusCode, respBody)
+	}
+	h := newTestRespHolder()
+	req := testPluginRequest(contactClient, "/redfish/v1/Managers/1")
+	_, err := h.getIndivdualInfo(mockContext(), "", 0, 10, req, nil)
+	assert.Nil(t, err)
+	assert.False(t, h.hasFatalError(), "broken links should not fail the discovery")
+
+	report := getLinkIntegrityReport(h.Problems)
+	assert.Equal(t, []string{"/redfish/v1/Managers/1/EthernetInterfaces"}, report.BrokenLinks)
+	if assert.Len(t, report.UnreachableLinks, 1) {
+		assert.Equal(t, "/redfish/v1/Managers/1/LogServices", report.UnreachableLinks[0].OID)
+		assert.Equal(t, int32(http.StatusServiceUnavailable), report.UnreachableLinks[0].StatusCode)
+	}
+
+	// the resources not contacted after the discovery is stopped are not reported
+	report = getLinkIntegrityReport([]discoveryProblem{{OID: "/redfish/v1/Chassis", StatusCode: discoveryCancelledStatus.StatusCode}})
+	assert.Empty(t, report.BrokenLinks)
+	assert.Empty(t, report.UnreachableLinks)
+}
+
+func Test_getVirtualMediaInfo(t *testing.T) {
+	config.SetUpMockConfig(t)
+	// VirtualMedia skipped under managers should still be discovered
+	config.Data.AddComputeSkipResources.SkipResourceListUnderManager = append(config.Data.AddComputeSkipResources.SkipResourceListUnderManager, "VirtualMedia")
+	device := map[string]string{
+		"/ODIM/v1/Managers":                    `{"Members":[{"@odata.id":"/ODIM/v1/Managers/1"},{"@odata.id":"/ODIM/v1/Managers/2"}]}`,
+		"/ODIM/v1/Managers/1":                  `{"@odata.id":"/ODIM/v1/Managers/1","Id":"1","VirtualMedia":{"@odata.id":"/ODIM/v1/Managers/1/VirtualMedia"}}`,
+		"/ODIM/v1/Managers/2":                  `{"@odata.id":"/ODIM/v1/Managers/2","Id":"2"}`,
+		"/ODIM/v1/Managers/1/VirtualMedia":     `{"@odata.id":"/ODIM/v1/Managers/1/VirtualMedia","Members":[{"@odata.id":"/ODIM/v1/Managers/1/VirtualMedia/CD1"}]}`,
+		"/ODIM/v1/Managers/1/VirtualMedia/CD1": `{"@odata.id":"/ODIM/v1/Managers/1/VirtualMedia/CD1","Id":"CD1","Inserted":false,"MediaTypes":["CD","DVD"]}`,
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody, ok := device[strings.TrimPrefix(url, "https://localhost:9091")]
+		if !ok {
+			return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := testPluginRequest(contactClient, "/redfish/v1/Managers")
+	newHolder := func() *respHolder {
+		h := newTestRespHolder()
+		h.getAllRootInfo(mockContext(), "", 0, 10, req, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+		return h
+	}
+
+	h := newTestRespHolder()
+	assert.NotContains(t, h.InventoryData, "VirtualMediaCollection:/redfish/v1/Managers/someuuid.1/VirtualMedia", "VirtualMedia should be skipped by the skip list")
+	h.getVirtualMediaInfo(mockContext(), "", 0, 0, req)
+	assert.Contains(t, h.InventoryData, "VirtualMediaCollection:/redfish/v1/Managers/someuuid.1/VirtualMedia", "VirtualMedia collection should be discovered")
+	assert.Contains(t, h.InventoryData, "VirtualMedia:/redfish/v1/Managers/someuuid.1/VirtualMedia/CD1", "VirtualMedia member should be discovered")
+	assert.Empty(t, h.ErrorMessage, "manager without VirtualMedia should be skipped")
+
+	config.Data.DiscoveryConf.DiscoverVirtualMedia = false
+	h = newTestRespHolder()
+	h.getVirtualMediaInfo(mockContext(), "", 0, 0, req)
+	assert.NotContains(t, h.InventoryData, "VirtualMediaCollection:/redfish/v1/Managers/someuuid.1/VirtualMedia", "VirtualMedia should not be discovered when disabled")
+}
+
+func Test_getManagerProtocolInfo(t *testing.T) {
+	config.SetUpMockConfig(t)
+	// NetworkProtocol and SerialInterfaces skipped under managers should still be discovered
+	config.Data.AddComputeSkipResources.SkipResourceListUnderManager = append(config.Data.AddComputeSkipResources.SkipResourceListUnderManager, "NetworkProtocol", "SerialInterfaces")
+	device := map[string]string{
+		"/ODIM/v1/Managers":                      `{"Members":[{"@odata.id":"/ODIM/v1/Managers/1"},{"@odata.id":"/ODIM/v1/Managers/2"}]}`,
+		"/ODIM/v1/Managers/1":                    `{"@odata.id":"/ODIM/v1/Managers/1","Id":"1","NetworkProtocol":{"@odata.id":"/ODIM/v1/Managers/1/NetworkProtocol"},"SerialInterfaces":{"@odata.id":"/ODIM/v1/Managers/1/SerialInterfaces"}}`,
+		"/ODIM/v1/Managers/2":                    `{"@odata.id":"/ODIM/v1/Managers/2","Id":"2"}`,
+		"/ODIM/v1/Managers/1/NetworkProtocol":    `{"@odata.id":"/ODIM/v1/Managers/1/NetworkProtocol","Id":"NetworkProtocol","HTTPS":{"ProtocolEnabled":true,"Port":443},"SSH":{"ProtocolEnabled":false,"Port":22}}`,
+		"/ODIM/v1/Managers/1/SerialInterfaces":   `{"@odata.id":"/ODIM/v1/Managers/1/SerialInterfaces","Members":[{"@odata.id":"/ODIM/v1/Managers/1/SerialInterfaces/1"}]}`,
+		"/ODIM/v1/Managers/1/SerialInterfaces/1": `{"@odata.id":"/ODIM/v1/Managers/1/SerialInterfaces/1","Id":"1","InterfaceEnabled":true}`,
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody, ok := device[strings.TrimPrefix(url, "https://localhost:9091")]
+		if !ok {
+			return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := testPluginRequest(contactClient, "/redfish/v1/Managers")
+	newHolder := func() *respHolder {
+		h := newTestRespHolder()
+		h.getAllRootInfo(mockContext(), "", 0, 10, req, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+		return h
+	}
+
+	h := newTestRespHolder()
+	assert.NotContains(t, h.InventoryData, "NetworkProtocol:/redfish/v1/Managers/someuuid.1/NetworkProtocol", "NetworkProtocol should be skipped by the skip list")
+	h.getManagerProtocolInfo(mockContext(), "", 0, 0, req)
+	if assert.Contains(t, h.InventoryData, "NetworkProtocol:/redfish/v1/Managers/someuuid.1/NetworkProtocol", "NetworkProtocol should be discovered") {
+		networkProtocol := h.InventoryData["NetworkProtocol:/redfish/v1/Managers/someuuid.1/NetworkProtocol"].(string)
+		assert.Contains(t, networkProtocol, `"@odata.id":"/redfish/v1/Managers/someuuid.1/NetworkProtocol"`, "NetworkProtocol should be saved with the device UUID")
+		assert.Contains(t, networkProtocol, `"Port":443`, "NetworkProtocol should be saved with the enabled services")
+	}
+	assert.Contains(t, h.InventoryData, "SerialInterfacesCollection:/redfish/v1/Managers/someuuid.1/SerialInterfaces", "SerialInterfaces collection should be discovered")
+	assert.Contains(t, h.InventoryData, "SerialInterfaces:/redfish/v1/Managers/someuuid.1/SerialInterfaces/1", "SerialInterfaces member should be discovered")
+	assert.Empty(t, h.ErrorMessage, "manager without NetworkProtocol and SerialInterfaces should be skipped")
+
+	config.Data.DiscoveryConf.DiscoverNetworkProtocol = false
+	config.Data.DiscoveryConf.DiscoverSerialInterfaces = false
+	h = newTestRespHolder()
+	h.getManagerProtocolInfo(mockContext(), "", 0, 0, req)
+	assert.NotContains(t, h.InventoryData, "NetworkProtocol:/redfish/v1/Managers/someuuid.1/NetworkProtocol", "NetworkProtocol should not be discovered when disabled")
+	assert.NotContains(t, h.InventoryData, "SerialInterfacesCollection:/redfish/v1/Managers/someuuid.1/SerialInterfaces", "SerialInterfaces should not be discovered when disabled")
+}
+
+func Test_getAllRootInfoProblems(t *testing.T) {
+	config.SetUpMockConfig(t)
+	device := map[string]string{
+		"/ODIM/v1/Managers":                      `{"Members":[{"@odata.id":"/ODIM/v1/Managers/1"},{"@odata.id":"/ODIM/v1/Managers/2"}]}`,
+		"/ODIM/v1/Managers/1":                    `{"@odata.id":"/ODIM/v1/Managers/1","Id":"1","EthernetInterfaces":{"@odata.id":"/ODIM/v1/Managers/1/EthernetInterfaces"}}`,
+		"/ODIM/v1/Managers/2":                    `{"@odata.id":"/ODIM/v1/Managers/2","Id":"2","NetworkProtocol":{"@odata.id":"/ODIM/v1/Managers/2/NetworkProtocol"}}`,
+		"/ODIM/v1/Managers/1/EthernetInterfaces": `{"@odata.id":"/ODIM/v1/Managers/1/EthernetInterfaces","Members":[]}`,
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		path := strings.TrimPrefix(url, "https://localhost:9091")
+		if path == "/ODIM/v1/Managers/1/EthernetInterfaces" {
+			return stubResponse(http.StatusBadGateway, `{"error":"bad gateway"}`)
+		}
+		respBody, ok := device[path]
+		if !ok {
+			return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := testPluginRequest(contactClient, "/redfish/v1/Managers")
+	h := newTestRespHolder()
+	h.getAllRootInfo(mockContext(), "", 0, 10, req, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+
+	problems := make(map[string]discoveryProblem)
+	for _, problem := range h.Problems {
+		problems[problem.OID] = problem
+	}
+	if assert.Len(t, problems, 2, "each of the failed resources should be reported") {
+		badGateway := problems["/redfish/v1/Managers/1/EthernetInterfaces"]
+		assert.Equal(t, int32(http.StatusBadGateway), badGateway.StatusCode)
+		assert.True(t, badGateway.Retryable, "bad gateway should be retryable")
+		assert.NotEmpty(t, badGateway.Message)
+		notFound := problems["/redfish/v1/Managers/2/NetworkProtocol"]
+		assert.Equal(t, int32(http.StatusNotFound), notFound.StatusCode)
+		assert.False(t, notFound.Retryable, "not found should not be retryable")
+	}
+
+	body, err := json.Marshal(problemReportResponse{DiscoveryProblems: h.Problems})
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), `"DiscoveryProblems":[`, "problems should be reported in the response body")
+}
+
+func Test_getResourceDetailsSubResourceErrorPolicy(t *testing.T) {
+	config.SetUpMockConfig(t)
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		if strings.HasSuffix(url, "/Processors/1") {
+			return stubResponse(http.StatusBadGateway, `{"error":"bad gateway"}`)
+		}
+		respBody := `{"@odata.id":"/redfish/v1/Systems/1/Processors","Members":[{"@odata.id":"/ODIM/v1/Systems/1/Processors/1"}]}`
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := testPluginRequest(contactClient, "/redfish/v1/Systems/1/Processors")
+	req.ParentOID = "/redfish/v1/Systems/1"
+	req.SystemID = "1"
+	tests := []struct {
+		name          string
+		policy        string
+		wantProgress  int32
+		wantWarnings  int
+		wantFatal     bool
+		wantErrorCode int32
+	}{
+		{name: "warn policy", policy: config.SubResourceErrorPolicyWarn, wantProgress: 10, wantWarnings: 1, wantFatal: false},
+		{name: "fail policy", policy: config.SubResourceErrorPolicyFail, wantProgress: 5, wantWarnings: 0, wantFatal: true, wantErrorCode: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Data.DiscoveryConf.SubResourceErrorPolicy = tt.policy
+			h := newTestRespHolder()
+			progress := h.getResourceDetails(mockContext(), "", 0, 10, req)
+			assert.Equal(t, tt.wantProgress, progress)
+			assert.Len(t, h.Warnings, tt.wantWarnings)
+			assert.Equal(t, tt.wantFatal, h.hasFatalError())
+			assert.Equal(t, tt.wantErrorCode, h.StatusCode)
+			assert.Contains(t, h.InventoryData, "ProcessorsCollection:/redfish/v1/Systems/someuuid.1/Processors", "parent resource should be discovered")
+		})
+	}
+}
+
+func Test_getResourceDetailsResourceTypeIndex(t *testing.T) {
+	config.SetUpMockConfig(t)
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody := `{"@odata.id":"/ODIM/v1/Systems/1/Processors","@odata.type":"#ProcessorCollection.ProcessorCollection","Members":[{"@odata.id":"/ODIM/v1/Systems/1/Processors/1"}]}`
+		if strings.HasSuffix(url, "/Processors/1") {
+			respBody = `{"@odata.id":"/ODIM/v1/Systems/1/Processors/1","@odata.type":"#Processor.v1_0_0.Processor","Id":"1"}`
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := testPluginRequest(contactClient, "/redfish/v1/Systems/1/Processors")
+	req.ParentOID = "/redfish/v1/Systems/1"
+	req.SystemID = "1"
+	h := newTestRespHolder()
+	h.getResourceDetails(mockContext(), "", 0, 10, req)
+	assert.Equal(t, "#ProcessorCollection.ProcessorCollection", h.InventoryData[agmodel.ResourceTypeTable+":/redfish/v1/Systems/someuuid.1/Processors"])
+	assert.Equal(t, "#Processor.v1_0_0.Processor", h.InventoryData[agmodel.ResourceTypeTable+":/redfish/v1/Systems/someuuid.1/Processors/1"])
+}
+
+func Test_checkStatus(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(orig func(string) error) { CheckEMBQueueAvailability = orig }(CheckEMBQueueAvailability)
+	CheckEMBQueueAvailability = func(queueName string) error { return nil }
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		if url == "https://localhost:9091/ODIM/v1/Status" {
+			return stubResponse(http.StatusOK, `{"Version": "1.0.0","EventMessageBus":{"EmbQueue":[{"EmbQueueName":"GRF"}]}}`)
+		}
+		return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+	}
+	pluginContactRequest := getResourceRequest{
+		ContactClient: contactClient,
+	}
+	tests := []struct {
+		name               string
+		managerAddress     string
+		firmwareVersion    string
+		allowMinorMismatch bool
+		wantStatusCode     int32
+		wantQueueList      []string
+		wantPluginVersion  string
+		wantErrorResponse  bool
+	}{
+		{name: "plugin is added", managerAddress: "localhost:9091", firmwareVersion: "1.0.0", wantStatusCode: http.StatusOK, wantQueueList: []string{"GRF"}, wantPluginVersion: "1.0.0"},
+		{name: "exact match with minor mismatch allowed", managerAddress: "localhost:9091", firmwareVersion: "1.0.0", allowMinorMismatch: true, wantStatusCode: http.StatusOK, wantQueueList: []string{"GRF"}, wantPluginVersion: "1.0.0"},
+		{name: "version mismatch", managerAddress: "localhost:9091", firmwareVersion: "2.0.0", wantStatusCode: http.StatusBadRequest, wantQueueList: []string{}, wantPluginVersion: "1.0.0", wantErrorResponse: true},
+		{name: "minor version mismatch", managerAddress: "localhost:9091", firmwareVersion: "1.2.0", wantStatusCode: http.StatusBadRequest, wantQueueList: []string{}, wantPluginVersion: "1.0.0", wantErrorResponse: true},
+		{name: "minor version mismatch allowed", managerAddress: "localhost:9091", firmwareVersion: "1.2.0", allowMinorMismatch: true, wantStatusCode: http.StatusOK, wantQueueList: []string{"GRF"}, wantPluginVersion: "1.0.0"},
+		{name: "major version mismatch with minor mismatch allowed", managerAddress: "localhost:9091", firmwareVersion: "2.0.0", allowMinorMismatch: true, wantStatusCode: http.StatusBadRequest, wantQueueList: []string{}, wantPluginVersion: "1.0.0", wantErrorResponse: true},
+		{name: "status not found for BMC", managerAddress: "localhost:9092", firmwareVersion: "1.0.0", wantStatusCode: http.StatusNotFound, wantQueueList: []string{}, wantErrorResponse: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := AddResourceRequest{
+				ManagerAddress: tt.managerAddress,
+				UserName:       "admin",
+				Password:       "password",
+			}
+			cmVariants := connectionMethodVariants{
+				PluginType:        "Compute",
+				PreferredAuthType: "BasicAuth",
+				PluginID:          "GRF",
+				FirmwareVersion:   tt.firmwareVersion,
+			}
+			config.Data.DiscoveryConf.AllowFirmwareMinorMismatch = tt.allowMinorMismatch
+			defer func() { config.Data.DiscoveryConf.AllowFirmwareMinorMismatch = false }()
+			result := checkStatus(mockContext(), pluginContactRequest, req, cmVariants, nil)
+			assert.Equal(t, tt.wantStatusCode, result.StatusCode)
+			assert.Equal(t, tt.wantQueueList, result.QueueList)
+			assert.Equal(t, tt.wantPluginVersion, result.PluginVersion)
+			assert.Equal(t, tt.wantErrorResponse, result.Response.StatusCode != 0)
+			if tt.wantStatusCode == http.StatusBadRequest {
+				body, _ := result.Response.Body.(response.CommonError)
+				if assert.Len(t, body.Error.MessageExtendedInfo, 2) {
+					assert.Equal(t, []interface{}{tt.firmwareVersion, "FirmwareVersion"}, body.Error.MessageExtendedInfo[0].MessageArgs, "expected version should be reported")
+					assert.Equal(t, []interface{}{"1.0.0", "Version"}, body.Error.MessageExtendedInfo[1].MessageArgs, "actual version should be reported")
+					assert.Contains(t, body.Error.MessageExtendedInfo[0].Message, "Firmware version 1.0.0 of the plugin GRF does not match the firmware version "+tt.firmwareVersion)
+				}
+			}
+		})
+	}
+
+	CheckEMBQueueAvailability = func(queueName string) error {
+		return fmt.Errorf("queue %s is not available", queueName)
+	}
+	req := AddResourceRequest{ManagerAddress: "localhost:9091", UserName: "admin", Password: "password"}
+	cmVariants := connectionMethodVariants{PluginType: "Compute", PreferredAuthType: "BasicAuth", PluginID: "GRF", FirmwareVersion: "1.0.0"}
+	result := checkStatus(mockContext(), pluginContactRequest, req, cmVariants, nil)
+	assert.Equal(t, int32(http.StatusOK), result.StatusCode, "unavailable queues should not fail the add")
+	assert.Equal(t, []string{"GRF"}, result.UnavailableQueues, "unavailable queues should be returned")
+}
+
+func Test_getIPAndPortFromAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		wantHost string
+		wantPort string
+	}{
+		{name: "IPv4 with port", address: "10.0.0.1:443", wantHost: "10.0.0.1", wantPort: "443"},
+		{name: "IPv4 without port", address: "10.0.0.1", wantHost: "10.0.0.1"},
+		{name: "host name with port", address: "bmc-1.odim.local:8443", wantHost: "bmc-1.odim.local", wantPort: "8443"},
+		{name: "host name without port", address: "bmc-1.odim.local", wantHost: "bmc-1.odim.local"},
+		{name: "bracketed IPv6 with port", address: "[fd00::1]:443", wantHost: "fd00::1", wantPort: "443"},
+		{name: "bracketed IPv6 without port", address: "[fd00::1]", wantHost: "fd00::1"},
+		{name: "bare IPv6", address: "fd00::1", wantHost: "fd00::1"},
+		{name: "bare IPv6 ending with a number", address: "fd00:0:0:0:0:0:0:443", wantHost: "fd00:0:0:0:0:0:0:443"},
+		{name: "bare IPv6 with zone", address: "fe80::1%eth0", wantHost: "fe80::1%eth0"},
+		{name: "bracketed IPv6 with zone and port", address: "[fe80::1%eth0]:443", wantHost: "fe80::1%eth0", wantPort: "443"},
+		{name: "bracketed IPv6 with zone without port", address: "[fe80::1%eth0]", wantHost: "fe80::1%eth0"},
+		{name: "IPv4-mapped IPv6 with port", address: "[::ffff:10.0.0.1]:443", wantHost: "::ffff:10.0.0.1", wantPort: "443"},
+		{name: "address with spaces", address: " 10.0.0.1:443 ", wantHost: "10.0.0.1", wantPort: "443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port := getIPAndPortFromAddress(tt.address)
+			assert.Equal(t, tt.wantHost, host)
+			assert.Equal(t, tt.wantPort, port)
+		})
+	}
+}
+
+func Test_getURLHost(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", getURLHost("10.0.0.1"))
+	assert.Equal(t, "bmc-1.odim.local", getURLHost("bmc-1.odim.local"))
+	assert.Equal(t, "[fd00::1]", getURLHost("fd00::1"))
+	assert.Equal(t, "[fe80::1%25eth0]", getURLHost("fe80::1%eth0"), "zone should be escaped")
+}
+
+func Test_getKeyFromManagerAddress(t *testing.T) {
+	defer func() {
+		agcommon.LookupIPfunc = net.LookupIP
+	}()
+	agcommon.LookupIPfunc = func(host string) ([]net.IP, error) {
+		if host == "bmc-1.odim.local" {
+			return []net.IP{net.ParseIP("10.0.0.5")}, nil
+		}
+		return nil, fmt.Errorf("no such host %s", host)
+	}
+	tests := []struct {
+		name    string
+		address string
+		want    string
+	}{
+		{name: "IPv4 with port", address: "10.0.0.1:443", want: "10.0.0.1:443"},
+		{name: "IPv4 without port", address: "10.0.0.1", want: "10.0.0.1"},
+		{name: "host name with port", address: "bmc-1.odim.local:443", want: "bmc-1.odim.local:443"},
+		{name: "host name without port is resolved", address: "bmc-1.odim.local", want: "10.0.0.5"},
+		{name: "unresolved host name", address: "unknown.odim.local", want: "unknown.odim.local"},
+		{name: "bracketed IPv6 with port", address: "[fd00::1]:443", want: "[fd00::1]:443"},
+		{name: "bracketed IPv6 without port", address: "[fd00::1]", want: "fd00::1"},
+		{name: "bare IPv6", address: "fd00::1", want: "fd00::1"},
+		{name: "bare IPv6 with zone", address: "fe80::1%eth0", want: "fe80::1%eth0"},
+		{name: "bracketed IPv6 with zone without port", address: "[fe80::1%eth0]", want: "fe80::1%eth0"},
+		{name: "bracketed IPv6 with zone and port", address: "[fe80::1%eth0]:443", want: "[fe80::1%eth0]:443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getKeyFromManagerAddress(tt.address))
+		})
+	}
+}
+
+func Test_checkStatusIPv6Address(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var contactedURL string
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		contactedURL = url
+		return stubResponse(http.StatusOK, `{"Version": "1.0.0"}`)
+	}
+	cmVariants := connectionMethodVariants{
+		PluginType:        "Compute",
+		PreferredAuthType: "BasicAuth",
+		PluginID:          "GRF",
+		FirmwareVersion:   "1.0.0",
+	}
+	tests := []struct {
+		name           string
+		managerAddress string
+		wantURL        string
+	}{
+		{name: "bracketed IPv6 with port", managerAddress: "[fd00::1]:45001", wantURL: "https://[fd00::1]:45001/ODIM/v1/Status"},
+		{name: "bracketed IPv6 with zone and port", managerAddress: "[fe80::1%eth0]:45001", wantURL: "https://[fe80::1%25eth0]:45001/ODIM/v1/Status"},
+		{name: "IPv4 with port", managerAddress: "10.0.0.1:45001", wantURL: "https://10.0.0.1:45001/ODIM/v1/Status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contactedURL = ""
+			req := AddResourceRequest{
+				ManagerAddress: tt.managerAddress,
+				UserName:       "admin",
+				Password:       "password",
+			}
+			result := checkStatus(mockContext(), getResourceRequest{ContactClient: contactClient}, req, cmVariants, nil)
+			assert.Equal(t, int32(http.StatusOK), result.StatusCode)
+			assert.Equal(t, tt.wantURL, contactedURL)
+		})
+	}
+}
+
+func Test_updateResourceDataWithUUID(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceData string
+		want         string
+	}{
+		{
+			name:         "links of systems, managers and chassis",
+			resourceData: `{"@odata.id":"/redfish/v1/Systems/1","Links":{"ManagedBy":[{"@odata.id":"/redfish/v1/Managers/1"}],"Chassis":[{"@odata.id":"/redfish/v1/Chassis/1"}]}}`,
+			want:         `{"@odata.id":"/redfish/v1/Systems/uuid.1","Links":{"ManagedBy":[{"@odata.id":"/redfish/v1/Managers/uuid.1"}],"Chassis":[{"@odata.id":"/redfish/v1/Chassis/uuid.1"}]}}`,
+		},
+		{
+			name:         "lower case links",
+			resourceData: `{"@odata.id":"/redfish/v1/systems/1","Links":{"ManagedBy":[{"@odata.id":"/redfish/v1/managers/1"}],"Chassis":[{"@odata.id":"/redfish/v1/chassis/1"}]}}`,
+			want:         `{"@odata.id":"/redfish/v1/Systems/uuid.1","Links":{"ManagedBy":[{"@odata.id":"/redfish/v1/Managers/uuid.1"}],"Chassis":[{"@odata.id":"/redfish/v1/Chassis/uuid.1"}]}}`,
+		},
+		{
+			name:         "action targets",
+			resourceData: `{"Actions":{"#ComputerSystem.Reset":{"target":"/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"}}}`,
+			want:         `{"Actions":{"#ComputerSystem.Reset":{"target":"/redfish/v1/Systems/uuid.1/Actions/ComputerSystem.Reset"}}}`,
+		},
+		{
+			name:         "links in the description",
+			resourceData: `{"@odata.id":"/redfish/v1/Systems/1","Description":"Refer /redfish/v1/Systems/1 and /redfish/v1/Chassis/1 for the details"}`,
+			want:         `{"@odata.id":"/redfish/v1/Systems/uuid.1","Description":"Refer /redfish/v1/Systems/1 and /redfish/v1/Chassis/1 for the details"}`,
+		},
+		{
+			name:         "URI of an external system",
+			resourceData: `{"@odata.id":"/redfish/v1/Managers/1","Oem":{"Source":"https://10.0.0.1/redfish/v1/Systems/1"}}`,
+			want:         `{"@odata.id":"/redfish/v1/Managers/uuid.1","Oem":{"Source":"https://10.0.0.1/redfish/v1/Systems/1"}}`,
+		},
+		{
+			name:         "collection itself",
+			resourceData: `{"@odata.id":"/redfish/v1/Systems","Systems":{"@odata.id":"/redfish/v1/Systems"}}`,
+			want:         `{"@odata.id":"/redfish/v1/Systems","Systems":{"@odata.id":"/redfish/v1/Systems"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, updateResourceDataWithUUID(tt.resourceData, "uuid"))
+		})
+	}
+}
+
+func Test_keyFormation(t *testing.T) {
+	config.SetUpMockConfig(t)
+	tests := []struct {
+		name     string
+		parents  []config.KeyPrefixParentConf
+		oid      string
+		systemID string
+		want     string
+	}{
+		{name: "system", oid: "/redfish/v1/Systems/1", systemID: "1", want: "/redfish/v1/Systems/uuid.1"},
+		{name: "sub resource of system", oid: "/redfish/v1/Systems/1/Memory/1", systemID: "1", want: "/redfish/v1/Systems/uuid.1/Memory/1"},
+		{name: "trailing slash", oid: "/redfish/v1/Managers/1/", systemID: "1", want: "/redfish/v1/Managers/uuid.1"},
+		{name: "chassis which is not the resource", oid: "/redfish/v1/Chassis/1", systemID: "2", want: "/redfish/v1/Chassis/1"},
+		{name: "firmware inventory", oid: "/redfish/v1/UpdateService/FirmwareInventory/BMC", systemID: "BMC", want: "/redfish/v1/UpdateService/FirmwareInventory/uuid.BMC"},
+		{name: "license", oid: "/redfish/v1/LicenseService/Licenses/1", systemID: "", want: "/redfish/v1/LicenseService/Licenses/uuid.1"},
+		{name: "parent not listed", oid: "/redfish/v1/ComponentIntegrity/SPDM", systemID: "SPDM", want: "/redfish/v1/ComponentIntegrity/SPDM"},
+		{
+			name:    "configured parent",
+			parents: []config.KeyPrefixParentConf{{Name: "ComponentIntegrity"}},
+			oid:     "/redfish/v1/ComponentIntegrity/SPDM",
+			want:    "/redfish/v1/ComponentIntegrity/uuid.SPDM",
+		},
+		{
+			name:    "only the member of configured parent is prefixed",
+			parents: []config.KeyPrefixParentConf{{Name: "Certificates"}},
+			oid:     "/redfish/v1/CertificateService/CertificateLocations/Certificates/1/Keys",
+			want:    "/redfish/v1/CertificateService/CertificateLocations/Certificates/uuid.1/Keys",
+		},
+		{
+			name:     "configured parent prefixing the resource only",
+			parents:  []config.KeyPrefixParentConf{{Name: "ComponentIntegrity", ResourceIDOnly: true}},
+			oid:      "/redfish/v1/ComponentIntegrity/SPDM",
+			systemID: "TPM",
+			want:     "/redfish/v1/ComponentIntegrity/SPDM",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultParents := config.Data.DiscoveryConf.KeyPrefixParents
+			defer func() { config.Data.DiscoveryConf.KeyPrefixParents = defaultParents }()
+			if tt.parents != nil {
+				config.Data.DiscoveryConf.KeyPrefixParents = tt.parents
+			}
+			assert.Equal(t, tt.want, keyFormation(tt.oid, tt.systemID, "uuid"))
+		})
+	}
+}
+
+func Test_storeTelemetryService(t *testing.T) {
+	config.SetUpMockConfig(t)
+	savedData := make(map[string]string)
+	e := &ExternalInterface{
+		GenericSave: func(body []byte, table, key string) error {
+			savedData[table+":"+key] = string(body)
+			return nil
+		},
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		if url == "https://localhost:9091/ODIM/v1/TelemetryService" {
+			respBody := `{"@odata.id":"/ODIM/v1/TelemetryService","Id":"TelemetryService","MetricDefinitions":{"@odata.id":"/ODIM/v1/TelemetryService/MetricDefinitions"},"Links":{"Chassis":[{"@odata.id":"/ODIM/v1/Chassis/1"}]}}`
+			return stubResponse(http.StatusOK, respBody)
+		}
+		return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	err := e.storeTelemetryService(mockContext(), req)
+	assert.Nil(t, err, "There should be no error")
+	data, ok := savedData["TelemetryService:/redfish/v1/TelemetryService"]
+	if assert.True(t, ok, "TelemetryService should be persisted") {
+		assert.Contains(t, data, `"@odata.id":"/redfish/v1/TelemetryService"`)
+		assert.Contains(t, data, "/redfish/v1/Chassis/someuuid.1", "links should be updated with the device UUID")
+	}
+
+	// plugin without TelemetryService should not fail the discovery
+	savedData = make(map[string]string)
+	req.Plugin.Port = "9092"
+	err = e.storeTelemetryService(mockContext(), req)
+	assert.Nil(t, err, "There should be no error")
+	assert.Empty(t, savedData, "nothing should be persisted")
+}
+
+func Test_getTelemetryServiceConcurrentCollections(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.TelemetryCollectionWorkerCount = 2
+	var lock sync.Mutex
+	savedData := make(map[string]string)
+	contacted := make(map[string]bool)
+	var activeCollections, maxActiveCollections int32
+	e := &ExternalInterface{
+		GenericSave: func(body []byte, table, key string) error {
+			lock.Lock()
+			defer lock.Unlock()
+			savedData[table+":"+key] = string(body)
+			return nil
+		},
+		GetResource: func(table, key string) (string, *errors.Error) {
+			return "", errors.PackError(errors.DBKeyNotFound, "not found")
+		},
+		CheckMetricRequest:  func(key string) (bool, *errors.Error) { return false, nil },
+		DeleteMetricRequest: func(key string) *errors.Error { return nil },
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		lock.Lock()
+		contacted[strings.Replace(odataID, "/ODIM/v1", "/redfish/v1", 1)] = true
+		lock.Unlock()
+		respBody := `{"@odata.id":"` + odataID + `","Id":"1"}`
+		collection := odataID[strings.LastIndex(odataID, "/")+1:]
+		switch collection {
+		case "TelemetryService":
+		case "MetricDefinitions", "MetricReportDefinitions", "MetricReports", "Triggers":
+			active := atomic.AddInt32(&activeCollections, 1)
+			defer atomic.AddInt32(&activeCollections, -1)
+			for {
+				max := atomic.LoadInt32(&maxActiveCollections)
+				if active <= max || atomic.CompareAndSwapInt32(&maxActiveCollections, max, active) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			respBody = `{"@odata.id":"` + odataID + `","Members":[{"@odata.id":"` + odataID + `/1"},{"@odata.id":"` + odataID + `/2"},{"@odata.id":"` + odataID + `/3"}]}`
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := getResourceRequest{
+		ContactClient: contactClient,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	saveSystem := agmodel.SaveSystem{ManagerAddress: "10.0.0.1", DeviceUUID: "someuuid"}
+	progress := e.getTelemetryService(mockContext(), "", "", 10, req, response.RPC{}, saveSystem)
+
+	assert.Equal(t, int32(19), progress, "progress should be advanced for all the collections")
+	assert.Equal(t, int32(2), maxActiveCollections, "collections should be discovered with a bounded pool")
+	for _, collection := range telemetryCollections {
+		assert.Contains(t, savedData, collection.resourceName+":"+collection.oid, "collection should be persisted")
+		for _, id := range []string{"1", "2", "3"} {
+			memberOID := collection.oid + "/" + id
+			if collection.resourceName == "MetricReportsCollection" {
+				assert.False(t, contacted[memberOID], "members of MetricReports should not be discovered")
+				continue
+			}
+			assert.True(t, contacted[memberOID], "member of the collection should be discovered")
+		}
+	}
+}
+
+func Test_getRegistriesInfoWithoutLanguage(t *testing.T) {
+	config.SetUpMockConfig(t)
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody := `{"Id":"CustomRegistry","Registry":"CustomRegistry.1.0","Location":[{"Uri":{"@odata.id":"/redfish/v1/Registries/CustomRegistry"}},{"Uri":"/redfish/v1/RegistryStore/CustomRegistry.1.0.json"}]}`
+		if strings.HasSuffix(url, "/RegistryStore/CustomRegistry.1.0.json") {
+			respBody = `{"Id":"CustomRegistry.1.0.0","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/Registries/CustomRegistry",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	h := newTestRespHolder()
+	progress := h.getRegistriesInfo(mockContext(), "", 0, 10, nil, req)
+	assert.Equal(t, int32(10), progress)
+	assert.Equal(t, `{"Id":"CustomRegistry.1.0.0","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`, h.InventoryData["Registries:CustomRegistry.1.0.json"], "registry file should be taken from the location without Language")
+
+	languagelessRegistries := false
+	config.Data.DiscoveryConf.LanguagelessRegistries = &languagelessRegistries
+	h.InventoryData = make(map[string]interface{})
+	h.getRegistriesInfo(mockContext(), "", 0, 10, nil, req)
+	assert.Empty(t, h.InventoryData, "registry file should be skipped when the fallback is disabled")
+}
+
+func Test_getRegistriesMalformed(t *testing.T) {
+	config.SetUpMockConfig(t)
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"registry name is not a string", `{"Id":"CustomRegistry","Registry":1,"Location":[{"Language":"en","Uri":"/redfish/v1/RegistryStore/CustomRegistry.1.0.json"}]}`},
+		{"Id is missing", `{"Registry":"#CustomRegistry.1.0","Location":[{"Language":"en","Uri":"/redfish/v1/RegistryStore/CustomRegistry.1.0.json"}]}`},
+		{"Id is not a string", `{"Id":null,"Registry":"#CustomRegistry.1.0","Location":[{"Language":"en","Uri":"/redfish/v1/RegistryStore/CustomRegistry.1.0.json"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+				return stubResponse(http.StatusOK, tt.body)
+			}
+			h := newTestRespHolder()
+			progress := h.getRegistriesInfo(mockContext(), "", 0, 10, nil, testPluginRequest(contactClient, "/redfish/v1/Registries/CustomRegistry"))
+			assert.Equal(t, int32(10), progress, "malformed registry should be skipped")
+			assert.Empty(t, h.InventoryData, "malformed registry should not be stored")
+			assert.Empty(t, h.ErrorMessage, "malformed registry should not fail the discovery")
+		})
+	}
+
+	for _, body := range []string{`{"Members":null}`, `{}`, `{"Members":[{"Id":"CustomRegistry"},"CustomRegistry"]}`} {
+		contactClient := func(ctx context.Context, url, method, token string, odataID string, reqBody interface{}, credentials map[string]string) (*http.Response, error) {
+			return stubResponse(http.StatusOK, body)
+		}
+		h := newTestRespHolder()
+		progress := h.getAllRegistries(mockContext(), "", 0, 10, testPluginRequest(contactClient, "/redfish/v1/Registries"))
+		assert.Equal(t, int32(10), progress, "malformed registries collection %s should be skipped", body)
+		assert.Empty(t, h.InventoryData)
+	}
+}
+
+func Test_getRegistryFile(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var registryBody string
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		return stubResponse(http.StatusOK, registryBody)
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/RegistryStore/CustomRegistry.1.0.json",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	tests := []struct {
+		name  string
+		body  string
+		saved bool
+	}{
+		{
+			name:  "valid registry",
+			body:  `{"Id":"CustomRegistry.1.0.0","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{"Success":{"Message":"Done"}}}`,
+			saved: true,
+		},
+		{
+			name:  "valid attribute registry",
+			body:  `{"@odata.type":"#AttributeRegistry.v1_3_5.AttributeRegistry","Id":"BiosAttributeRegistry.1.0.0","RegistryVersion":"1.0.0","RegistryEntries":{}}`,
+			saved: true,
+		},
+		{
+			name: "empty body",
+			body: " ",
+		},
+		{
+			name: "truncated JSON",
+			body: `{"Id":"CustomRegistry.1.0.0","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{"Succ`,
+		},
+		{
+			name: "registry without messages",
+			body: `{"Id":"CustomRegistry.1.0.0","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0"}`,
+		},
+		{
+			name: "registry without version",
+			body: `{"Id":"CustomRegistry.1.0.0","RegistryPrefix":"CustomRegistry","Messages":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registryBody = tt.body
+			h := newTestRespHolder()
+			h.getRegistryFile(mockContext(), "CustomRegistry.1.0", req)
+			if tt.saved {
+				assert.Equal(t, tt.body, h.InventoryData["Registries:CustomRegistry.1.0.json"], "valid registry should be saved")
+				assert.Empty(t, h.Warnings)
+				return
+			}
+			assert.NotContains(t, h.InventoryData, "Registries:CustomRegistry.1.0.json", "invalid registry should not be saved")
+			assert.Len(t, h.Warnings, 1, "invalid registry should be warned")
+			assert.Empty(t, h.ErrorMessage, "invalid registry should not fail the discovery")
+		})
+	}
+}
+
+func Test_isFileExistInvalidRegistry(t *testing.T) {
+	defer func(getRegistryFile func(string, string) (string, *errors.Error)) {
+		GetRegistryFileFunc = getRegistryFile
+	}(GetRegistryFileFunc)
+	savedRegistries := map[string]string{
+		"Valid.1.0.json": `{"Id":"Valid.1.0.0","RegistryPrefix":"Valid","RegistryVersion":"1.0.0","Messages":{}}`,
+		"Empty.1.0.json": ``,
+	}
+	GetRegistryFileFunc = func(table, key string) (string, *errors.Error) {
+		if registry, ok := savedRegistries[key]; ok {
+			return registry, nil
+		}
+		return "", errors.PackError(errors.DBKeyNotFound, "not found")
+	}
+	assert.True(t, isFileExist([]string{"Base.1.13.0.json"}, "Base.1.13.0.json"), "registry in the store should exist")
+	assert.True(t, isFileExist(nil, "Valid.1.0.json"), "valid registry in DB should exist")
+	assert.False(t, isFileExist(nil, "Empty.1.0.json"), "invalid registry in DB should be downloaded again")
+	assert.False(t, isFileExist(nil, "Missing.1.0.json"), "registry not in DB should not exist")
+}
+
+func Test_getRegistriesInfoForceRefresh(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer common.TruncateDB(common.InMemory)
+	oldRegistry := `{"Id":"CustomRegistry.1.0.0","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`
+	newRegistry := `{"Id":"CustomRegistry.1.0.1","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.1","Messages":{"Success":{"Message":"Done"}}}`
+	err := agmodel.SaveBMCInventory(map[string]interface{}{"Registries:CustomRegistry.1.0.json": oldRegistry})
+	assert.Nil(t, err, "registry should be saved")
+	var downloads int32
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody := `{"Id":"CustomRegistry","Registry":"CustomRegistry.1.0","Location":[{"Language":"en","Uri":"/redfish/v1/RegistryStore/CustomRegistry.1.0.json"}]}`
+		if strings.Contains(url, "/RegistryStore/") {
+			atomic.AddInt32(&downloads, 1)
+			respBody = newRegistry
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/Registries/CustomRegistry",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	h := newTestRespHolder()
+	progress := h.getRegistriesInfo(mockContext(), "", 0, 10, nil, req)
+	assert.Equal(t, int32(10), progress)
+	assert.Equal(t, int32(0), downloads, "existing registry should be skipped by default")
+	assert.Empty(t, h.InventoryData)
+	registry, _ := agmodel.GetRegistryFile("Registries", "CustomRegistry.1.0.json")
+	assert.Equal(t, oldRegistry, registry, "existing registry should not be overwritten by default")
+
+	req.ForceRegistryRefresh = true
+	h = newTestRespHolder()
+	progress = h.getRegistriesInfo(mockContext(), "", 0, 10, nil, req)
+	assert.Equal(t, int32(10), progress)
+	assert.Equal(t, int32(1), downloads, "existing registry should be downloaded when forced")
+	assert.Equal(t, newRegistry, h.InventoryData["Registries:CustomRegistry.1.0.json"], "refreshed registry should be staged with the inventory")
+	registry, _ = agmodel.GetRegistryFile("Registries", "CustomRegistry.1.0.json")
+	assert.Equal(t, oldRegistry, registry, "refreshed registry should not be saved before the inventory")
+	err = agmodel.SaveBMCInventory(h.InventoryData)
+	assert.Nil(t, err, "inventory should be saved")
+	registry, _ = agmodel.GetRegistryFile("Registries", "CustomRegistry.1.0.json")
+	assert.Equal(t, newRegistry, registry, "existing registry should be overwritten with the inventory when forced")
+
+	// the refresh waits for the refresh of the registry in progress and doesn't download it again
+	_, started := registryRefreshes.start("CustomRegistry.1.0.json")
+	assert.True(t, started)
+	done := make(chan struct{})
+	go func() {
+		newTestRespHolder().getRegistriesInfo(mockContext(), "", 0, 10, nil, req)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("refresh should wait for the refresh in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+	registryRefreshes.finish("CustomRegistry.1.0.json")
+	<-done
+	assert.Equal(t, int32(1), downloads, "registry refreshed concurrently should be downloaded once")
+}
+
+func Test_getRegistriesInfoLanguages(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var location string
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody := `{"Id":"CustomRegistry","Registry":"CustomRegistry.1.0","Location":` + location + `}`
+		if strings.Contains(url, "/RegistryStore/") {
+			respBody = `{"Id":"` + url[strings.LastIndex(url, "/")+1:] + `","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/Registries/CustomRegistry",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	tests := []struct {
+		name      string
+		languages []string
+		location  string
+		want      string
+	}{
+		{
+			name:      "only en-US is available",
+			languages: []string{"en"},
+			location:  `[{"Language":"en-US","Uri":"/redfish/v1/RegistryStore/en-US"}]`,
+			want:      `{"Id":"en-US","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`,
+		},
+		{
+			name:      "preference order is honored",
+			languages: []string{"de", "en-US", "en"},
+			location:  `[{"Language":"en","Uri":"/redfish/v1/RegistryStore/en"},{"Language":"EN-us","Uri":"/redfish/v1/RegistryStore/en-US"},{"Language":"de","Uri":{"@odata.id":"/redfish/v1/Registries/de"}}]`,
+			want:      `{"Id":"en-US","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`,
+		},
+		{
+			name:      "first language is taken when none is preferred",
+			languages: []string{"en"},
+			location:  `[{"Language":"fr","Uri":"/redfish/v1/RegistryStore/fr"},{"Language":"ja","Uri":"/redfish/v1/RegistryStore/ja"}]`,
+			want:      `{"Id":"fr","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`,
+		},
+		{
+			name:      "location without language",
+			languages: []string{"en"},
+			location:  `[{"Uri":"/redfish/v1/RegistryStore/none"}]`,
+			want:      `{"Id":"none","RegistryPrefix":"CustomRegistry","RegistryVersion":"1.0.0","Messages":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Data.DiscoveryConf.RegistryLanguages = tt.languages
+			location = tt.location
+			h := newTestRespHolder()
+			h.getRegistriesInfo(mockContext(), "", 0, 10, nil, req)
+			assert.Equal(t, tt.want, h.InventoryData["Registries:CustomRegistry.1.0.json"])
+		})
+	}
+}
+
+func Test_getConnectionMethodVariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant string
+		want    connectionMethodVariants
+		wantErr bool
+	}{
+		{
+			name:    "semantic version with leading v",
+			variant: "Compute:BasicAuth:GRF_v2.0.0",
+			want:    connectionMethodVariants{PluginType: "Compute", PreferredAuthType: "BasicAuth", PluginID: "GRF_v2.0.0", FirmwareVersion: "2.0.0"},
+		},
+		{
+			name:    "vendor version with spaces",
+			variant: "Storage:XAuthToken:STG_ 1.2 ",
+			want:    connectionMethodVariants{PluginType: "Storage", PreferredAuthType: "XAuthToken", PluginID: "STG_ 1.2 ", FirmwareVersion: "1.2"},
+		},
+		{
+			name:    "pre-release version",
+			variant: "Compute:BasicAuth:ILO_v1.0.0-beta.1",
+			want:    connectionMethodVariants{PluginType: "Compute", PreferredAuthType: "BasicAuth", PluginID: "ILO_v1.0.0-beta.1", FirmwareVersion: "1.0.0-beta.1"},
+		},
+		{
+			name:    "malformed version",
+			variant: "Compute:BasicAuth:GRF_latest",
+			want:    connectionMethodVariants{PluginType: "Compute", PreferredAuthType: "BasicAuth", PluginID: "GRF_latest"},
+			wantErr: true,
+		},
+		{
+			name:    "version not present",
+			variant: "Compute:BasicAuth:GRF",
+			want:    connectionMethodVariants{PluginType: "Compute", PreferredAuthType: "BasicAuth", PluginID: "GRF"},
+			wantErr: true,
+		},
+		{
+			name:    "incomplete variant",
+			variant: "Compute:BasicAuth",
+			wantErr: true,
+		},
+		{
+			name:    "firmware version missing after the plugin id",
+			variant: "Compute:BasicAuth:GRF_",
+			want:    connectionMethodVariants{PluginType: "Compute", PreferredAuthType: "BasicAuth", PluginID: "GRF_"},
+			wantErr: true,
+		},
+		{
+			name:    "auth type not present",
+			variant: "Compute::GRF_v1.0.0",
+			wantErr: true,
+		},
+		{
+			name:    "plugin id not present",
+			variant: "Compute:BasicAuth: ",
+			wantErr: true,
+		},
+		{
+			name:    "extra colons",
+			variant: "Compute:BasicAuth:GRF_v1.0.0:extra",
+			wantErr: true,
+		},
+		{
+			name:    "empty variant",
+			variant: "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getConnectionMethodVariants(tt.variant)
+			if err != nil {
+				assert.Contains(t, err.Error(), tt.variant, "error should name the variant")
+			}
+			assert.Equal(t, tt.wantErr, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_checkStatusWithoutSessionToken(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var statusCalled bool
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		if url == "https://localhost:9091/ODIM/v1/Status" {
+			statusCalled = true
+		}
+		// session is created without the X-Auth-Token header
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+		}, nil
+	}
+	req := AddResourceRequest{
+		ManagerAddress: "localhost:9091",
+		UserName:       "admin",
+		Password:       "password",
+	}
+	cmVariants := connectionMethodVariants{
+		PluginType:        "Compute",
+		PreferredAuthType: "XAuthToken",
+		PluginID:          "GRF",
+		FirmwareVersion:   "1.0.0",
+	}
+	result := checkStatus(mockContext(), getResourceRequest{ContactClient: contactClient}, req, cmVariants, nil)
+	assert.Equal(t, int32(http.StatusUnauthorized), result.StatusCode)
+	assert.Equal(t, response.ResourceAtURIUnauthorized, result.Response.StatusMessage)
+	assert.False(t, statusCalled, "status should not be requested without the session token")
+}
+
+func Test_checkStatusSessionTeardown(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(orig func(string) error) { CheckEMBQueueAvailability = orig }(CheckEMBQueueAvailability)
+	CheckEMBQueueAvailability = func(queueName string) error { return nil }
+	var deletedTokens []string
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		switch {
+		case method == http.MethodPost && strings.HasSuffix(url, "/ODIM/v1/Sessions"):
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"X-Auth-Token": []string{"plugintoken"}},
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+			}, nil
+		case method == http.MethodDelete && strings.HasSuffix(url, "/ODIM/v1/Sessions"):
+			deletedTokens = append(deletedTokens, token)
+			return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+		}
+		return stubResponse(http.StatusOK, `{"Version": "1.0.0","EventMessageBus":{"EmbQueue":[{"EmbQueueName":"GRF"}]}}`)
+	}
+	req := AddResourceRequest{
+		ManagerAddress: "localhost:9091",
+		UserName:       "admin",
+		Password:       "password",
+	}
+	cmVariants := connectionMethodVariants{
+		PluginType:        "Compute",
+		PreferredAuthType: "XAuthToken",
+		PluginID:          "GRF",
+		FirmwareVersion:   "1.0.0",
+	}
+	result := checkStatus(mockContext(), getResourceRequest{ContactClient: contactClient}, req, cmVariants, nil)
+	// failure to delete the session shouldn't fail the status check
+	assert.Equal(t, int32(http.StatusOK), result.StatusCode)
+	assert.Equal(t, []string{"plugintoken"}, deletedTokens, "session created for the status check should be deleted")
+
+	deletedTokens = nil
+	config.Data.DiscoveryConf.SkipPluginSessionTeardown = true
+	result = checkStatus(mockContext(), getResourceRequest{ContactClient: contactClient}, req, cmVariants, nil)
+	assert.Equal(t, int32(http.StatusOK), result.StatusCode)
+	assert.Empty(t, deletedTokens, "session should be kept when the teardown is skipped")
+}
+
+func Test_checkStatusForceBasicAuth(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(orig func(string) error) { CheckEMBQueueAvailability = orig }(CheckEMBQueueAvailability)
+	CheckEMBQueueAvailability = func(queueName string) error { return nil }
+	var sessionRequested bool
+	var usedCredentials []map[string]string
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		if strings.HasSuffix(url, "/ODIM/v1/Sessions") {
+			sessionRequested = true
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"X-Auth-Token": []string{"plugintoken"}},
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+			}, nil
+		}
+		usedCredentials = append(usedCredentials, credentials)
+		return stubResponse(http.StatusOK, `{"Version": "1.0.0","EventMessageBus":{"EmbQueue":[{"EmbQueueName":"GRF"}]}}`)
+	}
+	req := AddResourceRequest{
+		ManagerAddress: "localhost:9091",
+		UserName:       "admin",
+		Password:       "password",
+		ForceBasicAuth: true,
+	}
+	cmVariants := connectionMethodVariants{
+		PluginType:        "Compute",
+		PreferredAuthType: "XAuthToken",
+		PluginID:          "GRF",
+		FirmwareVersion:   "1.0.0",
+	}
+	result := checkStatus(mockContext(), getResourceRequest{ContactClient: contactClient}, req, cmVariants, nil)
+	assert.Equal(t, int32(http.StatusOK), result.StatusCode)
+	assert.False(t, sessionRequested, "session shouldn't be created when BasicAuth is forced")
+	if assert.NotEmpty(t, usedCredentials) {
+		assert.Equal(t, "admin", usedCredentials[0]["UserName"], "plugin should be contacted with the basic credentials")
+	}
+
+	plugin := agmodel.Plugin{PreferredAuthType: "XAuthToken", ForceBasicAuth: true}
+	assert.Equal(t, "BasicAuth", plugin.AuthType())
+	plugin.ForceBasicAuth = false
+	assert.Equal(t, "XAuthToken", plugin.AuthType())
+}
+
+func Test_getStorageDepthInfo(t *testing.T) {
+	config.SetUpMockConfig(t)
+	device := map[string]string{
+		"/ODIM/v1/Systems/1/Storage":                 `{"@odata.id":"/ODIM/v1/Systems/1/Storage","Members":[{"@odata.id":"/ODIM/v1/Systems/1/Storage/1"},{"@odata.id":"/ODIM/v1/Systems/1/Storage/2"}]}`,
+		"/ODIM/v1/Systems/1/Storage/1":               `{"@odata.id":"/ODIM/v1/Systems/1/Storage/1","Id":"1","Controllers":{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Controllers"},"Volumes":{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Volumes"}}`,
+		"/ODIM/v1/Systems/1/Storage/2":               `{"@odata.id":"/ODIM/v1/Systems/1/Storage/2","Id":"2"}`,
+		"/ODIM/v1/Systems/1/Storage/1/Controllers":   `{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Controllers","Members":[{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Controllers/1"}]}`,
+		"/ODIM/v1/Systems/1/Storage/1/Controllers/1": `{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Controllers/1","Id":"1"}`,
+		"/ODIM/v1/Systems/1/Storage/1/Volumes":       `{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Volumes","Members":[{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Volumes/1"}]}`,
+		"/ODIM/v1/Systems/1/Storage/1/Volumes/1":     `{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Volumes/1","Id":"1","CapacityBytes":1073741824}`,
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody, ok := device[strings.TrimPrefix(url, "https://localhost:9091")]
+		if !ok {
+			return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := testPluginRequest(contactClient, "/redfish/v1/Systems/1/Storage")
+	req.ParentOID = "/redfish/v1/Systems/1"
+	req.SystemID = "1"
+	h := newTestRespHolder()
+	h.getStorageDepthInfo(mockContext(), "", 0, 0, req)
+	assert.Empty(t, h.ErrorMessage, "storage without the volumes shouldn't fail the discovery")
+	for _, key := range []string{
+		"StorageCollection:/redfish/v1/Systems/someuuid.1/Storage",
+		"Storage:/redfish/v1/Systems/someuuid.1/Storage/1",
+		"Storage:/redfish/v1/Systems/someuuid.1/Storage/2",
+		"ControllersCollection:/redfish/v1/Systems/someuuid.1/Storage/1/Controllers",
+		"Controllers:/redfish/v1/Systems/someuuid.1/Storage/1/Controllers/1",
+		"VolumesCollection:/redfish/v1/Systems/someuuid.1/Storage/1/Volumes",
+		"Volumes:/redfish/v1/Systems/someuuid.1/Storage/1/Volumes/1",
+	} {
+		assert.Contains(t, h.InventoryData, key, key+" should be discovered")
+	}
+	volumes, _ := h.InventoryData["VolumesCollection:/redfish/v1/Systems/someuuid.1/Storage/1/Volumes"].(string)
+	assert.Contains(t, volumes, "@Redfish.CollectionCapabilities", "collection capabilities should be added to the volumes")
+
+	// resources already discovered by the link traversal are not discovered again
+	h = &respHolder{
+		TraversedLinks: map[string]bool{"/redfish/v1/Systems/1/Storage/1/Volumes": true},
+		InventoryData:  make(map[string]interface{}),
+	}
+	h.getStorageDepthInfo(mockContext(), "", 0, 0, req)
+	assert.NotContains(t, h.InventoryData, "VolumesCollection:/redfish/v1/Systems/someuuid.1/Storage/1/Volumes")
+	assert.Contains(t, h.InventoryData, "ControllersCollection:/redfish/v1/Systems/someuuid.1/Storage/1/Controllers")
+
+	// system without storage
+	req.OID = "/redfish/v1/Systems/2/Storage"
+	h = newTestRespHolder()
+	h.getStorageDepthInfo(mockContext(), "", 0, 0, req)
+	assert.Empty(t, h.InventoryData)
+	assert.Empty(t, h.Problems)
+}
+
+func Test_getServiceRootInfo(t *testing.T) {
+	config.SetUpMockConfig(t)
+	serviceRoot := `{"@odata.id":"/ODIM/v1","RedfishVersion":"1.11.0","Vendor":"Contoso","UUID":"7a4d7a9e-0000-4a56-8e8d-1f2a3b4c5d6e",` +
+		`"Systems":{"@odata.id":"/ODIM/v1/Systems"},"Managers":{"@odata.id":"/ODIM/v1/Managers"}}`
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		return stubResponse(http.StatusOK, serviceRoot)
+	}
+	req := getResourceRequest{
+		ContactClient: contactClient,
+		DeviceUUID:    "someuuid",
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	h := &respHolder{InventoryData: make(map[string]interface{})}
+	h.getServiceRootInfo(mockContext(), req)
+	assert.Equal(t, serviceRootInfo{
+		UUID:           "7a4d7a9e-0000-4a56-8e8d-1f2a3b4c5d6e",
+		RedfishVersion: "1.11.0",
+		Vendor:         "Contoso",
+		Services:       []string{"Managers", "Systems"},
+	}, h.ServiceRoot)
+	assert.Contains(t, h.InventoryData, "ServiceRoot:/redfish/v1/ServiceRoot/someuuid", "service root should be persisted")
+
+	// ServiceEntryPointUUID of the manager matching the service root UUID
+	h.InventoryData["Managers:/redfish/v1/Managers/someuuid.1"] = `{"Id":"1","ServiceEntryPointUUID":"7A4D7A9E-0000-4A56-8E8D-1F2A3B4C5D6E"}`
+	h.crossCheckServiceRootUUID(mockContext())
+	assert.Empty(t, h.Warnings)
+
+	h.InventoryData["Managers:/redfish/v1/Managers/someuuid.1"] = `{"Id":"1","ServiceEntryPointUUID":"00000000-0000-0000-0000-000000000001"}`
+	h.crossCheckServiceRootUUID(mockContext())
+	if assert.Len(t, h.Warnings, 1) {
+		assert.Contains(t, h.Warnings[0], "doesn't match the ServiceEntryPointUUID")
+	}
+
+	// minimal service root without the UUID and the links
+	serviceRoot = `{"@odata.id":"/ODIM/v1"}`
+	h = &respHolder{InventoryData: make(map[string]interface{})}
+	h.getServiceRootInfo(mockContext(), req)
+	assert.Equal(t, serviceRootInfo{}, h.ServiceRoot)
+	assert.Contains(t, h.InventoryData, "ServiceRoot:/redfish/v1/ServiceRoot/someuuid")
+	h.InventoryData["Managers:/redfish/v1/Managers/someuuid.1"] = `{"Id":"1","ServiceEntryPointUUID":"00000000-0000-0000-0000-000000000001"}`
+	h.crossCheckServiceRootUUID(mockContext())
+	assert.Empty(t, h.Warnings, "cross check should be skipped without the service root UUID")
+}
+
+func Test_getDefaultSubscriptionMessageIDs(t *testing.T) {
+	config.SetUpMockConfig(t)
+	assert.Equal(t, []string{}, getDefaultSubscriptionMessageIDs(), "all the MessageIds should be subscribed by default")
+
+	config.Data.EventConf.DefaultSubscriptionMessageIDs = []string{"Alert.1.0.LanDisconnect", "Alert.1.0.ResourceUpdated", "Alert.1.0.TemperatureHigh"}
+	config.Data.EventConf.DeniedMessageIDs = []string{"Alert.1.0.ResourceUpdated"}
+	assert.Equal(t, []string{"Alert.1.0.LanDisconnect", "Alert.1.0.TemperatureHigh"}, getDefaultSubscriptionMessageIDs(),
+		"denied MessageIds should be excluded")
+
+	config.Data.EventConf.DefaultSubscriptionMessageIDs = nil
+	assert.Equal(t, []string{}, getDefaultSubscriptionMessageIDs())
+
+}
+
+func Test_getDefaultSubscriptionEventTypes(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		defaultSubscriptionEventTypes = []string{"Alert"}
+	}()
+	defaultSubscriptionEventTypes = []string{"Alert", "StatusChange"}
+	eventService := `{"@odata.id":"/redfish/v1/EventService","DeliveryRetryAttempts":3,"DeliveryRetryIntervalSeconds":60,` +
+		`"EventTypesForSubscription":["Alert","ResourceUpdated"],"ServerSentEventUri":"/redfish/v1/EventService/SSE"}`
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			if !strings.HasSuffix(url, "/EventService") {
+				return stubResponse(http.StatusNotFound, "")
+			}
+			return stubResponse(http.StatusOK, eventService)
+		},
+		DeviceUUID: "someuuid",
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	h := &respHolder{InventoryData: make(map[string]interface{})}
+	h.getEventServiceInfo(mockContext(), req)
+	assert.Equal(t, &agmodel.EventServiceCapabilities{
+		DeliveryRetryAttempts:        3,
+		DeliveryRetryIntervalSeconds: 60,
+		EventTypesForSubscription:    []string{"Alert", "ResourceUpdated"},
+		SSESupported:                 true,
+	}, h.EventService)
+	assert.Equal(t, []string{"Alert"}, getDefaultSubscriptionEventTypes(mockContext(), h.EventService),
+		"EventTypes not supported by the device should be excluded")
+
+	// device without EventService
+	h = &respHolder{InventoryData: make(map[string]interface{})}
+	req.ContactClient = func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		return stubResponse(http.StatusNotFound, "")
+	}
+	h.getEventServiceInfo(mockContext(), req)
+	assert.Nil(t, h.EventService)
+	assert.Equal(t, []string{"Alert", "StatusChange"}, getDefaultSubscriptionEventTypes(mockContext(), h.EventService),
+		"defaults should be used for the devices without EventService")
+
+	assert.Equal(t, []string{"Alert", "StatusChange"}, getDefaultSubscriptionEventTypes(mockContext(),
+		&agmodel.EventServiceCapabilities{EventTypesForSubscription: []string{"MetricReport"}}),
+		"defaults should be used when none of them are supported")
+}
+
+func Test_formWildCard(t *testing.T) {
+	config.SetUpMockConfig(t)
+	resourceData := map[string]interface{}{
+		"MetricProperties": []interface{}{
+			"/redfish/v1/Systems/uuid.1#/Status/Health",
+			"/redfish/v1/Chassis/uuid.1#/Status/Health",
+			"/redfish/v1/Managers/uuid.1#/Status/Health",
+		},
+	}
+	data, err := formWildCard("", resourceData)
+	assert.Nil(t, err)
+	var metricDefinition struct {
+		Wildcards        []WildCard
+		MetricProperties []string
+	}
+	assert.Nil(t, json.Unmarshal([]byte(data), &metricDefinition))
+	assert.Equal(t, []WildCard{
+		{Name: "SystemID", Values: []string{"uuid.1"}},
+		{Name: "ChassisID", Values: []string{"uuid.1"}},
+	}, metricDefinition.Wildcards)
+	assert.Equal(t, []string{
+		"/redfish/v1/Systems/{SystemID}#/Status/Health",
+		"/redfish/v1/Chassis/{ChassisID}#/Status/Health",
+		"/redfish/v1/Managers/uuid.1#/Status/Health",
+	}, metricDefinition.MetricProperties)
+}
+
+func Test_formWildCardWithCustomDefinition(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.TelemetryWildCards = append(config.Data.DiscoveryConf.TelemetryWildCards,
+		config.WildCardConf{Name: "ManagerID", URIKeyword: "Managers"})
+
+	wildCards := getEmptyWildCard()
+	assert.Equal(t, []WildCard{
+		{Name: "SystemID", Values: []string{}},
+		{Name: "ChassisID", Values: []string{}},
+		{Name: "ManagerID", Values: []string{}},
+	}, wildCards)
+
+	// metric definition stored before the ManagerID wildcard was configured
+	dbData := `{"Wildcards":[{"Name":"SystemID","Values":["uuid.1"]}],"MetricProperties":["/redfish/v1/Systems/{SystemID}#/Status/Health"]}`
+	resourceData := map[string]interface{}{
+		"MetricProperties": []interface{}{
+			"/redfish/v1/Systems/uuid.2#/Status/Health",
+			"/redfish/v1/Managers/uuid.1#/Status/Health",
+			"/redfish/v1/Managers/uuid.2#/Status/Health",
+		},
+	}
+	data, err := formWildCard(dbData, resourceData)
+	assert.Nil(t, err)
+	var metricDefinition struct {
+		Wildcards        []WildCard
+		MetricProperties []string
+	}
+	assert.Nil(t, json.Unmarshal([]byte(data), &metricDefinition))
+	assert.Equal(t, []WildCard{
+		{Name: "SystemID", Values: []string{"uuid.1", "uuid.2"}},
+		{Name: "ManagerID", Values: []string{"uuid.1", "uuid.2"}},
+	}, metricDefinition.Wildcards)
+	assert.Equal(t, []string{
+		"/redfish/v1/Systems/{SystemID}#/Status/Health",
+		"/redfish/v1/Managers/{ManagerID}#/Status/Health",
+	}, metricDefinition.MetricProperties)
+}
+
+func Test_getChassisAssetInfo(t *testing.T) {
+	config.SetUpMockConfig(t)
+	// Assembly and PCIeDevices skipped under chassis should still be discovered
+	config.Data.AddComputeSkipResources.SkipResourceListUnderChassis = append(config.Data.AddComputeSkipResources.SkipResourceListUnderChassis, "Assembly", "PCIeDevices")
+	device := map[string]string{
+		"/ODIM/v1/Chassis":                                    `{"Members":[{"@odata.id":"/ODIM/v1/Chassis/1"},{"@odata.id":"/ODIM/v1/Chassis/2"}]}`,
+		"/ODIM/v1/Chassis/1":                                  `{"@odata.id":"/ODIM/v1/Chassis/1","Id":"1","Assembly":{"@odata.id":"/ODIM/v1/Chassis/1/Assembly"},"PCIeDevices":{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices"}}`,
+		"/ODIM/v1/Chassis/2":                                  `{"@odata.id":"/ODIM/v1/Chassis/2","Id":"2"}`,
+		"/ODIM/v1/Chassis/1/Assembly":                         `{"@odata.id":"/ODIM/v1/Chassis/1/Assembly","Id":"Assembly","Assemblies":[]}`,
+		"/ODIM/v1/Chassis/1/PCIeDevices":                      `{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices","Members":[{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices/NIC1"}]}`,
+		"/ODIM/v1/Chassis/1/PCIeDevices/NIC1":                 `{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices/NIC1","Id":"NIC1","PCIeFunctions":{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices/NIC1/PCIeFunctions"}}`,
+		"/ODIM/v1/Chassis/1/PCIeDevices/NIC1/PCIeFunctions":   `{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices/NIC1/PCIeFunctions","Members":[{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices/NIC1/PCIeFunctions/1"}]}`,
+		"/ODIM/v1/Chassis/1/PCIeDevices/NIC1/PCIeFunctions/1": `{"@odata.id":"/ODIM/v1/Chassis/1/PCIeDevices/NIC1/PCIeFunctions/1","Id":"1","FunctionType":"Physical"}`,
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody, ok := device[strings.TrimPrefix(url, "https://localhost:9091")]
+		if !ok {
+			return stubResponse(http.StatusNotFound, `{"error":"not found"}`)
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := testPluginRequest(contactClient, "/redfish/v1/Chassis")
+	newHolder := func() *respHolder {
+		h := newTestRespHolder()
+		h.getAllRootInfo(mockContext(), "", 0, 10, req, config.Data.AddComputeSkipResources.SkipResourceListUnderChassis)
+		return h
+	}
+
+	h := newTestRespHolder()
+	assert.NotContains(t, h.InventoryData, "PCIeDevicesCollection:/redfish/v1/Chassis/someuuid.1/PCIeDevices", "PCIeDevices should be skipped by the skip list")
+	h.getChassisAssetInfo(mockContext(), "", 0, 0, req)
+	assert.Contains(t, h.InventoryData, "Assembly:/redfish/v1/Chassis/someuuid.1/Assembly", "Assembly should be discovered")
+	assert.Contains(t, h.InventoryData, "PCIeDevicesCollection:/redfish/v1/Chassis/someuuid.1/PCIeDevices", "PCIeDevices collection should be discovered")
+	assert.Contains(t, h.InventoryData, "PCIeDevices:/redfish/v1/Chassis/someuuid.1/PCIeDevices/NIC1", "PCIeDevices member should be discovered")
+	assert.Contains(t, h.InventoryData, "PCIeFunctionsCollection:/redfish/v1/Chassis/someuuid.1/PCIeDevices/NIC1/PCIeFunctions", "PCIeFunctions collection should be discovered")
+	assert.Contains(t, h.InventoryData, "PCIeFunctions:/redfish/v1/Chassis/someuuid.1/PCIeDevices/NIC1/PCIeFunctions/1", "PCIeFunctions member should be discovered")
+	assert.Empty(t, h.ErrorMessage, "chassis without Assembly and PCIeDevices should be skipped")
+
+	config.Data.DiscoveryConf.DiscoverChassisAssembly = false
+	config.Data.DiscoveryConf.DiscoverPCIeDevices = false
+	h = newTestRespHolder()
+	h.getChassisAssetInfo(mockContext(), "", 0, 0, req)
+	assert.NotContains(t, h.InventoryData, "Assembly:/redfish/v1/Chassis/someuuid.1/Assembly", "Assembly should not be discovered when disabled")
+	assert.NotContains(t, h.InventoryData, "PCIeDevicesCollection:/redfish/v1/Chassis/someuuid.1/PCIeDevices", "PCIeDevices should not be discovered when disabled")
+}
+
+func mockPluginTaskContactClient(percentComplete func() int32) func(context.Context, string, string, string, string, interface{}, map[string]string) (*http.Response, error) {
+	return func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		percent := percentComplete()
+		statusCode := http.StatusAccepted
+		if percent >= 100 {
+			statusCode = http.StatusOK
+		}
+		return stubResponse(statusCode, fmt.Sprintf(`{"TaskState":"Running","PercentComplete":%d}`, percent))
+	}
+}
+
+func getMonitorTaskRequest(contactClient func(context.Context, string, string, string, string, interface{}, map[string]string) (*http.Response, error)) *monitorTaskRequest {
+	return &monitorTaskRequest{
+		respBody:  []byte(`{"TaskState":"Running","PercentComplete":0}`),
+		subTaskID: "subtask1",
+		serverURI: "/redfish/v1/Systems/someuuid.1",
+		location:  "/taskmon/1",
+		taskInfo:  &common.TaskUpdateInfo{Context: mockContext(), TaskID: "task1", TargetURI: "/redfish/v1/Systems/someuuid.1", UpdateTask: mockUpdateTask},
+		pluginRequest: getResourceRequest{
+			ContactClient: contactClient,
+			Plugin: agmodel.Plugin{
+				IP:                "localhost",
+				Port:              "9091",
+				PreferredAuthType: "BasicAuth",
+			},
+		},
+	}
+}
+
+func Test_monitorPluginTaskStalled(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.PluginTaskConf.StallTimeoutInSecs = 2
+	e := &ExternalInterface{UpdateTask: mockUpdateTask}
+	var polls int32
+	// the task progresses once and then stalls at 50 percent
+	contactClient := mockPluginTaskContactClient(func() int32 {
+		polls++
+		return 50
+	})
+	subTaskChannel := make(chan int32, 1)
+	resp, err := e.monitorPluginTask(mockContext(), subTaskChannel, getMonitorTaskRequest(contactClient))
+	assert.NotNil(t, err, "stalled task should fail")
+	assert.Contains(t, err.Error(), "didn't progress from 50 percent")
+	assert.Equal(t, int32(http.StatusInternalServerError), resp.StatusCode)
+	assert.Equal(t, int32(http.StatusInternalServerError), <-subTaskChannel)
+	assert.True(t, polls >= 2, "task should be polled till the stall timeout")
+}
+
+func Test_monitorPluginTaskProgressing(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.PluginTaskConf.StallTimeoutInSecs = 1
+	e := &ExternalInterface{UpdateTask: mockUpdateTask}
+	var percent int32
+	// the task progresses in every poll, so it shouldn't be failed by the stall timeout
+	contactClient := mockPluginTaskContactClient(func() int32 {
+		percent += 25
+		return percent
+	})
+	subTaskChannel := make(chan int32, 1)
+	resp, err := e.monitorPluginTask(mockContext(), subTaskChannel, getMonitorTaskRequest(contactClient))
+	assert.Nil(t, err, "progressing task should complete")
+	assert.Equal(t, int32(http.StatusOK), resp.StatusCode)
+	assert.Empty(t, subTaskChannel)
+}
+
+func Test_monitorPluginTaskFinalBody(t *testing.T) {
+	config.SetUpMockConfig(t)
+	e := &ExternalInterface{UpdateTask: mockUpdateTask}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		return stubResponse(http.StatusOK, `{"TaskState":"Completed","PercentComplete":100,`+
+			`"Messages":[{"MessageId":"Base.1.11.Success","Message":"BIOS and BMC firmware updated."}]}`)
+	}
+	subTaskChannel := make(chan int32, 1)
+	result, err := e.monitorPluginTask(mockContext(), subTaskChannel, getMonitorTaskRequest(contactClient))
+	assert.Nil(t, err, "completed task shouldn't fail")
+	assert.Equal(t, int32(http.StatusOK), result.StatusCode)
+	assert.Equal(t, "Completed", result.taskBody["TaskState"], "final task body of the device should be returned")
+	assert.Equal(t, "BIOS and BMC firmware updated.", result.finalMessage())
+}
+
+func Test_monitorPluginTaskTimeout(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.PluginTaskConf.StallTimeoutInSecs = 2
+	config.Data.PluginTaskConf.TimeoutInSecs = 2
+	e := &ExternalInterface{UpdateTask: mockUpdateTask}
+	var percent int32
+	// the task progresses in every poll, but doesn't complete in time
+	contactClient := mockPluginTaskContactClient(func() int32 {
+		percent++
+		return percent
+	})
+	subTaskChannel := make(chan int32, 1)
+	_, err := e.monitorPluginTask(mockContext(), subTaskChannel, getMonitorTaskRequest(contactClient))
+	assert.NotNil(t, err, "task running beyond the timeout should fail")
+	assert.Contains(t, err.Error(), "didn't complete in")
+	assert.Equal(t, int32(http.StatusInternalServerError), <-subTaskChannel)
+}
+
+func Test_callPluginForwardedHeaders(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var forwarded map[string]string
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			forwarded, _ = ctx.Value(common.ForwardedHeaders).(map[string]string)
+			return stubResponse(http.StatusOK, `{}`)
+		},
+		OID:            "/redfish/v1/Systems",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+		NorthBoundHeaders: map[string]string{
+			"traceparent":  "00-trace-span-01",
+			"X-Tenant-Id":  "tenant1",
+			"X-Auth-Token": "token",
+			"Cookie":       "session=token",
+			"User-Agent":   "curl",
+		},
+		HeaderAllowList: []string{"Traceparent", "x-tenant-id", "X-Auth-Token", "Cookie"},
+	}
+
+	_, err := callPlugin(mockContext(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"Traceparent": "00-trace-span-01",
+		"X-Tenant-Id": "tenant1",
+	}, forwarded, "only the allow listed headers other than the auth headers should be forwarded")
+
+	req.HeaderAllowList = nil
+	forwarded = nil
+	_, err = callPlugin(mockContext(), req)
+	assert.Nil(t, err)
+	assert.Nil(t, forwarded, "headers should not be forwarded without an allow list")
+}
+
+func Test_callPluginTenantHeader(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var forwarded map[string]string
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			forwarded, _ = ctx.Value(common.ForwardedHeaders).(map[string]string)
+			return stubResponse(http.StatusOK, `{}`)
+		},
+		OID:            "/redfish/v1/Systems",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	ctx := context.WithValue(mockContext(), common.TenantID, "tenant1")
+
+	// tenant ID is not sent without the configured header
+	_, err := callPlugin(ctx, req)
+	assert.Nil(t, err)
+	assert.Nil(t, forwarded, "tenant ID should not be sent without the configured header")
+
+	config.Data.PluginTenantHeader = "x-odim-tenant"
+	_, err = callPlugin(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"X-Odim-Tenant": "tenant1"}, forwarded, "tenant ID of the context should be sent in the configured header")
+
+	req.TenantID = "tenant2"
+	req.NorthBoundHeaders = map[string]string{"Traceparent": "00-trace-span-01"}
+	req.HeaderAllowList = []string{"Traceparent"}
+	_, err = callPlugin(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"Traceparent":   "00-trace-span-01",
+		"X-Odim-Tenant": "tenant2",
+	}, forwarded, "tenant ID of the request should be sent along with the forwarded headers")
+}
+
+func Test_rollbackInMemoryRetry(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(interval time.Duration) {
+		rollbackRetryInterval = interval
+	}(rollbackRetryInterval)
+	rollbackRetryInterval = time.Millisecond
+
+	var attempts int
+	var deletedKey string
+	e := &ExternalInterface{
+		DeleteComputeSystem: func(index int, key string) *errors.Error {
+			attempts++
+			if attempts == 1 {
+				return errors.PackError(errors.TimeoutError, "DB is unavailable")
+			}
+			deletedKey = key
+			return nil
+		},
+	}
+	e.rollbackInMemory(mockContext(), "/redfish/v1/Systems/someuuid.1")
+	assert.Equal(t, 2, attempts, "delete should be retried after the failure")
+	assert.Equal(t, "/redfish/v1/Systems/someuuid.1", deletedKey)
+
+	// retries are bounded when the delete keeps failing
+	attempts = 0
+	e.DeleteComputeSystem = func(index int, key string) *errors.Error {
+		attempts++
+		return errors.PackError(errors.TimeoutError, "DB is unavailable")
+	}
+	e.rollbackInMemory(mockContext(), "/redfish/v1/Systems/someuuid.1")
+	assert.Equal(t, rollbackRetryCount, attempts)
+
+	// data which is not found is not retried
+	attempts = 0
+	e.DeleteComputeSystem = func(index int, key string) *errors.Error {
+		attempts++
+		return errors.PackError(errors.DBKeyNotFound, "no data found")
+	}
+	e.rollbackInMemory(mockContext(), "/redfish/v1/Systems/someuuid.1")
+	assert.Equal(t, 1, attempts)
+}
+
+func Test_callPluginTimeout(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var timeout time.Duration
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			timeout, _ = ctx.Value(common.PluginTimeout).(time.Duration)
+			return stubResponse(http.StatusOK, `{}`)
+		},
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	tests := []struct {
+		name      string
+		method    string
+		operation pluginOperation
+		want      time.Duration
+	}{
+		{name: "status check", method: http.MethodGet, operation: statusOperation, want: 5 * time.Second},
+		{name: "session creation in status check", method: http.MethodPost, operation: statusOperation, want: 5 * time.Second},
+		{name: "discovery", method: http.MethodGet, operation: discoveryOperation, want: 10 * time.Second},
+		{name: "action", method: http.MethodPost, operation: actionOperation, want: 20 * time.Second},
+		{name: "GET without operation", method: http.MethodGet, operation: defaultOperation, want: 10 * time.Second},
+		{name: "POST without operation", method: http.MethodPost, operation: defaultOperation, want: 20 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req.HTTPMethodType = tt.method
+			req.Operation = tt.operation
+			timeout = 0
+			_, err := callPlugin(mockContext(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, timeout, "plugin should be called with the configured timeout of the operation")
+		})
+	}
+}
+
+func Test_getVolumeCapabilities(t *testing.T) {
+	collectionOID := "/redfish/v1/Systems/1/Storage/1/Volumes"
+	tests := []struct {
+		name         string
+		resourceData string
+		want         []string
+	}{
+		{
+			name:         "no actions reported",
+			resourceData: `{"Members":[{"@odata.id":"/redfish/v1/Systems/1/Storage/1/Volumes/1"}]}`,
+			want:         []string{"VolumeCreation"},
+		},
+		{
+			name:         "delete action on the collection",
+			resourceData: `{"Members":[],"Actions":{"#VolumeCollection.Delete":{"target":"/redfish/v1/Systems/1/Storage/1/Volumes/Actions/VolumeCollection.Delete"}}}`,
+			want:         []string{"VolumeCreation", "VolumeDeletion"},
+		},
+		{
+			name:         "delete and secure erase actions on the members",
+			resourceData: `{"Members":[{"@odata.id":"/redfish/v1/Systems/1/Storage/1/Volumes/1","Actions":{"#Volume.SecureErase":{},"Oem":{"#Vendor.Delete":{}}}}]}`,
+			want:         []string{"VolumeCreation", "VolumeDeletion", "VolumeSecureErase"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resourceData map[string]interface{}
+			json.Unmarshal([]byte(tt.resourceData), &resourceData)
+			capabilities := getVolumeCapabilities(collectionOID, resourceData)
+			var useCases []string
+			for _, capability := range capabilities {
+				assert.Equal(t, collectionOID+"/Capabilities", capability.CapabilitiesObject.Oid)
+				assert.Equal(t, collectionOID, capability.Links.TargetCollection.Oid)
+				useCases = append(useCases, capability.UseCase)
+			}
+			assert.Equal(t, tt.want, useCases)
+		})
+	}
+}
+
+func Test_workShares(t *testing.T) {
+	tests := []struct {
+		name        string
+		alottedWork int32
+		count       int
+	}{
+		{name: "members dividing the work evenly", alottedWork: 40, count: 4},
+		{name: "members not dividing the work evenly", alottedWork: 10, count: 3},
+		{name: "more members than the work", alottedWork: 5, count: 8},
+		{name: "no members", alottedWork: 10, count: 0},
+		{name: "work close to the int32 bound", alottedWork: math.MaxInt32, count: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := newWorkShares(tt.alottedWork, tt.count)
+			var total int64
+			for i := 0; i < tt.count; i++ {
+				share := shares.next()
+				assert.GreaterOrEqual(t, share, int32(0))
+				total += int64(share)
+			}
+			assert.Equal(t, int32(0), shares.next(), "no work should be handed out once all the members got their share")
+			total += int64(shares.rest())
+			assert.Equal(t, int64(tt.alottedWork), total, "shares should add up to the alotted work")
+		})
+	}
+}
+
+func Test_clampProgress(t *testing.T) {
+	assert.Equal(t, int32(100), clampProgress(143))
+	assert.Equal(t, int32(0), clampProgress(-5))
+	assert.Equal(t, int32(50), clampProgress(50))
+	assert.Equal(t, int32(100), fillTaskData("1", "", "", response.RPC{}, common.Running, common.OK, 143, http.MethodPost).PercentComplete)
+}
+
+func Test_getResourceDetailsProgress(t *testing.T) {
+	config.SetUpMockConfig(t)
+	tests := []struct {
+		name    string
+		members int
+	}{
+		{name: "members not dividing the work evenly", members: 3},
+		{name: "more members than the work", members: 7},
+		{name: "no members", members: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var members []string
+			for i := 1; i <= tt.members; i++ {
+				members = append(members, fmt.Sprintf(`{"@odata.id":"/redfish/v1/Systems/1/Processors/%d"}`, i))
+			}
+			contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+				respBody := `{"@odata.id":"/redfish/v1/Systems/1/Processors","Members":[` + strings.Join(members, ",") + `]}`
+				if !strings.HasSuffix(url, "/Processors") {
+					id := url[strings.LastIndex(url, "/")+1:]
+					respBody = `{"@odata.id":"/redfish/v1/Systems/1/Processors/` + id + `","Id":"` + id + `"}`
+				}
+				return stubResponse(http.StatusOK, respBody)
+			}
+			req := testPluginRequest(contactClient, "/redfish/v1/Systems/1/Processors")
+			req.ParentOID = "/redfish/v1/Systems/1"
+			req.SystemID = "1"
+			h := newTestRespHolder()
+			progress := h.getResourceDetails(mockContext(), "", 10, 5, req)
+			assert.Equal(t, int32(15), progress, "progress should reach the alotted work")
+			assert.Len(t, h.InventoryData, tt.members+1, "all the members should be discovered")
+		})
+	}
+}
+
+func Test_getResourceDetailsSharedLink(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var sharedContacts int32
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody := `{"@odata.id":"/redfish/v1/Chassis/shared/NetworkAdapters/1","Id":"1"}`
+		switch {
+		case strings.HasSuffix(url, "/Chassis/1"), strings.HasSuffix(url, "/Chassis/2"):
+			id := url[strings.LastIndex(url, "/")+1:]
+			respBody = `{"@odata.id":"/redfish/v1/Chassis/` + id + `","Id":"` + id + `",` +
+				`"Links":{"NetworkAdapters":[{"@odata.id":"/redfish/v1/Chassis/shared/NetworkAdapters/1"}]}}`
+		default:
+			if atomic.AddInt32(&sharedContacts, 1) == 1 {
+				close(fetching)
+			}
+			<-release
+		}
+		return stubResponse(http.StatusOK, respBody)
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		SystemID:       "1",
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	h := newTestRespHolder()
+	var wg sync.WaitGroup
+	progress := make([]int32, 3)
+	discover := func(i int, oid string) {
+		defer wg.Done()
+		childReq := req
+		childReq.OID = oid
+		progress[i] = h.getResourceDetails(mockContext(), "", 0, 10, childReq)
+	}
+	wg.Add(1)
+	go discover(0, "/redfish/v1/Chassis/1")
+	<-fetching
+	// both the paths reach the shared link while it is being fetched
+	wg.Add(2)
+	go discover(1, "/redfish/v1/Chassis/2")
+	go discover(2, "/redfish/v1/Chassis/shared/NetworkAdapters/1")
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sharedContacts), "shared link should be fetched once")
+	assert.Equal(t, []int32{10, 10, 10}, progress, "progress should be advanced for the paths reusing the shared link")
+	assert.Len(t, h.InventoryData, 3, "all the resources should be discovered")
+	assert.Empty(t, h.fetchesInProgress)
+}
@@ -0,0 +1,122 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+)
+
+// decodeJSON decodes the plugin response discovered from the server. The numbers are decoded
+// as json.Number to keep the precision of the large integers, and the responses nested deeper
+// than the configured MaxJSONDepth are rejected before decoding them
+func decodeJSON(data []byte, v interface{}) error {
+	if err := checkJSONDepth(data, config.Data.DiscoveryConf.MaxJSONDepth); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after the top-level value")
+	}
+	return nil
+}
+
+// checkJSONDepth returns an error when the objects and arrays of the JSON data
+// are nested deeper than maxDepth, the depth is not checked when maxDepth is not set
+func checkJSONDepth(data []byte, maxDepth int) error {
+	if maxDepth <= 0 {
+		return nil
+	}
+	var depth int
+	var inString, escaped bool
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return fmt.Errorf("JSON data exceeds the maximum nesting depth of %d", maxDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// toFloat64 returns the number decoded either by decodeJSON or json.Unmarshal as float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case json.Number:
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// bytesToGB converts the bytes to GB in decimal format
+func bytesToGB(value interface{}) (float64, bool) {
+	return bytesToUnit(value, 1000000000)
+}
+
+// bytesToUnit converts the bytes to the unit of the size, the integer bytes decoded by
+// decodeJSON are divided without converting them to float64 first to avoid the rounding
+func bytesToUnit(value interface{}, unitSize int64) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		if capacity, err := number.Int64(); err == nil {
+			return float64(capacity/unitSize) + float64(capacity%unitSize)/float64(unitSize), true
+		}
+	}
+	capacity, ok := toFloat64(value)
+	return capacity / float64(unitSize), ok
+}
+
+// bytesToCapacity converts the bytes to the configured StorageCapacityUnit, GB and GiB are
+// in decimal and binary format respectively. Nothing is returned for the absent or zero bytes
+func bytesToCapacity(value interface{}, unit string) (float64, bool) {
+	var capacity float64
+	var ok bool
+	switch unit {
+	case config.StorageCapacityUnitBytes:
+		capacity, ok = toFloat64(value)
+	case config.StorageCapacityUnitGiB:
+		capacity, ok = bytesToUnit(value, 1<<30)
+	default:
+		capacity, ok = bytesToGB(value)
+	}
+	return capacity, ok && capacity > 0
+}
@@ -0,0 +1,106 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_decodeJSONPrecision(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var resource map[string]interface{}
+	err := decodeJSON([]byte(`{"@odata.id":"/redfish/v1/Systems/1/Storage/1/Drives/1","CapacityBytes":9007199254740993}`), &resource)
+	assert.Nil(t, err)
+	assert.Equal(t, json.Number("9007199254740993"), resource["CapacityBytes"], "large integer should keep its precision")
+
+	data, _ := json.Marshal(resource)
+	assert.Contains(t, string(data), `"CapacityBytes":9007199254740993`, "large integer should keep its precision when saved")
+
+	capacity, ok := bytesToGB(resource["CapacityBytes"])
+	assert.True(t, ok)
+	assert.InDelta(t, 9007199.254740993, capacity, 1e-8, "capacity should be converted to GB without rounding the bytes")
+	capacity, ok = bytesToGB(float64(480103981056))
+	assert.True(t, ok)
+	assert.Equal(t, 480.103981056, capacity, "capacity decoded as float64 should be converted to GB")
+
+	count, ok := toFloat64(json.Number("2"))
+	assert.True(t, ok)
+	assert.Equal(t, float64(2), count)
+	_, ok = toFloat64("2")
+	assert.False(t, ok, "string should not be converted to a number")
+}
+
+func Test_bytesToCapacity(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		unit   string
+		want   float64
+		wantOK bool
+	}{
+		{name: "GB", value: json.Number("480103981056"), unit: config.StorageCapacityUnitGB, want: 480.103981056, wantOK: true},
+		{name: "GiB", value: json.Number("1073741824"), unit: config.StorageCapacityUnitGiB, want: 1, wantOK: true},
+		{name: "GiB decoded as float64", value: float64(1610612736), unit: config.StorageCapacityUnitGiB, want: 1.5, wantOK: true},
+		{name: "Bytes", value: json.Number("480103981056"), unit: config.StorageCapacityUnitBytes, want: 480103981056, wantOK: true},
+		{name: "absent bytes", value: nil, unit: config.StorageCapacityUnitGB},
+		{name: "zero bytes", value: json.Number("0"), unit: config.StorageCapacityUnitBytes},
+		{name: "bytes as string", value: "1073741824", unit: config.StorageCapacityUnitGiB},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := bytesToCapacity(tt.value, tt.unit)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.InDelta(t, tt.want, got, 1e-9)
+			}
+		})
+	}
+}
+
+func Test_decodeJSONDepth(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.MaxJSONDepth = 5
+	var resource map[string]interface{}
+
+	nested := strings.Repeat(`{"a":`, 5) + "1" + strings.Repeat("}", 5)
+	assert.Nil(t, decodeJSON([]byte(nested), &resource), "data within the maximum depth should be decoded")
+
+	nested = strings.Repeat(`{"a":[`, 3) + "1" + strings.Repeat("]}", 3)
+	err := decodeJSON([]byte(nested), &resource)
+	if assert.Error(t, err, "data deeper than the maximum depth should be rejected") {
+		assert.Contains(t, err.Error(), "maximum nesting depth")
+	}
+
+	brackets := `{"Description":"` + strings.Repeat("{[", 10) + `\"{"}`
+	assert.Nil(t, decodeJSON([]byte(brackets), &resource), "brackets in the strings should not be counted")
+
+	assert.Error(t, decodeJSON([]byte(`{"a":1}{"b":2}`), &resource), "data after the top-level value should be rejected")
+}
+
+func Test_createServerSearchIndexDecodedNumbers(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var computeSystem map[string]interface{}
+	err := decodeJSON([]byte(`{"MemorySummary":{"TotalSystemMemoryGiB":384},"ProcessorSummary":{"Count":2,"Model":"Intel Xeon"}}`), &computeSystem)
+	assert.Nil(t, err)
+	searchForm := createServerSearchIndex(mockContext(), computeSystem, "/redfish/v1/Systems/1", "someuuid")
+	assert.Equal(t, float64(384), searchForm["MemorySummary/TotalSystemMemoryGiB"], "decoded memory should be indexed as float64")
+	assert.Equal(t, float64(2), searchForm["ProcessorSummary/Count"], "decoded processor count should be indexed as float64")
+	assert.NotContains(t, searchForm, "MemorySummary/TotalSystemPersistentMemoryGiB")
+}
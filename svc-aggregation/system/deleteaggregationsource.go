@@ -56,7 +56,9 @@ func (e *ExternalInterface) DeleteAggregationSources(ctx context.Context, taskID
 		})
 		go runtime.Goexit()
 	}
+	hostName, connectionMethodURI := getAggregationSourceAuditDetails(req.URL)
 	data := e.DeleteAggregationSource(ctx, req)
+	auditInventoryChange(ctx, inventoryAuditRemove, req.URL, hostName, connectionMethodURI, data)
 	err = e.UpdateTask(ctx, common.TaskData{
 		TaskID:          taskID,
 		TargetURI:       targetURI,
@@ -112,7 +114,16 @@ func (e *ExternalInterface) DeleteAggregationSource(ctx context.Context, req *ag
 	uuid := resource[strings.LastIndexByte(resource, '/')+1:]
 	target, terr := agmodel.GetTarget(uuid)
 	if terr != nil || target == nil {
-		cmVariants := getConnectionMethodVariants(connectionMethod.ConnectionMethodVariant)
+		// firmware version is not required to remove the plugin
+		cmVariants, cmErr := getConnectionMethodVariants(connectionMethod.ConnectionMethodVariant)
+		if cmErr != nil && cmVariants.PluginID == "" {
+			errMsg := "Unable to get the connection method variant: " + cmErr.Error()
+			l.LogWithFields(ctx).Error(errMsg)
+			return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+		}
+		if cmErr != nil {
+			l.LogWithFields(ctx).Warn(cmErr.Error())
+		}
 		if len(connectionMethod.Links.AggregationSources) > 1 {
 			errMsg := fmt.Sprintf("Plugin " + cmVariants.PluginID + " can't be removed since it managing devices")
 			l.LogWithFields(ctx).Info(errMsg)
@@ -190,6 +201,21 @@ func (e *ExternalInterface) DeleteAggregationSource(ctx context.Context, req *ag
 	return resp
 }
 
+// getAggregationSourceAuditDetails returns the host name and the connection method of the aggregation
+// source for its audit event, the details are empty when the aggregation source can't be read
+func getAggregationSourceAuditDetails(aggregationSourceURI string) (hostName, connectionMethodURI string) {
+	aggregationSource, err := agmodel.GetAggregationSourceInfo(aggregationSourceURI)
+	if err != nil {
+		return "", ""
+	}
+	if links, ok := aggregationSource.Links.(map[string]interface{}); ok {
+		if connectionMethod, ok := links["ConnectionMethod"].(map[string]interface{}); ok {
+			connectionMethodURI, _ = connectionMethod["@odata.id"].(string)
+		}
+	}
+	return aggregationSource.HostName, connectionMethodURI
+}
+
 // removeAggregationSourceFromAggregates will remove the element from the aggregate
 // if the system is deleted from ODIM
 func removeAggregationSourceFromAggregates(ctx context.Context, systemList []string) {
@@ -537,7 +563,8 @@ func (e *ExternalInterface) deleteWildCardValues(ctx context.Context, systemID s
 	}
 	for _, oid := range telemetryList {
 		oID := strings.Split(oid, ":")
-		if !strings.Contains(oid, "MetricReports") && !strings.Contains(oid, "Collection") {
+		// TelemetryService resource is shared by all the servers like the collections
+		if !strings.Contains(oid, "MetricReports") && !strings.Contains(oid, "Collection") && oID[1] != telemetryServiceURI {
 			odataID := oID[1]
 			resourceData := make(map[string]interface{})
 			data, dbErr := agmodel.GetResourceDetails(odataID)
@@ -0,0 +1,137 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+)
+
+// discoveryMetricsKey is the context key of the discovery metrics
+type discoveryMetricsKey struct{}
+
+// discoveryMetrics accumulates the cost of discovering a device,
+// the counters are updated by contactPlugin through the context
+type discoveryMetrics struct {
+	resources int64
+	bytesRead int64
+	startTime time.Time
+}
+
+// withDiscoveryMetrics returns a context carrying a new discovery metrics accumulator
+func withDiscoveryMetrics(ctx context.Context) (context.Context, *discoveryMetrics) {
+	metrics := &discoveryMetrics{startTime: time.Now()}
+	return context.WithValue(ctx, discoveryMetricsKey{}, metrics), metrics
+}
+
+// getDiscoveryMetrics returns the discovery metrics accumulator of the context if any
+func getDiscoveryMetrics(ctx context.Context) *discoveryMetrics {
+	if ctx == nil {
+		return nil
+	}
+	metrics, _ := ctx.Value(discoveryMetricsKey{}).(*discoveryMetrics)
+	return metrics
+}
+
+// addResource accounts a resource read from the plugin along with the size of its body
+func (m *discoveryMetrics) addResource(bytesRead int) {
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.resources, 1)
+	atomic.AddInt64(&m.bytesRead, int64(bytesRead))
+}
+
+// log writes the accumulated metrics of the discovery of the device as a structured log
+func (m *discoveryMetrics) log(ctx context.Context, deviceUUID, managerAddress string) {
+	if m == nil {
+		return
+	}
+	resources := atomic.LoadInt64(&m.resources)
+	elapsed := time.Since(m.startTime)
+	var resourcesPerSec float64
+	if elapsed > 0 {
+		resourcesPerSec = float64(resources) / elapsed.Seconds()
+	}
+	l.LogWithFields(ctx).WithFields(map[string]interface{}{
+		"DeviceUUID":         deviceUUID,
+		"ManagerAddress":     managerAddress,
+		"Resources":          resources,
+		"BytesRead":          atomic.LoadInt64(&m.bytesRead),
+		"ElapsedTimeInMs":    elapsed.Milliseconds(),
+		"ResourcesPerSecond": resourcesPerSec,
+	}).Info("discovery metrics of the device")
+}
+
+// DiscoveryMetric is the timing data of a resource discovered from a device,
+// it is reported to the DiscoveryMetricHook of ExternalInterface
+type DiscoveryMetric struct {
+	OID          string
+	ResourceType string
+	StatusCode   int32
+	// PluginLatency is the time taken by the plugin to return the resource
+	PluginLatency time.Duration
+	// DBSaveLatency is the time taken to save the resource, it is zero for the resources
+	// saved along with the inventory of the device
+	DBSaveLatency time.Duration
+	// ChildLinks is the number of the links collected from the resource
+	ChildLinks int
+}
+
+// resourceMetric records the DiscoveryMetric of a resource for the DiscoveryMetricHook of the request
+type resourceMetric struct {
+	DiscoveryMetric
+	hook func(context.Context, DiscoveryMetric)
+}
+
+// newResourceMetric starts recording the DiscoveryMetric of the resource of the request
+func newResourceMetric(req getResourceRequest, resourceType string) *resourceMetric {
+	return &resourceMetric{
+		DiscoveryMetric: DiscoveryMetric{
+			OID:          req.OID,
+			ResourceType: resourceType,
+		},
+		hook: req.DiscoveryMetricHook,
+	}
+}
+
+// pluginCallDone records the latency and the status code of the plugin call started at startTime
+func (m *resourceMetric) pluginCallDone(startTime time.Time, getResponse responseStatus) {
+	m.PluginLatency = time.Since(startTime)
+	m.StatusCode = getResponse.StatusCode
+}
+
+// childLinkCount returns the number of the links collected from the resource other than its own link
+func childLinkCount(links map[string]bool, oid string) int {
+	count := 0
+	for link := range links {
+		if strings.TrimSuffix(link, "/") != oid {
+			count++
+		}
+	}
+	return count
+}
+
+// report passes the recorded DiscoveryMetric to the hook, the requests without hook are skipped
+func (m *resourceMetric) report(ctx context.Context) {
+	if m.hook == nil {
+		return
+	}
+	m.hook(ctx, m.DiscoveryMetric)
+}
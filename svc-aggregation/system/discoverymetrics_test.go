@@ -0,0 +1,110 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_discoveryMetrics(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ctx, metrics := withDiscoveryMetrics(mockContext())
+	assert.Equal(t, metrics, getDiscoveryMetrics(ctx), "metrics should be carried in the context")
+	assert.Nil(t, getDiscoveryMetrics(mockContext()), "context without metrics")
+
+	req := getResourceRequest{
+		ContactClient:  mockPreviewContactClient,
+		OID:            "/redfish/v1/Systems/1/Processors",
+		ParentOID:      "/redfish/v1/Systems/1",
+		SystemID:       "1",
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: "GET",
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	h.getResourceDetails(ctx, "", 0, 10, req)
+
+	// Processors collection and its member are read from the plugin
+	assert.Equal(t, int64(2), metrics.resources)
+	expectedBytes := len(previewDevice["/ODIM/v1/Systems/1/Processors"]) + len(previewDevice["/ODIM/v1/Systems/1/Processors/1"])
+	assert.Equal(t, int64(expectedBytes), metrics.bytesRead)
+	metrics.log(ctx, "someuuid", "localhost")
+}
+
+func Test_discoveryMetricHook(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var lock sync.Mutex
+	metrics := make(map[string][]DiscoveryMetric)
+	req := getResourceRequest{
+		ContactClient:  mockPreviewContactClient,
+		OID:            "/redfish/v1/Systems/1/Processors",
+		ParentOID:      "/redfish/v1/Systems/1",
+		SystemID:       "1",
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: "GET",
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+		DiscoveryMetricHook: func(ctx context.Context, metric DiscoveryMetric) {
+			lock.Lock()
+			defer lock.Unlock()
+			metrics[metric.OID] = append(metrics[metric.OID], metric)
+		},
+	}
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	h.getResourceDetails(mockContext(), "", 0, 10, req)
+
+	assert.Len(t, metrics, 2, "hook should be called for the collection and its member")
+	if assert.Len(t, metrics["/redfish/v1/Systems/1/Processors"], 1) {
+		metric := metrics["/redfish/v1/Systems/1/Processors"][0]
+		assert.Equal(t, "ProcessorsCollection", metric.ResourceType)
+		assert.Equal(t, int32(http.StatusOK), metric.StatusCode)
+		assert.Equal(t, 1, metric.ChildLinks, "member of the collection should be counted")
+		assert.True(t, metric.PluginLatency > 0, "plugin latency should be recorded")
+		assert.Zero(t, metric.DBSaveLatency, "collection is saved along with the inventory")
+	}
+	if assert.Len(t, metrics["/redfish/v1/Systems/1/Processors/1"], 1) {
+		metric := metrics["/redfish/v1/Systems/1/Processors/1"][0]
+		assert.Equal(t, "Processors", metric.ResourceType)
+		assert.Equal(t, 0, metric.ChildLinks)
+	}
+
+	// the resources not found are reported with the status code of the plugin
+	metrics = make(map[string][]DiscoveryMetric)
+	req.OID = "/redfish/v1/Systems/1/Memory"
+	h.getResourceDetails(mockContext(), "", 0, 10, req)
+	if assert.Len(t, metrics[req.OID], 1) {
+		assert.Equal(t, int32(http.StatusNotFound), metrics[req.OID][0].StatusCode)
+	}
+}
@@ -0,0 +1,270 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+// previewNode is a resource in the discovery preview tree
+type previewNode struct {
+	OID      string         `json:"@odata.id"`
+	Error    string         `json:"Error,omitempty"`
+	Children []*previewNode `json:"Children,omitempty"`
+}
+
+// discoveryPreview holds the resource tree a device exposes along with
+// the number of resources which would be discovered under each root collection
+type discoveryPreview struct {
+	Roots          []*previewNode `json:"Roots"`
+	ResourceCount  int            `json:"ResourceCount"`
+	ResourceCounts map[string]int `json:"ResourceCounts"`
+	Warnings       []string       `json:"Warnings,omitempty"`
+	traversedLinks map[string]bool
+	// h guards the traversal with the checks of the discovery, it stops the preview when the
+	// add is cancelled and holds the warnings of the links beyond the MaxTraversalDepth
+	h *respHolder
+}
+
+// previewRoot is a root collection walked in the preview along with the
+// resources which are skipped under its members
+type previewRoot struct {
+	oid          string
+	resourceList []string
+}
+
+// getPreviewRoots returns the root collections in the order they are discovered while adding a server
+func getPreviewRoots() []previewRoot {
+	return []previewRoot{
+		{oid: "/redfish/v1/Systems", resourceList: config.Data.AddComputeSkipResources.SkipResourceListUnderSystem},
+		{oid: "/redfish/v1/UpdateService/FirmwareInventory", resourceList: config.Data.AddComputeSkipResources.SkipResourceListUnderOthers},
+		{oid: "/redfish/v1/UpdateService/SoftwareInventory", resourceList: config.Data.AddComputeSkipResources.SkipResourceListUnderOthers},
+		{oid: "/redfish/v1/LicenseService/Licenses", resourceList: config.Data.AddComputeSkipResources.SkipResourceListUnderOthers},
+		{oid: "/redfish/v1/Chassis", resourceList: config.Data.AddComputeSkipResources.SkipResourceListUnderChassis},
+		{oid: "/redfish/v1/Managers", resourceList: config.Data.AddComputeSkipResources.SkipResourceListUnderManager},
+	}
+}
+
+// previewDiscovery runs the same link traversal as the add compute flow against the device
+// and returns the tree of resources which would be discovered. Nothing is persisted in DB,
+// so it can be used to review the resources exposed by a device before adding it. It is
+// run for the dry run of adding a server with PreviewResources. The traversal is stopped
+// and bounded the same way as the discovery through h.
+func previewDiscovery(ctx context.Context, h *respHolder, req getResourceRequest) *discoveryPreview {
+	preview := &discoveryPreview{
+		ResourceCounts: make(map[string]int),
+		traversedLinks: make(map[string]bool),
+		h:              h,
+	}
+	h.lock.Lock()
+	warningsBefore := len(h.Warnings)
+	h.lock.Unlock()
+	req.HTTPMethodType = http.MethodGet
+	for _, root := range getPreviewRoots() {
+		req.OID = root.oid
+		rootNode := &previewNode{OID: root.oid}
+		preview.Roots = append(preview.Roots, rootNode)
+		if err := h.checkCancelled(ctx, root.oid); err != nil {
+			rootNode.Error = err.Error()
+			continue
+		}
+		body, _, _, err := contactPlugin(ctx, req, "error while trying to get the "+root.oid+" collection details: ")
+		if err != nil {
+			l.LogWithFields(ctx).Warn(err)
+			rootNode.Error = err.Error()
+			continue
+		}
+		var collection map[string]interface{}
+		if err := decodeJSON(body, &collection); err != nil {
+			rootNode.Error = "error while trying unmarshal " + root.oid + ": " + err.Error()
+			continue
+		}
+		members, _ := collection["Members"].([]interface{})
+		countBefore := preview.ResourceCount
+		for _, member := range members {
+			oDataID, ok := getMemberODataID(member)
+			if !ok {
+				continue
+			}
+			memberReq := req
+			memberReq.OID = oDataID
+			rootNode.Children = append(rootNode.Children, preview.previewMember(ctx, memberReq, root.resourceList))
+		}
+		preview.ResourceCounts[root.oid] = preview.ResourceCount - countBefore
+	}
+	h.lock.Lock()
+	preview.Warnings = append(preview.Warnings, h.Warnings[warningsBefore:]...)
+	h.lock.Unlock()
+	return preview
+}
+
+// previewMember walks a member of a root collection, it is the read only counterpart of getIndivdualInfo
+func (p *discoveryPreview) previewMember(ctx context.Context, req getResourceRequest, resourceList []string) *previewNode {
+	node := &previewNode{OID: req.OID}
+	p.ResourceCount++
+	p.traversedLinks[req.OID] = true
+	resource, err := p.previewGetResource(ctx, req)
+	if err != nil {
+		node.Error = err.Error()
+		return node
+	}
+	var retrievalLinks = make(map[string]bool)
+	collectLinks(ctx, req.OID, resource, retrievalLinks, false)
+	removeRetrievalLinks(retrievalLinks, req.OID, resourceList, p.traversedLinks)
+	req.ParentOID = req.OID
+	for _, resourceOID := range sortedLinks(retrievalLinks) {
+		childReq := req
+		childReq.OID = strings.TrimSuffix(resourceOID, "/")
+		childReq.OemFlag = retrievalLinks[resourceOID]
+		node.Children = append(node.Children, p.previewResource(ctx, childReq))
+	}
+	return node
+}
+
+// previewResource walks a resource and the resources linked to it, it is the read only counterpart of getResourceDetails
+func (p *discoveryPreview) previewResource(ctx context.Context, req getResourceRequest) *previewNode {
+	node := &previewNode{OID: req.OID}
+	p.ResourceCount++
+	p.traversedLinks[req.OID] = true
+	resource, err := p.previewGetResource(ctx, req)
+	if err != nil {
+		node.Error = err.Error()
+		return node
+	}
+	var retrievalLinks = make(map[string]bool)
+	collectLinks(ctx, req.OID, resource, retrievalLinks, req.OemFlag)
+	for _, oid := range sortedLinks(retrievalLinks) {
+		// links could be traversed while walking the previous siblings
+		if !checkRetrieval(oid, req.OID, p.traversedLinks) {
+			continue
+		}
+		oid = strings.TrimSuffix(oid, "/")
+		p.h.lock.Lock()
+		exceedsDepth := p.h.exceedsTraversalDepth(ctx, oid, req)
+		p.h.lock.Unlock()
+		if exceedsDepth {
+			continue
+		}
+		childReq := req
+		childReq.OID = oid
+		childReq.ParentOID = req.OID
+		childReq.OemFlag = retrievalLinks[oid]
+		childReq.Depth = req.Depth + 1
+		node.Children = append(node.Children, p.previewResource(ctx, childReq))
+	}
+	return node
+}
+
+// previewGetResource gets the resource of the request from the plugin, the plugin
+// isn't contacted once the preview is stopped
+func (p *discoveryPreview) previewGetResource(ctx context.Context, req getResourceRequest) (map[string]interface{}, error) {
+	if err := p.h.checkCancelled(ctx, req.OID); err != nil {
+		return nil, err
+	}
+	body, _, _, err := contactPlugin(ctx, req, "error while trying to get the "+req.OID+" details: ")
+	if err != nil {
+		return nil, err
+	}
+	var resource map[string]interface{}
+	if err := decodeJSON(body, &resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// sortedLinks returns the links in a stable order so that the preview is repeatable
+func sortedLinks(links map[string]bool) []string {
+	oids := make([]string, 0, len(links))
+	for oid := range links {
+		oids = append(oids, oid)
+	}
+	sort.Strings(oids)
+	return oids
+}
+
+// PreviewAddCompute validates the credentials of the server through the plugin and returns the tree
+// of the resources which would be discovered by adding it. Nothing is saved in DB, so support staff
+// can review the resources exposed by a server before adding it.
+func (e *ExternalInterface) PreviewAddCompute(ctx context.Context, pluginID string, req AddResourceRequest) response.RPC {
+	plugin, errs := agmodel.GetPluginData(pluginID)
+	if errs != nil {
+		errMsg := "error while getting plugin data: " + errs.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"plugin", pluginID}, nil)
+	}
+	var pluginContactRequest getResourceRequest
+	pluginContactRequest.ContactClient = e.ContactClient
+	pluginContactRequest.GetPluginStatus = e.GetPluginStatus
+	pluginContactRequest.Plugin = plugin
+	pluginContactRequest.StatusPoll = e.GetPluginStatus != nil
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
+		// the session is reused for the whole preview and deleted once it is done
+		pluginContactRequest.Sessions = newPluginToken()
+		if !config.Data.DiscoveryConf.SkipPluginSessionTeardown {
+			defer deletePluginSessions(ctx, pluginContactRequest)
+		}
+		token, getResponse, err := getPluginSessionToken(ctx, pluginContactRequest)
+		if err != nil {
+			errMsg := err.Error()
+			l.LogWithFields(ctx).Error(errMsg)
+			return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, nil)
+		}
+		pluginContactRequest.Token = token
+	} else {
+		pluginContactRequest.LoginCredentials = map[string]string{
+			"UserName": plugin.Username,
+			"Password": string(plugin.Password),
+		}
+	}
+
+	managerAddress := strings.ToLower(req.ManagerAddress)
+	pluginContactRequest.DeviceInfo = agmodel.SaveSystem{
+		ManagerAddress: managerAddress,
+		UserName:       req.UserName,
+		Password:       []byte(req.Password),
+		PluginID:       pluginID,
+	}
+	pluginContactRequest.OID = "/ODIM/v1/validate"
+	pluginContactRequest.HTTPMethodType = http.MethodPost
+	if _, _, getResponse, err := contactPlugin(ctx, pluginContactRequest, "error while trying to authenticate the compute server: "); err != nil {
+		errMsg := err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, nil)
+	}
+
+	pluginContactRequest.DeviceInfo = map[string]interface{}{
+		"ManagerAddress": managerAddress,
+		"UserName":       req.UserName,
+		"Password":       []byte(req.Password),
+	}
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	return response.RPC{
+		StatusCode:    http.StatusOK,
+		StatusMessage: response.Success,
+		Body:          previewDiscovery(ctx, h, pluginContactRequest),
+	}
+}
@@ -0,0 +1,188 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+var previewDevice = map[string]string{
+	"/ODIM/v1/Systems":                       `{"Members":[{"@odata.id":"/ODIM/v1/Systems/1"}]}`,
+	"/ODIM/v1/Systems/1":                     `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1","Processors":{"@odata.id":"/ODIM/v1/Systems/1/Processors"},"Links":{"Chassis":[{"@odata.id":"/ODIM/v1/Chassis/1"}]}}`,
+	"/ODIM/v1/Systems/1/Processors":          `{"@odata.id":"/ODIM/v1/Systems/1/Processors","Members":[{"@odata.id":"/ODIM/v1/Systems/1/Processors/1"}]}`,
+	"/ODIM/v1/Systems/1/Processors/1":        `{"@odata.id":"/ODIM/v1/Systems/1/Processors/1","Id":"1"}`,
+	"/ODIM/v1/Chassis":                       `{"Members":[{"@odata.id":"/ODIM/v1/Chassis/1"}]}`,
+	"/ODIM/v1/Chassis/1":                     `{"@odata.id":"/ODIM/v1/Chassis/1","Id":"1","Power":{"@odata.id":"/ODIM/v1/Chassis/1/Power"},"Links":{"ComputerSystems":[{"@odata.id":"/ODIM/v1/Systems/1"}]}}`,
+	"/ODIM/v1/Chassis/1/Power":               `{"@odata.id":"/ODIM/v1/Chassis/1/Power","Id":"Power"}`,
+	"/ODIM/v1/Managers":                      `{"Members":[{"@odata.id":"/ODIM/v1/Managers/1"}]}`,
+	"/ODIM/v1/Managers/1":                    `{"@odata.id":"/ODIM/v1/Managers/1","Id":"1","EthernetInterfaces":{"@odata.id":"/ODIM/v1/Managers/1/EthernetInterfaces"}}`,
+	"/ODIM/v1/Managers/1/EthernetInterfaces": `{"@odata.id":"/ODIM/v1/Managers/1/EthernetInterfaces","Members":[]}`,
+}
+
+func mockPreviewContactClient(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+	if method == http.MethodPost && strings.HasSuffix(url, "/ODIM/v1/validate") {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"MessageId": "` + response.Success + `"}`)),
+		}, nil
+	}
+	if method != http.MethodGet {
+		return &http.Response{
+			StatusCode: http.StatusMethodNotAllowed,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		}, nil
+	}
+	respBody, ok := previewDevice[strings.TrimPrefix(url, "https://localhost:9091")]
+	if !ok {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error":"not found"}`)),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(respBody)),
+	}, nil
+}
+
+func Test_previewDiscovery(t *testing.T) {
+	config.SetUpMockConfig(t)
+	req := getResourceRequest{
+		ContactClient: mockPreviewContactClient,
+		DeviceUUID:    "someuuid",
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+
+	preview := previewDiscovery(mockContext(), newTestRespHolder(), req)
+
+	expectedRoots := []*previewNode{
+		{OID: "/redfish/v1/Systems", Children: []*previewNode{
+			{OID: "/redfish/v1/Systems/1", Children: []*previewNode{
+				{OID: "/redfish/v1/Systems/1/Processors", Children: []*previewNode{
+					{OID: "/redfish/v1/Systems/1/Processors/1"},
+				}},
+			}},
+		}},
+		{OID: "/redfish/v1/UpdateService/FirmwareInventory"},
+		{OID: "/redfish/v1/UpdateService/SoftwareInventory"},
+		{OID: "/redfish/v1/LicenseService/Licenses"},
+		{OID: "/redfish/v1/Chassis", Children: []*previewNode{
+			{OID: "/redfish/v1/Chassis/1", Children: []*previewNode{
+				{OID: "/redfish/v1/Chassis/1/Power"},
+			}},
+		}},
+		{OID: "/redfish/v1/Managers", Children: []*previewNode{
+			{OID: "/redfish/v1/Managers/1", Children: []*previewNode{
+				{OID: "/redfish/v1/Managers/1/EthernetInterfaces"},
+			}},
+		}},
+	}
+	if assert.Len(t, preview.Roots, len(expectedRoots)) {
+		for i, root := range preview.Roots {
+			// the collections the device doesn't expose are reported with the error
+			if len(expectedRoots[i].Children) == 0 {
+				assert.Equal(t, expectedRoots[i].OID, root.OID)
+				assert.NotEmpty(t, root.Error, "error should be reported for "+root.OID)
+				continue
+			}
+			assert.Equal(t, expectedRoots[i], root, "tree of "+root.OID+" should match the device")
+		}
+	}
+	assert.Equal(t, 7, preview.ResourceCount)
+	assert.Equal(t, 3, preview.ResourceCounts["/redfish/v1/Systems"])
+	assert.Equal(t, 2, preview.ResourceCounts["/redfish/v1/Chassis"])
+	assert.Equal(t, 2, preview.ResourceCounts["/redfish/v1/Managers"])
+}
+
+func Test_previewDiscoveryGuards(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		config.Data.DiscoveryConf.MaxTraversalDepth = 0
+	}()
+	var calls int
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		calls++
+		switch strings.TrimPrefix(url, "https://localhost:9091") {
+		case "/ODIM/v1/Systems/1/Processors/1":
+			return stubResponse(http.StatusOK, `{"@odata.id":"/ODIM/v1/Systems/1/Processors/1","Id":"1","Metrics":{"@odata.id":"/ODIM/v1/Systems/1/Processors/1/ProcessorMetrics"}}`)
+		case "/ODIM/v1/Systems/1/Processors/1/ProcessorMetrics":
+			return stubResponse(http.StatusOK, `{"@odata.id":"/ODIM/v1/Systems/1/Processors/1/ProcessorMetrics","Id":"ProcessorMetrics"}`)
+		}
+		return mockPreviewContactClient(ctx, url, method, token, odataID, body, credentials)
+	}
+	req := testPluginRequest(contactClient, "")
+
+	// the links beyond the MaxTraversalDepth are not followed like in the discovery
+	config.Data.DiscoveryConf.MaxTraversalDepth = 1
+	preview := previewDiscovery(mockContext(), newTestRespHolder(), req)
+	processor := preview.Roots[0].Children[0].Children[0].Children[0]
+	assert.Equal(t, "/redfish/v1/Systems/1/Processors/1", processor.OID)
+	assert.Empty(t, processor.Children, "link beyond the maximum traversal depth should not be followed")
+	assert.Len(t, preview.Warnings, 1, "warning should be reported for the link which is not followed")
+
+	config.Data.DiscoveryConf.MaxTraversalDepth = 0
+	preview = previewDiscovery(mockContext(), newTestRespHolder(), req)
+	processor = preview.Roots[0].Children[0].Children[0].Children[0]
+	assert.Len(t, processor.Children, 1, "link should be followed when the depth isn't limited")
+
+	// the plugin isn't contacted once the request is cancelled
+	ctx, cancel := context.WithCancel(mockContext())
+	cancel()
+	calls = 0
+	preview = previewDiscovery(ctx, newTestRespHolder(), req)
+	assert.Equal(t, 0, calls, "plugin should not be contacted after the request is cancelled")
+	assert.Equal(t, 0, preview.ResourceCount)
+	for _, root := range preview.Roots {
+		assert.NotEmpty(t, root.Error, "cancellation should be reported for "+root.OID)
+	}
+}
+
+func TestExternalInterface_PreviewAddCompute(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		common.TruncateDB(common.OnDisk)
+		common.TruncateDB(common.InMemory)
+	}()
+	if err := mockPluginData(t, "GRF"); err != nil {
+		t.Fatalf("error while trying to create mock plugin data: %v", err)
+	}
+	e := &ExternalInterface{ContactClient: mockPreviewContactClient}
+	req := AddResourceRequest{ManagerAddress: "100.0.0.1", UserName: "admin", Password: "password"}
+
+	resp := e.PreviewAddCompute(mockContext(), "GRF", req)
+	assert.Equal(t, int32(http.StatusOK), resp.StatusCode)
+	preview, ok := resp.Body.(*discoveryPreview)
+	if assert.True(t, ok, "preview should respond with the resource tree") {
+		assert.Equal(t, 7, preview.ResourceCount)
+	}
+
+	resp = e.PreviewAddCompute(mockContext(), "UnknownPlugin", req)
+	assert.Equal(t, int32(http.StatusNotFound), resp.StatusCode, "preview should fail for an unknown plugin")
+}
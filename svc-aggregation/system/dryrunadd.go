@@ -0,0 +1,171 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+const (
+	dryRunSourcePlugin = "Plugin"
+	dryRunSourceBMC    = "BMC"
+)
+
+// dryRunSummary is the response of a dry run of adding an aggregation source,
+// it summarizes what would be added without adding it
+type dryRunSummary struct {
+	HostName         string            `json:"HostName"`
+	ConnectionMethod *ConnectionMethod `json:"ConnectionMethod"`
+	SourceType       string            `json:"SourceType"`
+	PluginID         string            `json:"PluginID"`
+	PluginVersion    string            `json:"PluginVersion,omitempty"`
+	Systems          []string          `json:"Systems,omitempty"`
+	Message          string            `json:"Message"`
+	// ResourceTree is the tree of the resources exposed by the server, when it is requested
+	ResourceTree *discoveryPreview `json:"ResourceTree,omitempty"`
+}
+
+// dryRunAggregationSource completes the dry run of adding an aggregation source once the status of
+// the manager address is checked. The plugins are checked for the duplicates, the servers for their
+// credentials and the duplicates of their systems. Nothing is saved in DB, so there is nothing to
+// roll back when the checks fail.
+func (e *ExternalInterface) dryRunAggregationSource(ctx context.Context, taskID, targetURI string, req AddResourceRequest, pluginContactRequest getResourceRequest,
+	connectionMethod agmodel.ConnectionMethod, cmVariants connectionMethodVariants, statusResult statusCheckResult, taskInfo *common.TaskUpdateInfo) response.RPC {
+	summary := dryRunSummary{
+		HostName:         req.ManagerAddress,
+		ConnectionMethod: req.ConnectionMethod,
+		PluginID:         cmVariants.PluginID,
+	}
+	switch statusResult.StatusCode {
+	case http.StatusOK:
+		if len(connectionMethod.Links.AggregationSources) > 0 {
+			errMsg := "Cant proceed to add aggregation source, since connection method is already managing other aggregation sources"
+			l.LogWithFields(ctx).Error(errMsg)
+			return common.GeneralError(http.StatusConflict, response.ResourceInUse, errMsg, nil, taskInfo)
+		}
+		if _, errs := agmodel.GetPluginData(cmVariants.PluginID); errs == nil {
+			errMsg := "error:plugin with name " + cmVariants.PluginID + " already exists"
+			l.LogWithFields(ctx).Error(errMsg)
+			return common.GeneralError(http.StatusConflict, response.ResourceAlreadyExists, errMsg, []interface{}{"Plugin", "PluginID", cmVariants.PluginID}, taskInfo)
+		}
+		summary.SourceType = dryRunSourcePlugin
+		summary.PluginVersion = statusResult.PluginVersion
+		summary.Message = fmt.Sprintf("The plugin %s of version %s would be added", cmVariants.PluginID, statusResult.PluginVersion)
+	case http.StatusNotFound:
+		systems, preview, resp := e.dryRunCompute(ctx, cmVariants.PluginID, req, pluginContactRequest, taskInfo)
+		if resp.StatusCode != 0 {
+			return resp
+		}
+		summary.SourceType = dryRunSourceBMC
+		summary.Systems = systems
+		summary.ResourceTree = preview
+		summary.Message = fmt.Sprintf("The server would be added with %d systems using the plugin %s", len(systems), cmVariants.PluginID)
+	default:
+		return statusResult.Response
+	}
+	l.LogWithFields(ctx).Info("dry run of adding the aggregation source " + req.ManagerAddress + " succeeded: " + summary.Message)
+	resp := response.RPC{
+		StatusCode:    http.StatusOK,
+		StatusMessage: response.Success,
+		Body:          summary,
+	}
+	e.UpdateTask(ctx, fillTaskData(taskID, targetURI, pluginContactRequest.TaskRequest, resp, common.Completed, common.OK, 100, http.MethodPost))
+	return resp
+}
+
+// dryRunCompute validates the credentials of the server through the plugin and checks none of its
+// systems are added already, it is the read only counterpart of the start of addCompute.
+// It returns the URIs of the systems of the server along with the tree of its resources when
+// PreviewResources is requested, or the error response when a check fails.
+func (e *ExternalInterface) dryRunCompute(ctx context.Context, pluginID string, req AddResourceRequest, pluginContactRequest getResourceRequest, taskInfo *common.TaskUpdateInfo) ([]string, *discoveryPreview, response.RPC) {
+	plugin, errs := agmodel.GetPluginData(pluginID)
+	if errs != nil {
+		errMsg := "error while getting plugin data: " + errs.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return nil, nil, common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"plugin", pluginID}, taskInfo)
+	}
+	pluginContactRequest.Plugin = plugin
+	pluginContactRequest.StatusPoll = true
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
+		token, getResponse, err := getPluginSessionToken(ctx, pluginContactRequest)
+		if err != nil {
+			errMsg := err.Error()
+			l.LogWithFields(ctx).Error(errMsg)
+			return nil, nil, common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo)
+		}
+		pluginContactRequest.Token = token
+		// the sessions kept for the add are deleted with deletePluginSessions once the add is done
+		if pluginContactRequest.Sessions == nil && !config.Data.DiscoveryConf.SkipPluginSessionTeardown {
+			defer deletePluginSession(ctx, pluginContactRequest)
+		}
+	} else {
+		pluginContactRequest.LoginCredentials = map[string]string{
+			"UserName": plugin.Username,
+			"Password": string(plugin.Password),
+		}
+	}
+
+	managerAddress := strings.ToLower(req.ManagerAddress)
+	pluginContactRequest.DeviceInfo = agmodel.SaveSystem{
+		ManagerAddress: managerAddress,
+		UserName:       req.UserName,
+		Password:       []byte(req.Password),
+		PluginID:       pluginID,
+	}
+	pluginContactRequest.OID = "/ODIM/v1/validate"
+	pluginContactRequest.HTTPMethodType = http.MethodPost
+	if _, _, getResponse, err := contactPlugin(ctx, pluginContactRequest, "error while trying to authenticate the compute server: "); err != nil {
+		errMsg := err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return nil, nil, common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, errMsg, getResponse.MsgArgs, taskInfo)
+	}
+
+	pluginContactRequest.DeviceInfo = map[string]interface{}{
+		"ManagerAddress": managerAddress,
+		"UserName":       req.UserName,
+		"Password":       []byte(req.Password),
+	}
+	pluginContactRequest.OID = "/redfish/v1/Systems"
+	pluginContactRequest.HTTPMethodType = http.MethodGet
+	pluginContactRequest.BMCAddress = managerAddress
+	pluginContactRequest.DryRun = true
+	pluginContactRequest.InFlightCalls = newDiscoveryCallLimiter(config.Data.DiscoveryConf.MaxInFlightPluginCalls)
+
+	var h respHolder
+	h.TraversedLinks = make(map[string]bool)
+	h.InventoryData = make(map[string]interface{})
+	h.cancelRequestKey = getCancelRequestKey(getKeyFromManagerAddress(req.ManagerAddress))
+	h.checkActiveRequest = e.CheckActiveRequest
+	if _, _, _, err := h.getAllSystemInfo(ctx, "", 0, 0, pluginContactRequest); err != nil {
+		errMsg := "error while trying to add compute: " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		if msgArg, fatal := getSystemErrorArgs(h.StatusMessage, req.ManagerAddress, pluginID); fatal {
+			return nil, nil, common.GeneralError(h.StatusCode, h.StatusMessage, errMsg, msgArg, taskInfo)
+		}
+	}
+	if !req.PreviewResources {
+		return h.SystemURL, nil, response.RPC{}
+	}
+	return h.SystemURL, previewDiscovery(ctx, &h, pluginContactRequest), response.RPC{}
+}
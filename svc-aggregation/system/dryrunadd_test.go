@@ -0,0 +1,160 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	aggregatorproto "github.com/ODIM-Project/ODIM/lib-utilities/proto/aggregator"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalInterface_AddAggregationSourceDryRun(t *testing.T) {
+	common.MuxLock.Lock()
+	config.SetUpMockConfig(t)
+	common.MuxLock.Unlock()
+	config.Data.AddComputeSkipResources = &config.AddComputeSkipResources{
+		SkipResourceListUnderSystem: []string{"Chassis", "LogServices"},
+	}
+	ctx := mockContext()
+	defer func() {
+		common.TruncateDB(common.OnDisk)
+		common.TruncateDB(common.InMemory)
+	}()
+	mockPluginData(t, "GRF_v2.0.0")
+
+	var dbWrites []string
+	p := getMockExternalInterface()
+	p.GenericSave = func(data []byte, table, key string) error {
+		dbWrites = append(dbWrites, table+":"+key)
+		return nil
+	}
+	p.UpdateConnectionMethod = func(connectionMethod agmodel.ConnectionMethod, cmURI string) *errors.Error {
+		dbWrites = append(dbWrites, "ConnectionMethod:"+cmURI)
+		return nil
+	}
+	dryRunRequest := func(hostName, password string, previewResources bool) *aggregatorproto.AggregatorRequest {
+		body, _ := json.Marshal(AggregationSource{
+			HostName: hostName,
+			UserName: "admin",
+			Password: password,
+			Links: &Links{
+				ConnectionMethod: &ConnectionMethod{
+					OdataID: "/redfish/v1/AggregationService/ConnectionMethods/7ff3bd97-c41c-5de0-937d-85d390691b73",
+				},
+			},
+			Oem: &AggregationSourceOem{DryRun: true, PreviewResources: previewResources},
+		})
+		return &aggregatorproto.AggregatorRequest{SessionToken: "validToken", RequestBody: body}
+	}
+	assertNothingSaved := func(t *testing.T, hostName string) {
+		assert.Empty(t, dbWrites, "dry run should not save anything")
+		systems, _ := agmodel.GetAllMatchingDetails("ComputerSystem", "", common.InMemory)
+		assert.Empty(t, systems, "systems should not be saved in dry run")
+		sources, _ := agmodel.GetAllKeysFromTable("AggregationSource")
+		assert.Empty(t, sources, "aggregation source should not be saved in dry run")
+		indexList, _ := agmodel.GetString("BMCAddress", hostName)
+		assert.Empty(t, indexList, "server should not be indexed in dry run")
+	}
+
+	t.Run("server would be added", func(t *testing.T) {
+		dbWrites = nil
+		resp := p.AddAggregationSource(ctx, "123", "validUserName", dryRunRequest("100.0.0.1", "password", false))
+		assert.Equal(t, int32(http.StatusOK), resp.StatusCode)
+		summary, ok := resp.Body.(dryRunSummary)
+		if assert.True(t, ok, "dry run should respond with the summary") {
+			assert.Equal(t, dryRunSourceBMC, summary.SourceType)
+			assert.Equal(t, "GRF_v2.0.0", summary.PluginID)
+			assert.Len(t, summary.Systems, 1)
+			assert.Nil(t, summary.ResourceTree, "resource tree should be returned only when requested")
+		}
+		assertNothingSaved(t, "100.0.0.1")
+	})
+	t.Run("server would be added with the resource tree", func(t *testing.T) {
+		dbWrites = nil
+		resp := p.AddAggregationSource(ctx, "123", "validUserName", dryRunRequest("100.0.0.1", "password", true))
+		assert.Equal(t, int32(http.StatusOK), resp.StatusCode)
+		summary, ok := resp.Body.(dryRunSummary)
+		if assert.True(t, ok, "dry run should respond with the summary") && assert.NotNil(t, summary.ResourceTree) {
+			assert.Len(t, summary.ResourceTree.Roots, len(getPreviewRoots()), "all the root collections should be previewed")
+			assert.Equal(t, "/redfish/v1/Systems", summary.ResourceTree.Roots[0].OID)
+			assert.NotNil(t, summary.ResourceTree.ResourceCounts, "resources should be counted in the preview")
+		}
+		assertNothingSaved(t, "100.0.0.1")
+	})
+	t.Run("incorrect server credentials", func(t *testing.T) {
+		dbWrites = nil
+		resp := p.AddAggregationSource(ctx, "123", "validUserName", dryRunRequest("100.0.0.12", "incorrectPassword", false))
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.StatusCode)
+		assertNothingSaved(t, "100.0.0.12")
+	})
+}
+
+func TestExternalInterface_dryRunComputeSessionTeardown(t *testing.T) {
+	common.MuxLock.Lock()
+	config.SetUpMockConfig(t)
+	common.MuxLock.Unlock()
+	defer func() {
+		common.TruncateDB(common.OnDisk)
+		common.TruncateDB(common.InMemory)
+	}()
+	mockPluginData(t, "XAuthPlugin")
+
+	var deletedTokens []string
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		switch {
+		case method == http.MethodPost && strings.HasSuffix(url, "/ODIM/v1/Sessions"):
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"X-Auth-Token": []string{"plugintoken"}},
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+			}, nil
+		case method == http.MethodDelete && strings.HasSuffix(url, "/ODIM/v1/Sessions"):
+			deletedTokens = append(deletedTokens, token)
+			return stubResponse(http.StatusNoContent, "")
+		}
+		// the server credentials are rejected, so the dry run returns right after the validation
+		return stubResponse(http.StatusUnauthorized, `{"error":"unauthorized"}`)
+	}
+	p := getMockExternalInterface()
+	req := AddResourceRequest{ManagerAddress: "100.0.0.1", UserName: "admin", Password: "password", DryRun: true}
+
+	_, _, resp := p.dryRunCompute(mockContext(), "XAuthPlugin", req, getResourceRequest{ContactClient: contactClient}, nil)
+	assert.Equal(t, int32(http.StatusUnauthorized), resp.StatusCode)
+	assert.Equal(t, []string{"plugintoken"}, deletedTokens, "session created for the dry run should be deleted")
+
+	// the sessions kept for the add are left to deletePluginSessions
+	deletedTokens = nil
+	pluginContactRequest := getResourceRequest{ContactClient: contactClient, Sessions: newPluginToken()}
+	p.dryRunCompute(mockContext(), "XAuthPlugin", req, pluginContactRequest, nil)
+	assert.Empty(t, deletedTokens, "session kept for the add should be deleted only once the add is done")
+	deletePluginSessions(mockContext(), pluginContactRequest)
+	assert.Equal(t, []string{"plugintoken"}, deletedTokens)
+
+	deletedTokens = nil
+	config.Data.DiscoveryConf.SkipPluginSessionTeardown = true
+	p.dryRunCompute(mockContext(), "XAuthPlugin", req, getResourceRequest{ContactClient: contactClient}, nil)
+	assert.Empty(t, deletedTokens, "session should be kept when the teardown is skipped")
+}
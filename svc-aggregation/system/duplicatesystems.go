@@ -0,0 +1,132 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+var (
+	// GetUUIDIndexEntriesFunc function pointer for the agmodel.GetUUIDIndexEntries
+	GetUUIDIndexEntriesFunc = agmodel.GetUUIDIndexEntries
+	// GetTargetFunc function pointer for the agmodel.GetTarget
+	GetTargetFunc = agmodel.GetTarget
+)
+
+// DuplicateSystems holds the systems which are indexed with the same UUID.
+// Preferred is the most recently discovered system, the rest are the duplicates
+type DuplicateSystems struct {
+	UUID       string
+	Preferred  string
+	Duplicates []string
+}
+
+// indexedSystem is a system in the UUID index along with the details of its aggregation source
+type indexedSystem struct {
+	key          string
+	managed      bool
+	discoveredAt string
+}
+
+// FindDuplicateSystems scans the UUID index for the systems which are added more than once.
+// It is a diagnostic for the inconsistencies left behind by the re-adds, the systems are not modified.
+func FindDuplicateSystems(ctx context.Context) ([]DuplicateSystems, error) {
+	entries, err := GetUUIDIndexEntriesFunc()
+	if err != nil {
+		return nil, err
+	}
+	var duplicates []DuplicateSystems
+	for systemUUID, keys := range entries {
+		if len(keys) < 2 {
+			continue
+		}
+		systems := make([]indexedSystem, 0, len(keys))
+		for _, key := range keys {
+			systems = append(systems, getIndexedSystem(key))
+		}
+		sortByDiscovery(systems)
+		duplicate := DuplicateSystems{
+			UUID:      systemUUID,
+			Preferred: systems[0].key,
+		}
+		for _, system := range systems[1:] {
+			duplicate.Duplicates = append(duplicate.Duplicates, system.key)
+		}
+		l.LogWithFields(ctx).Warn(fmt.Sprintf("system with UUID %s is added more than once, %s is retained over %s",
+			systemUUID, duplicate.Preferred, strings.Join(duplicate.Duplicates, ", ")))
+		duplicates = append(duplicates, duplicate)
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].UUID < duplicates[j].UUID
+	})
+	return duplicates, nil
+}
+
+// RepairDuplicateSystems removes the inventory of the duplicate systems found by FindDuplicateSystems,
+// so that only the most recently discovered system remains for each UUID. The aggregation sources
+// of the removed systems are left as they are and have to be deleted by the operator.
+func (e *ExternalInterface) RepairDuplicateSystems(ctx context.Context) ([]DuplicateSystems, error) {
+	duplicates, err := FindDuplicateSystems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, duplicate := range duplicates {
+		for _, key := range duplicate.Duplicates {
+			if derr := e.DeleteComputeSystem(strings.LastIndexAny(key, "/"), key); derr != nil {
+				l.LogWithFields(ctx).Error("error while trying to delete the duplicate system " + key + ": " + derr.Error())
+				continue
+			}
+			l.LogWithFields(ctx).Info("deleted the duplicate system " + key + " of " + duplicate.Preferred)
+		}
+	}
+	return duplicates, nil
+}
+
+// getIndexedSystem reads the aggregation source of the system, which is the device UUID in the system key
+func getIndexedSystem(key string) indexedSystem {
+	system := indexedSystem{key: key}
+	deviceUUID, _, err := getIDsFromURI(key)
+	if err != nil {
+		return system
+	}
+	target, terr := GetTargetFunc(deviceUUID)
+	if terr != nil {
+		return system
+	}
+	system.managed = true
+	system.discoveredAt = target.DiscoveredAt
+	return system
+}
+
+// sortByDiscovery orders the systems with the most recently discovered one first. The systems
+// without an aggregation source or discovered before the time was recorded are ordered last.
+func sortByDiscovery(systems []indexedSystem) {
+	sort.SliceStable(systems, func(i, j int) bool {
+		if systems[i].managed != systems[j].managed {
+			return systems[i].managed
+		}
+		// RFC3339 timestamps in UTC are ordered as strings
+		if systems[i].discoveredAt != systems[j].discoveredAt {
+			return systems[i].discoveredAt > systems[j].discoveredAt
+		}
+		return systems[i].key < systems[j].key
+	})
+}
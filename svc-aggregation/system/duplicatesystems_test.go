@@ -0,0 +1,94 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDuplicateSystems mocks the UUID index and the aggregation sources, the returned function restores them
+func mockDuplicateSystems(entries map[string][]string, targets map[string]*agmodel.Target) func() {
+	getEntries, getTarget := GetUUIDIndexEntriesFunc, GetTargetFunc
+	GetUUIDIndexEntriesFunc = func() (map[string][]string, error) {
+		return entries, nil
+	}
+	GetTargetFunc = func(deviceUUID string) (*agmodel.Target, error) {
+		if target, ok := targets[deviceUUID]; ok {
+			return target, nil
+		}
+		return nil, fmt.Errorf("no data with the with key %s found", deviceUUID)
+	}
+	return func() {
+		GetUUIDIndexEntriesFunc, GetTargetFunc = getEntries, getTarget
+	}
+}
+
+func TestFindDuplicateSystems(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer mockDuplicateSystems(map[string][]string{
+		"uuid-1": {"/redfish/v1/Systems/old-device.1", "/redfish/v1/Systems/new-device.1", "/redfish/v1/Systems/removed-device.1"},
+		"uuid-2": {"/redfish/v1/Systems/other-device.1"},
+		"uuid-3": {"/redfish/v1/Systems/legacy-device.1", "/redfish/v1/Systems/readded-device.1"},
+	}, map[string]*agmodel.Target{
+		"old-device":     {DeviceUUID: "old-device", DiscoveredAt: "2026-01-01T10:00:00Z"},
+		"new-device":     {DeviceUUID: "new-device", DiscoveredAt: "2026-03-01T10:00:00Z"},
+		"other-device":   {DeviceUUID: "other-device", DiscoveredAt: "2026-01-01T10:00:00Z"},
+		"legacy-device":  {DeviceUUID: "legacy-device"},
+		"readded-device": {DeviceUUID: "readded-device", DiscoveredAt: "2026-02-01T10:00:00Z"},
+	})()
+
+	duplicates, err := FindDuplicateSystems(mockContext())
+	assert.Nil(t, err)
+	assert.Equal(t, []DuplicateSystems{
+		{
+			UUID:       "uuid-1",
+			Preferred:  "/redfish/v1/Systems/new-device.1",
+			Duplicates: []string{"/redfish/v1/Systems/old-device.1", "/redfish/v1/Systems/removed-device.1"},
+		},
+		{
+			UUID:       "uuid-3",
+			Preferred:  "/redfish/v1/Systems/readded-device.1",
+			Duplicates: []string{"/redfish/v1/Systems/legacy-device.1"},
+		},
+	}, duplicates, "systems sharing a UUID should be reported with the most recently discovered one preferred")
+}
+
+func TestRepairDuplicateSystems(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer mockDuplicateSystems(map[string][]string{
+		"uuid-1": {"/redfish/v1/Systems/old-device.1", "/redfish/v1/Systems/new-device.1"},
+	}, map[string]*agmodel.Target{
+		"old-device": {DeviceUUID: "old-device", DiscoveredAt: "2026-01-01T10:00:00Z"},
+		"new-device": {DeviceUUID: "new-device", DiscoveredAt: "2026-03-01T10:00:00Z"},
+	})()
+	var deleted []string
+	e := &ExternalInterface{
+		DeleteComputeSystem: func(index int, key string) *errors.Error {
+			deleted = append(deleted, key)
+			return nil
+		},
+	}
+
+	duplicates, err := e.RepairDuplicateSystems(mockContext())
+	assert.Nil(t, err)
+	assert.Len(t, duplicates, 1)
+	assert.Equal(t, []string{"/redfish/v1/Systems/old-device.1"}, deleted, "only the duplicate should be deleted")
+}
@@ -0,0 +1,93 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agcommon"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+var (
+	// GetActiveRequestsFunc function pointer for the agmodel.GetActiveRequests
+	GetActiveRequestsFunc = agmodel.GetActiveRequests
+	// PingDBFunc function pointer for the agmodel.PingDB
+	PingDBFunc = agmodel.PingDB
+	// GetAllPluginStatusRecordsFunc function pointer for the agcommon.GetAllPluginStatusRecords
+	GetAllPluginStatusRecordsFunc = agcommon.GetAllPluginStatusRecords
+)
+
+// healthSummary is the health of the discovery subsystem of the aggregation service
+type healthSummary struct {
+	Health           string                  `json:"Health"`
+	ActiveAdds       []string                `json:"ActiveAdds"`
+	PluginTypeLimits map[string]limiterState `json:"PluginTypeLimits"`
+	Plugins          map[string]pluginHealth `json:"Plugins"`
+	DB               map[string]string       `json:"DB"`
+}
+
+// pluginHealth is the state of a plugin as per the periodic status check, the plugin
+// is Inactive when it failed the InactiveCount number of the recent status checks
+type pluginHealth struct {
+	State         string `json:"State"`
+	InactiveCount int    `json:"InactiveCount"`
+}
+
+// GetHealthSummary assembles the health summary of the discovery subsystem from the active
+// add requests, the limiters of the plugin types, the status of the plugins and the DBs.
+// Health is Critical when a DB is unreachable and Warning when a plugin is inactive.
+func GetHealthSummary(ctx context.Context) response.RPC {
+	summary := healthSummary{
+		Health:           common.OK,
+		ActiveAdds:       []string{},
+		PluginTypeLimits: addLimiter.state(),
+		Plugins:          make(map[string]pluginHealth),
+		DB:               make(map[string]string),
+	}
+	activeAdds, err := GetActiveRequestsFunc()
+	if err != nil {
+		l.LogWithFields(ctx).Warn("failed to get the active add requests: " + err.Error())
+	} else {
+		summary.ActiveAdds = append(summary.ActiveAdds, activeAdds...)
+		sort.Strings(summary.ActiveAdds)
+	}
+	for pluginID, inactiveCount := range GetAllPluginStatusRecordsFunc() {
+		plugin := pluginHealth{State: "Active", InactiveCount: inactiveCount}
+		if inactiveCount > 0 {
+			plugin.State = "Inactive"
+			summary.Health = common.Warning
+		}
+		summary.Plugins[pluginID] = plugin
+	}
+	for name, dbType := range map[string]common.DbType{"InMemory": common.InMemory, "OnDisk": common.OnDisk} {
+		summary.DB[name] = common.OK
+		if err := PingDBFunc(dbType); err != nil {
+			l.LogWithFields(ctx).Error("failed to reach " + name + " DB: " + err.Error())
+			summary.DB[name] = common.Critical
+			summary.Health = common.Critical
+		}
+	}
+	return response.RPC{
+		StatusCode:    http.StatusOK,
+		StatusMessage: response.Success,
+		Body:          summary,
+	}
+}
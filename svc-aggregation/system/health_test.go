@@ -0,0 +1,83 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHealthSummary(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.MaxConcurrentAddsPerPluginType = map[string]int{"Compute": 2}
+	defer func(orig *pluginTypeLimiter) { addLimiter = orig }(addLimiter)
+	addLimiter = &pluginTypeLimiter{semaphores: make(map[string]chan struct{})}
+	defer func(orig func() ([]string, *errors.Error)) { GetActiveRequestsFunc = orig }(GetActiveRequestsFunc)
+	GetActiveRequestsFunc = func() ([]string, *errors.Error) {
+		return []string{"10.24.0.2", "10.24.0.1"}, nil
+	}
+	defer func(orig func() map[string]int) { GetAllPluginStatusRecordsFunc = orig }(GetAllPluginStatusRecordsFunc)
+	GetAllPluginStatusRecordsFunc = func() map[string]int {
+		return map[string]int{"GRF": 0, "ILO": 2}
+	}
+	defer func(orig func(common.DbType) error) { PingDBFunc = orig }(PingDBFunc)
+	PingDBFunc = func(dbType common.DbType) error { return nil }
+
+	// one add is in progress and the other is waiting for it
+	release, err := addLimiter.acquire(context.Background(), "Compute")
+	assert.Nil(t, err)
+	release2, err := addLimiter.acquire(context.Background(), "Compute")
+	assert.Nil(t, err)
+	queued := make(chan struct{})
+	go func() {
+		if release3, err := addLimiter.acquire(context.Background(), "Compute"); err == nil {
+			release3()
+		}
+		close(queued)
+	}()
+	assert.Eventually(t, func() bool { return addLimiter.state()["Compute"].Queued == 1 }, time.Second, 10*time.Millisecond)
+
+	resp := GetHealthSummary(mockContext())
+	assert.Equal(t, int32(http.StatusOK), resp.StatusCode)
+	summary := resp.Body.(healthSummary)
+	assert.Equal(t, common.Warning, summary.Health, "health should be Warning when a plugin is inactive")
+	assert.Equal(t, []string{"10.24.0.1", "10.24.0.2"}, summary.ActiveAdds)
+	assert.Equal(t, limiterState{Limit: 2, InProgress: 2, Queued: 1}, summary.PluginTypeLimits["Compute"])
+	assert.Equal(t, pluginHealth{State: "Active"}, summary.Plugins["GRF"])
+	assert.Equal(t, pluginHealth{State: "Inactive", InactiveCount: 2}, summary.Plugins["ILO"])
+	assert.Equal(t, map[string]string{"InMemory": common.OK, "OnDisk": common.OK}, summary.DB)
+	release()
+	release2()
+	<-queued
+
+	PingDBFunc = func(dbType common.DbType) error {
+		if dbType == common.OnDisk {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	}
+	summary = GetHealthSummary(mockContext()).Body.(healthSummary)
+	assert.Equal(t, common.Critical, summary.Health, "health should be Critical when a DB is unreachable")
+	assert.Equal(t, common.Critical, summary.DB["OnDisk"])
+	assert.Equal(t, limiterState{Limit: 2}, summary.PluginTypeLimits["Compute"])
+}
@@ -0,0 +1,98 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+)
+
+// inFlightTaskTracker holds the last update of the tasks which are not yet in a terminal
+// state, so that they can be marked as interrupted when the service is stopped
+type inFlightTaskTracker struct {
+	lock        sync.Mutex
+	tasks       map[string]common.TaskData
+	interrupted map[string]bool
+}
+
+var inFlightTasks = &inFlightTaskTracker{
+	tasks:       make(map[string]common.TaskData),
+	interrupted: make(map[string]bool),
+}
+
+// isTerminalTaskState returns true when no further updates are expected for the task in the state
+func isTerminalTaskState(taskState string) bool {
+	switch taskState {
+	case common.Completed, common.Exception, common.Cancelled, common.Killed, common.Interrupted:
+		return true
+	}
+	return false
+}
+
+// track records the update of the task and returns it with its percentage raised to the
+// percentage of the last update, as the progress reported by the parallel workers of the
+// discovery can be out of order. It returns false when the task is already marked as
+// interrupted and the update would move it back to a non terminal state.
+func (t *inFlightTaskTracker) track(taskData common.TaskData) (common.TaskData, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if last, ok := t.tasks[taskData.TaskID]; ok && last.PercentComplete > taskData.PercentComplete {
+		taskData.PercentComplete = last.PercentComplete
+	}
+	if isTerminalTaskState(taskData.TaskState) {
+		delete(t.tasks, taskData.TaskID)
+		if taskData.TaskState != common.Interrupted {
+			delete(t.interrupted, taskData.TaskID)
+		}
+		return taskData, true
+	}
+	if t.interrupted[taskData.TaskID] {
+		return taskData, false
+	}
+	t.tasks[taskData.TaskID] = taskData
+	return taskData, true
+}
+
+// drain returns the tasks in flight and marks them as interrupted
+func (t *inFlightTaskTracker) drain() []common.TaskData {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tasks := make([]common.TaskData, 0, len(t.tasks))
+	for taskID, taskData := range t.tasks {
+		tasks = append(tasks, taskData)
+		t.interrupted[taskID] = true
+		delete(t.tasks, taskID)
+	}
+	return tasks
+}
+
+// InterruptInFlightTasks is called while the service is stopped. It updates the tasks which are
+// still in flight with the Interrupted state at the percentage of their last update, so that they
+// are not left running forever. The updates of the interrupted tasks to a non terminal state are
+// ignored from then on.
+func (e *ExternalInterface) InterruptInFlightTasks(ctx context.Context) {
+	for _, taskData := range inFlightTasks.drain() {
+		taskData.TaskState = common.Interrupted
+		taskData.TaskStatus = common.Warning
+		if err := e.UpdateTask(ctx, taskData); err != nil {
+			l.LogWithFields(ctx).Error("error while marking the task " + taskData.TaskID + " as interrupted: " + err.Error())
+			continue
+		}
+		l.LogWithFields(ctx).Warn("task " + taskData.TaskID + " is marked as interrupted at " + taskData.TargetURI)
+	}
+}
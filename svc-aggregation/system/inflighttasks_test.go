@@ -0,0 +1,88 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterruptInFlightTasks(t *testing.T) {
+	defer func(orig *inFlightTaskTracker) { inFlightTasks = orig }(inFlightTasks)
+	inFlightTasks = &inFlightTaskTracker{
+		tasks:       make(map[string]common.TaskData),
+		interrupted: make(map[string]bool),
+	}
+	var lock sync.Mutex
+	updates := make(map[string]common.TaskData)
+	e := &ExternalInterface{
+		UpdateTask: func(ctx context.Context, taskData common.TaskData) error {
+			taskData, ok := inFlightTasks.track(taskData)
+			if !ok {
+				return nil
+			}
+			lock.Lock()
+			updates[taskData.TaskID] = taskData
+			lock.Unlock()
+			return nil
+		},
+	}
+	e.UpdateTask(mockContext(), common.TaskData{TaskID: "task1", TargetURI: "/redfish/v1/AggregationService/AggregationSources", TaskState: common.Running, TaskStatus: common.OK, PercentComplete: 40})
+	e.UpdateTask(mockContext(), common.TaskData{TaskID: "task1", TargetURI: "/redfish/v1/AggregationService/AggregationSources", TaskState: common.Running, TaskStatus: common.OK, PercentComplete: 60})
+	e.UpdateTask(mockContext(), common.TaskData{TaskID: "task2", TaskState: common.Running, TaskStatus: common.OK, PercentComplete: 50})
+	e.UpdateTask(mockContext(), common.TaskData{TaskID: "task2", TaskState: common.Completed, TaskStatus: common.OK, PercentComplete: 100})
+
+	e.InterruptInFlightTasks(mockContext())
+
+	assert.Equal(t, common.Interrupted, updates["task1"].TaskState, "task in flight should be marked as interrupted")
+	assert.Equal(t, common.Warning, updates["task1"].TaskStatus)
+	assert.Equal(t, int32(60), updates["task1"].PercentComplete, "task should be interrupted at the percentage of its last update")
+	assert.Equal(t, common.Completed, updates["task2"].TaskState, "completed task should not be interrupted")
+
+	// the discovery still running shouldn't move the interrupted task back to running
+	e.UpdateTask(mockContext(), common.TaskData{TaskID: "task1", TaskState: common.Running, TaskStatus: common.OK, PercentComplete: 70})
+	assert.Equal(t, common.Interrupted, updates["task1"].TaskState, "update of the interrupted task should be ignored")
+	assert.Empty(t, inFlightTasks.drain(), "no task should be in flight after the shutdown")
+}
+
+func TestInFlightTasksProgressIsMonotonic(t *testing.T) {
+	defer func(orig *inFlightTaskTracker) { inFlightTasks = orig }(inFlightTasks)
+	inFlightTasks = &inFlightTaskTracker{
+		tasks:       make(map[string]common.TaskData),
+		interrupted: make(map[string]bool),
+	}
+	taskData, ok := inFlightTasks.track(common.TaskData{TaskID: "task1", TaskState: common.Running, PercentComplete: 60})
+	assert.True(t, ok)
+	assert.Equal(t, int32(60), taskData.PercentComplete)
+
+	// a worker reporting the progress it started from shouldn't move the task backwards
+	taskData, ok = inFlightTasks.track(common.TaskData{TaskID: "task1", TaskState: common.Running, PercentComplete: 45})
+	assert.True(t, ok)
+	assert.Equal(t, int32(60), taskData.PercentComplete, "progress should not move backwards")
+
+	taskData, _ = inFlightTasks.track(common.TaskData{TaskID: "task1", TaskState: common.Running, PercentComplete: 75})
+	assert.Equal(t, int32(75), taskData.PercentComplete, "progress should move forwards")
+
+	taskData, _ = inFlightTasks.track(common.TaskData{TaskID: "task1", TaskState: common.Exception, PercentComplete: 0})
+	assert.Equal(t, int32(75), taskData.PercentComplete, "final update should keep the progress made")
+
+	// the progress of a new task with the same ID starts afresh
+	taskData, _ = inFlightTasks.track(common.TaskData{TaskID: "task1", TaskState: common.Running, PercentComplete: 10})
+	assert.Equal(t, int32(10), taskData.PercentComplete)
+}
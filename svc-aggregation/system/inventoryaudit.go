@@ -0,0 +1,86 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+)
+
+// actions of the inventory audit events
+const (
+	inventoryAuditAdd        = "Add"
+	inventoryAuditRemove     = "Remove"
+	inventoryAuditRediscover = "Rediscover"
+)
+
+// inventoryAuditSystemUser is the user of the inventory changes done by ODIM by itself,
+// like the rediscovery on the restart of the server
+const inventoryAuditSystemUser = "ODIMRA"
+
+// InventoryAuditEvent is the audit record of a change in the inventory
+type InventoryAuditEvent struct {
+	Action           string
+	User             string
+	Resource         string
+	ManagerAddress   string
+	ConnectionMethod string
+	Result           string
+	StatusCode       int32
+	Timestamp        string
+}
+
+// InventoryAuditSink is the function pointer to which the audit events of the add, remove and
+// rediscover of the inventory are sent. The events are logged by default, it can be replaced
+// to send the events to an external SIEM
+var InventoryAuditSink = logInventoryAuditEvent
+
+// logInventoryAuditEvent logs the audit event as a JSON record
+func logInventoryAuditEvent(ctx context.Context, event InventoryAuditEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		l.LogWithFields(ctx).Error("error while trying to marshal the inventory audit event: " + err.Error())
+		return
+	}
+	l.LogWithFields(ctx).Info("inventory audit event: " + string(data))
+}
+
+// auditInventoryChange sends the audit event of the inventory change with the response of the action
+// to the InventoryAuditSink. The user is taken from the context, when the change is requested by a user
+func auditInventoryChange(ctx context.Context, action, resource, managerAddress, connectionMethod string, resp response.RPC) {
+	user, _ := ctx.Value(common.SessionUserID).(string)
+	if user == "" {
+		user = inventoryAuditSystemUser
+	}
+	result := "Failure"
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		result = "Success"
+	}
+	InventoryAuditSink(ctx, InventoryAuditEvent{
+		Action:           action,
+		User:             user,
+		Resource:         resource,
+		ManagerAddress:   managerAddress,
+		ConnectionMethod: connectionMethod,
+		Result:           result,
+		StatusCode:       resp.StatusCode,
+		Timestamp:        time.Now().UTC().Format(time.RFC3339),
+	})
+}
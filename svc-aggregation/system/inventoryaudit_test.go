@@ -0,0 +1,103 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	aggregatorproto "github.com/ODIM-Project/ODIM/lib-utilities/proto/aggregator"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalInterface_AddAggregationSourceAuditEvent(t *testing.T) {
+	common.MuxLock.Lock()
+	config.SetUpMockConfig(t)
+	common.MuxLock.Unlock()
+	config.Data.AddComputeSkipResources = &config.AddComputeSkipResources{
+		SkipResourceListUnderSystem: []string{"Chassis", "LogServices"},
+	}
+	defer func() {
+		if err := common.TruncateDB(common.OnDisk); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		if err := common.TruncateDB(common.InMemory); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}()
+	mockPluginData(t, "GRF_v2.0.0")
+	mockManagersData("/redfish/v1/Managers/1234877451-1234", map[string]interface{}{
+		"Name": "GRF_v2.0.0",
+		"UUID": "1234877451-1234",
+	})
+
+	var events []InventoryAuditEvent
+	defer func() {
+		InventoryAuditSink = logInventoryAuditEvent
+	}()
+	InventoryAuditSink = func(ctx context.Context, event InventoryAuditEvent) {
+		events = append(events, event)
+	}
+
+	connectionMethodURI := "/redfish/v1/AggregationService/ConnectionMethods/7ff3bd97-c41c-5de0-937d-85d390691b73"
+	reqBody, _ := json.Marshal(AggregationSource{
+		HostName: "100.0.0.1",
+		UserName: "admin",
+		Password: "password",
+		Links: &Links{
+			ConnectionMethod: &ConnectionMethod{
+				OdataID: connectionMethodURI,
+			},
+		},
+	})
+	p := getMockExternalInterface()
+	resp := p.AddAggregationSource(mockContext(), "123", "admin", &aggregatorproto.AggregatorRequest{
+		SessionToken: "validToken",
+		RequestBody:  reqBody,
+	})
+	assert.Equal(t, int32(http.StatusCreated), resp.StatusCode)
+	if assert.Len(t, events, 1, "an audit event should be emitted for the add") {
+		event := events[0]
+		assert.Equal(t, inventoryAuditAdd, event.Action)
+		assert.Equal(t, "admin", event.User)
+		assert.Equal(t, resp.Header["Location"], event.Resource)
+		assert.Equal(t, "100.0.0.1", event.ManagerAddress)
+		assert.Equal(t, connectionMethodURI, event.ConnectionMethod)
+		assert.Equal(t, "Success", event.Result)
+		assert.NotEmpty(t, event.Timestamp)
+	}
+}
+
+func Test_auditInventoryChange(t *testing.T) {
+	var events []InventoryAuditEvent
+	defer func() {
+		InventoryAuditSink = logInventoryAuditEvent
+	}()
+	InventoryAuditSink = func(ctx context.Context, event InventoryAuditEvent) {
+		events = append(events, event)
+	}
+	auditInventoryChange(mockContext(), inventoryAuditRediscover, "/redfish/v1/Systems/someuuid.1", "10.0.0.1", "",
+		response.RPC{StatusCode: http.StatusNotFound})
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, inventoryAuditSystemUser, events[0].User, "the changes done by ODIM should be audited as done by ODIMRA")
+		assert.Equal(t, "Failure", events[0].Result)
+		assert.Equal(t, int32(http.StatusNotFound), events[0].StatusCode)
+	}
+}
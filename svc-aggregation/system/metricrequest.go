@@ -0,0 +1,79 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agcommon"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/google/uuid"
+)
+
+const (
+	PruneMetricRequestsActionID = "218"
+
+	PruneMetricRequestsActionName = "PruneActiveMetricRequests"
+)
+
+var (
+	// GetActiveMetricRequestsFunc function pointer for the agmodel.GetActiveMetricRequests
+	GetActiveMetricRequestsFunc = agmodel.GetActiveMetricRequests
+	// DeleteMetricRequestFunc function pointer for the agmodel.DeleteMetricRequest
+	DeleteMetricRequestFunc = agmodel.DeleteMetricRequest
+)
+
+// PruneActiveMetricRequests deletes the ActiveMetricRequest entries which are older than the
+// configured age at the start up and then over the same interval. The entries are left behind
+// when the service stops while discovering the telemetry resources, and they block the discovery
+// of those resources till they are deleted.
+func PruneActiveMetricRequests() {
+	transactionID := uuid.New()
+	ctx := agcommon.CreateContext(transactionID.String(), PruneMetricRequestsActionID, PruneMetricRequestsActionName, "1", common.AggregationService, podName)
+	l.LogWithFields(ctx).Info("active metric requests pruning routine started")
+	for {
+		maxAge := time.Duration(config.Data.DiscoveryConf.ActiveMetricRequestMaxAgeInSecs) * time.Second
+		pruneStaleMetricRequests(ctx, maxAge)
+		time.Sleep(maxAge)
+	}
+}
+
+// pruneStaleMetricRequests deletes the active metric requests created before the max age,
+// the requests saved without the created time are also deleted. It returns the number of
+// requests deleted.
+func pruneStaleMetricRequests(ctx context.Context, maxAge time.Duration) int {
+	requests, err := GetActiveMetricRequestsFunc()
+	if err != nil {
+		l.LogWithFields(ctx).Error("failed to get the active metric requests: " + err.Error())
+		return 0
+	}
+	var pruned int
+	for key, createdTime := range requests {
+		if !createdTime.IsZero() && time.Since(createdTime) < maxAge {
+			continue
+		}
+		if err := DeleteMetricRequestFunc(key); err != nil {
+			l.LogWithFields(ctx).Error("failed to delete the active metric request " + key + ": " + err.Error())
+			continue
+		}
+		pruned++
+		l.LogWithFields(ctx).Info("deleted the stale active metric request " + key)
+	}
+	return pruned
+}
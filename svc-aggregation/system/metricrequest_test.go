@@ -0,0 +1,55 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pruneStaleMetricRequests(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		err := common.TruncateDB(common.InMemory)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}()
+	staleKey := "/redfish/v1/TelemetryService/MetricDefinitions/Stale"
+	activeKey := "/redfish/v1/TelemetryService/MetricDefinitions/Active"
+	staleTime := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	if err := agmodel.GenericSave([]byte(staleTime), "ActiveMetricRequest", staleKey); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	activeTime := time.Now().UTC().Format(time.RFC3339)
+	if err := agmodel.GenericSave([]byte(activeTime), "ActiveMetricRequest", activeKey); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	pruned := pruneStaleMetricRequests(mockContext(), time.Duration(config.Data.DiscoveryConf.ActiveMetricRequestMaxAgeInSecs)*time.Second)
+	assert.Equal(t, 1, pruned, "only the stale request should be deleted")
+
+	exist, err := agmodel.CheckMetricRequest(staleKey)
+	assert.Nil(t, err, "There should be no error")
+	assert.False(t, exist, "stale request should be deleted")
+	exist, err = agmodel.CheckMetricRequest(activeKey)
+	assert.Nil(t, err, "There should be no error")
+	assert.True(t, exist, "active request should not be deleted")
+}
@@ -0,0 +1,151 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+)
+
+// passthroughCollections are the collections whose members are prefixed with the device UUID
+var passthroughCollections = []string{"Systems", "Chassis", "Managers"}
+
+// getDeviceURI validates that the northbound URI belongs to the device of the system and
+// returns the URI of the resource in the device, i.e. without the device UUID
+func getDeviceURI(uri, deviceUUID string) (string, error) {
+	uri = strings.TrimSuffix(uri, "/")
+	if strings.Contains(uri, "..") || strings.ContainsAny(uri, "?#") {
+		return "", fmt.Errorf("URI %s is not a plain resource path", uri)
+	}
+	for _, collection := range passthroughCollections {
+		prefix := "/redfish/v1/" + collection + "/" + deviceUUID + "."
+		if strings.HasPrefix(uri, prefix) {
+			return "/redfish/v1/" + collection + "/" + strings.TrimPrefix(uri, prefix), nil
+		}
+	}
+	return "", fmt.Errorf("URI %s doesn't belong to the device %s", uri, deviceUUID)
+}
+
+// GetDeviceResource fetches the live resource of the device for the northbound URI, for the
+// diagnostics of the resources which are not in the stored inventory. Only GET is supported
+// and the URI should belong to the same device as the system. The response of the device is
+// returned with the URIs translated to northbound, and it is not persisted.
+func (e *ExternalInterface) GetDeviceResource(ctx context.Context, method, systemID, uri string) response.RPC {
+	if method != http.MethodGet {
+		errMsg := "method " + method + " is not supported for the device passthrough"
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusMethodNotAllowed, response.ActionNotSupported, errMsg, []interface{}{method}, nil)
+	}
+	deviceUUID := strings.SplitN(systemID, ".", 2)[0]
+	deviceURI, err := getDeviceURI(uri, deviceUUID)
+	if err != nil {
+		l.LogWithFields(ctx).Error(err.Error())
+		return common.GeneralError(http.StatusBadRequest, response.PropertyValueFormatError, err.Error(), []interface{}{uri, "URI"}, nil)
+	}
+
+	aggregationSourceURI := "/redfish/v1/AggregationService/AggregationSources/" + systemID
+	aggregationSource, dbErr := e.GetAggregationSourceInfo(aggregationSourceURI)
+	if dbErr != nil {
+		errMsg := "unable to get aggregation source: " + dbErr.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		if errors.DBKeyNotFound == dbErr.ErrNo() {
+			return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"AggregationSource", aggregationSourceURI}, nil)
+		}
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+	links, _ := aggregationSource.Links.(map[string]interface{})
+	connectionMethodOdataID, _ := getMemberODataID(links["ConnectionMethod"])
+	connectionMethod, dbErr := e.GetConnectionMethod(connectionMethodOdataID)
+	if dbErr != nil {
+		errMsg := "unable to get connection method: " + dbErr.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+	cmVariants, err := getConnectionMethodVariants(connectionMethod.ConnectionMethodVariant)
+	if err != nil {
+		l.LogWithFields(ctx).Error(err.Error())
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, err.Error(), nil, nil)
+	}
+	plugin, dbErr := e.GetPluginMgrAddr(cmVariants.PluginID)
+	if dbErr != nil {
+		errMsg := "unable to get plugin " + cmVariants.PluginID + ": " + dbErr.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"plugin", cmVariants.PluginID}, nil)
+	}
+	password, err := e.DecryptPassword(aggregationSource.Password)
+	if err != nil {
+		errMsg := "unable to decrypt device password: " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+
+	req := getResourceRequest{
+		ContactClient:   e.ContactClient,
+		GetPluginStatus: e.GetPluginStatus,
+		Plugin:          plugin,
+		StatusPoll:      true,
+	}
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
+		// the session is deleted once the resource is fetched, so that the plugin
+		// doesn't pile up a session for each passthrough request
+		req.Sessions = newPluginToken()
+		defer deletePluginSessions(ctx, req)
+		token, getResponse, err := getPluginSessionToken(ctx, req)
+		if err != nil {
+			l.LogWithFields(ctx).Error(err.Error())
+			return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, err.Error(), getResponse.MsgArgs, nil)
+		}
+		req.Token = token
+	} else {
+		req.LoginCredentials = map[string]string{
+			"UserName": plugin.Username,
+			"Password": string(plugin.Password),
+		}
+	}
+	req.DeviceInfo = map[string]interface{}{
+		"ManagerAddress": aggregationSource.HostName,
+		"UserName":       aggregationSource.UserName,
+		"Password":       password,
+	}
+	req.OID = deviceURI
+	req.HTTPMethodType = http.MethodGet
+	body, _, getResponse, err := contactPlugin(ctx, req, "error while trying to get the "+deviceURI+" details: ")
+	if err != nil {
+		l.LogWithFields(ctx).Error(err.Error())
+		return common.GeneralError(getResponse.StatusCode, getResponse.StatusMessage, err.Error(), getResponse.MsgArgs, nil)
+	}
+	var resource map[string]interface{}
+	if err := json.Unmarshal([]byte(updateResourceDataWithUUID(string(body), deviceUUID)), &resource); err != nil {
+		errMsg := "error while trying to unmarshal the response of " + deviceURI + ": " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+	return response.RPC{
+		StatusCode:    http.StatusOK,
+		StatusMessage: response.Success,
+		Header: map[string]string{
+			"Content-type": "application/json; charset=utf-8",
+		},
+		Body: resource,
+	}
+}
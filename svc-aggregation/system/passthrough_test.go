@@ -0,0 +1,137 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDeviceResource(t *testing.T) {
+	config.SetUpMockConfig(t)
+	deviceUUID := "36474ba4-a201-46aa-badf-d8104da418e8"
+	var contactedURLs []string
+	e := &ExternalInterface{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			contactedURLs = append(contactedURLs, method+" "+url)
+			if url != "https://localhost:9091/ODIM/v1/Systems/1/Bios" {
+				return nil, fmt.Errorf("unexpected URL %s", url)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"@odata.id":"/ODIM/v1/Systems/1/Bios","Id":"Bios"}`)),
+			}, nil
+		},
+		GetPluginStatus:          func(ctx context.Context, plugin agmodel.Plugin) bool { return false },
+		GetAggregationSourceInfo: mockGetAggregationSourceInfo,
+		GetConnectionMethod:      mockGetConnectionMethod,
+		GetPluginMgrAddr: func(pluginID string) (agmodel.Plugin, *errors.Error) {
+			return agmodel.Plugin{
+				ID:                pluginID,
+				IP:                "localhost",
+				Port:              "9091",
+				Username:          "admin",
+				Password:          []byte("password"),
+				PreferredAuthType: "BasicAuth",
+			}, nil
+		},
+		DecryptPassword: stubDevicePassword,
+	}
+	ctx := mockContext()
+
+	resp := e.GetDeviceResource(ctx, http.MethodGet, deviceUUID+".1", "/redfish/v1/Systems/"+deviceUUID+".1/Bios")
+	assert.Equal(t, http.StatusOK, int(resp.StatusCode), "GET of the device resource should succeed")
+	assert.Equal(t, []string{"GET https://localhost:9091/ODIM/v1/Systems/1/Bios"}, contactedURLs, "plugin should be contacted with the southbound URI")
+	body := resp.Body.(map[string]interface{})
+	assert.Equal(t, "/redfish/v1/Systems/"+deviceUUID+".1/Bios", body["@odata.id"], "response should have the northbound URI")
+
+	contactedURLs = nil
+	resp = e.GetDeviceResource(ctx, http.MethodPost, deviceUUID+".1", "/redfish/v1/Systems/"+deviceUUID+".1/Bios")
+	assert.Equal(t, http.StatusMethodNotAllowed, int(resp.StatusCode), "only GET should be allowed")
+	resp = e.GetDeviceResource(ctx, http.MethodGet, deviceUUID+".1", "/redfish/v1/Systems/other-device.1/Bios")
+	assert.Equal(t, http.StatusBadRequest, int(resp.StatusCode), "URI of another device should be rejected")
+	resp = e.GetDeviceResource(ctx, http.MethodGet, deviceUUID+".1", "/redfish/v1/Systems/"+deviceUUID+".1/../../Sessions")
+	assert.Equal(t, http.StatusBadRequest, int(resp.StatusCode), "URI escaping the device should be rejected")
+	assert.Empty(t, contactedURLs, "plugin should not be contacted for the rejected requests")
+
+	resp = e.GetDeviceResource(ctx, http.MethodGet, "unknown.1", "/redfish/v1/Systems/unknown.1")
+	assert.Equal(t, http.StatusNotFound, int(resp.StatusCode), "unknown system should not be found")
+
+	// malformed connection method variant in the DB
+	e.GetConnectionMethod = func(uri string) (agmodel.ConnectionMethod, *errors.Error) {
+		return agmodel.ConnectionMethod{ConnectionMethodType: "Redfish", ConnectionMethodVariant: "Compute:BasicAuth"}, nil
+	}
+	resp = e.GetDeviceResource(ctx, http.MethodGet, deviceUUID+".1", "/redfish/v1/Systems/"+deviceUUID+".1/Bios")
+	assert.Equal(t, http.StatusInternalServerError, int(resp.StatusCode), "malformed connection method variant should fail the request")
+	errBody, _ := json.Marshal(resp.Body)
+	assert.Contains(t, string(errBody), "Compute:BasicAuth", "error should name the malformed variant")
+	assert.Empty(t, contactedURLs, "plugin should not be contacted with the malformed variant")
+}
+
+func TestGetDeviceResourceWithXAuthToken(t *testing.T) {
+	config.SetUpMockConfig(t)
+	deviceUUID := "36474ba4-a201-46aa-badf-d8104da418e8"
+	var contactedURLs []string
+	e := &ExternalInterface{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			contactedURLs = append(contactedURLs, method+" "+url)
+			switch {
+			case url == "https://localhost:9091/ODIM/v1/Sessions" && method == http.MethodPost:
+				resp, err := stubResponse(http.StatusCreated, "")
+				resp.Header = http.Header{"X-Auth-Token": []string{"sometoken"}}
+				return resp, err
+			case url == "https://localhost:9091/ODIM/v1/Sessions" && method == http.MethodDelete:
+				assert.Equal(t, "sometoken", token, "session of the request should be deleted")
+				return stubResponse(http.StatusNoContent, "")
+			case url == "https://localhost:9091/ODIM/v1/Systems/1/Bios":
+				assert.Equal(t, "sometoken", token, "resource should be fetched with the session")
+				return stubResponse(http.StatusOK, `{"@odata.id":"/ODIM/v1/Systems/1/Bios","Id":"Bios"}`)
+			}
+			return nil, fmt.Errorf("unexpected URL %s", url)
+		},
+		GetPluginStatus:          func(ctx context.Context, plugin agmodel.Plugin) bool { return false },
+		GetAggregationSourceInfo: mockGetAggregationSourceInfo,
+		GetConnectionMethod:      mockGetConnectionMethod,
+		GetPluginMgrAddr: func(pluginID string) (agmodel.Plugin, *errors.Error) {
+			return agmodel.Plugin{
+				ID:                pluginID,
+				IP:                "localhost",
+				Port:              "9091",
+				Username:          "admin",
+				Password:          []byte("password"),
+				PreferredAuthType: "XAuthToken",
+			}, nil
+		},
+		DecryptPassword: stubDevicePassword,
+	}
+
+	resp := e.GetDeviceResource(mockContext(), http.MethodGet, deviceUUID+".1", "/redfish/v1/Systems/"+deviceUUID+".1/Bios")
+	assert.Equal(t, http.StatusOK, int(resp.StatusCode), "GET of the device resource should succeed")
+	assert.Equal(t, []string{
+		"POST https://localhost:9091/ODIM/v1/Sessions",
+		"GET https://localhost:9091/ODIM/v1/Systems/1/Bios",
+		"DELETE https://localhost:9091/ODIM/v1/Sessions",
+	}, contactedURLs, "session of the plugin should be deleted once the resource is fetched")
+}
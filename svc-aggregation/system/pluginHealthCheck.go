@@ -44,7 +44,12 @@ var (
 	DecryptWithPrivateKey = common.DecryptWithPrivateKey
 	// GetPluginStatusRecord function pointer for the agcommon.GetPluginStatusRecord
 	GetPluginStatusRecord = agcommon.GetPluginStatusRecord
-	podName               = os.Getenv("POD_NAME")
+	// UpdatePluginVersionFunc function pointer for the agmodel.UpdatePluginVersion
+	UpdatePluginVersionFunc = agmodel.UpdatePluginVersion
+	// RediscoverPluginServersFunc is for rediscovering the servers managed by a plugin
+	// when the version of the plugin changes, it is set while starting the service
+	RediscoverPluginServersFunc func(context.Context, string)
+	podName                     = os.Getenv("POD_NAME")
 )
 
 const (
@@ -116,7 +121,10 @@ func PerformPluginHealthCheck() {
 }
 
 func checkPluginStatus(ctx context.Context, phc *agcommon.PluginHealthCheckInterface, plugin agmodel.Plugin) {
-	active, topics := phc.GetPluginStatus(ctx, plugin)
+	active, version, topics := phc.GetPluginStatusDetailed(ctx, plugin)
+	if active {
+		checkPluginVersion(ctx, phc, plugin, version)
+	}
 	if count, exist := GetPluginStatusRecord(plugin.ID); !exist {
 		agcommon.SetPluginStatusRecord(plugin.ID, 0)
 	} else {
@@ -137,6 +145,31 @@ func checkPluginStatus(ctx context.Context, phc *agcommon.PluginHealthCheckInter
 	}
 }
 
+// checkPluginVersion compares the version reported by the plugin with the stored version,
+// and on a change, the stored version is updated and the rediscovery of the servers managed
+// by the plugin is enqueued, if RediscoverOnVersionChange is enabled
+func checkPluginVersion(ctx context.Context, phc *agcommon.PluginHealthCheckInterface, plugin agmodel.Plugin, reportedVersion string) {
+	version, err := normalizeFirmwareVersion(reportedVersion)
+	if err != nil || version == plugin.Version {
+		return
+	}
+	if err := UpdatePluginVersionFunc(plugin.ID, version); err != nil {
+		l.LogWithFields(ctx).Error("failed to update the version of plugin " + plugin.ID + ": " + err.Error())
+		return
+	}
+	// plugins added before the version was stored don't have a version to compare with
+	if plugin.Version == "" {
+		l.LogWithFields(ctx).Infof("recorded version %s of plugin %s", version, plugin.ID)
+		return
+	}
+	l.LogWithFields(ctx).Infof("version of plugin %s changed from %s to %s", plugin.ID, plugin.Version, version)
+	if !phc.PluginConfig.RediscoverOnVersionChange || plugin.PluginType != "Compute" || RediscoverPluginServersFunc == nil {
+		return
+	}
+	l.LogWithFields(ctx).Info("enqueuing the rediscovery of the servers managed by plugin " + plugin.ID)
+	go RediscoverPluginServersFunc(ctx, plugin.ID)
+}
+
 // SendPluginStartUpData is for sending the plugin startup data
 // when the plugin requests through an event
 func SendPluginStartUpData(ctx context.Context, pluginIP string, plugin agmodel.Plugin) error {
@@ -18,10 +18,13 @@
 package system
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
 	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
 	"github.com/ODIM-Project/ODIM/svc-aggregation/agcommon"
 	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
 	"github.com/stretchr/testify/assert"
@@ -65,6 +68,61 @@ func Test_checkPluginStatus(t *testing.T) {
 	}
 }
 
+func Test_checkPluginVersion(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(orig func(string, string) *errors.Error) { UpdatePluginVersionFunc = orig }(UpdatePluginVersionFunc)
+	defer func(orig func(context.Context, string)) { RediscoverPluginServersFunc = orig }(RediscoverPluginServersFunc)
+
+	var updatedVersion string
+	UpdatePluginVersionFunc = func(pluginID, version string) *errors.Error {
+		updatedVersion = version
+		return nil
+	}
+	rediscovered := make(chan string, 1)
+	RediscoverPluginServersFunc = func(ctx context.Context, pluginID string) {
+		rediscovered <- pluginID
+	}
+	plugin := agmodel.Plugin{
+		ID:         "ILO_v1.0.0",
+		PluginType: "Compute",
+		Version:    "1.0.0",
+	}
+	ctx := mockContext()
+	phc := &agcommon.PluginHealthCheckInterface{}
+	phc.PluginConfig.RediscoverOnVersionChange = true
+
+	// version change should update the stored version and enqueue the rediscovery
+	checkPluginVersion(ctx, phc, plugin, "v2.0.0")
+	assert.Equal(t, "2.0.0", updatedVersion, "stored version should be updated")
+	select {
+	case pluginID := <-rediscovered:
+		assert.Equal(t, plugin.ID, pluginID, "servers of the plugin should be rediscovered")
+	case <-time.After(time.Second):
+		t.Fatal("rediscovery should be enqueued on version change")
+	}
+
+	// same version should neither update nor rediscover
+	updatedVersion = ""
+	checkPluginVersion(ctx, phc, plugin, "1.0.0")
+	assert.Equal(t, "", updatedVersion, "stored version should not be updated")
+
+	// version is only recorded when there isn't a stored version
+	plugin.Version = ""
+	checkPluginVersion(ctx, phc, plugin, "2.0.0")
+	assert.Equal(t, "2.0.0", updatedVersion, "reported version should be recorded")
+
+	// version change shouldn't rediscover when it is not enabled
+	plugin.Version = "1.0.0"
+	phc.PluginConfig.RediscoverOnVersionChange = false
+	checkPluginVersion(ctx, phc, plugin, "3.0.0")
+	assert.Equal(t, "3.0.0", updatedVersion, "stored version should be updated")
+	select {
+	case <-rediscovered:
+		t.Fatal("rediscovery should not be enqueued when it is not enabled")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func mockPlugins(t *testing.T) {
 	connPool, err := common.GetDBConnection(common.OnDisk)
 	if err != nil {
@@ -0,0 +1,93 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+// pluginResponseAuditTable is the table in which the raw responses of the plugins are stored
+const pluginResponseAuditTable = "PluginResponseAudit"
+
+// SavePluginResponseAudit is the function pointer to store the audit record of the plugin response
+var SavePluginResponseAudit = agmodel.GenericSave
+
+// credentialPattern matches the JSON properties holding the credentials in the plugin response
+var credentialPattern = regexp.MustCompile(`("(?i:[^"]*(?:password|token|secret)[^"]*)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// pluginResponseAudit is the audit record of a raw plugin response
+type pluginResponseAudit struct {
+	RequestID  string
+	OID        string
+	Method     string
+	StatusCode int
+	Truncated  bool
+	Body       string
+	Time       string
+}
+
+// maskCredentials masks the values of the credential properties in the body
+func maskCredentials(body []byte) string {
+	return credentialPattern.ReplaceAllString(string(body), `$1"******"`)
+}
+
+// getLoggableBody returns the body to include in the error messages and logs, the credentials
+// are masked and the bodies larger than ErrorBodyMaxBytes are truncated with a note of the total size
+func getLoggableBody(body []byte) string {
+	masked := maskCredentials(body)
+	maxBytes := config.Data.DiscoveryConf.ErrorBodyMaxBytes
+	if maxBytes <= 0 || len(masked) <= maxBytes {
+		return masked
+	}
+	return fmt.Sprintf("%s... (truncated, total %d bytes)", masked[:maxBytes], len(masked))
+}
+
+// auditPluginResponse stores the raw response of the plugin, before the north bound URL translation,
+// keyed by the request ID and the OID. It is done only if the audit is enabled in the configuration
+func auditPluginResponse(ctx context.Context, req getResourceRequest, statusCode int, body []byte) {
+	if !config.Data.DiscoveryConf.AuditPluginResponses {
+		return
+	}
+	requestID, _ := ctx.Value(common.TransactionID).(string)
+	record := pluginResponseAudit{
+		RequestID:  requestID,
+		OID:        req.OID,
+		Method:     req.HTTPMethodType,
+		StatusCode: statusCode,
+		Body:       maskCredentials(body),
+		Time:       time.Now().UTC().Format(time.RFC3339),
+	}
+	if maxBytes := config.Data.DiscoveryConf.AuditResponseMaxBytes; maxBytes > 0 && len(record.Body) > maxBytes {
+		record.Body = record.Body[:maxBytes]
+		record.Truncated = true
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		l.LogWithFields(ctx).Error("error while trying to marshal the audit record of " + req.OID + ": " + err.Error())
+		return
+	}
+	if err := SavePluginResponseAudit(data, pluginResponseAuditTable, requestID+":"+req.OID); err != nil {
+		l.LogWithFields(ctx).Error("error while trying to save the audit record of " + req.OID + ": " + err.Error())
+	}
+}
@@ -0,0 +1,119 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_auditPluginResponse(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() {
+		SavePluginResponseAudit = agmodel.GenericSave
+	}()
+	records := make(map[string]pluginResponseAudit)
+	SavePluginResponseAudit = func(data []byte, table, key string) error {
+		var record pluginResponseAudit
+		json.Unmarshal(data, &record)
+		records[table+":"+key] = record
+		return nil
+	}
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		respBody := `{"@odata.id":"/ODIM/v1/Managers/1","UserName":"admin","Password":"secret123","Oem":{"AuthToken":"abc"}}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(respBody)),
+		}, nil
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/Managers/1",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+
+	// audit is disabled by default
+	contactPlugin(mockContext(), req, "")
+	assert.Empty(t, records, "audit record should not be written when disabled")
+
+	config.Data.DiscoveryConf.AuditPluginResponses = true
+	contactPlugin(mockContext(), req, "")
+	record, ok := records["PluginResponseAudit:xyz:/redfish/v1/Managers/1"]
+	if assert.True(t, ok, "audit record should be written with the request ID and the OID") {
+		assert.Equal(t, http.StatusOK, record.StatusCode)
+		assert.Contains(t, record.Body, `"@odata.id":"/ODIM/v1/Managers/1"`, "response should be stored before the URL translation")
+		assert.Contains(t, record.Body, `"Password":"******"`, "password should be masked")
+		assert.Contains(t, record.Body, `"AuthToken":"******"`, "token should be masked")
+		assert.NotContains(t, record.Body, "secret123")
+		assert.False(t, record.Truncated)
+	}
+
+	config.Data.DiscoveryConf.AuditResponseMaxBytes = 10
+	contactPlugin(mockContext(), req, "")
+	record = records["PluginResponseAudit:xyz:/redfish/v1/Managers/1"]
+	assert.Len(t, record.Body, 10, "response should be truncated")
+	assert.True(t, record.Truncated)
+}
+
+func Test_contactPluginErrorBodyTruncation(t *testing.T) {
+	config.SetUpMockConfig(t)
+	largeBody := `{"Password":"secret123","Message":"` + strings.Repeat("x", 5000) + `"}`
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(largeBody)),
+		}, nil
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/Managers/1",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+
+	config.Data.DiscoveryConf.ErrorBodyMaxBytes = 100
+	_, _, _, err := contactPlugin(mockContext(), req, "error: ")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `"Password":"******"`, "password should be masked before truncation")
+		assert.NotContains(t, err.Error(), "secret123")
+		assert.Contains(t, err.Error(), fmt.Sprintf("... (truncated, total %d bytes)", len(largeBody)-len("secret123")+len("******")), "total size should be noted")
+		assert.Less(t, len(err.Error()), 200, "body in the error should be truncated")
+	}
+
+	config.Data.DiscoveryConf.ErrorBodyMaxBytes = 10000
+	_, _, _, err = contactPlugin(mockContext(), req, "error: ")
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "truncated", "body smaller than the maximum should not be truncated")
+	}
+}
@@ -0,0 +1,108 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+)
+
+var (
+	// ErrPluginUnreachable is returned by contactPlugin when the plugin could not be
+	// contacted or the connection failed before the response was read
+	ErrPluginUnreachable = errors.New("plugin unreachable")
+	// ErrAuth is returned by contactPlugin when the plugin or the device rejected the credentials
+	ErrAuth = errors.New("unauthorized")
+	// ErrDeviceError is returned by contactPlugin when the plugin responded with an error status
+	ErrDeviceError = errors.New("device error")
+	// ErrSystemNotSupported is returned by contactPlugin along with ErrDeviceError when
+	// the plugin responded the computer system is not supported
+	ErrSystemNotSupported = errors.New("system not supported")
+	// ErrResponseTooLarge is returned by contactPlugin when the plugin responded with
+	// a body larger than the MaxPluginResponseBytes
+	ErrResponseTooLarge = errors.New("plugin response body is larger than the limit")
+)
+
+// PluginContactError is the error returned by contactPlugin. It keeps the message built by
+// contactPlugin along with the status of the failure, so that the callers can map it to the
+// northbound response without matching the message. The kind of the failure is matched using
+// errors.Is with the sentinel errors, and the underlying cause is unwrapped with errors.Unwrap.
+type PluginContactError struct {
+	StatusCode    int32
+	StatusMessage string
+	MsgArgs       []interface{}
+	Err           error
+	kinds         []error
+	message       string
+}
+
+func (e *PluginContactError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the underlying cause of the failure
+func (e *PluginContactError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the kinds of the failure
+func (e *PluginContactError) Is(target error) bool {
+	for _, kind := range e.kinds {
+		if kind == target {
+			return true
+		}
+	}
+	return false
+}
+
+// status returns the responseStatus of the failure returned by contactPlugin along with the error
+func (e *PluginContactError) status() responseStatus {
+	return responseStatus{
+		StatusCode:    e.StatusCode,
+		StatusMessage: e.StatusMessage,
+		MsgArgs:       e.MsgArgs,
+	}
+}
+
+// newPluginError returns the error of the failed plugin contact with the message, the status and the cause
+func newPluginError(message string, status responseStatus, cause error, kinds ...error) *PluginContactError {
+	return &PluginContactError{
+		StatusCode:    status.StatusCode,
+		StatusMessage: status.StatusMessage,
+		MsgArgs:       status.MsgArgs,
+		Err:           cause,
+		kinds:         kinds,
+		message:       message,
+	}
+}
+
+// getDiscoveryErrorStatus returns the status of the failure returned by contactPlugin while discovering
+// a system, the systems which are not supported by the plugin are reported as the action not supported
+func getDiscoveryErrorStatus(err error) responseStatus {
+	var contactErr *PluginContactError
+	if !errors.As(err, &contactErr) {
+		return responseStatus{
+			StatusCode:    http.StatusInternalServerError,
+			StatusMessage: response.InternalError,
+		}
+	}
+	status := contactErr.status()
+	if errors.Is(err, ErrSystemNotSupported) {
+		status.StatusMessage = response.ActionNotSupported
+	}
+	return status
+}
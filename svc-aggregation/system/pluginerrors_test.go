@@ -0,0 +1,264 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	liberrors "github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockPluginErrorContactClient(statusCode int, err error) func(context.Context, string, string, string, string, interface{}, map[string]string) (*http.Response, error) {
+	return func(ctx context.Context, url, method, token, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error":"some error"}`)),
+		}, nil
+	}
+}
+
+func Test_contactPluginErrors(t *testing.T) {
+	config.SetUpMockConfig(t)
+	tests := []struct {
+		name        string
+		statusCode  int
+		contactErr  error
+		wantErr     error
+		otherErrors []error
+	}{
+		{
+			name:        "plugin unreachable",
+			contactErr:  fmt.Errorf("connection refused"),
+			wantErr:     ErrPluginUnreachable,
+			otherErrors: []error{ErrAuth, ErrDeviceError},
+		},
+		{
+			name:        "invalid credentials",
+			statusCode:  http.StatusUnauthorized,
+			wantErr:     ErrAuth,
+			otherErrors: []error{ErrPluginUnreachable, ErrDeviceError},
+		},
+		{
+			name:        "device error",
+			statusCode:  http.StatusInternalServerError,
+			wantErr:     ErrDeviceError,
+			otherErrors: []error{ErrPluginUnreachable, ErrAuth},
+		},
+		{
+			name:        "resource not found",
+			statusCode:  http.StatusNotFound,
+			wantErr:     ErrDeviceError,
+			otherErrors: []error{ErrPluginUnreachable, ErrAuth},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := getResourceRequest{
+				ContactClient:  mockPluginErrorContactClient(tt.statusCode, tt.contactErr),
+				OID:            "/redfish/v1/Systems",
+				HTTPMethodType: http.MethodGet,
+				Plugin: agmodel.Plugin{
+					IP:                "localhost",
+					Port:              "9091",
+					PreferredAuthType: "BasicAuth",
+				},
+			}
+			_, _, _, err := contactPlugin(mockContext(), req, "error while trying to get the systems: ")
+			assert.True(t, errors.Is(err, tt.wantErr), "error should wrap "+tt.wantErr.Error())
+			for _, otherErr := range tt.otherErrors {
+				assert.False(t, errors.Is(err, otherErr), "error should not wrap "+otherErr.Error())
+			}
+			assert.Contains(t, err.Error(), "error while trying to get the systems: ")
+		})
+	}
+}
+
+func Test_contactPluginUnreachableAfterStatusPoll(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var statusPolled bool
+	req := getResourceRequest{
+		ContactClient:  mockPluginErrorContactClient(0, fmt.Errorf("connection refused")),
+		OID:            "/redfish/v1/Systems",
+		HTTPMethodType: http.MethodGet,
+		StatusPoll:     true,
+		GetPluginStatus: func(ctx context.Context, plugin agmodel.Plugin) bool {
+			statusPolled = true
+			return true
+		},
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	_, _, resp, err := contactPlugin(mockContext(), req, "")
+	assert.True(t, statusPolled, "plugin status should be polled")
+	assert.True(t, errors.Is(err, ErrPluginUnreachable))
+	assert.Equal(t, int32(http.StatusServiceUnavailable), resp.StatusCode)
+}
+
+func Test_contactPluginDroppedResponse(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var calls int
+	// the connection drops while the first response is read
+	contactClient := func(ctx context.Context, url, method, token, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(io.MultiReader(bytes.NewBufferString(`{"Members":`), iotest.ErrReader(io.ErrUnexpectedEOF))),
+			}, nil
+		}
+		return stubResponse(http.StatusOK, `{"Members":[]}`)
+	}
+	var statusPolled bool
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/Systems",
+		HTTPMethodType: http.MethodGet,
+		StatusPoll:     true,
+		GetPluginStatus: func(ctx context.Context, plugin agmodel.Plugin) bool {
+			statusPolled = true
+			return true
+		},
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	body, _, _, err := contactPlugin(mockContext(), req, "")
+	assert.Nil(t, err)
+	assert.True(t, statusPolled, "plugin status should be polled when the response is dropped")
+	assert.Equal(t, `{"Members":[]}`, string(body))
+	assert.Equal(t, 2, calls)
+
+	// the requests which reached the plugin are not repeated unless they are safe to repeat
+	calls, statusPolled = 0, false
+	req.HTTPMethodType = http.MethodPost
+	_, _, _, err = contactPlugin(mockContext(), req, "")
+	assert.True(t, errors.Is(err, ErrPluginUnreachable))
+	assert.False(t, statusPolled, "plugin status should not be polled to repeat the request")
+	assert.Equal(t, 1, calls)
+}
+
+func Test_contactPluginErrorStatus(t *testing.T) {
+	config.SetUpMockConfig(t)
+	connErr := fmt.Errorf("connection refused")
+	req := getResourceRequest{
+		ContactClient:  mockPluginErrorContactClient(0, connErr),
+		OID:            "/redfish/v1/Systems",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	_, _, resp, err := contactPlugin(mockContext(), req, "")
+	var contactErr *PluginContactError
+	if assert.True(t, errors.As(err, &contactErr), "error should be a PluginContactError") {
+		assert.Equal(t, resp, contactErr.status(), "status of the error should match the response status")
+		assert.Equal(t, connErr, errors.Unwrap(err), "cause of the error should be unwrapped")
+	}
+
+	req.ContactClient = mockPluginErrorContactClient(http.StatusUnauthorized, nil)
+	_, _, resp, err = contactPlugin(mockContext(), req, "")
+	if assert.True(t, errors.As(err, &contactErr), "error should be a PluginContactError") {
+		assert.Equal(t, int32(http.StatusUnauthorized), contactErr.StatusCode)
+		assert.Equal(t, response.ResourceAtURIUnauthorized, contactErr.StatusMessage)
+		assert.Equal(t, resp.MsgArgs, contactErr.MsgArgs)
+	}
+}
+
+func Test_getSystemInfoPluginErrors(t *testing.T) {
+	config.SetUpMockConfig(t)
+	// the body is truncated in the error message, so the mapping can't depend on the message
+	config.Data.DiscoveryConf.ErrorBodyMaxBytes = 16
+	notSupportedBody := `{"error":{"message":"` + strings.Repeat("x", 32) + " " + liberrors.SystemNotSupportedErrString + `"}}`
+	tests := []struct {
+		name              string
+		statusCode        int
+		body              string
+		wantStatusCode    int32
+		wantStatusMessage string
+	}{
+		{
+			name:              "system not supported",
+			statusCode:        http.StatusBadRequest,
+			body:              notSupportedBody,
+			wantStatusCode:    http.StatusBadRequest,
+			wantStatusMessage: response.ActionNotSupported,
+		},
+		{
+			name:              "unauthorized",
+			statusCode:        http.StatusUnauthorized,
+			body:              `{"error":"unauthorized"}`,
+			wantStatusCode:    http.StatusUnauthorized,
+			wantStatusMessage: response.ResourceAtURIUnauthorized,
+		},
+		{
+			name:              "device error",
+			statusCode:        http.StatusInternalServerError,
+			body:              `{"error":"some error"}`,
+			wantStatusCode:    http.StatusInternalServerError,
+			wantStatusMessage: response.InternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := getResourceRequest{
+				ContactClient: func(ctx context.Context, url, method, token, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       ioutil.NopCloser(bytes.NewBufferString(tt.body)),
+					}, nil
+				},
+				OID:            "/redfish/v1/Systems/1",
+				HTTPMethodType: http.MethodGet,
+				Plugin: agmodel.Plugin{
+					IP:                "localhost",
+					Port:              "9091",
+					PreferredAuthType: "BasicAuth",
+				},
+			}
+			h := &respHolder{
+				TraversedLinks: make(map[string]bool),
+				InventoryData:  make(map[string]interface{}),
+			}
+			_, _, _, err := h.getSystemInfo(mockContext(), "", 0, 0, req)
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.wantStatusCode, h.StatusCode)
+			assert.Equal(t, tt.wantStatusMessage, h.StatusMessage)
+			assert.Equal(t, tt.wantStatusMessage == response.ActionNotSupported, errors.Is(err, ErrSystemNotSupported))
+		})
+	}
+}
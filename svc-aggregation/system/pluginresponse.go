@@ -0,0 +1,176 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+)
+
+// translationBufferSize is the size of the chunks in which the large plugin responses are translated
+const translationBufferSize = 32 * 1024
+
+// readPluginResponse reads the body of the plugin response, failing with ErrResponseTooLarge when it is
+// larger than the MaxPluginResponseBytes. The successful responses larger than the StreamPluginResponseBytes,
+// or without the Content-Length, are translated with the translations while being read, so that only the
+// translated body is held in memory, translated is true for them. The rest are read as they are.
+func readPluginResponse(pluginResp *http.Response, translations map[string]string) (body []byte, translated bool, err error) {
+	maxBytes := int64(config.Data.DiscoveryConf.MaxPluginResponseBytes)
+	reader := pluginResp.Body
+	if maxBytes > 0 {
+		// a byte beyond the limit is read to find out the body is larger than the limit
+		reader = ioutil.NopCloser(io.LimitReader(pluginResp.Body, maxBytes+1))
+	}
+	successful := pluginResp.StatusCode == http.StatusOK || pluginResp.StatusCode == http.StatusCreated ||
+		pluginResp.StatusCode == http.StatusAccepted
+	streamBytes := int64(config.Data.DiscoveryConf.StreamPluginResponseBytes)
+	if successful && (pluginResp.ContentLength < 0 || pluginResp.ContentLength > streamBytes) {
+		var out bytes.Buffer
+		if pluginResp.ContentLength > 0 && (maxBytes <= 0 || pluginResp.ContentLength <= maxBytes) {
+			out.Grow(int(pluginResp.ContentLength))
+		}
+		read, err := translateStream(&out, reader, translations, translationBufferSize)
+		if err != nil {
+			return nil, false, err
+		}
+		if maxBytes > 0 && read > maxBytes {
+			return nil, false, fmt.Errorf("%w: the limit is %d bytes", ErrResponseTooLarge, maxBytes)
+		}
+		return out.Bytes(), true, nil
+	}
+	body, err = ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, false, err
+	}
+	if maxBytes > 0 && int64(len(body)) > maxBytes {
+		return nil, false, fmt.Errorf("%w: the limit is %d bytes", ErrResponseTooLarge, maxBytes)
+	}
+	return body, false, nil
+}
+
+// callAndReadPlugin contacts the plugin of the request and reads its response. When the plugin could not
+// be reached and its status is polled for the request, the plugin is contacted again once it is found up.
+// The plugin is not contacted again for the requests which reached it unless they are safe to repeat.
+func callAndReadPlugin(ctx context.Context, req getResourceRequest, errorMessage string, translations map[string]string) (*http.Response, []byte, bool, *PluginContactError) {
+	pluginResp, body, translated, err := readPlugin(ctx, req, errorMessage, translations)
+	if err != nil && errors.Is(err, ErrPluginUnreachable) && req.StatusPoll && (pluginResp == nil || req.HTTPMethodType == http.MethodGet) {
+		if req.GetPluginStatus(ctx, req.Plugin) {
+			pluginResp, body, translated, err = readPlugin(ctx, req, errorMessage, translations)
+		}
+	}
+	return pluginResp, body, translated, err
+}
+
+// readPlugin contacts the plugin of the request and reads its response, the failures are returned as
+// the PluginContactError. The response is returned along with the error when its body could not be read.
+func readPlugin(ctx context.Context, req getResourceRequest, errorMessage string, translations map[string]string) (*http.Response, []byte, bool, *PluginContactError) {
+	pluginResp, err := callPlugin(ctx, req)
+	if err != nil {
+		resp := responseStatus{
+			StatusCode:    http.StatusServiceUnavailable,
+			StatusMessage: response.CouldNotEstablishConnection,
+			MsgArgs:       []interface{}{"https://" + req.Plugin.IP + ":" + req.Plugin.Port + req.OID},
+		}
+		return nil, nil, false, newPluginError(errorMessage+err.Error(), resp, err, ErrPluginUnreachable)
+	}
+	defer pluginResp.Body.Close()
+	if req.pluginOperation() == discoveryOperation {
+		discoveryRateLimiter.setLoad(req.Plugin, pluginResp.Header.Get(config.Data.DiscoveryConf.ThrottleHintHeader))
+	}
+	body, translated, err := readPluginResponse(pluginResp, translations)
+	if err != nil {
+		errorMessage := "error while trying to read plugin response body of " + req.OID + ": " + err.Error()
+		resp := responseStatus{
+			StatusCode:    http.StatusInternalServerError,
+			StatusMessage: response.InternalError,
+		}
+		return pluginResp, nil, false, newPluginError(errorMessage, resp, err, getReadErrorKind(err))
+	}
+	return pluginResp, body, translated, nil
+}
+
+// getReadErrorKind returns the kind of the failure while reading the plugin response body
+func getReadErrorKind(err error) error {
+	if errors.Is(err, ErrResponseTooLarge) {
+		return ErrResponseTooLarge
+	}
+	return ErrPluginUnreachable
+}
+
+// translateStream copies the data read from in to out, replacing the keys of the translations with
+// their values. The data is read in chunks of bufferSize, and the tail of the chunk which could be the
+// start of a key is carried over to the next chunk, so the memory used doesn't depend on the data size.
+// The longest key is replaced when the keys match at the same position. The number of bytes read is returned.
+func translateStream(out *bytes.Buffer, in io.Reader, translations map[string]string, bufferSize int) (int64, error) {
+	keys := sortTranslationKeys(translations)
+	maxKeyLen := 1
+	if len(keys) > 0 {
+		maxKeyLen = len(keys[0])
+	}
+
+	var read int64
+	chunk := make([]byte, bufferSize)
+	pending := make([]byte, 0, bufferSize+maxKeyLen)
+	for {
+		n, err := in.Read(chunk)
+		read += int64(n)
+		pending = append(pending, chunk[:n]...)
+		if err != nil && err != io.EOF {
+			return read, err
+		}
+		final := err == io.EOF
+		safe := len(pending)
+		if !final {
+			safe -= maxKeyLen - 1
+		}
+		consumed := translateChunk(out, pending, safe, keys, translations)
+		pending = append(pending[:0], pending[consumed:]...)
+		if final {
+			return read, nil
+		}
+	}
+}
+
+// translateChunk writes the data to out replacing the keys, only the keys starting before safe are
+// replaced. The number of bytes of the data consumed is returned, the rest is to be translated
+// along with the data read next.
+func translateChunk(out *bytes.Buffer, data []byte, safe int, keys []string, translations map[string]string) int {
+	i := 0
+	for i < safe {
+		matchIndex, matchKey := -1, ""
+		for _, key := range keys {
+			index := bytes.Index(data[i:], []byte(key))
+			if index >= 0 && (matchIndex < 0 || index < matchIndex) {
+				matchIndex, matchKey = index, key
+			}
+		}
+		if matchIndex < 0 || i+matchIndex >= safe {
+			out.Write(data[i:safe])
+			return safe
+		}
+		out.Write(data[i : i+matchIndex])
+		out.WriteString(translations[matchKey])
+		i += matchIndex + len(matchKey)
+	}
+	return i
+}
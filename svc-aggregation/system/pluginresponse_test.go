@@ -0,0 +1,144 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_contactPluginResponseSize(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.MaxPluginResponseBytes = 128
+	config.Data.DiscoveryConf.StreamPluginResponseBytes = 64
+	smallBody := `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1"}`
+	largeBody := `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1","Links":{"Chassis":[{"@odata.id":"/ODIM/v1/Chassis/1"}]}}`
+	oversizedBody := `{"@odata.id":"/ODIM/v1/Systems/1","Description":"` + strings.Repeat("x", 128) + `"}`
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		statusCode    int
+		want          string
+		tooLarge      bool
+	}{
+		{
+			name:          "normal body",
+			body:          smallBody,
+			contentLength: int64(len(smallBody)),
+			statusCode:    http.StatusOK,
+			want:          strings.Replace(smallBody, "ODIM", "redfish", -1),
+		},
+		{
+			name:          "body translated while being read",
+			body:          largeBody,
+			contentLength: int64(len(largeBody)),
+			statusCode:    http.StatusOK,
+			want:          strings.Replace(largeBody, "ODIM", "redfish", -1),
+		},
+		{
+			name:          "body without content length",
+			body:          smallBody,
+			contentLength: -1,
+			statusCode:    http.StatusOK,
+			want:          strings.Replace(smallBody, "ODIM", "redfish", -1),
+		},
+		{
+			name:          "body over the limit",
+			body:          oversizedBody,
+			contentLength: int64(len(oversizedBody)),
+			statusCode:    http.StatusOK,
+			tooLarge:      true,
+		},
+		{
+			name:          "body over the limit without content length",
+			body:          oversizedBody,
+			contentLength: -1,
+			statusCode:    http.StatusOK,
+			tooLarge:      true,
+		},
+		{
+			name:          "error body over the limit",
+			body:          oversizedBody,
+			contentLength: int64(len(oversizedBody)),
+			statusCode:    http.StatusInternalServerError,
+			tooLarge:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := getResourceRequest{
+				ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+					return &http.Response{
+						StatusCode:    tt.statusCode,
+						ContentLength: tt.contentLength,
+						Body:          ioutil.NopCloser(bytes.NewBufferString(tt.body)),
+					}, nil
+				},
+				OID:            "/redfish/v1/Systems/1",
+				HTTPMethodType: http.MethodGet,
+				Plugin: agmodel.Plugin{
+					IP:                "localhost",
+					Port:              "9091",
+					PreferredAuthType: "BasicAuth",
+				},
+			}
+			body, _, getResponse, err := contactPlugin(mockContext(), req, "")
+			if tt.tooLarge {
+				assert.True(t, errors.Is(err, ErrResponseTooLarge), "body over the limit should fail with ErrResponseTooLarge")
+				assert.Contains(t, err.Error(), "/redfish/v1/Systems/1", "error should name the resource")
+				assert.Equal(t, int32(http.StatusInternalServerError), getResponse.StatusCode)
+				assert.Nil(t, body)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, string(body), "body should be translated")
+		})
+	}
+}
+
+func Test_translateStream(t *testing.T) {
+	translations := map[string]string{
+		"/ODIM/v1/":       "/redfish/v1/",
+		"ODIM":            "redfish",
+		"/ODIM/v1/Oem/":   "/redfish/v1/Oem/Ext/",
+		"unused-key-long": "unused",
+	}
+	data := strings.Repeat(`{"@odata.id":"/ODIM/v1/Systems/1","Oem":{"@odata.id":"/ODIM/v1/Oem/1"},"Name":"ODIM"},`, 50)
+	want := strings.Replace(data, "/ODIM/v1/Oem/", "/redfish/v1/Oem/Ext/", -1)
+	want = strings.Replace(want, "ODIM", "redfish", -1)
+	// the small chunks split the keys across the chunks
+	for _, bufferSize := range []int{1, 3, 7, 16, 4096} {
+		var out bytes.Buffer
+		read, err := translateStream(&out, strings.NewReader(data), translations, bufferSize)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(len(data)), read, "all the data should be read")
+		assert.Equal(t, want, out.String(), "data should be translated with the chunks of %d bytes", bufferSize)
+	}
+
+	var out bytes.Buffer
+	_, err := translateStream(&out, strings.NewReader(data), nil, 16)
+	assert.Nil(t, err)
+	assert.Equal(t, data, out.String(), "data should be copied as it is without the translations")
+}
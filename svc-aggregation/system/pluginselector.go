@@ -0,0 +1,148 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+// pluginReplica is a plugin which can serve the add request along with its connection method
+type pluginReplica struct {
+	PluginID            string
+	ConnectionMethodURI string
+	ConnectionMethod    agmodel.ConnectionMethod
+	Variants            connectionMethodVariants
+}
+
+// pluginSelector picks the plugins among the replicas using weighted round-robin,
+// a counter is tracked for each set of replicas
+type pluginSelector struct {
+	lock     sync.Mutex
+	counters map[string]uint64
+}
+
+var replicaSelector = &pluginSelector{
+	counters: make(map[string]uint64),
+}
+
+// getPluginWeight returns the weight configured for the plugin, plugins without
+// a weight configured have the equal weight of 1
+func getPluginWeight(pluginID string) int {
+	if weight := config.Data.DiscoveryConf.PluginWeights[pluginID]; weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// selectPlugin returns the id of the plugin to be used for the next add request among the plugin ids.
+// Over the weight sum of the selections, each plugin is selected as many times as its weight.
+func (s *pluginSelector) selectPlugin(pluginIDs []string) string {
+	if len(pluginIDs) == 0 {
+		return ""
+	}
+	ids := make([]string, len(pluginIDs))
+	copy(ids, pluginIDs)
+	sort.Strings(ids)
+	var totalWeight uint64
+	for _, id := range ids {
+		totalWeight += uint64(getPluginWeight(id))
+	}
+	key := strings.Join(ids, ",")
+	s.lock.Lock()
+	counter := s.counters[key]
+	s.counters[key] = counter + 1
+	s.lock.Unlock()
+
+	slot := counter % totalWeight
+	for _, id := range ids {
+		weight := uint64(getPluginWeight(id))
+		if slot < weight {
+			return id
+		}
+		slot -= weight
+	}
+	return ids[len(ids)-1]
+}
+
+// getPluginReplicas returns the plugins which are added in ODIM and are identical with the plugin
+// of the connection method, i.e. plugins of the same connection method type, plugin type,
+// preferred auth type and firmware version. The plugin of the connection method is always included.
+func (e *ExternalInterface) getPluginReplicas(ctx context.Context, connectionMethodURI string, connectionMethod agmodel.ConnectionMethod, cmVariants connectionMethodVariants) []pluginReplica {
+	replicas := []pluginReplica{{
+		PluginID:            cmVariants.PluginID,
+		ConnectionMethodURI: connectionMethodURI,
+		ConnectionMethod:    connectionMethod,
+		Variants:            cmVariants,
+	}}
+	connectionMethodURIs, err := e.GetAllKeysFromTable("ConnectionMethod")
+	if err != nil {
+		l.LogWithFields(ctx).Warn("unable to get the connection methods for finding the plugin replicas: " + err.Error())
+		return replicas
+	}
+	for _, uri := range connectionMethodURIs {
+		if uri == connectionMethodURI {
+			continue
+		}
+		cm, dbErr := e.GetConnectionMethod(uri)
+		if dbErr != nil || cm.ConnectionMethodType != connectionMethod.ConnectionMethodType {
+			continue
+		}
+		variants, err := getConnectionMethodVariants(cm.ConnectionMethodVariant)
+		if err != nil || variants.PluginType != cmVariants.PluginType ||
+			variants.PreferredAuthType != cmVariants.PreferredAuthType ||
+			variants.FirmwareVersion != cmVariants.FirmwareVersion {
+			continue
+		}
+		// plugin of the connection method should be added to be used
+		if _, dbErr := agmodel.GetPluginData(variants.PluginID); dbErr != nil {
+			continue
+		}
+		replicas = append(replicas, pluginReplica{
+			PluginID:            variants.PluginID,
+			ConnectionMethodURI: uri,
+			ConnectionMethod:    cm,
+			Variants:            variants,
+		})
+	}
+	return replicas
+}
+
+// selectPluginReplica returns the replica to be used for adding the server among the
+// replicas of the plugin of the connection method
+func (e *ExternalInterface) selectPluginReplica(ctx context.Context, connectionMethodURI string, connectionMethod agmodel.ConnectionMethod, cmVariants connectionMethodVariants) pluginReplica {
+	replicas := e.getPluginReplicas(ctx, connectionMethodURI, connectionMethod, cmVariants)
+	if len(replicas) == 1 {
+		return replicas[0]
+	}
+	pluginIDs := make([]string, 0, len(replicas))
+	for _, replica := range replicas {
+		pluginIDs = append(pluginIDs, replica.PluginID)
+	}
+	selectedID := replicaSelector.selectPlugin(pluginIDs)
+	for _, replica := range replicas {
+		if replica.PluginID == selectedID {
+			l.LogWithFields(ctx).Info("plugin " + selectedID + " is selected among the replicas " + strings.Join(pluginIDs, ", "))
+			return replica
+		}
+	}
+	return replicas[0]
+}
@@ -0,0 +1,61 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_selectPluginEvenDistribution(t *testing.T) {
+	config.SetUpMockConfig(t)
+	selector := &pluginSelector{counters: make(map[string]uint64)}
+	pluginIDs := []string{"GRF_1", "GRF_2", "GRF_3"}
+	selections := make(map[string]int)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pluginID := selector.selectPlugin(pluginIDs)
+			lock.Lock()
+			selections[pluginID]++
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, map[string]int{"GRF_1": 100, "GRF_2": 100, "GRF_3": 100}, selections, "selections should be evenly distributed")
+}
+
+func Test_selectPluginWeightedDistribution(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.PluginWeights = map[string]int{"GRF_1": 3}
+	selector := &pluginSelector{counters: make(map[string]uint64)}
+	selections := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		// order of the plugin ids should not affect the selection
+		if i%2 == 0 {
+			selections[selector.selectPlugin([]string{"GRF_1", "GRF_2"})]++
+		} else {
+			selections[selector.selectPlugin([]string{"GRF_2", "GRF_1"})]++
+		}
+	}
+	assert.Equal(t, map[string]int{"GRF_1": 300, "GRF_2": 100}, selections, "selections should be distributed as per the weights")
+	assert.Equal(t, "", selector.selectPlugin(nil), "no plugin should be selected without the plugin ids")
+}
@@ -0,0 +1,148 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+// pluginToken holds the XAuthToken sessions created with the plugins during a discovery run
+// against the plugin, so that a session is reused for all the plugin contacts of the run
+type pluginToken struct {
+	tokens  map[string]string
+	plugins map[string]agmodel.Plugin
+	lock    sync.Mutex
+}
+
+func newPluginToken() *pluginToken {
+	return &pluginToken{
+		tokens:  make(map[string]string),
+		plugins: make(map[string]agmodel.Plugin),
+	}
+}
+
+// pluginSessionKey returns the key of the session of the plugin, the address is part of the key
+// as the status of the manager address is checked with the ID of the plugin which adds it
+func pluginSessionKey(plugin agmodel.Plugin) string {
+	return plugin.ID + "@" + plugin.IP + ":" + plugin.Port
+}
+
+// storeToken stores the session token of the plugin
+func (p *pluginToken) storeToken(plugin agmodel.Plugin, token string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.tokens[pluginSessionKey(plugin)] = token
+	p.plugins[pluginSessionKey(plugin)] = plugin
+}
+
+// getToken returns the session token of the plugin
+func (p *pluginToken) getToken(plugin agmodel.Plugin) string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.tokens[pluginSessionKey(plugin)]
+}
+
+// refreshToken creates a new session with the plugin when its session token is still the expired one,
+// the session already refreshed by another plugin contact of the run is reused
+func (p *pluginToken) refreshToken(ctx context.Context, req getResourceRequest, expiredToken string) (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	key := pluginSessionKey(req.Plugin)
+	if token := p.tokens[key]; token != "" && token != expiredToken {
+		return token, nil
+	}
+	token, _, err := createPluginSession(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	p.tokens[key] = token
+	p.plugins[key] = req.Plugin
+	return token, nil
+}
+
+// isXAuthToken checks if the plugin of the request is contacted with the session token
+func (req getResourceRequest) isXAuthToken() bool {
+	return strings.EqualFold(req.Plugin.AuthType(), "XAuthToken")
+}
+
+// sessionToken returns the token the plugin of the request is contacted with, the session of the
+// discovery run is preferred over the token of the request
+func (req getResourceRequest) sessionToken() string {
+	if req.Sessions != nil {
+		if token := req.Sessions.getToken(req.Plugin); token != "" {
+			return token
+		}
+	}
+	return req.Token
+}
+
+// canRefreshSession checks if the session of the request can be re-authenticated when the
+// plugin responds with 401 Unauthorized
+func (req getResourceRequest) canRefreshSession() bool {
+	return req.Sessions != nil && req.isXAuthToken() && req.OID != "/ODIM/v1/Sessions"
+}
+
+// getPluginSessionToken returns the session token of the plugin of the request, the session of
+// the discovery run is reused when there is one, else a session is created and kept for the run
+func getPluginSessionToken(ctx context.Context, req getResourceRequest) (string, responseStatus, error) {
+	if req.Sessions != nil {
+		if token := req.Sessions.getToken(req.Plugin); token != "" {
+			return token, responseStatus{StatusCode: http.StatusOK}, nil
+		}
+	}
+	token, resp, err := createPluginSession(ctx, req)
+	if err != nil {
+		return "", resp, err
+	}
+	if req.Sessions != nil {
+		req.Sessions.storeToken(req.Plugin, token)
+	}
+	return token, resp, nil
+}
+
+// createPluginSession creates a session with the plugin of the request and returns its token
+func createPluginSession(ctx context.Context, req getResourceRequest) (string, responseStatus, error) {
+	req.HTTPMethodType = http.MethodPost
+	req.DeviceInfo = map[string]interface{}{
+		"Username": req.Plugin.Username,
+		"Password": string(req.Plugin.Password),
+	}
+	req.OID = "/ODIM/v1/Sessions"
+	req.Sessions = nil
+	_, token, resp, err := contactPlugin(ctx, req, "error while creating the session: ")
+	return token, resp, err
+}
+
+// deletePluginSessions deletes the sessions created with the plugins during the discovery run
+func deletePluginSessions(ctx context.Context, req getResourceRequest) {
+	if req.Sessions == nil {
+		return
+	}
+	req.Sessions.lock.Lock()
+	defer req.Sessions.lock.Unlock()
+	for key, token := range req.Sessions.tokens {
+		sessionReq := req
+		sessionReq.Plugin = req.Sessions.plugins[key]
+		sessionReq.Token = token
+		sessionReq.Sessions = nil
+		deletePluginSession(ctx, sessionReq)
+	}
+	req.Sessions.tokens = make(map[string]string)
+}
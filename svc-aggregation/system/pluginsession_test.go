@@ -0,0 +1,127 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSessionPlugin is a plugin with XAuthToken authentication, which accepts only the latest session
+type mockSessionPlugin struct {
+	lock         sync.Mutex
+	sessionPosts int
+	validToken   string
+	// expireAt is the resource after serving which the session of the plugin expires
+	expireAt string
+}
+
+func (m *mockSessionPlugin) contactClient(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if strings.HasSuffix(url, "/ODIM/v1/Sessions") && method == http.MethodPost {
+		m.sessionPosts++
+		m.validToken = fmt.Sprintf("token-%d", m.sessionPosts)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{"X-Auth-Token": []string{m.validToken}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+		}, nil
+	}
+	if token == "" || token != m.validToken {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error":"unauthorized"}`)),
+		}, nil
+	}
+	var contactedURLs []string
+	resp, err := mockRediscoveryClient(&contactedURLs)(ctx, url, method, token, odataID, body, credentials)
+	if m.expireAt != "" && strings.HasSuffix(url, m.expireAt) {
+		m.validToken = ""
+		m.expireAt = ""
+	}
+	return resp, err
+}
+
+func getSessionDiscoveryRequest(m *mockSessionPlugin) getResourceRequest {
+	return getResourceRequest{
+		ContactClient:  m.contactClient,
+		OID:            "/redfish/v1/Systems/1",
+		SystemID:       "1",
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: http.MethodGet,
+		UpdateFlag:     true,
+		Plugin: agmodel.Plugin{
+			ID:                "XAuthPlugin",
+			IP:                "localhost",
+			Port:              "9091",
+			Username:          "admin",
+			Password:          []byte("password"),
+			PreferredAuthType: "XAuthToken",
+		},
+		Sessions: newPluginToken(),
+	}
+}
+
+func Test_pluginSessionReusedForDiscovery(t *testing.T) {
+	config.SetUpMockConfig(t)
+	m := &mockSessionPlugin{}
+	req := getSessionDiscoveryRequest(m)
+
+	// the session created while checking the status is reused for the discovery
+	token, _, err := getPluginSessionToken(mockContext(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+	token, _, err = getPluginSessionToken(mockContext(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token)
+
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	h.getResourceDetails(mockContext(), "", 0, 100, req)
+	assert.Empty(t, h.Problems)
+	assert.Contains(t, h.InventoryData, "Drives:/redfish/v1/Systems/someuuid.1/Storage/1/Drives/0")
+	assert.Equal(t, 1, m.sessionPosts, "only one session should be created for the discovery")
+}
+
+func Test_pluginSessionRefreshedOnUnauthorized(t *testing.T) {
+	config.SetUpMockConfig(t)
+	m := &mockSessionPlugin{expireAt: "/ODIM/v1/Systems/1/Storage"}
+	req := getSessionDiscoveryRequest(m)
+	_, _, err := getPluginSessionToken(mockContext(), req)
+	assert.Nil(t, err)
+
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	h.getResourceDetails(mockContext(), "", 0, 100, req)
+	assert.Empty(t, h.Problems, "the discovery should continue with the re-authenticated session")
+	assert.Contains(t, h.InventoryData, "Drives:/redfish/v1/Systems/someuuid.1/Storage/1/Drives/0")
+	assert.Equal(t, 2, m.sessionPosts, "the session should be re-authenticated only once")
+	assert.Equal(t, "token-2", req.Sessions.getToken(req.Plugin))
+}
@@ -0,0 +1,168 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+// pluginRateLimiter paces the discovery calls made to each of the plugins. The interval between
+// the calls is taken from PluginRequestsPerSecond, and it is extended in proportion to the load
+// the device reports through the ThrottleHintHeader of the plugin responses.
+type pluginRateLimiter struct {
+	lock    sync.Mutex
+	plugins map[string]*pluginRate
+}
+
+// pluginRate is the pacing state of a plugin
+type pluginRate struct {
+	// nextCall is the time from which the next call to the plugin is allowed
+	nextCall time.Time
+	// load is the load of the device in percentage reported in the last response
+	load int
+}
+
+var discoveryRateLimiter = &pluginRateLimiter{
+	plugins: make(map[string]*pluginRate),
+}
+
+// getRate returns the pacing state of the plugin, caller should hold the lock
+func (p *pluginRateLimiter) getRate(plugin agmodel.Plugin) *pluginRate {
+	key := pluginSessionKey(plugin)
+	rate, ok := p.plugins[key]
+	if !ok {
+		rate = &pluginRate{}
+		p.plugins[key] = rate
+	}
+	return rate
+}
+
+// interval returns the interval between the calls to a plugin for the load of the device
+func (p *pluginRateLimiter) interval(load int) time.Duration {
+	var interval time.Duration
+	if config.Data.DiscoveryConf.PluginRequestsPerSecond > 0 {
+		interval = time.Second / time.Duration(config.Data.DiscoveryConf.PluginRequestsPerSecond)
+	}
+	maxDelay := time.Duration(config.Data.DiscoveryConf.MaxThrottleDelayInMs) * time.Millisecond
+	return interval + maxDelay*time.Duration(load)/100
+}
+
+// effectiveInterval returns the current interval between the calls to the plugin
+func (p *pluginRateLimiter) effectiveInterval(plugin agmodel.Plugin) time.Duration {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.interval(p.getRate(plugin).load)
+}
+
+// wait blocks till the next call to the plugin is allowed, the slot of the call is reserved
+// so that the calls made concurrently to the plugin are spaced by the interval
+func (p *pluginRateLimiter) wait(ctx context.Context, plugin agmodel.Plugin) error {
+	p.lock.Lock()
+	rate := p.getRate(plugin)
+	interval := p.interval(rate.load)
+	if interval <= 0 {
+		p.lock.Unlock()
+		return nil
+	}
+	now := time.Now()
+	if rate.nextCall.Before(now) {
+		rate.nextCall = now
+	}
+	delay := rate.nextCall.Sub(now)
+	rate.nextCall = rate.nextCall.Add(interval)
+	p.lock.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// setLoad records the load of the device reported through the throttle hint of the plugin
+// response. The hint is ignored when the ThrottleHintHeader isn't configured, a response without
+// the hint clears the load and the invalid hints are ignored.
+func (p *pluginRateLimiter) setLoad(plugin agmodel.Plugin, hint string) {
+	if config.Data.DiscoveryConf.ThrottleHintHeader == "" {
+		return
+	}
+	load := 0
+	if hint = strings.TrimSuffix(strings.TrimSpace(hint), "%"); hint != "" {
+		var err error
+		if load, err = strconv.Atoi(hint); err != nil {
+			return
+		}
+	}
+	if load < 0 {
+		load = 0
+	}
+	if load > 100 {
+		load = 100
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.getRate(plugin).load = load
+}
+
+// discoveryCallLimiter limits the plugin calls in flight during a discovery run. The slot of a
+// call is held only till its response is read, so the calls for the links of the resource never
+// wait on the call of the resource itself and the discovery can't deadlock however deep the tree is.
+type discoveryCallLimiter struct {
+	slots chan struct{}
+}
+
+// newDiscoveryCallLimiter returns the limiter allowing the limit number of calls in flight,
+// the calls are not limited when the limit is not positive
+func newDiscoveryCallLimiter(limit int) *discoveryCallLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &discoveryCallLimiter{
+		slots: make(chan struct{}, limit),
+	}
+}
+
+// acquire blocks till a slot is available for the call or the context is done
+func (c *discoveryCallLimiter) acquire(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of a call which is acquired
+func (c *discoveryCallLimiter) release() {
+	if c == nil {
+		return
+	}
+	<-c.slots
+}
@@ -0,0 +1,199 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pluginRateLimiterThrottleHint(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.PluginRequestsPerSecond = 20
+	config.Data.DiscoveryConf.ThrottleHintHeader = "X-Throttle-Hint"
+	config.Data.DiscoveryConf.MaxThrottleDelayInMs = 100
+	plugin := agmodel.Plugin{
+		ID:                "ThrottledPlugin",
+		IP:                "localhost",
+		Port:              "9190",
+		PreferredAuthType: "BasicAuth",
+	}
+	defer func() {
+		discoveryRateLimiter.lock.Lock()
+		delete(discoveryRateLimiter.plugins, pluginSessionKey(plugin))
+		discoveryRateLimiter.lock.Unlock()
+	}()
+	hint := "50"
+	req := getResourceRequest{
+		ContactClient: func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+			header := http.Header{}
+			if hint != "" {
+				header.Set("X-Throttle-Hint", hint)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"@odata.id":"/ODIM/v1/Systems"}`)),
+			}, nil
+		},
+		OID:            "/redfish/v1/Systems",
+		HTTPMethodType: http.MethodGet,
+		Plugin:         plugin,
+	}
+	staticInterval := 50 * time.Millisecond
+	assert.Equal(t, staticInterval, discoveryRateLimiter.effectiveInterval(plugin))
+
+	_, _, _, err := contactPlugin(mockContext(), req, "")
+	assert.Nil(t, err)
+	throttledInterval := discoveryRateLimiter.effectiveInterval(plugin)
+	assert.Equal(t, 100*time.Millisecond, throttledInterval, "throttle hint should reduce the rate of the calls")
+
+	// the calls made back to back are spaced by the throttled interval
+	startTime := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, discoveryRateLimiter.wait(mockContext(), plugin))
+	}
+	assert.True(t, time.Since(startTime) >= 2*throttledInterval, "calls should be paced with the throttled interval")
+
+	// invalid hints are ignored
+	hint = "busy"
+	contactPlugin(mockContext(), req, "")
+	assert.Equal(t, throttledInterval, discoveryRateLimiter.effectiveInterval(plugin))
+
+	// a response without hint restores the configured rate
+	hint = ""
+	contactPlugin(mockContext(), req, "")
+	assert.Equal(t, staticInterval, discoveryRateLimiter.effectiveInterval(plugin))
+
+	// the hints are capped at full load
+	discoveryRateLimiter.setLoad(plugin, "250%")
+	assert.Equal(t, staticInterval+100*time.Millisecond, discoveryRateLimiter.effectiveInterval(plugin))
+
+	// the wait is given up once the discovery is cancelled
+	ctx, cancel := context.WithCancel(mockContext())
+	cancel()
+	discoveryRateLimiter.wait(ctx, plugin)
+	assert.NotNil(t, discoveryRateLimiter.wait(ctx, plugin))
+}
+
+func Test_discoveryCallLimiterWideTree(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DiscoveryConf.RootInfoWorkerCount = 8
+	const limit, members = 3, 12
+	links := []string{"Sensors", "PCIeDevices", "NetworkAdapters", "Assembly"}
+	var lock sync.Mutex
+	fetched := make(map[string]int)
+	var activeCalls, maxActiveCalls int32
+	contactClient := func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		active := atomic.AddInt32(&activeCalls, 1)
+		defer atomic.AddInt32(&activeCalls, -1)
+		for {
+			max := atomic.LoadInt32(&maxActiveCalls)
+			if active <= max || atomic.CompareAndSwapInt32(&maxActiveCalls, max, active) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		path := url[strings.Index(url, "/ODIM/v1/"):]
+		lock.Lock()
+		fetched[path]++
+		lock.Unlock()
+		var respBody string
+		segments := strings.Split(strings.TrimPrefix(path, "/ODIM/v1/"), "/")
+		switch len(segments) {
+		case 1:
+			var memberList []string
+			for i := 1; i <= members; i++ {
+				memberList = append(memberList, `{"@odata.id":"/ODIM/v1/Chassis/`+strconv.Itoa(i)+`"}`)
+			}
+			respBody = `{"Members":[` + strings.Join(memberList, ",") + `]}`
+		case 2:
+			var linkList []string
+			for _, link := range links {
+				linkList = append(linkList, `"`+link+`":{"@odata.id":"`+path+`/`+link+`"}`)
+			}
+			respBody = `{"@odata.id":"` + path + `","Id":"` + segments[1] + `",` + strings.Join(linkList, ",") + `}`
+		default:
+			// the links back to the chassis are already traversed and must not be fetched again
+			parent := path[:strings.LastIndex(path, "/")]
+			respBody = `{"@odata.id":"` + path + `","Id":"` + segments[2] + `","Links":{"Chassis":[{"@odata.id":"` + parent + `"}]}}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(respBody)),
+		}, nil
+	}
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	req := getResourceRequest{
+		ContactClient:  contactClient,
+		OID:            "/redfish/v1/Chassis",
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: http.MethodGet,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+		InFlightCalls: newDiscoveryCallLimiter(limit),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.getAllRootInfo(mockContext(), "", 0, 40, req, config.Data.AddComputeSkipResources.SkipResourceListUnderChassis)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("discovery of the tree should not deadlock")
+	}
+	assert.True(t, maxActiveCalls <= limit, "plugin calls in flight should not exceed the limit")
+	assert.Equal(t, int32(limit), maxActiveCalls, "plugin calls should be made in parallel up to the limit")
+	assert.Len(t, fetched, 1+members+members*len(links), "every resource of the tree should be fetched")
+	for path, count := range fetched {
+		assert.Equal(t, 1, count, "resource "+path+" should be fetched once")
+	}
+	assert.Empty(t, req.InFlightCalls.slots, "every slot should be released")
+}
+
+func Test_discoveryCallLimiter(t *testing.T) {
+	assert.Nil(t, newDiscoveryCallLimiter(0), "calls should not be limited without a limit")
+	var unlimited *discoveryCallLimiter
+	assert.Nil(t, unlimited.acquire(context.Background()), "nil limiter should not block")
+	unlimited.release()
+
+	limiter := newDiscoveryCallLimiter(1)
+	assert.Nil(t, limiter.acquire(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.acquire(ctx), "acquire should give up when the context is done")
+	limiter.release()
+	assert.Nil(t, limiter.acquire(context.Background()), "released slot should be reused")
+}
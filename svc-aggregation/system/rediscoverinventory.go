@@ -45,6 +45,10 @@ func (e *ExternalInterface) RediscoverSystemInventory(ctx context.Context, devic
 	l.LogWithFields(ctx).Info("Rediscovery of the BMC with ID " + deviceUUID + " is started.")
 
 	var resp response.RPC
+	var managerAddress string
+	defer func() {
+		auditInventoryChange(ctx, inventoryAuditRediscover, systemURL, managerAddress, "", resp)
+	}()
 	systemURL = strings.TrimSuffix(systemURL, "/")
 	data := strings.Split(systemURL, "/")
 	// Getting the SystemID from system url
@@ -73,6 +77,7 @@ func (e *ExternalInterface) RediscoverSystemInventory(ctx context.Context, devic
 		return
 	}
 	target.Password = decryptedPasswordByte
+	managerAddress = target.ManagerAddress
 
 	// get the plugin information
 	plugin, errs := agmodel.GetPluginData(target.PluginID)
@@ -87,10 +92,12 @@ func (e *ExternalInterface) RediscoverSystemInventory(ctx context.Context, devic
 	var req getResourceRequest
 	req.ContactClient = e.ContactClient
 	req.GetPluginStatus = e.GetPluginStatus
+	req.DiscoveryMetricHook = e.DiscoveryMetricHook
+	req.InFlightCalls = newDiscoveryCallLimiter(config.Data.DiscoveryConf.MaxInFlightPluginCalls)
 	req.Plugin = plugin
 	req.StatusPoll = true
 	req.BMCAddress = target.ManagerAddress
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		var err error
 		req.HTTPMethodType = http.MethodPost
 		req.DeviceInfo = map[string]interface{}{
@@ -161,11 +168,14 @@ func (e *ExternalInterface) RediscoverSystemInventory(ctx context.Context, devic
 		req.OID = "/redfish/v1/Chassis"
 		chassisEstimatedWork := int32(15)
 		progress = h.getAllRootInfo(ctx, "", progress, chassisEstimatedWork, req, config.Data.AddComputeSkipResources.SkipResourceListUnderChassis)
+		progress = h.getChassisAssetInfo(ctx, "", progress, 0, req)
 
 		//rediscovering the Manager Information
 		req.OID = "/redfish/v1/Managers"
 		managerEstimatedWork := int32(15)
 		progress = h.getAllRootInfo(ctx, "", progress, managerEstimatedWork, req, config.Data.AddComputeSkipResources.SkipResourceListUnderManager)
+		progress = h.getVirtualMediaInfo(ctx, "", progress, 0, req)
+		progress = h.getManagerProtocolInfo(ctx, "", progress, 0, req)
 		agmodel.SaveBMCInventory(h.InventoryData)
 	}
 
@@ -203,6 +213,33 @@ func (e *ExternalInterface) RediscoverResources() error {
 		return nil
 	}
 
+	e.rediscoverTargets(ctx, targets, false)
+	// if everything is OK return success
+	return nil
+
+}
+
+// RediscoverPluginServers rediscovers the inventory of all the servers managed by the plugin,
+// irrespective of whether the inventory is present in the InMemory DB
+func (e *ExternalInterface) RediscoverPluginServers(ctx context.Context, pluginID string) {
+	targets, err := agmodel.GetAllSystems()
+	if err != nil {
+		l.LogWithFields(ctx).Error("failed to get the servers managed by the plugin " + pluginID + ": " + err.Error())
+		return
+	}
+	var pluginTargets []agmodel.Target
+	for _, target := range targets {
+		if target.PluginID == pluginID {
+			pluginTargets = append(pluginTargets, target)
+		}
+	}
+	l.LogWithFields(ctx).Infof("rediscovering %d servers managed by the plugin %s", len(pluginTargets), pluginID)
+	e.rediscoverTargets(ctx, pluginTargets, true)
+}
+
+// rediscoverTargets rediscovers the systems of the targets in batches of ServerRediscoveryBatchSize,
+// the systems which are already in the InMemory DB are rediscovered only when forced
+func (e *ExternalInterface) rediscoverTargets(ctx context.Context, targets []agmodel.Target, force bool) {
 	serverBatchSize := config.Data.ServerRediscoveryBatchSize
 	if config.Data.ServerRediscoveryBatchSize <= 0 {
 		serverBatchSize = 1
@@ -234,7 +271,7 @@ func (e *ExternalInterface) RediscoverResources() error {
 			var systemURLArray []string
 			for _, member := range members.([]interface{}) {
 				systemURL := member.(map[string]interface{})["@odata.id"].(string)
-				if e.isServerRediscoveryRequired(ctxt, target.DeviceUUID, systemURL) == true {
+				if force || e.isServerRediscoveryRequired(ctxt, target.DeviceUUID, systemURL) == true {
 					e.RediscoverSystemInventory(ctxt, target.DeviceUUID, systemURL, true)
 					systemURLArray = append(systemURLArray, systemURL)
 				}
@@ -242,9 +279,6 @@ func (e *ExternalInterface) RediscoverResources() error {
 			e.publishResourceUpdatedEvent(ctxt, systemURLArray, "SystemsCollection")
 		}(ctxt, targets[index])
 	}
-	// if everything is OK return success
-	return nil
-
 }
 func (e *ExternalInterface) getTargetSystemCollection(ctx context.Context, target agmodel.Target) ([]byte, error) {
 
@@ -263,9 +297,11 @@ func (e *ExternalInterface) getTargetSystemCollection(ctx context.Context, targe
 	var req getResourceRequest
 	req.ContactClient = e.ContactClient
 	req.GetPluginStatus = e.GetPluginStatus
+	req.DiscoveryMetricHook = e.DiscoveryMetricHook
+	req.InFlightCalls = newDiscoveryCallLimiter(config.Data.DiscoveryConf.MaxInFlightPluginCalls)
 	req.Plugin = plugin
 	req.StatusPoll = true
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		var err error
 		req.HTTPMethodType = http.MethodPost
 		req.DeviceInfo = map[string]interface{}{
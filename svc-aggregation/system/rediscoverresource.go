@@ -0,0 +1,225 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+// rediscoverableCollections are the root collections whose resources can be rediscovered individually
+var rediscoverableCollections = []string{"Systems", "Chassis", "Managers"}
+
+// RediscoverResource rediscovers the resource of the server with the OID along with the resources
+// under it, so that a resource can be refreshed after a hardware change without rediscovering the
+// whole server. The other resources of the server are left untouched. The search index of the system
+// is rebuilt when the resource is a ComputerSystem or a Storage of it
+func (e *ExternalInterface) RediscoverResource(ctx context.Context, deviceUUID, oid string) (resp response.RPC) {
+	oid = strings.TrimSuffix(oid, "/")
+	var managerAddress string
+	defer func() {
+		auditInventoryChange(ctx, inventoryAuditRediscover, oid, managerAddress, "", resp)
+	}()
+	pluginOID, resourceID, ok := getDeviceResourceOID(deviceUUID, oid)
+	if !ok {
+		errMsg := "resource " + oid + " doesn't belong to the server " + deviceUUID
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"Resource", oid}, nil)
+	}
+	target, err := agmodel.GetTarget(deviceUUID)
+	if err != nil {
+		errMsg := "error while trying to get the server " + deviceUUID + ": " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusNotFound, response.ResourceNotFound, errMsg, []interface{}{"AggregationSource", deviceUUID}, nil)
+	}
+	managerAddress = target.ManagerAddress
+	req, err := e.getTargetPluginRequest(ctx, target)
+	if err != nil {
+		errMsg := "error while trying to rediscover " + oid + ": " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+	req.OID = pluginOID
+	req.SystemID = resourceID
+	req.UpdateFlag = true
+
+	l.LogWithFields(ctx).Info("Rediscovery of the resource " + oid + " is started.")
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	resource, err := h.rediscoverResource(ctx, req, oid)
+	if err != nil {
+		l.LogWithFields(ctx).Error(err.Error())
+		return common.GeneralError(h.StatusCode, h.StatusMessage, err.Error(), h.MsgArgs, nil)
+	}
+	if err := agmodel.SaveBMCInventory(h.InventoryData); err != nil {
+		errMsg := "error while trying to save the rediscovered resources of " + oid + ": " + err.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
+	if systemURI, ok := getIndexedSystemURI(deviceUUID, oid); ok {
+		if err := reindexSystem(ctx, systemURI, deviceUUID, target.ManagerAddress); err != nil {
+			l.LogWithFields(ctx).Error(err.Error())
+			return common.GeneralError(http.StatusInternalServerError, response.InternalError, err.Error(), nil, nil)
+		}
+	}
+	l.LogWithFields(ctx).Info("Rediscovery of the resource " + oid + " is now complete.")
+	return response.RPC{
+		StatusCode:    http.StatusOK,
+		StatusMessage: response.Success,
+		Body:          resource,
+	}
+}
+
+// rediscoverResource discovers the resource of the request and the resources under it, the links
+// to the other resources are not traversed. The inventory data of the resource is returned
+func (h *respHolder) rediscoverResource(ctx context.Context, req getResourceRequest, oid string) (map[string]interface{}, error) {
+	h.scope = req.OID
+	h.getResourceDetails(ctx, "", 0, 100, req)
+	resourceKey := ""
+	for key := range h.InventoryData {
+		table := strings.SplitN(key, ":", 2)[0]
+		if key == table+":"+oid && table != agmodel.ResourceTypeTable {
+			resourceKey = key
+			break
+		}
+	}
+	if resourceKey == "" || h.hasFatalError() {
+		if h.ErrorMessage == "" {
+			h.StatusCode = http.StatusInternalServerError
+			h.StatusMessage = response.InternalError
+			h.ErrorMessage = "resource " + oid + " is not rediscovered"
+			// the errors recorded as warnings are reported, as nothing is rediscovered
+			if len(h.Warnings) > 0 {
+				h.ErrorMessage += ": " + h.Warnings[0]
+			}
+		}
+		return nil, fmt.Errorf("error while trying to rediscover %s: %s", oid, h.ErrorMessage)
+	}
+	// the systems are saved in the ComputerSystem table, not in the table of the collection
+	if resourceKey == "Systems:"+oid {
+		h.InventoryData["ComputerSystem:"+oid] = h.InventoryData[resourceKey]
+		delete(h.InventoryData, resourceKey)
+		resourceKey = "ComputerSystem:" + oid
+	}
+	var resource map[string]interface{}
+	data, _ := h.InventoryData[resourceKey].(string)
+	if err := decodeJSON([]byte(data), &resource); err != nil {
+		h.StatusCode = http.StatusInternalServerError
+		h.StatusMessage = response.InternalError
+		h.ErrorMessage = err.Error()
+		return nil, fmt.Errorf("error while trying to decode the rediscovered %s: %v", oid, err)
+	}
+	return resource, nil
+}
+
+// getDeviceResourceOID validates the resource with the OID belongs to the server and returns the OID
+// of the resource in the plugin along with the ID of its System, Chassis or Manager
+func getDeviceResourceOID(deviceUUID, oid string) (pluginOID, resourceID string, ok bool) {
+	for _, collection := range rediscoverableCollections {
+		collectionURI := "/redfish/v1/" + collection + "/"
+		if !strings.HasPrefix(oid, collectionURI+deviceUUID+".") {
+			continue
+		}
+		pluginOID = collectionURI + strings.TrimPrefix(oid, collectionURI+deviceUUID+".")
+		resourceID = strings.SplitN(strings.TrimPrefix(pluginOID, collectionURI), "/", 2)[0]
+		if resourceID == "" {
+			return "", "", false
+		}
+		return pluginOID, resourceID, true
+	}
+	return "", "", false
+}
+
+// getIndexedSystemURI returns the URI of the system whose search index is to be rebuilt
+// after rediscovering the resource, it is either the system or a storage of it
+func getIndexedSystemURI(deviceUUID, oid string) (string, bool) {
+	systemsURI := "/redfish/v1/Systems/" + deviceUUID + "."
+	if !strings.HasPrefix(oid, systemsURI) {
+		return "", false
+	}
+	path := strings.Split(strings.TrimPrefix(oid, systemsURI), "/")
+	if len(path) == 1 || path[1] == "Storage" {
+		return systemsURI + path[0], true
+	}
+	return "", false
+}
+
+// reindexSystem rebuilds the search index of the saved system
+func reindexSystem(ctx context.Context, systemURI, deviceUUID, bmcAddress string) error {
+	computeSystem, ok := getSavedResource(systemURI)
+	if !ok {
+		return fmt.Errorf("error while trying to get the system %s for rebuilding its search index", systemURI)
+	}
+	systemUUID, _ := computeSystem["UUID"].(string)
+	searchForm := createServerSearchIndex(ctx, computeSystem, systemURI, deviceUUID)
+	if err := agmodel.UpdateIndex(searchForm, systemURI, systemUUID, bmcAddress); err != nil {
+		return fmt.Errorf("error while trying to rebuild the search index of the system %s: %v", systemURI, err)
+	}
+	return nil
+}
+
+// getTargetPluginRequest returns the request for contacting the plugin of the server
+func (e *ExternalInterface) getTargetPluginRequest(ctx context.Context, target *agmodel.Target) (getResourceRequest, error) {
+	var req getResourceRequest
+	decryptedPasswordByte, err := e.DecryptPassword(target.Password)
+	if err != nil {
+		return req, fmt.Errorf("error while trying to decrypt device password: %v", err)
+	}
+	target.Password = decryptedPasswordByte
+	plugin, errs := agmodel.GetPluginData(target.PluginID)
+	if errs != nil {
+		return req, errs
+	}
+	req.ContactClient = e.ContactClient
+	req.GetPluginStatus = e.GetPluginStatus
+	req.DiscoveryMetricHook = e.DiscoveryMetricHook
+	req.InFlightCalls = newDiscoveryCallLimiter(config.Data.DiscoveryConf.MaxInFlightPluginCalls)
+	req.UpdateTask = e.UpdateTask
+	req.Plugin = plugin
+	req.StatusPoll = true
+	req.BMCAddress = target.ManagerAddress
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
+		req.HTTPMethodType = http.MethodPost
+		req.DeviceInfo = map[string]interface{}{
+			"UserName": plugin.Username,
+			"Password": string(plugin.Password),
+		}
+		req.OID = "/ODIM/v1/Sessions"
+		_, token, _, err := contactPlugin(ctx, req, "error while getting the details "+req.OID+": ")
+		if err != nil {
+			return req, err
+		}
+		req.Token = token
+	} else {
+		req.LoginCredentials = map[string]string{
+			"UserName": plugin.Username,
+			"Password": string(plugin.Password),
+		}
+	}
+	req.HTTPMethodType = http.MethodGet
+	req.DeviceUUID = target.DeviceUUID
+	req.DeviceInfo = target
+	return req, nil
+}
@@ -0,0 +1,208 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockRediscoveryClient(contactedURLs *[]string) func(context.Context, string, string, string, string, interface{}, map[string]string) (*http.Response, error) {
+	resources := map[string]string{
+		"/ODIM/v1/Systems/1":                    `{"@odata.id":"/ODIM/v1/Systems/1","Id":"1","UUID":"someuuid","Storage":{"@odata.id":"/ODIM/v1/Systems/1/Storage"}}`,
+		"/ODIM/v1/Systems/1/Storage":            `{"@odata.id":"/ODIM/v1/Systems/1/Storage","Members":[{"@odata.id":"/ODIM/v1/Systems/1/Storage/1"}]}`,
+		"/ODIM/v1/Systems/1/Storage/1":          `{"@odata.id":"/ODIM/v1/Systems/1/Storage/1","Id":"1","Drives":[{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Drives/0"}],"Links":{"Enclosures":[{"@odata.id":"/ODIM/v1/Chassis/1"}]}}`,
+		"/ODIM/v1/Systems/1/Storage/1/Drives/0": `{"@odata.id":"/ODIM/v1/Systems/1/Storage/1/Drives/0","Id":"0","Links":{"Chassis":{"@odata.id":"/ODIM/v1/Chassis/1"}}}`,
+		"/ODIM/v1/Chassis/1":                    `{"@odata.id":"/ODIM/v1/Chassis/1","Id":"1","Links":{"ComputerSystems":[{"@odata.id":"/ODIM/v1/Systems/1"}]}}`,
+	}
+	return func(ctx context.Context, url, method, token string, odataID string, body interface{}, credentials map[string]string) (*http.Response, error) {
+		*contactedURLs = append(*contactedURLs, url)
+		for oid, respBody := range resources {
+			if strings.HasSuffix(url, oid) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(respBody)),
+				}, nil
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error":"not found"}`)),
+		}, nil
+	}
+}
+
+func Test_rediscoverResource(t *testing.T) {
+	config.SetUpMockConfig(t)
+	deviceUUID := "someuuid"
+	tests := []struct {
+		name         string
+		oid          string
+		wantKeys     []string
+		wantContacts int
+	}{
+		{
+			name: "storage of the system",
+			oid:  "/redfish/v1/Systems/someuuid.1/Storage/1",
+			wantKeys: []string{
+				"Storage:/redfish/v1/Systems/someuuid.1/Storage/1",
+				"Drives:/redfish/v1/Systems/someuuid.1/Storage/1/Drives/0",
+			},
+			wantContacts: 2,
+		},
+		{
+			name: "system",
+			oid:  "/redfish/v1/Systems/someuuid.1",
+			wantKeys: []string{
+				"ComputerSystem:/redfish/v1/Systems/someuuid.1",
+				"StorageCollection:/redfish/v1/Systems/someuuid.1/Storage",
+				"Storage:/redfish/v1/Systems/someuuid.1/Storage/1",
+				"Drives:/redfish/v1/Systems/someuuid.1/Storage/1/Drives/0",
+			},
+			wantContacts: 4,
+		},
+		{
+			name:         "chassis",
+			oid:          "/redfish/v1/Chassis/someuuid.1",
+			wantKeys:     []string{"Chassis:/redfish/v1/Chassis/someuuid.1"},
+			wantContacts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contactedURLs []string
+			pluginOID, resourceID, ok := getDeviceResourceOID(deviceUUID, tt.oid)
+			assert.True(t, ok)
+			req := getResourceRequest{
+				ContactClient:  mockRediscoveryClient(&contactedURLs),
+				OID:            pluginOID,
+				SystemID:       resourceID,
+				DeviceUUID:     deviceUUID,
+				HTTPMethodType: http.MethodGet,
+				UpdateFlag:     true,
+				Plugin: agmodel.Plugin{
+					IP:                "localhost",
+					Port:              "9091",
+					PreferredAuthType: "BasicAuth",
+				},
+			}
+			h := &respHolder{
+				TraversedLinks: make(map[string]bool),
+				InventoryData:  make(map[string]interface{}),
+			}
+			resource, err := h.rediscoverResource(mockContext(), req, tt.oid)
+			assert.Nil(t, err)
+			assert.Equal(t, resourceID, resource["Id"])
+			var keys []string
+			for key := range h.InventoryData {
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, tt.wantKeys, keys, "only the resources under the rediscovered resource should be updated")
+			assert.Len(t, contactedURLs, tt.wantContacts, "the resources outside the rediscovered resource should not be fetched")
+		})
+	}
+}
+
+func Test_rediscoverResourceNotFound(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var contactedURLs []string
+	req := getResourceRequest{
+		ContactClient:  mockRediscoveryClient(&contactedURLs),
+		OID:            "/redfish/v1/Managers/1",
+		SystemID:       "1",
+		DeviceUUID:     "someuuid",
+		HTTPMethodType: http.MethodGet,
+		UpdateFlag:     true,
+		Plugin: agmodel.Plugin{
+			IP:                "localhost",
+			Port:              "9091",
+			PreferredAuthType: "BasicAuth",
+		},
+	}
+	h := &respHolder{
+		TraversedLinks: make(map[string]bool),
+		InventoryData:  make(map[string]interface{}),
+	}
+	_, err := h.rediscoverResource(mockContext(), req, "/redfish/v1/Managers/someuuid.1")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(http.StatusNotFound), h.StatusCode)
+	assert.Empty(t, h.InventoryData)
+}
+
+func Test_getDeviceResourceOID(t *testing.T) {
+	tests := []struct {
+		name           string
+		oid            string
+		wantPluginOID  string
+		wantResourceID string
+		wantOK         bool
+	}{
+		{
+			name:           "drive of the system",
+			oid:            "/redfish/v1/Systems/someuuid.1/Storage/1/Drives/0",
+			wantPluginOID:  "/redfish/v1/Systems/1/Storage/1/Drives/0",
+			wantResourceID: "1",
+			wantOK:         true,
+		},
+		{
+			name:           "manager",
+			oid:            "/redfish/v1/Managers/someuuid.bmc",
+			wantPluginOID:  "/redfish/v1/Managers/bmc",
+			wantResourceID: "bmc",
+			wantOK:         true,
+		},
+		{
+			name: "resource of another server",
+			oid:  "/redfish/v1/Systems/otheruuid.1",
+		},
+		{
+			name: "resource outside the root collections",
+			oid:  "/redfish/v1/TaskService/Tasks/someuuid.1",
+		},
+		{
+			name: "resource without id",
+			oid:  "/redfish/v1/Chassis/someuuid.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginOID, resourceID, ok := getDeviceResourceOID("someuuid", tt.oid)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantPluginOID, pluginOID)
+			assert.Equal(t, tt.wantResourceID, resourceID)
+		})
+	}
+}
+
+func Test_getIndexedSystemURI(t *testing.T) {
+	systemURI, ok := getIndexedSystemURI("someuuid", "/redfish/v1/Systems/someuuid.1/Storage/1/Drives/0")
+	assert.True(t, ok)
+	assert.Equal(t, "/redfish/v1/Systems/someuuid.1", systemURI)
+	systemURI, ok = getIndexedSystemURI("someuuid", "/redfish/v1/Systems/someuuid.1")
+	assert.True(t, ok)
+	assert.Equal(t, "/redfish/v1/Systems/someuuid.1", systemURI)
+	_, ok = getIndexedSystemURI("someuuid", "/redfish/v1/Systems/someuuid.1/Processors/1")
+	assert.False(t, ok, "search index should not be rebuilt for the resources other than the storage")
+	_, ok = getIndexedSystemURI("someuuid", "/redfish/v1/Chassis/someuuid.1")
+	assert.False(t, ok)
+}
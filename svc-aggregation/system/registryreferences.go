@@ -0,0 +1,114 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/svc-aggregation/agmodel"
+)
+
+// GetRegistryFileFunc function pointer for the agmodel.GetRegistryFile
+var GetRegistryFileFunc = agmodel.GetRegistryFile
+
+// findMissingRegistries scans the discovered resources for the MessageIds and returns the
+// registries referenced by them which are neither in the registry store, nor discovered
+// from the server, nor in DB. Messages of such registries can't be resolved at runtime,
+// so the missing registries are recorded as warnings of the discovery.
+func (h *respHolder) findMissingRegistries(ctx context.Context) []string {
+	var standardFiles []string
+	if regFiles, err := ioutil.ReadDir(config.Data.RegistryStorePath); err == nil {
+		for _, regFile := range regFiles {
+			standardFiles = append(standardFiles, regFile.Name())
+		}
+	}
+	var discoveredRegistries []string
+	var referencedRegistries = make(map[string]bool)
+	h.lock.Lock()
+	for key, data := range h.InventoryData {
+		if strings.HasPrefix(key, "Registries:") {
+			discoveredRegistries = append(discoveredRegistries, strings.TrimPrefix(key, "Registries:"))
+			continue
+		}
+		resourceData, ok := data.(string)
+		if !ok || !strings.Contains(resourceData, "MessageId") {
+			continue
+		}
+		var resource interface{}
+		if err := json.Unmarshal([]byte(resourceData), &resource); err != nil {
+			continue
+		}
+		getReferencedRegistries(resource, referencedRegistries)
+	}
+	h.lock.Unlock()
+
+	var missingRegistries []string
+	for registry := range referencedRegistries {
+		if isRegistryPresent(registry, standardFiles) || isRegistryPresent(registry, discoveredRegistries) {
+			continue
+		}
+		if _, err := GetRegistryFileFunc("Registries", registry+".json"); err == nil {
+			continue
+		}
+		missingRegistries = append(missingRegistries, registry)
+	}
+	sort.Strings(missingRegistries)
+	for _, registry := range missingRegistries {
+		warning := "registry " + registry + " referenced by the discovered resources is not available"
+		l.LogWithFields(ctx).Warn(warning)
+		h.lock.Lock()
+		h.Warnings = append(h.Warnings, warning)
+		h.lock.Unlock()
+	}
+	return missingRegistries
+}
+
+// getReferencedRegistries adds the registries of the MessageIds in the resource to registries.
+// MessageId is in the format RegistryName.MajorVersion.MinorVersion.MessageKey
+func getReferencedRegistries(resource interface{}, registries map[string]bool) {
+	switch value := resource.(type) {
+	case map[string]interface{}:
+		for key, property := range value {
+			if messageID, ok := property.(string); ok && key == "MessageId" {
+				if index := strings.LastIndex(messageID, "."); index > 0 {
+					registries[messageID[:index]] = true
+				}
+				continue
+			}
+			getReferencedRegistries(property, registries)
+		}
+	case []interface{}:
+		for _, property := range value {
+			getReferencedRegistries(property, registries)
+		}
+	}
+}
+
+// isRegistryPresent checks if any of the registry files is of the registry, the file names
+// can have the errata version too, like Base.1.13.0.json for the registry Base.1.13
+func isRegistryPresent(registry string, files []string) bool {
+	for _, file := range files {
+		if file == registry+".json" || strings.HasPrefix(file, registry+".") {
+			return true
+		}
+	}
+	return false
+}
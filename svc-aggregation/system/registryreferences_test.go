@@ -0,0 +1,49 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findMissingRegistries(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func(getRegistryFile func(string, string) (string, *errors.Error)) {
+		GetRegistryFileFunc = getRegistryFile
+	}(GetRegistryFileFunc)
+	GetRegistryFileFunc = func(table, key string) (string, *errors.Error) {
+		if key == "Stored.1.0.json" {
+			return "{}", nil
+		}
+		return "", errors.PackError(errors.DBKeyNotFound, "no data with the with key "+key+" found")
+	}
+	h := &respHolder{
+		InventoryData: map[string]interface{}{
+			"Registries:Oem.2.1.json": `{"Id":"Oem.2.1.0"}`,
+			"LogEntry:/redfish/v1/Systems/uuid.1/LogServices/SEL/Entries/1": `{"@odata.id":"/redfish/v1/Systems/uuid.1/LogServices/SEL/Entries/1","MessageId":"Base.1.13.Success"}`,
+			"LogEntry:/redfish/v1/Systems/uuid.1/LogServices/SEL/Entries/2": `{"@odata.id":"/redfish/v1/Systems/uuid.1/LogServices/SEL/Entries/2","MessageId":"Oem.2.1.FanFailed"}`,
+			"LogEntry:/redfish/v1/Systems/uuid.1/LogServices/SEL/Entries/3": `{"@odata.id":"/redfish/v1/Systems/uuid.1/LogServices/SEL/Entries/3","MessageId":"Stored.1.0.Event"}`,
+			"ComputerSystem:/redfish/v1/Systems/uuid.1":                     `{"@odata.id":"/redfish/v1/Systems/uuid.1","Status":{"Conditions":[{"MessageId":"Vendor.3.0.DriveFailed"}]}}`,
+		},
+	}
+
+	missing := h.findMissingRegistries(mockContext())
+	assert.Equal(t, []string{"Vendor.3.0"}, missing, "only the registry which is not available should be reported")
+	assert.Len(t, h.Warnings, 1, "missing registry should be recorded as a warning")
+}
@@ -54,7 +54,7 @@ func (e *ExternalInterface) UpdateSystemState(ctx context.Context, updateReq *ag
 	req.GetPluginStatus = e.GetPluginStatus
 	req.Plugin = plugin
 	req.StatusPoll = true
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		var err error
 		req.HTTPMethodType = http.MethodPost
 		req.DeviceInfo = map[string]interface{}{
@@ -0,0 +1,70 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+)
+
+// retryTaskCreation calls createTask till it succeeds or the configured retries are exhausted,
+// the interval between the retries is doubled every time. The error of the last attempt is
+// returned after the exhaustion of the retries or when the context is done while waiting.
+func retryTaskCreation(ctx context.Context, createTask func() (string, error)) (string, error) {
+	maxRetries := config.Data.TaskCreationConf.MaxRetryAttempts
+	interval := time.Duration(config.Data.TaskCreationConf.RetryIntervalInMillisecs) * time.Millisecond
+	var attempt int
+	for {
+		taskURI, err := createTask()
+		if err == nil {
+			return taskURI, nil
+		}
+		if attempt >= maxRetries {
+			return "", fmt.Errorf("failed to create the task after %d attempts: %v", attempt+1, err)
+		}
+		attempt++
+		l.LogWithFields(ctx).Warnf("failed to create the task, retrying in %v (%d/%d): %v", interval, attempt, maxRetries, err)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return "", fmt.Errorf("failed to create the task: %v: %v", ctx.Err(), err)
+		}
+		interval *= 2
+	}
+}
+
+// WithCreateTaskRetry returns the createTask function which retries the task creation
+// on failure, so that a brief unavailability of the task service doesn't fail the request
+func WithCreateTaskRetry(createTask func(context.Context, string) (string, error)) func(context.Context, string) (string, error) {
+	return func(ctx context.Context, sessionUserName string) (string, error) {
+		return retryTaskCreation(ctx, func() (string, error) {
+			return createTask(ctx, sessionUserName)
+		})
+	}
+}
+
+// WithCreateChildTaskRetry returns the createChildTask function which retries the child
+// task creation on failure
+func WithCreateChildTaskRetry(createChildTask func(context.Context, string, string) (string, error)) func(context.Context, string, string) (string, error) {
+	return func(ctx context.Context, sessionUserName, parentTaskID string) (string, error) {
+		return retryTaskCreation(ctx, func() (string, error) {
+			return createChildTask(ctx, sessionUserName, parentTaskID)
+		})
+	}
+}
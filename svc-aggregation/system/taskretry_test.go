@@ -0,0 +1,51 @@
+//(C) Copyright [2020] Hewlett Packard Enterprise Development LP
+//
+//Licensed under the Apache License, Version 2.0 (the "License"); you may
+//not use this file except in compliance with the License. You may obtain
+//a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+//WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+//License for the specific language governing permissions and limitations
+// under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ODIM-Project/ODIM/lib-utilities/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCreateTaskRetry(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var attempts int
+	// task service is unavailable for the first two attempts
+	createTask := WithCreateTaskRetry(func(ctx context.Context, sessionUserName string) (string, error) {
+		attempts++
+		if attempts <= 2 {
+			return "", fmt.Errorf("rpc error: task service unavailable")
+		}
+		return "/redfish/v1/TaskService/Tasks/task1", nil
+	})
+	taskURI, err := createTask(mockContext(), "admin")
+	assert.Nil(t, err, "task creation should succeed after the retries")
+	assert.Equal(t, "/redfish/v1/TaskService/Tasks/task1", taskURI)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	createChildTask := WithCreateChildTaskRetry(func(ctx context.Context, sessionUserName, parentTaskID string) (string, error) {
+		attempts++
+		return "", fmt.Errorf("rpc error: task service unavailable")
+	})
+	_, err = createChildTask(mockContext(), "admin", "task1")
+	assert.NotNil(t, err, "task creation should fail once the retries are exhausted")
+	assert.Contains(t, err.Error(), "after 3 attempts")
+	assert.Equal(t, config.Data.TaskCreationConf.MaxRetryAttempts+1, attempts)
+}
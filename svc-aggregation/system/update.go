@@ -165,7 +165,12 @@ func (e *ExternalInterface) updateAggregationSourceWithConnectionMethod(ctx cont
 		}
 		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errorMessage, nil, nil)
 	}
-	cmVariants := getConnectionMethodVariants(connectionMethod.ConnectionMethodVariant)
+	cmVariants, cmErr := getConnectionMethodVariants(connectionMethod.ConnectionMethodVariant)
+	if cmErr != nil {
+		errMsg := "Unable to get the connection method variant: " + cmErr.Error()
+		l.LogWithFields(ctx).Error(errMsg)
+		return common.GeneralError(http.StatusInternalServerError, response.InternalError, errMsg, nil, nil)
+	}
 	var data = strings.Split(url, "/redfish/v1/AggregationService/AggregationSources/")
 	uuid := url[strings.LastIndexByte(url, '/')+1:]
 	uuidData := strings.SplitN(uuid, ".", 2)
@@ -194,7 +199,7 @@ func (e *ExternalInterface) updateManagerAggregationSource(ctx context.Context,
 
 	pluginContactRequest.Plugin = plugin
 	pluginContactRequest.StatusPoll = true
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		pluginContactRequest.HTTPMethodType = http.MethodPost
 		pluginContactRequest.DeviceInfo = map[string]interface{}{
 			"Username": plugin.Username,
@@ -309,7 +314,7 @@ func (e *ExternalInterface) updateBMCAggregationSource(ctx context.Context, aggr
 	pluginContactRequest.Plugin = plugin
 	pluginContactRequest.StatusPoll = true
 
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		var err error
 		pluginContactRequest.HTTPMethodType = http.MethodPost
 		pluginContactRequest.DeviceInfo = map[string]interface{}{
@@ -436,6 +441,10 @@ func (e *ExternalInterface) updateBMCAggregationSource(ctx context.Context, aggr
 	}
 	saveSystem.Password = ciphertext
 	updateRequest["Password"] = ciphertext
+	// retaining the time at which the system was discovered
+	if target, err := agmodel.GetTarget(aggregationSourceID); err == nil {
+		saveSystem.DiscoveredAt = target.DiscoveredAt
+	}
 	dbErr := agmodel.UpdateSystemData(saveSystem, aggregationSourceID)
 	if dbErr != nil {
 		errMsg := "Unable to update system info: " + dbErr.Error()
@@ -26,7 +26,7 @@ import (
 
 // GetRegistryFile fetches a resource from database using table and key
 func GetRegistryFile(ctx context.Context, Table, key string) ([]byte, *errors.Error) {
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType(Table, common.InMemory))
 	if err != nil {
 		return nil, errors.PackError(err.ErrNo(), err)
 	}
@@ -46,7 +46,7 @@ func GetRegistryFile(ctx context.Context, Table, key string) ([]byte, *errors.Er
 // GetAllRegistryFileNamesFromDB return all key in given table
 func GetAllRegistryFileNamesFromDB(ctx context.Context, table string) ([]string, *errors.Error) {
 
-	conn, err := common.GetDBConnection(common.InMemory)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return nil, err
 	}
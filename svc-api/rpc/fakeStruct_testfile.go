@@ -176,6 +176,10 @@ func (fakeStruct) DeleteAggregateSubscriptionsRPC(ctx context.Context, in *event
 
 	return nil, errors.New("fakeError")
 }
+func (fakeStruct) IsEMBConsumed(ctx context.Context, in *events.SubscribeEMBRequest, opts ...grpc.CallOption) (*events.SubscribeEMBResponse, error) {
+
+	return nil, errors.New("fakeError")
+}
 
 //--------------------------------CHASSIS--------------------------------
 
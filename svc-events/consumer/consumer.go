@@ -19,12 +19,14 @@ package consumer
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	dc "github.com/ODIM-Project/ODIM/lib-messagebus/datacommunicator"
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
 	"github.com/ODIM-Project/ODIM/lib-utilities/config"
 	l "github.com/ODIM-Project/ODIM/lib-utilities/logs"
+	"github.com/ODIM-Project/ODIM/svc-events/evmodel"
 )
 
 var (
@@ -40,6 +42,13 @@ var (
 	CtrlMsgProcQueue <-chan interface{}
 )
 
+// EMBProbeEventType is the type of the probe events published on the EMB topics to
+// verify they are consumed, the probes aren't forwarded to the subscribers
+const EMBProbeEventType = "EMBProbe"
+
+// SaveEMBProbeFunc is pointer function evmodel.SaveEMBProbe
+var SaveEMBProbeFunc = evmodel.SaveEMBProbe
+
 // EventSubscriber consume messages from PMB
 func EventSubscriber(event interface{}) {
 	byteData, _ := json.Marshal(&event)
@@ -50,9 +59,36 @@ func EventSubscriber(event interface{}) {
 		l.Log.Error("error while unmarshaling the event" + err.Error())
 		return
 	}
+	if message.EventType == EMBProbeEventType {
+		if err := SaveEMBProbeFunc(string(message.Request)); err != nil {
+			l.Log.Error("error while recording the consumed probe: " + err.Error())
+		}
+		return
+	}
 	writeEventToJobQueue(message)
 }
 
+// PublishProbe publishes the probe event with the ID on the topic, the probe
+// is recorded in DB by the consumer of the topic
+func PublishProbe(topicName, probeID string) error {
+	config.TLSConfMutex.RLock()
+	MessageBusConfigFilePath := config.Data.MessageBusConf.MessageBusConfigFilePath
+	messagebusType := config.Data.MessageBusConf.MessageBusType
+	config.TLSConfMutex.RUnlock()
+	k, err := dc.Communicator(messagebusType, MessageBusConfigFilePath, topicName)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the message bus: %s", err.Error())
+	}
+	probe := common.Events{
+		EventType: EMBProbeEventType,
+		Request:   []byte(probeID),
+	}
+	if err := k.Distribute(probe); err != nil {
+		return fmt.Errorf("unable to publish the probe on %s: %s", topicName, err.Error())
+	}
+	return nil
+}
+
 // writeEventToJobQueue align events to job queue
 func writeEventToJobQueue(message common.Events) {
 	// events contains a slice of event subscribed from kafka
@@ -98,6 +134,19 @@ func Consume(topicName string) {
 		l.Log.Error("Unable to connect to kafka" + err.Error())
 		return
 	}
+	config.TLSConfMutex.RLock()
+	resumeFromOffset := config.Data.EventConf.ResumeFromStoredOffset
+	persistInterval := time.Duration(config.Data.EventConf.OffsetPersistIntervalSecs) * time.Second
+	config.TLSConfMutex.RUnlock()
+	if resumeFromOffset {
+		if offsetConsumer, ok := k.(dc.OffsetConsumer); ok {
+			if err := offsetConsumer.AcceptFromOffset(EventSubscriber, topicOffsetStore{}, persistInterval); err != nil {
+				l.Log.Error(err.Error())
+			}
+			return
+		}
+		l.Log.Warn("resuming the consumption from the stored offset is not supported by " + messagebusType + ", consuming the topic " + topicName + " from the latest offset")
+	}
 	// subscribe from message bus
 	if err := k.Accept(EventSubscriber); err != nil {
 		l.Log.Error(err.Error())
@@ -106,6 +155,33 @@ func Consume(topicName string) {
 	return
 }
 
+// Unsubscribe removes the subscription of the topic from message bus,
+// so that the consumers of the topic stop receiving its messages
+func Unsubscribe(topicName string) error {
+	config.TLSConfMutex.RLock()
+	MessageBusConfigFilePath := config.Data.MessageBusConf.MessageBusConfigFilePath
+	messagebusType := config.Data.MessageBusConf.MessageBusType
+	config.TLSConfMutex.RUnlock()
+	k, err := dc.Communicator(messagebusType, MessageBusConfigFilePath, topicName)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the message bus: %s", err.Error())
+	}
+	return k.Remove()
+}
+
+// topicOffsetStore persists the offset of the consumed EMB topics in DB
+type topicOffsetStore struct{}
+
+// GetOffset returns the stored offset of the topic
+func (topicOffsetStore) GetOffset(topicName string) (string, error) {
+	return evmodel.GetTopicOffset(topicName)
+}
+
+// SaveOffset stores the offset of the topic
+func (topicOffsetStore) SaveOffset(topicName, offset string) error {
+	return evmodel.SaveTopicOffset(topicName, offset)
+}
+
 // SubscribeCtrlMsgQueue creates a consumer for the kafka topic
 func SubscribeCtrlMsgQueue(topicName string) {
 	config.TLSConfMutex.RLock()
@@ -67,6 +67,28 @@ func TestKafkaSubscriber(t *testing.T) {
 	EventSubscriber("invalidJson")
 }
 
+func TestEventSubscriberProbe(t *testing.T) {
+	defer func(save func(string) error) { SaveEMBProbeFunc = save }(SaveEMBProbeFunc)
+	var savedProbes []string
+	SaveEMBProbeFunc = func(probeID string) error {
+		savedProbes = append(savedProbes, probeID)
+		return nil
+	}
+	In, Out = common.CreateJobQueue(1)
+	EventSubscriber(common.Events{
+		EventType: EMBProbeEventType,
+		Request:   []byte("probe-1"),
+	})
+	time.Sleep(time.Second)
+	close(In)
+	for range Out {
+		t.Errorf("error: probe should not be forwarded to the subscribers")
+	}
+	if len(savedProbes) != 1 || savedProbes[0] != "probe-1" {
+		t.Errorf("error: expected the probe to be recorded but got %v", savedProbes)
+	}
+}
+
 func TestConsume(t *testing.T) {
 	config.SetUpMockConfig(t)
 	type args struct {
@@ -22,9 +22,11 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ODIM-Project/ODIM/lib-rest-client/pmbhandle"
@@ -36,6 +38,7 @@ import (
 	"github.com/ODIM-Project/ODIM/svc-events/consumer"
 	"github.com/ODIM-Project/ODIM/svc-events/evmodel"
 	"github.com/ODIM-Project/ODIM/svc-events/evresponse"
+	uuid "github.com/satori/go.uuid"
 )
 
 // StartUpInteraface Holds the function pointer of  external interface functions
@@ -54,15 +57,35 @@ type StartUpInteraface struct {
 var (
 	//GetAllPluginsFunc is pointer function evmodel.GetAllPlugins
 	GetAllPluginsFunc = evmodel.GetAllPlugins
+	// EMBConsumeFunc is pointer function consumer.Consume, which blocks
+	// for as long as the consumer of the topic is alive
+	EMBConsumeFunc = consumer.Consume
+	// EMBUnsubscribeFunc is pointer function consumer.Unsubscribe
+	EMBUnsubscribeFunc = consumer.Unsubscribe
+	// EMBProbeFunc is pointer function consumer.PublishProbe
+	EMBProbeFunc = consumer.PublishProbe
+	// EMBProbeConsumedFunc is pointer function evmodel.IsEMBProbeConsumed
+	EMBProbeConsumedFunc = evmodel.IsEMBProbeConsumed
+	// EMBProbeTimeout is the maximum time waited for the probe of a topic to be consumed
+	EMBProbeTimeout = 15 * time.Second
+	// EMBProbeInterval is the interval in which the probe is published again till it is consumed
+	EMBProbeInterval = time.Second
 	// ConfigFilePath holds the value of odim config file path
 	ConfigFilePath string
 )
 
 // EmbTopic hold the list all consuming topics after
+// the value of a topic in TopicsList is true while its consumers are running
+// and is set to false once all the consumer goroutines exit
 type EmbTopic struct {
 	TopicsList map[string]bool
 	lock       sync.RWMutex
 	EMBConsume func(string)
+	// runningConsumers holds the number of consumer goroutines running for each topic
+	runningConsumers map[string]int
+	// generations is incremented each time a topic is unsubscribed, so that the consumers
+	// started before it are not accounted once they exit
+	generations map[string]int
 }
 
 // SavedSystems holds the resource details of the saved system
@@ -92,6 +115,57 @@ type StartUpMap struct {
 	Device     SavedSystems
 }
 
+// subscribedEventsDetails holds the subscription location and
+// the subscribed event types of a device
+type subscribedEventsDetails struct {
+	location   string
+	eventTypes []string
+}
+
+// startUpCache holds the subscription details of the devices looked up
+// during a single plugin startup cycle, keyed by the device IP address.
+// The subscriptions are looked up by the IP address, so the devices served on
+// different ports of the same IP and the batches retried after a failed startup
+// call are served from the cache. The cache is created for each cycle so that
+// the details never go stale
+type startUpCache struct {
+	entries map[string]subscribedEventsDetails
+	maxSize int
+	lock    sync.Mutex
+}
+
+// startUpCacheMaxEntries is the maximum number of devices cached in a startup cycle
+const startUpCacheMaxEntries = 1000
+
+func newStartUpCache(maxSize int) *startUpCache {
+	return &startUpCache{
+		entries: make(map[string]subscribedEventsDetails),
+		maxSize: maxSize,
+	}
+}
+
+func (c *startUpCache) get(deviceIPAddress string) (subscribedEventsDetails, bool) {
+	if c == nil {
+		return subscribedEventsDetails{}, false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	details, ok := c.entries[deviceIPAddress]
+	return details, ok
+}
+
+func (c *startUpCache) add(deviceIPAddress string, details subscribedEventsDetails) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.entries) >= c.maxSize {
+		return
+	}
+	c.entries[deviceIPAddress] = details
+}
+
 // PluginToken interface to hold the token
 type PluginToken struct {
 	Tokens map[string]string
@@ -116,13 +190,170 @@ func (p *PluginToken) GetToken(pluginID string) string {
 }
 
 // ConsumeTopic check the existing topic list if it is not present then it will add topic name to list and consume that topic
+// with the configured number of consumers
 func (e *EmbTopic) ConsumeTopic(topicName string) {
-	e.lock.RLock()
-	defer e.lock.RUnlock()
+	e.lock.Lock()
+	defer e.lock.Unlock()
 	if ok := e.TopicsList[topicName]; !ok {
-		go consumer.Consume(topicName)
-		e.TopicsList[topicName] = true
 		//consume the topic
+		e.TopicsList[topicName] = true
+		e.startConsumers(topicName)
+	}
+}
+
+// getConsumerWorkerCount returns the number of consumers to be started for each topic.
+// A single consumer is started when the consumption is resumed from the stored offset,
+// since the consumers reading from the offset would receive the same messages
+func getConsumerWorkerCount() int {
+	config.TLSConfMutex.RLock()
+	defer config.TLSConfMutex.RUnlock()
+	if config.Data.EventConf == nil || config.Data.EventConf.ConsumerWorkerCount <= 0 || config.Data.EventConf.ResumeFromStoredOffset {
+		return config.DefaultConsumerWorkerCount
+	}
+	return config.Data.EventConf.ConsumerWorkerCount
+}
+
+// startConsumers starts the consumers of the topic which are not running,
+// so that the configured number of consumers are draining the topic.
+// Caller should hold the lock
+func (e *EmbTopic) startConsumers(topicName string) {
+	if e.runningConsumers == nil {
+		e.runningConsumers = make(map[string]int)
+	}
+	for i := e.runningConsumers[topicName]; i < getConsumerWorkerCount(); i++ {
+		e.runningConsumers[topicName]++
+		go e.runConsumer(topicName, e.generations[topicName])
+	}
+}
+
+// runConsumer consumes the topic and marks the topic as exited
+// when the consume calls of all its consumers return, so that it can be restarted
+func (e *EmbTopic) runConsumer(topicName string, generation int) {
+	EMBConsumeFunc(topicName)
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	// the consumers of an unsubscribed topic are not restarted
+	if e.generations[topicName] != generation {
+		return
+	}
+	l.Log.Warn("consumer of the EMB topic " + topicName + " has exited")
+	e.runningConsumers[topicName]--
+	if e.runningConsumers[topicName] <= 0 {
+		e.TopicsList[topicName] = false
+	}
+}
+
+// UnsubscribeTopic removes the topic from the list and stops consuming it,
+// the topics which are not in the list are ignored
+func (e *EmbTopic) UnsubscribeTopic(topicName string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if _, ok := e.TopicsList[topicName]; !ok {
+		return nil
+	}
+	delete(e.TopicsList, topicName)
+	delete(e.runningConsumers, topicName)
+	if e.generations == nil {
+		e.generations = make(map[string]int)
+	}
+	e.generations[topicName]++
+	return EMBUnsubscribeFunc(topicName)
+}
+
+// Reconcile aligns the consumed topics with the desired topics in one pass, the topics which
+// are not desired are unsubscribed and the desired topics which are not consumed are consumed.
+// The topics added and removed are returned.
+func (e *EmbTopic) Reconcile(desiredTopics []string) (added, removed []string) {
+	desired := make(map[string]bool, len(desiredTopics))
+	for _, topicName := range desiredTopics {
+		desired[topicName] = true
+	}
+	e.lock.RLock()
+	for topicName := range e.TopicsList {
+		if !desired[topicName] {
+			removed = append(removed, topicName)
+		}
+	}
+	for topicName := range desired {
+		if !e.TopicsList[topicName] {
+			added = append(added, topicName)
+		}
+	}
+	e.lock.RUnlock()
+	sort.Strings(added)
+	sort.Strings(removed)
+	for _, topicName := range removed {
+		l.Log.Info("unsubscribing the stale EMB topic " + topicName)
+		if err := e.UnsubscribeTopic(topicName); err != nil {
+			l.Log.Error("error while unsubscribing the EMB topic " + topicName + ": " + err.Error())
+		}
+	}
+	for _, topicName := range added {
+		e.ConsumeTopic(topicName)
+	}
+	return added, removed
+}
+
+// ProbeEMBConsumption publishes a probe event on the topic through the message bus and waits
+// till it is consumed, by the consumer of the topic in any of the events service instances.
+// The probe is published again in EMBProbeInterval, as the consumer of a newly subscribed
+// topic reads only the messages published after it is started.
+func ProbeEMBConsumption(topicName string) error {
+	probeID := uuid.NewV4().String()
+	deadline := time.Now().Add(EMBProbeTimeout)
+	for {
+		if err := EMBProbeFunc(topicName, probeID); err != nil {
+			return err
+		}
+		time.Sleep(EMBProbeInterval)
+		consumed, err := EMBProbeConsumedFunc(probeID)
+		if err != nil {
+			return err
+		}
+		if consumed {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("probe published on %s is not consumed in %v", topicName, EMBProbeTimeout)
+		}
+	}
+}
+
+// MonitorConsumers checks the consumers of all the topics in the configured
+// interval and restarts the consumers which have exited, until the context is done
+func (e *EmbTopic) MonitorConsumers(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(getConsumerMonitorInterval()):
+			e.restartExitedConsumers()
+		}
+	}
+}
+
+// getConsumerMonitorInterval returns the interval in which the consumers of the topics are checked
+func getConsumerMonitorInterval() time.Duration {
+	config.TLSConfMutex.RLock()
+	defer config.TLSConfMutex.RUnlock()
+	if config.Data.EventConf == nil || config.Data.EventConf.ConsumerMonitorIntervalSecs <= 0 {
+		return time.Duration(config.DefaultConsumerMonitorIntervalSecs) * time.Second
+	}
+	return time.Duration(config.Data.EventConf.ConsumerMonitorIntervalSecs) * time.Second
+}
+
+// restartExitedConsumers restarts the consumers of every topic
+// whose consumer goroutines are no longer running
+func (e *EmbTopic) restartExitedConsumers() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	workerCount := getConsumerWorkerCount()
+	for topicName := range e.TopicsList {
+		if e.runningConsumers[topicName] < workerCount {
+			l.Log.Info("restarting the consumers of the EMB topic " + topicName)
+			e.TopicsList[topicName] = true
+			e.startConsumers(topicName)
+		}
 	}
 }
 
@@ -132,17 +363,38 @@ var EMBTopics EmbTopic
 // PluginStartUp is used to call plugin "Startup" only on plugin restart and not on every status check
 var PluginStartUp = false
 
+// statusPollerPaused is 1 while the plugin status polling is paused, it is
+// read as the gauge of the paused state of the poller
+var statusPollerPaused int32
+
+// PauseStatusPoller pauses the plugin status polling, the poll cycles are skipped
+// till the poller is resumed. It is used during the planned maintenance
+// to avoid the status checks and the resulting re-subscriptions
+func PauseStatusPoller() {
+	if atomic.SwapInt32(&statusPollerPaused, 1) == 0 {
+		l.Log.Info("plugin status polling is paused")
+	}
+}
+
+// ResumeStatusPoller resumes the plugin status polling from the next poll cycle
+func ResumeStatusPoller() {
+	if atomic.SwapInt32(&statusPollerPaused, 0) == 1 {
+		l.Log.Info("plugin status polling is resumed")
+	}
+}
+
+// StatusPollerPaused returns the gauge of the paused state of the plugin
+// status poller, 1 when it is paused and 0 otherwise
+func StatusPollerPaused() int32 {
+	return atomic.LoadInt32(&statusPollerPaused)
+}
+
 // GetAllPluginStatus ...
 func (st *StartUpInteraface) GetAllPluginStatus() {
 	for {
-		pluginList, err := evmodel.GetAllPlugins()
-		if err != nil {
-			l.Log.Error(err.Error())
+		if !st.pollAllPluginStatus() {
 			return
 		}
-		for i := 0; i < len(pluginList); i++ {
-			go st.getPluginStatus(context.TODO(), pluginList[i]) //TODO: Pass context
-		}
 		var pollingTime int
 		config.TLSConfMutex.RLock()
 		pollingTime = config.Data.PluginStatusPolling.PollingFrequencyInMins
@@ -152,7 +404,93 @@ func (st *StartUpInteraface) GetAllPluginStatus() {
 
 }
 
-func (st *StartUpInteraface) getPluginStatus(ctx context.Context, plugin evmodel.Plugin) {
+// pollAllPluginStatus runs a single poll cycle of the status of all the plugins, the cycle
+// is skipped while the poller is paused. It returns false when the polling can't continue
+func (st *StartUpInteraface) pollAllPluginStatus() bool {
+	if StatusPollerPaused() == 1 {
+		l.Log.Debug("plugin status polling is paused, skipping the poll cycle")
+		return true
+	}
+	pluginList, err := GetAllPluginsFunc()
+	if err != nil {
+		l.Log.Error(err.Error())
+		return false
+	}
+	// the topics are reconciled once the status of all the plugins is checked,
+	// without delaying the next poll cycle
+	go func(pluginList []evmodel.Plugin) {
+		var unavailablePlugins int32
+		topics := collectPluginTopics(pluginList, func(plugin evmodel.Plugin) []string {
+			topicsList := st.getPluginStatus(context.TODO(), plugin) //TODO: Pass context
+			if len(topicsList) == 0 {
+				atomic.AddInt32(&unavailablePlugins, 1)
+			}
+			return topicsList
+		})
+		st.reconcileTopics(topics, atomic.LoadInt32(&unavailablePlugins) == 0)
+	}(pluginList)
+	return true
+}
+
+// reconcileTopics aligns the consumed topics with the topics advertised by the plugins. The stale
+// topics are removed only when all the plugins advertised their topics, so that the topics of a
+// plugin which is down for a poll cycle are not unsubscribed. Otherwise the topics are only consumed.
+func (st *StartUpInteraface) reconcileTopics(topics []string, allPluginsAvailable bool) {
+	if !allPluginsAvailable {
+		st.consumeTopics(topics)
+		return
+	}
+	EMBTopics.lock.Lock()
+	EMBTopics.EMBConsume = st.EMBConsume
+	EMBTopics.lock.Unlock()
+	added, removed := EMBTopics.Reconcile(topics)
+	if len(added) > 0 || len(removed) > 0 {
+		l.Log.Info(fmt.Sprintf("EMB topics are reconciled with the plugins, consumed %v and unsubscribed %v", added, removed))
+	}
+}
+
+// collectPluginTopics gets the EMB topics of all the plugins in parallel and returns them
+// deduplicated across the plugins, in the order they are first advertised
+func collectPluginTopics(pluginList []evmodel.Plugin, getTopics func(evmodel.Plugin) []string) []string {
+	pluginTopics := make([][]string, len(pluginList))
+	var wg sync.WaitGroup
+	for i := range pluginList {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pluginTopics[i] = getTopics(pluginList[i])
+		}(i)
+	}
+	wg.Wait()
+	var topics []string
+	seen := make(map[string]bool)
+	for _, topicsList := range pluginTopics {
+		for _, topic := range topicsList {
+			if !seen[topic] {
+				seen[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+	}
+	return topics
+}
+
+// consumeTopics consumes each of the topics
+func (st *StartUpInteraface) consumeTopics(topics []string) {
+	if len(topics) == 0 {
+		return
+	}
+	EMBTopics.lock.Lock()
+	EMBTopics.EMBConsume = st.EMBConsume
+	EMBTopics.lock.Unlock()
+	for _, topic := range topics {
+		EMBTopics.ConsumeTopic(topic)
+	}
+}
+
+// getPluginStatus checks the status of the plugin, calls the plugin startup when required
+// and returns the EMB topics of the plugin
+func (st *StartUpInteraface) getPluginStatus(ctx context.Context, plugin evmodel.Plugin) []string {
 	PluginsMap := make(map[string]bool)
 	StartUpResourceBatchSize := config.Data.PluginStatusPolling.StartUpResouceBatchSize
 	config.TLSConfMutex.RLock()
@@ -168,7 +506,7 @@ func (st *StartUpInteraface) getPluginStatus(ctx context.Context, plugin evmodel
 		PluginPort:              plugin.Port,
 		PluginUsername:          plugin.Username,
 		PluginUserPassword:      string(plugin.Password),
-		PluginPrefferedAuthType: plugin.PreferredAuthType,
+		PluginPrefferedAuthType: plugin.AuthType(),
 		CACertificate:           &config.Data.KeyCertConf.RootCACertificate,
 	}
 	config.TLSConfMutex.RUnlock()
@@ -176,7 +514,7 @@ func (st *StartUpInteraface) getPluginStatus(ctx context.Context, plugin evmodel
 	if err != nil && !status {
 		PluginStartUp = false
 		l.Log.Error("Error While getting the status for plugin " + plugin.ID + err.Error())
-		return
+		return nil
 	}
 	l.Log.Info("Status of plugin " + plugin.ID + " is " + strconv.FormatBool(status))
 	PluginsMap[plugin.ID] = status
@@ -188,9 +526,10 @@ func (st *StartUpInteraface) getPluginStatus(ctx context.Context, plugin evmodel
 				l.Log.Error("Error While getting the servers" + pluginID + err.Error())
 				continue
 			}
+			cache := newStartUpCache(startUpCacheMaxEntries)
 			for {
 				if len(allServers) < StartUpResourceBatchSize {
-					err = st.callPluginStartUp(ctx, allServers, pluginID)
+					err = st.callPluginStartUp(ctx, allServers, pluginID, cache)
 					if err != nil {
 						l.Log.Error("Error While trying call plugin startup" +
 							pluginID + err.Error())
@@ -198,7 +537,7 @@ func (st *StartUpInteraface) getPluginStatus(ctx context.Context, plugin evmodel
 					break
 				}
 				batchServers := allServers[:StartUpResourceBatchSize]
-				err = st.callPluginStartUp(ctx, batchServers, pluginID)
+				err = st.callPluginStartUp(ctx, batchServers, pluginID, cache)
 				if err != nil {
 					l.Log.Error("Error While trying call plugin startup" + pluginID + err.Error())
 					continue
@@ -208,14 +547,50 @@ func (st *StartUpInteraface) getPluginStatus(ctx context.Context, plugin evmodel
 			PluginStartUp = true
 		}
 	}
-	// Adding the topics to the list
-	EMBTopics.lock.Lock()
-	EMBTopics.EMBConsume = st.EMBConsume
-	EMBTopics.lock.Unlock()
-	for j := 0; j < len(topicsList); j++ {
-		EMBTopics.ConsumeTopic(topicsList[j])
+	return topicsList
+}
+
+// ResubscribeSummary holds the result of re-subscribing the devices managed by a plugin
+type ResubscribeSummary struct {
+	PluginID         string
+	TotalServers     int
+	ProcessedServers int
+	FailedServers    int
+}
+
+// ResubscribePluginDevices calls the plugin startup for all the devices managed by the plugin
+// in batches. Unlike the status polling, it doesn't check the PluginStartUp flag so that the
+// devices can be re-subscribed on demand, for example after the plugin is upgraded
+func (st *StartUpInteraface) ResubscribePluginDevices(ctx context.Context, pluginID string) (ResubscribeSummary, error) {
+	summary := ResubscribeSummary{PluginID: pluginID}
+	allServers, err := st.getAllServers(pluginID)
+	if err != nil {
+		return summary, fmt.Errorf("error while getting the servers of the plugin %s: %s", pluginID, err.Error())
+	}
+	summary.TotalServers = len(allServers)
+	config.TLSConfMutex.RLock()
+	batchSize := config.Data.PluginStatusPolling.StartUpResouceBatchSize
+	config.TLSConfMutex.RUnlock()
+	if batchSize <= 0 {
+		batchSize = len(allServers)
+	}
+	cache := newStartUpCache(startUpCacheMaxEntries)
+	for len(allServers) > 0 {
+		if len(allServers) < batchSize {
+			batchSize = len(allServers)
+		}
+		batchServers := allServers[:batchSize]
+		allServers = allServers[batchSize:]
+		if err := st.callPluginStartUp(ctx, batchServers, pluginID, cache); err != nil {
+			l.LogWithFields(ctx).Error("error while trying to call plugin startup for " + pluginID + ": " + err.Error())
+			summary.FailedServers += len(batchServers)
+			continue
+		}
+		summary.ProcessedServers += len(batchServers)
 	}
-	return
+	l.LogWithFields(ctx).Info("re-subscription of the devices of the plugin " + pluginID + " is completed, processed " +
+		strconv.Itoa(summary.ProcessedServers) + " of " + strconv.Itoa(summary.TotalServers) + " servers")
+	return summary, nil
 }
 
 func (st *StartUpInteraface) getAllServers(pluginID string) ([]SavedSystems, error) {
@@ -261,7 +636,7 @@ func GetPluginStatus(plugin *evmodel.Plugin) bool {
 		PluginPort:              plugin.Port,
 		PluginUsername:          plugin.Username,
 		PluginUserPassword:      string(plugin.Password),
-		PluginPrefferedAuthType: plugin.PreferredAuthType,
+		PluginPrefferedAuthType: plugin.AuthType(),
 		CACertificate:           &config.Data.KeyCertConf.RootCACertificate,
 	}
 	status, _, _, err := pluginStatus.CheckStatus()
@@ -273,7 +648,7 @@ func GetPluginStatus(plugin *evmodel.Plugin) bool {
 	return status
 }
 
-func (st *StartUpInteraface) callPluginStartUp(ctx context.Context, servers []SavedSystems, pluginID string) error {
+func (st *StartUpInteraface) callPluginStartUp(ctx context.Context, servers []SavedSystems, pluginID string, cache *startUpCache) error {
 	var startUpMap []StartUpMap
 	plugin, errs := st.GetPluginData(pluginID)
 	if errs != nil {
@@ -282,7 +657,7 @@ func (st *StartUpInteraface) callPluginStartUp(ctx context.Context, servers []Sa
 	for _, server := range servers {
 		var s StartUpMap
 		var err error
-		s.Location, s.EventTypes, err = st.getSubscribedEventsDetails(server.ManagerAddress)
+		s.Location, s.EventTypes, err = st.getSubscribedEventsDetails(server.ManagerAddress, cache)
 		if err != nil {
 			l.Log.Error("Error while retrieving the Subsction details from DB for device: " +
 				server.ManagerAddress + err.Error())
@@ -298,7 +673,7 @@ func (st *StartUpInteraface) callPluginStartUp(ctx context.Context, servers []Sa
 	contactRequest.HTTPMethodType = http.MethodPost
 	contactRequest.PostBody = startUpMap
 
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		var err error
 		contactRequest.HTTPMethodType = http.MethodPost
 		contactRequest.PostBody = map[string]interface{}{
@@ -335,17 +710,19 @@ func (st *StartUpInteraface) callPluginStartUp(ctx context.Context, servers []Sa
 
 func callPlugin(ctx context.Context, req PluginContactRequest) (*http.Response, error) {
 	var reqURL = "https://" + req.Plugin.IP + ":" + req.Plugin.Port + req.URL
-	if strings.EqualFold(req.Plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(req.Plugin.AuthType(), "XAuthToken") {
 		return pmbhandle.ContactPlugin(ctx, reqURL, req.HTTPMethodType, "", "", req.PostBody, nil)
 	}
-	if strings.EqualFold(req.Plugin.PreferredAuthType, "BasicAuth") {
+	if strings.EqualFold(req.Plugin.AuthType(), "BasicAuth") {
 		return pmbhandle.ContactPlugin(ctx, reqURL, req.HTTPMethodType, "", "", req.PostBody, req.LoginCredential)
 	}
 	return pmbhandle.ContactPlugin(ctx, reqURL, req.HTTPMethodType, req.Token, "", req.PostBody, nil)
 
 }
 
-func (st *StartUpInteraface) getSubscribedEventsDetails(serverAddress string) (string, []string, error) {
+// getSubscribedEventsDetails returns the subscription location and event types of the device,
+// the details are served from the cache if the device was already looked up in the cycle
+func (st *StartUpInteraface) getSubscribedEventsDetails(serverAddress string, cache *startUpCache) (string, []string, error) {
 	var location string
 	var eventTypes []string
 	var emptyListFlag bool
@@ -354,6 +731,9 @@ func (st *StartUpInteraface) getSubscribedEventsDetails(serverAddress string) (s
 	if errorMessage != "" {
 		return "", nil, fmt.Errorf(errorMessage)
 	}
+	if details, ok := cache.get(deviceIPAddress); ok {
+		return details.location, details.eventTypes, nil
+	}
 	searchKey := GetSearchKey(deviceIPAddress, evmodel.DeviceSubscriptionIndex)
 	deviceSubscription, err := st.GetDeviceSubscriptions(searchKey)
 	if err != nil {
@@ -378,9 +758,48 @@ func (st *StartUpInteraface) getSubscribedEventsDetails(serverAddress string) (s
 	} else {
 		eventTypes = removeDuplicates(eventTypes)
 	}
+	cache.add(deviceIPAddress, subscribedEventsDetails{location: location, eventTypes: eventTypes})
 	return location, eventTypes, nil
 }
 
+// DeviceEventSubscription is a subscription of a device along with the events it is subscribed for
+type DeviceEventSubscription struct {
+	SubscriptionID  string
+	Name            string
+	Destination     string
+	EventTypes      []string
+	MessageIds      []string
+	ResourceTypes   []string
+	OriginResources []string
+}
+
+// GetDeviceEventSubscriptions lists the subscriptions of the device with the events they are subscribed for.
+// It helps to find out why the expected events of a device are not delivered.
+func (st *StartUpInteraface) GetDeviceEventSubscriptions(serverAddress string) ([]DeviceEventSubscription, error) {
+	deviceIPAddress, errorMessage := GetIPFromHostName(serverAddress)
+	if errorMessage != "" {
+		return nil, fmt.Errorf(errorMessage)
+	}
+	searchKey := GetSearchKey(deviceIPAddress, evmodel.SubscriptionIndex)
+	subscriptionDetails, err := st.GetEvtSubscriptions(searchKey)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := make([]DeviceEventSubscription, 0, len(subscriptionDetails))
+	for _, subscription := range subscriptionDetails {
+		subscriptions = append(subscriptions, DeviceEventSubscription{
+			SubscriptionID:  subscription.SubscriptionID,
+			Name:            subscription.Name,
+			Destination:     subscription.Destination,
+			EventTypes:      subscription.EventTypes,
+			MessageIds:      subscription.MessageIds,
+			ResourceTypes:   subscription.ResourceTypes,
+			OriginResources: subscription.OriginResources,
+		})
+	}
+	return subscriptions, nil
+}
+
 func removeDuplicates(elements []string) []string {
 	existing := map[string]bool{}
 	result := []string{}
@@ -532,12 +951,11 @@ func (st *StartUpInteraface) SubscribePluginEMB() {
 		l.Log.Error(err.Error())
 		return
 	}
-	for i := 0; i < len(pluginList); i++ {
-		go st.getPluginEMB(pluginList[i])
-	}
+	st.consumeTopics(collectPluginTopics(pluginList, st.getPluginEMB))
 }
 
-func (st *StartUpInteraface) getPluginEMB(plugin evmodel.Plugin) {
+// getPluginEMB returns the EMB topics of the plugin
+func (st *StartUpInteraface) getPluginEMB(plugin evmodel.Plugin) []string {
 	config.TLSConfMutex.RLock()
 	var pluginStatus = common.PluginStatus{
 		Method: http.MethodGet,
@@ -551,22 +969,16 @@ func (st *StartUpInteraface) getPluginEMB(plugin evmodel.Plugin) {
 		PluginPort:              plugin.Port,
 		PluginUsername:          plugin.Username,
 		PluginUserPassword:      string(plugin.Password),
-		PluginPrefferedAuthType: plugin.PreferredAuthType,
+		PluginPrefferedAuthType: plugin.AuthType(),
 		CACertificate:           &config.Data.KeyCertConf.RootCACertificate,
 	}
 	config.TLSConfMutex.RUnlock()
 	status, _, topicsList, err := pluginStatus.CheckStatus()
 	if err != nil && !status {
 		l.Log.Error("status check of plugin " + plugin.ID + " failed: " + err.Error())
-		return
-	}
-	EMBTopics.lock.Lock()
-	EMBTopics.EMBConsume = st.EMBConsume
-	EMBTopics.lock.Unlock()
-	for j := 0; j < len(topicsList); j++ {
-		EMBTopics.ConsumeTopic(topicsList[j])
+		return nil
 	}
-	return
+	return topicsList
 }
 
 func TrackConfigFileChanges(errChan chan error) {
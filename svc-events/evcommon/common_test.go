@@ -20,17 +20,21 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ODIM-Project/ODIM/lib-utilities/common"
 	"github.com/ODIM-Project/ODIM/lib-utilities/config"
 	"github.com/ODIM-Project/ODIM/lib-utilities/errors"
 	lg "github.com/ODIM-Project/ODIM/lib-utilities/logs"
 	"github.com/ODIM-Project/ODIM/lib-utilities/response"
+	"github.com/ODIM-Project/ODIM/svc-events/consumer"
 	"github.com/ODIM-Project/ODIM/svc-events/evmodel"
 	"github.com/ODIM-Project/ODIM/svc-events/evresponse"
 	"github.com/stretchr/testify/assert"
@@ -319,13 +323,45 @@ func TestCallPluginStartUp(t *testing.T) {
 		GetDeviceSubscriptions:           MockGetDeviceSubscriptions,
 		UpdateDeviceSubscriptionLocation: MockUpdateDeviceSubscriptionLocation,
 	}
-	err := st.callPluginStartUp(context.TODO(), servers, "ILO")
+	err := st.callPluginStartUp(context.TODO(), servers, "ILO", newStartUpCache(startUpCacheMaxEntries))
 	assert.Nil(t, err, "Error Should be nil")
 
-	err = st.callPluginStartUp(context.TODO(), servers, "pluginBadData")
+	err = st.callPluginStartUp(context.TODO(), servers, "pluginBadData", nil)
 	assert.NotNil(t, err, "error should not be nil")
 }
 
+func TestResubscribePluginDevices(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ts := startTestServer()
+	// Start the server.
+	ts.StartTLS()
+	defer ts.Close()
+	config.Data.PluginStatusPolling.StartUpResouceBatchSize = 1
+	PluginStartUp = true
+	defer func() {
+		PluginStartUp = false
+	}()
+	st := StartUpInteraface{
+		DecryptPassword:                  stubDevicePassword,
+		EMBConsume:                       stubEMBConsume,
+		GetAllSystems:                    MockGetAllSystems,
+		GetSingleSystem:                  MockGetSingleSystem,
+		GetPluginData:                    MockGetPluginData,
+		GetEvtSubscriptions:              MockGetEvtSubscriptions,
+		GetDeviceSubscriptions:           MockGetDeviceSubscriptions,
+		UpdateDeviceSubscriptionLocation: MockUpdateDeviceSubscriptionLocation,
+	}
+	// startup should be called even though the PluginStartUp flag is already set
+	summary, err := st.ResubscribePluginDevices(context.TODO(), "ILO")
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, ResubscribeSummary{PluginID: "ILO", TotalServers: 2, ProcessedServers: 2}, summary)
+
+	summary, err = st.ResubscribePluginDevices(context.TODO(), "GRF")
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, 1, summary.TotalServers, "all the servers of the plugin should be processed")
+	assert.Equal(t, summary.TotalServers, summary.ProcessedServers+summary.FailedServers)
+}
+
 func TestGetandStoreToken(t *testing.T) {
 	var result = &PluginToken{
 		Tokens: make(map[string]string),
@@ -475,3 +511,460 @@ func TestProcessCtrlMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestEmbTopic_RestartExitedConsumers(t *testing.T) {
+	var mu sync.Mutex
+	consumeCount := 0
+	block := make(chan struct{})
+	defer close(block)
+	defer func() { EMBConsumeFunc = consumer.Consume }()
+	EMBConsumeFunc = func(topicName string) {
+		mu.Lock()
+		consumeCount++
+		count := consumeCount
+		mu.Unlock()
+		// the first consumer exits immediately, the restarted one keeps running
+		if count > 1 {
+			<-block
+		}
+	}
+	e := EmbTopic{TopicsList: make(map[string]bool)}
+	e.ConsumeTopic("EVENTS")
+
+	assert.Eventually(t, func() bool {
+		e.lock.RLock()
+		defer e.lock.RUnlock()
+		return !e.TopicsList["EVENTS"]
+	}, time.Second, 10*time.Millisecond, "consumer should be marked as exited")
+
+	e.restartExitedConsumers()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return consumeCount == 2
+	}, time.Second, 10*time.Millisecond, "consumer should be restarted")
+	e.lock.RLock()
+	assert.True(t, e.TopicsList["EVENTS"], "restarted consumer should be marked as running")
+	e.lock.RUnlock()
+
+	// a running consumer should not be started again
+	e.restartExitedConsumers()
+	e.ConsumeTopic("EVENTS")
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 2, consumeCount, "running consumer should not be restarted")
+	mu.Unlock()
+}
+
+func TestEmbTopic_MonitorConsumers(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var mu sync.Mutex
+	consumeCount := 0
+	block := make(chan struct{})
+	defer close(block)
+	defer func() { EMBConsumeFunc = consumer.Consume }()
+	EMBConsumeFunc = func(topicName string) {
+		mu.Lock()
+		consumeCount++
+		count := consumeCount
+		mu.Unlock()
+		// the first consumer exits immediately, the restarted one keeps running
+		if count > 1 {
+			<-block
+		}
+	}
+	e := EmbTopic{TopicsList: make(map[string]bool)}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		e.MonitorConsumers(ctx)
+		close(stopped)
+	}()
+	e.ConsumeTopic("EVENTS")
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return consumeCount == 2
+	}, 3*time.Second, 10*time.Millisecond, "exited consumer should be restarted in the configured interval")
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		select {
+		case <-stopped:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond, "monitor should stop when the context is done")
+}
+
+func TestEmbTopic_ConsumeTopicWithWorkers(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.EventConf.ConsumerWorkerCount = 3
+	var mu sync.Mutex
+	consumeCount := 0
+	block := make(chan struct{})
+	defer close(block)
+	defer func() { EMBConsumeFunc = consumer.Consume }()
+	EMBConsumeFunc = func(topicName string) {
+		mu.Lock()
+		consumeCount++
+		mu.Unlock()
+		<-block
+	}
+	e := EmbTopic{TopicsList: make(map[string]bool)}
+	e.ConsumeTopic("EVENTS")
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return consumeCount == 3
+	}, time.Second, 10*time.Millisecond, "configured number of consumers should be started")
+
+	// consumers should not be started again while they are running
+	e.ConsumeTopic("EVENTS")
+	e.restartExitedConsumers()
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 3, consumeCount, "running consumers should not be restarted")
+	mu.Unlock()
+	e.lock.RLock()
+	assert.Equal(t, 3, e.runningConsumers["EVENTS"], "all the consumers should be marked as running")
+	e.lock.RUnlock()
+}
+
+func TestGetSubscribedEventsDetailsWithCache(t *testing.T) {
+	var dbCalls int
+	st := StartUpInteraface{
+		GetDeviceSubscriptions: func(searchKey string) (*evmodel.DeviceSubscription, error) {
+			dbCalls++
+			return MockGetDeviceSubscriptions(searchKey)
+		},
+		GetEvtSubscriptions: func(searchKey string) ([]evmodel.Subscription, error) {
+			dbCalls++
+			return MockGetEvtSubscriptions(searchKey)
+		},
+	}
+	cache := newStartUpCache(startUpCacheMaxEntries)
+	location, eventTypes, err := st.getSubscribedEventsDetails("100.100.100.100", cache)
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, 2, dbCalls, "first lookup should hit the DB")
+
+	cachedLocation, cachedEventTypes, err := st.getSubscribedEventsDetails("100.100.100.100", cache)
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, 2, dbCalls, "repeated lookup should be served from the cache")
+	assert.Equal(t, location, cachedLocation, "Should be same")
+	assert.Equal(t, eventTypes, cachedEventTypes, "Should be same")
+
+	// without a cache every lookup hits the DB
+	st.getSubscribedEventsDetails("100.100.100.100", nil)
+	assert.Equal(t, 4, dbCalls, "lookup without cache should hit the DB")
+}
+
+func TestCallPluginStartUpWithCache(t *testing.T) {
+	config.SetUpMockConfig(t)
+	ts := startTestServer()
+	// Start the server.
+	ts.StartTLS()
+	defer ts.Close()
+	var dbCalls int
+	st := StartUpInteraface{
+		GetPluginData: MockGetPluginData,
+		GetDeviceSubscriptions: func(searchKey string) (*evmodel.DeviceSubscription, error) {
+			dbCalls++
+			return MockGetDeviceSubscriptions(searchKey)
+		},
+		GetEvtSubscriptions: func(searchKey string) ([]evmodel.Subscription, error) {
+			dbCalls++
+			return MockGetEvtSubscriptions(searchKey)
+		},
+		UpdateDeviceSubscriptionLocation: MockUpdateDeviceSubscriptionLocation,
+	}
+	// the devices are served on different ports of the same IP address
+	servers := []SavedSystems{
+		{ManagerAddress: "100.100.100.100:443", DeviceUUID: "6d4a0a66-7efa-578e-83cf-44dc68d2874e", PluginID: "ILO"},
+		{ManagerAddress: "100.100.100.100:8443", DeviceUUID: "7a2c6100-67da-5fd6-ab82-6870d29c7279", PluginID: "ILO"},
+	}
+	cache := newStartUpCache(startUpCacheMaxEntries)
+	err := st.callPluginStartUp(context.TODO(), servers, "ILO", cache)
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, 2, dbCalls, "devices with the same IP address should be looked up once")
+
+	// a retried batch is served from the cache of the cycle
+	err = st.callPluginStartUp(context.TODO(), servers, "ILO", cache)
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, 2, dbCalls, "retried batch should be served from the cache")
+
+	err = st.callPluginStartUp(context.TODO(), servers, "ILO", nil)
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, 6, dbCalls, "every device should be looked up without a cache")
+}
+
+func TestStartUpCacheIsBounded(t *testing.T) {
+	cache := newStartUpCache(1)
+	cache.add("10.10.10.1", subscribedEventsDetails{location: "loc1"})
+	cache.add("10.10.10.2", subscribedEventsDetails{location: "loc2"})
+	_, ok := cache.get("10.10.10.1")
+	assert.True(t, ok, "first entry should be cached")
+	_, ok = cache.get("10.10.10.2")
+	assert.False(t, ok, "entry beyond the max size should not be cached")
+}
+
+func benchmarkGetSubscribedEventsDetails(b *testing.B, useCache bool) {
+	var dbCalls int
+	st := StartUpInteraface{
+		GetDeviceSubscriptions: func(searchKey string) (*evmodel.DeviceSubscription, error) {
+			dbCalls++
+			return MockGetDeviceSubscriptions(searchKey)
+		},
+		GetEvtSubscriptions: func(searchKey string) ([]evmodel.Subscription, error) {
+			dbCalls++
+			return MockGetEvtSubscriptions(searchKey)
+		},
+	}
+	// a large startup of the devices served on different ports of two IP
+	// addresses, where the batch is retried once after a failed startup call
+	var servers []string
+	for port := 8000; port < 8250; port++ {
+		servers = append(servers, fmt.Sprintf("100.100.100.100:%d", port), fmt.Sprintf("100.100.100.101:%d", port))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var cache *startUpCache
+		if useCache {
+			cache = newStartUpCache(startUpCacheMaxEntries)
+		}
+		for retry := 0; retry < 2; retry++ {
+			for _, server := range servers {
+				st.getSubscribedEventsDetails(server, cache)
+			}
+		}
+	}
+	b.ReportMetric(float64(dbCalls)/float64(b.N), "dbcalls/op")
+}
+
+func BenchmarkGetSubscribedEventsDetailsWithoutCache(b *testing.B) {
+	benchmarkGetSubscribedEventsDetails(b, false)
+}
+
+func BenchmarkGetSubscribedEventsDetailsWithCache(b *testing.B) {
+	benchmarkGetSubscribedEventsDetails(b, true)
+}
+
+func TestGetDeviceEventSubscriptions(t *testing.T) {
+	var searchKeys []string
+	st := StartUpInteraface{
+		GetEvtSubscriptions: func(searchKey string) ([]evmodel.Subscription, error) {
+			searchKeys = append(searchKeys, searchKey)
+			return []evmodel.Subscription{
+				{
+					SubscriptionID:  "81de0110-c35a-4859-984c-072d6c5a32d7",
+					Name:            "Alerts",
+					Destination:     "https://odim.destination.com:9090/events",
+					EventTypes:      []string{"Alert"},
+					MessageIds:      []string{"IndicatorChanged"},
+					ResourceTypes:   []string{"ComputerSystem"},
+					OriginResources: []string{"/redfish/v1/Systems/6d4a0a66-7efa-578e-83cf-44dc68d2874e.1"},
+					Hosts:           []string{"100.100.100.100"},
+				},
+				{
+					SubscriptionID: "71de0110-c35a-4859-984c-072d6c5a32d8",
+					Name:           "Inventory",
+					Destination:    "https://odim.inventory.com:9090/events",
+					EventTypes:     []string{"ResourceAdded", "ResourceRemoved"},
+					Hosts:          []string{"100.100.100.100", "100.100.100.101"},
+				},
+			}, nil
+		},
+	}
+	subscriptions, err := st.GetDeviceEventSubscriptions("100.100.100.100:443")
+	assert.Nil(t, err, "Error Should be nil")
+	assert.Equal(t, []string{"[^0-9]100.100.100.100[^0-9]"}, searchKeys, "subscriptions should be searched with the device IP")
+	assert.Equal(t, []DeviceEventSubscription{
+		{
+			SubscriptionID:  "81de0110-c35a-4859-984c-072d6c5a32d7",
+			Name:            "Alerts",
+			Destination:     "https://odim.destination.com:9090/events",
+			EventTypes:      []string{"Alert"},
+			MessageIds:      []string{"IndicatorChanged"},
+			ResourceTypes:   []string{"ComputerSystem"},
+			OriginResources: []string{"/redfish/v1/Systems/6d4a0a66-7efa-578e-83cf-44dc68d2874e.1"},
+		},
+		{
+			SubscriptionID: "71de0110-c35a-4859-984c-072d6c5a32d8",
+			Name:           "Inventory",
+			Destination:    "https://odim.inventory.com:9090/events",
+			EventTypes:     []string{"ResourceAdded", "ResourceRemoved"},
+		},
+	}, subscriptions, "all the subscriptions of the device should be listed")
+
+	// errors while getting the subscriptions are returned
+	st.GetEvtSubscriptions = func(searchKey string) ([]evmodel.Subscription, error) {
+		return nil, fmt.Errorf("DB is unavailable")
+	}
+	_, err = st.GetDeviceEventSubscriptions("100.100.100.100")
+	assert.NotNil(t, err, "Error Should not be nil")
+}
+
+func TestCollectAndConsumePluginTopics(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var mu sync.Mutex
+	consumeCount := make(map[string]int)
+	block := make(chan struct{})
+	defer close(block)
+	defer func() { EMBConsumeFunc = consumer.Consume }()
+	EMBConsumeFunc = func(topicName string) {
+		mu.Lock()
+		consumeCount[topicName]++
+		mu.Unlock()
+		<-block
+	}
+	pluginTopics := map[string][]string{
+		"GRF":    {"GRF", "SHARED"},
+		"ILO":    {"SHARED", "ILO"},
+		"LENOVO": {"SHARED", "GRF"},
+		"DOWN":   nil,
+	}
+	pluginList := []evmodel.Plugin{{ID: "GRF"}, {ID: "ILO"}, {ID: "LENOVO"}, {ID: "DOWN"}}
+	topics := collectPluginTopics(pluginList, func(plugin evmodel.Plugin) []string {
+		return pluginTopics[plugin.ID]
+	})
+	assert.Equal(t, []string{"GRF", "SHARED", "ILO"}, topics, "topics should be deduplicated across the plugins")
+
+	EMBTopics.lock.Lock()
+	EMBTopics.TopicsList = make(map[string]bool)
+	EMBTopics.runningConsumers = nil
+	EMBTopics.lock.Unlock()
+	st := StartUpInteraface{EMBConsume: stubEMBConsume}
+	st.consumeTopics(topics)
+	workerCount := getConsumerWorkerCount()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return consumeCount["GRF"] == workerCount && consumeCount["SHARED"] == workerCount && consumeCount["ILO"] == workerCount
+	}, time.Second, 10*time.Millisecond, "each topic should be consumed")
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Len(t, consumeCount, 3)
+	assert.Equal(t, workerCount, consumeCount["SHARED"], "shared topic should be consumed once")
+	mu.Unlock()
+}
+
+func TestPauseStatusPoller(t *testing.T) {
+	config.SetUpMockConfig(t)
+	defer func() { GetAllPluginsFunc = evmodel.GetAllPlugins }()
+	defer ResumeStatusPoller()
+	var polls int
+	GetAllPluginsFunc = func() ([]evmodel.Plugin, *errors.Error) {
+		polls++
+		return nil, nil
+	}
+	st := StartUpInteraface{EMBConsume: stubEMBConsume}
+
+	assert.True(t, st.pollAllPluginStatus())
+	assert.Equal(t, 1, polls, "plugins should be polled while not paused")
+	assert.Equal(t, int32(0), StatusPollerPaused())
+
+	PauseStatusPoller()
+	assert.Equal(t, int32(1), StatusPollerPaused(), "gauge should report the poller as paused")
+	assert.True(t, st.pollAllPluginStatus(), "polling should continue while paused")
+	assert.True(t, st.pollAllPluginStatus())
+	assert.Equal(t, 1, polls, "poll cycles should be skipped while paused")
+
+	ResumeStatusPoller()
+	assert.Equal(t, int32(0), StatusPollerPaused())
+	assert.True(t, st.pollAllPluginStatus())
+	assert.Equal(t, 2, polls, "plugins should be polled once resumed")
+}
+
+func TestEmbTopic_Reconcile(t *testing.T) {
+	config.SetUpMockConfig(t)
+	var mu sync.Mutex
+	consumeCount := make(map[string]int)
+	var unsubscribed []string
+	block := make(chan struct{})
+	defer close(block)
+	defer func() {
+		EMBConsumeFunc = consumer.Consume
+		EMBUnsubscribeFunc = consumer.Unsubscribe
+	}()
+	EMBConsumeFunc = func(topicName string) {
+		mu.Lock()
+		consumeCount[topicName]++
+		mu.Unlock()
+		<-block
+	}
+	EMBUnsubscribeFunc = func(topicName string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		unsubscribed = append(unsubscribed, topicName)
+		return nil
+	}
+	e := EmbTopic{TopicsList: make(map[string]bool)}
+	e.ConsumeTopic("GRF")
+	e.ConsumeTopic("STALE")
+	// the topic whose consumers exited is consumed again
+	e.lock.Lock()
+	e.TopicsList["EXITED"] = false
+	e.lock.Unlock()
+
+	added, removed := e.Reconcile([]string{"GRF", "ILO", "EXITED"})
+	assert.Equal(t, []string{"EXITED", "ILO"}, added, "missing topics should be consumed")
+	assert.Equal(t, []string{"STALE"}, removed, "stale topics should be unsubscribed")
+	workerCount := getConsumerWorkerCount()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return consumeCount["ILO"] == workerCount && consumeCount["EXITED"] == workerCount
+	}, time.Second, 10*time.Millisecond, "missing topics should be consumed")
+	mu.Lock()
+	assert.Equal(t, []string{"STALE"}, unsubscribed)
+	mu.Unlock()
+	e.lock.RLock()
+	assert.Equal(t, map[string]bool{"GRF": true, "ILO": true, "EXITED": true}, e.TopicsList, "topics should converge to the desired set")
+	e.lock.RUnlock()
+
+	// reconciling with the same set is a no-op
+	added, removed = e.Reconcile([]string{"EXITED", "GRF", "ILO"})
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+
+	// the unsubscribed topic is consumed again once it is advertised
+	added, _ = e.Reconcile([]string{"GRF", "ILO", "EXITED", "STALE"})
+	assert.Equal(t, []string{"STALE"}, added)
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return consumeCount["STALE"] == 2*workerCount
+	}, time.Second, 10*time.Millisecond, "resubscribed topic should be consumed")
+	e.lock.RLock()
+	assert.Equal(t, workerCount, e.runningConsumers["STALE"], "consumers of the unsubscribed topic should not be accounted")
+	e.lock.RUnlock()
+}
+
+func TestReconcileTopicsWithUnavailablePlugin(t *testing.T) {
+	config.SetUpMockConfig(t)
+	block := make(chan struct{})
+	defer close(block)
+	defer func() {
+		EMBConsumeFunc = consumer.Consume
+		EMBUnsubscribeFunc = consumer.Unsubscribe
+	}()
+	EMBConsumeFunc = func(topicName string) { <-block }
+	var unsubscribed []string
+	EMBUnsubscribeFunc = func(topicName string) error {
+		unsubscribed = append(unsubscribed, topicName)
+		return nil
+	}
+	EMBTopics.lock.Lock()
+	EMBTopics.TopicsList = map[string]bool{"DOWN": true}
+	EMBTopics.runningConsumers = nil
+	EMBTopics.lock.Unlock()
+	st := StartUpInteraface{EMBConsume: stubEMBConsume}
+
+	st.reconcileTopics([]string{"GRF"}, false)
+	assert.Empty(t, unsubscribed, "topics should not be unsubscribed while a plugin is unavailable")
+	assert.True(t, EMBTopics.IsConsumed("GRF"))
+
+	st.reconcileTopics([]string{"GRF"}, true)
+	assert.Equal(t, []string{"DOWN"}, unsubscribed, "stale topic should be unsubscribed once all the plugins are available")
+}
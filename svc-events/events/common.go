@@ -414,7 +414,7 @@ func getAggregateID(origin string) string {
 // callPlugin check the given request url and PreferAuth type plugin
 func (e *ExternalInterfaces) callPlugin(ctx context.Context, req evcommon.PluginContactRequest) (*http.Response, error) {
 	var reqURL = "https://" + req.Plugin.IP + ":" + req.Plugin.Port + req.URL
-	if strings.EqualFold(req.Plugin.PreferredAuthType, "BasicAuth") {
+	if strings.EqualFold(req.Plugin.AuthType(), "BasicAuth") {
 		return e.ContactClient(ctx, reqURL, req.HTTPMethodType, "", "", req.PostBody, req.LoginCredential)
 	}
 	return e.ContactClient(ctx, reqURL, req.HTTPMethodType, req.Token, "", req.PostBody, nil)
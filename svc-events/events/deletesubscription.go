@@ -395,7 +395,7 @@ func (e *ExternalInterfaces) subscribe(subscriptionPost evmodel.EvtSubPost, orig
 
 	var contactRequest evcommon.PluginContactRequest
 	contactRequest.Plugin = plugin
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		token := e.getPluginToken(plugin)
 		if token == "" {
 			return fmt.Errorf("error: Unable to create session with plugin " + plugin.ID)
@@ -474,7 +474,7 @@ func (e *ExternalInterfaces) DeleteFabricsSubscription(originResource string, pl
 	var contactRequest evcommon.PluginContactRequest
 
 	contactRequest.Plugin = plugin
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		token := e.getPluginToken(plugin)
 		if token == "" {
 			evcommon.GenErrorResponse("error: Unable to create session with plugin "+plugin.ID, response.NoValidSession, http.StatusUnauthorized,
@@ -498,7 +498,7 @@ func (e *ExternalInterfaces) DeleteFabricsSubscription(originResource string, pl
 	if err != nil {
 		return resp, err
 	}
-	if resp.StatusCode == http.StatusUnauthorized && strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if resp.StatusCode == http.StatusUnauthorized && strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		resp, _, _, err = e.retryEventOperation(contactRequest)
 		if err != nil {
 			return resp, err
@@ -545,7 +545,7 @@ func (e *ExternalInterfaces) resubscribeFabricsSubscription(subscriptionPost evm
 		var contactRequest evcommon.PluginContactRequest
 
 		contactRequest.Plugin = plugin
-		if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+		if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 			token := e.getPluginToken(plugin)
 			if token == "" {
 				return fmt.Errorf("error: Unable to create session with plugin " + plugin.ID)
@@ -580,7 +580,7 @@ func (e *ExternalInterfaces) resubscribeFabricsSubscription(subscriptionPost evm
 		if err != nil {
 			return err
 		}
-		if response.StatusCode == http.StatusUnauthorized && strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+		if response.StatusCode == http.StatusUnauthorized && strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 			_, _, _, err = e.retryEventOperation(contactRequest)
 			if err != nil {
 				return err
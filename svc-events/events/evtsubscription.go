@@ -341,7 +341,7 @@ func (e *ExternalInterfaces) eventSubscription(postRequest evmodel.RequestBody,
 		}
 
 		contactRequest.Plugin = plugin
-		if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+		if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 			token := e.getPluginToken(plugin)
 			if token == "" {
 				evcommon.GenEventErrorResponse("error: Unable to create session with plugin "+plugin.ID, errResponse.NoValidSession, http.StatusUnauthorized,
@@ -797,7 +797,7 @@ func (e *ExternalInterfaces) DeleteSubscriptions(originResource, token string, p
 	var contactRequest evcommon.PluginContactRequest
 
 	contactRequest.Plugin = plugin
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		token := e.getPluginToken(plugin)
 		if token == "" {
 			evcommon.GenErrorResponse("error: Unable to create session with plugin "+plugin.ID, errResponse.NoValidSession, http.StatusUnauthorized,
@@ -1022,7 +1022,7 @@ func (e *ExternalInterfaces) createFabricSubscription(postRequest evmodel.Reques
 		return "", resp
 	}
 	contactRequest.Plugin = plugin
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		token := e.getPluginToken(plugin)
 		if token == "" {
 			evcommon.GenEventErrorResponse("error: Unable to create session with plugin "+plugin.ID, errResponse.NoValidSession, http.StatusUnauthorized,
@@ -1110,7 +1110,7 @@ func (e *ExternalInterfaces) createFabricSubscription(postRequest evmodel.Reques
 	}
 	defer response.Body.Close()
 	//retrying the operation if status code is 401
-	if response.StatusCode == http.StatusUnauthorized && strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if response.StatusCode == http.StatusUnauthorized && strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		response, resp, err = e.retryEventSubscriptionOperation(contactRequest)
 		if err != nil {
 			return "", resp
@@ -1194,7 +1194,7 @@ func (e *ExternalInterfaces) UpdateEventSubscriptions(req *eventsproto.EventUpda
 	}
 
 	contactRequest.Plugin = plugin
-	if strings.EqualFold(plugin.PreferredAuthType, "XAuthToken") {
+	if strings.EqualFold(plugin.AuthType(), "XAuthToken") {
 		token := e.getPluginToken(plugin)
 		if token == "" {
 			l.Log.Info("error: Unable to create session with plugin " + plugin.ID)
@@ -56,6 +56,14 @@ const (
 	// AggregateSubscriptionIndex is a index name which required for indexing
 	// subscription of device
 	AggregateSubscriptionIndex = common.AggregateSubscriptionIndex
+
+	// TopicOffset holds table for the offset of the consumed EMB topics
+	TopicOffset = "TopicOffset"
+
+	// EMBProbe holds table for the probe events consumed from the EMB topics
+	EMBProbe = "EMBProbe"
+	// embProbeExpiryInSecs is the time the consumed probe is kept in DB
+	embProbeExpiryInSecs = 300
 )
 
 var (
@@ -157,6 +165,16 @@ type Plugin struct {
 	ID                string
 	PluginType        string
 	PreferredAuthType string
+	ForceBasicAuth    bool
+}
+
+// AuthType returns the auth type used to contact the plugin, BasicAuth is used
+// when it is forced for the plugin irrespective of the PreferredAuthType
+func (p Plugin) AuthType() string {
+	if p.ForceBasicAuth {
+		return "BasicAuth"
+	}
+	return p.PreferredAuthType
 }
 
 // Fabric is the model for fabrics information
@@ -172,7 +190,7 @@ type Aggregate struct {
 
 //GetResource fetches a resource from database using table and key
 func GetResource(Table, key string) (string, *errors.Error) {
-	conn, err := GetDbConnection(common.InMemory)
+	conn, err := GetDbConnection(common.GetTableDBType(Table, common.InMemory))
 	if err != nil {
 		return "", errors.PackError(err.ErrNo(), err)
 	}
@@ -190,7 +208,7 @@ func GetResource(Table, key string) (string, *errors.Error) {
 //GetTarget fetches the System(Target Device Credentials) table details
 func GetTarget(deviceUUID string) (*Target, error) {
 	var target Target
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +228,7 @@ func GetTarget(deviceUUID string) (*Target, error) {
 func GetPluginData(pluginID string) (*Plugin, *errors.Error) {
 	var plugin Plugin
 
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +253,7 @@ func GetPluginData(pluginID string) (*Plugin, *errors.Error) {
 
 //GetAllPlugins gets all the Plugin from the db
 func GetAllPlugins() ([]Plugin, *errors.Error) {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -269,7 +287,7 @@ func GetAllPlugins() ([]Plugin, *errors.Error) {
 
 //GetAllKeysFromTable return all matching data give table name
 func GetAllKeysFromTable(table string) ([]string, error) {
-	conn, err := GetDbConnection(common.InMemory)
+	conn, err := GetDbConnection(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return nil, err
 	}
@@ -282,7 +300,7 @@ func GetAllKeysFromTable(table string) ([]string, error) {
 
 //GetAllSystems retrieves all the compute systems in odimra
 func GetAllSystems() ([]string, error) {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -295,7 +313,7 @@ func GetAllSystems() ([]string, error) {
 
 //GetSingleSystem retrieves specific compute system in odimra based on the ID
 func GetSingleSystem(id string) (string, error) {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return "", errors.PackError(errors.UndefinedErrorType, err)
 	}
@@ -311,7 +329,7 @@ func GetSingleSystem(id string) (string, error) {
 func GetFabricData(fabricID string) (Fabric, error) {
 	var fabric Fabric
 
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("Fabric", common.OnDisk))
 	if err != nil {
 		return fabric, err
 	}
@@ -331,7 +349,7 @@ func GetFabricData(fabricID string) (Fabric, error) {
 // GetAggregateData  will fetch aggregate details
 func GetAggregateData(aggreagetKey string) (Aggregate, error) {
 	var aggregate Aggregate
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("Aggregate", common.OnDisk))
 	if err != nil {
 		return aggregate, err
 	}
@@ -348,7 +366,7 @@ func GetAggregateData(aggreagetKey string) (Aggregate, error) {
 
 //GetAllFabrics return all Fabrics
 func GetAllFabrics() ([]string, error) {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType("Fabric", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -505,7 +523,7 @@ func UpdateEventSubscription(evtSubscription Subscription) error {
 
 //GetAllMatchingDetails accepts the table name ,pattern and DB type and return all the keys which mathces the pattern
 func GetAllMatchingDetails(table, pattern string, dbtype common.DbType) ([]string, *errors.Error) {
-	conn, err := GetDbConnection(dbtype)
+	conn, err := GetDbConnection(common.GetTableDBType(table, dbtype))
 	if err != nil {
 		return []string{}, err
 	}
@@ -514,7 +532,7 @@ func GetAllMatchingDetails(table, pattern string, dbtype common.DbType) ([]strin
 
 // SaveUndeliveredEvents accepts the undelivered event and destination with unique eventid and saves it
 func SaveUndeliveredEvents(key string, event []byte) error {
-	connPool, err := GetDbConnection(common.OnDisk)
+	connPool, err := GetDbConnection(common.GetTableDBType(UndeliveredEvents, common.OnDisk))
 	if err != nil {
 		l.Log.Error("While trying to get DB Connection : " + err.Error())
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
@@ -528,7 +546,7 @@ func SaveUndeliveredEvents(key string, event []byte) error {
 
 // GetUndeliveredEvents read the undelivered events for the destination
 func GetUndeliveredEvents(destination string) (string, error) {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType(UndeliveredEvents, common.OnDisk))
 	if err != nil {
 		return "", fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
 	}
@@ -543,7 +561,7 @@ func GetUndeliveredEvents(destination string) (string, error) {
 
 // DeleteUndeliveredEvents deletes the undelivered events for the destination
 func DeleteUndeliveredEvents(destination string) error {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType(UndeliveredEvents, common.OnDisk))
 	if err != nil {
 		return fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
 	}
@@ -556,7 +574,7 @@ func DeleteUndeliveredEvents(destination string) error {
 // SetUndeliveredEventsFlag will set the flag to maintain one instance already picked up
 // the undelivered events for the destination
 func SetUndeliveredEventsFlag(destination string) error {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType(ReadInProgres, common.OnDisk))
 	if err != nil {
 		return fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
 	}
@@ -573,7 +591,7 @@ func SetUndeliveredEventsFlag(destination string) error {
 // GetUndeliveredEventsFlag will get the flag to maintain one instance already picked up
 // the undelivered events for the destination
 func GetUndeliveredEventsFlag(destination string) (bool, error) {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType(ReadInProgres, common.OnDisk))
 	if err != nil {
 		return false, fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
 	}
@@ -586,7 +604,7 @@ func GetUndeliveredEventsFlag(destination string) (bool, error) {
 
 // DeleteUndeliveredEventsFlag deletes the PickUpUndeliveredEventsFlag key from the DB, return error if any
 func DeleteUndeliveredEventsFlag(destination string) error {
-	conn, err := GetDbConnection(common.OnDisk)
+	conn, err := GetDbConnection(common.GetTableDBType(ReadInProgres, common.OnDisk))
 	if err != nil {
 		return fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
 	}
@@ -658,3 +676,65 @@ func GetAggregateList(hostIP string) ([]string, error) {
 	}
 	return aggregates, nil
 }
+
+// GetTopicOffset reads the stored offset of the EMB topic, the offset
+// is empty when it is not stored for the topic
+func GetTopicOffset(topicName string) (string, error) {
+	conn, err := GetDbConnection(common.GetTableDBType(TopicOffset, common.OnDisk))
+	if err != nil {
+		return "", fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
+	}
+	data, err := conn.Read(TopicOffset, topicName)
+	if err != nil {
+		if err.ErrNo() == errors.DBKeyNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("error: while trying to fetch details: %v", err.Error())
+	}
+	var offset string
+	if err := json.Unmarshal([]byte(data), &offset); err != nil {
+		return "", fmt.Errorf("error while trying to unmarshal the offset of the topic %v: %v", topicName, err.Error())
+	}
+	return offset, nil
+}
+
+// SaveTopicOffset stores the offset of the last consumed message of the EMB topic
+func SaveTopicOffset(topicName, offset string) error {
+	conn, err := GetDbConnection(common.GetTableDBType(TopicOffset, common.OnDisk))
+	if err != nil {
+		return fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
+	}
+	if err = conn.AddResourceData(TopicOffset, topicName, offset); err != nil {
+		return fmt.Errorf("error while trying to save the offset of the topic %v: %v", topicName, err.Error())
+	}
+	return nil
+}
+
+// SaveEMBProbe records the probe event with the ID as consumed, the record
+// expires once the probe is no longer verified
+func SaveEMBProbe(probeID string) error {
+	conn, err := GetDbConnection(common.GetTableDBType(EMBProbe, common.InMemory))
+	if err != nil {
+		return fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
+	}
+	// the probe published again is consumed more than once
+	if err = conn.SetExpire(EMBProbe, probeID, true, embProbeExpiryInSecs); err != nil && err.ErrNo() != errors.DBKeyAlreadyExist {
+		return fmt.Errorf("error while trying to save the probe %v: %v", probeID, err.Error())
+	}
+	return nil
+}
+
+// IsEMBProbeConsumed checks whether the probe event with the ID is consumed
+func IsEMBProbeConsumed(probeID string) (bool, error) {
+	conn, err := GetDbConnection(common.GetTableDBType(EMBProbe, common.InMemory))
+	if err != nil {
+		return false, fmt.Errorf("error: while trying to create connection with DB: %v", err.Error())
+	}
+	if _, err = conn.Read(EMBProbe, probeID); err != nil {
+		if err.ErrNo() == errors.DBKeyNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("error while trying to read the probe %v: %v", probeID, err.Error())
+	}
+	return true, nil
+}
@@ -707,6 +707,25 @@ func TestSaveUndeliveredEvents(t *testing.T) {
 	}
 }
 
+func TestSaveAndGetTopicOffset(t *testing.T) {
+	common.SetUpMockConfig()
+	defer func() {
+		err := common.TruncateDB(common.OnDisk)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}()
+	offset, err := GetTopicOffset("EVENTS")
+	assert.Nil(t, err, "error should be nil when offset is not stored")
+	assert.Empty(t, offset, "offset should be empty when it is not stored")
+	if cerr := SaveTopicOffset("EVENTS", "1526-0"); cerr != nil {
+		t.Errorf("Error while saving the topic offset : %v\n", cerr.Error())
+	}
+	offset, err = GetTopicOffset("EVENTS")
+	assert.Nil(t, err, "error should be nil")
+	assert.Equal(t, "1526-0", offset, "stored offset should be returned")
+}
+
 func TestGetUndeliveredEvents(t *testing.T) {
 	common.SetUpMockConfig()
 	defer func() {
@@ -14,6 +14,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -116,6 +117,11 @@ func main() {
 	}
 	go startUPInterface.SubscribePluginEMB()
 
+	// Restart the consumers of EMB topics which have exited
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	go evcommon.EMBTopics.MonitorConsumers(monitorCtx)
+
 	// Run server
 	if err := services.ODIMService.Run(); err != nil {
 		log.Fatal(err.Error())
@@ -373,6 +373,21 @@ func (e *Events) SubsribeEMB(ctx context.Context, req *eventsproto.SubscribeEMBR
 	return &resp, nil
 }
 
+//IsEMBConsumed defines the operations which handles the RPC request response
+// it checks whether all the given event message bus queues are being consumed,
+// by publishing a probe event on each queue and waiting for it to be consumed
+func (e *Events) IsEMBConsumed(ctx context.Context, req *eventsproto.SubscribeEMBRequest) (*eventsproto.SubscribeEMBResponse, error) {
+	var resp eventsproto.SubscribeEMBResponse
+	resp.Status = true
+	for _, queue := range req.EMBQueueName {
+		if err := evcommon.ProbeEMBConsumption(queue); err != nil {
+			l.Log.Warn("EMB queue " + queue + " of plugin " + req.PluginID + " is not being consumed: " + err.Error())
+			resp.Status = false
+		}
+	}
+	return &resp, nil
+}
+
 func generateTaskRespone(taskID, taskURI string, resp *eventsproto.EventSubResponse) {
 	commonResponse := response.Response{
 		OdataType:    common.TaskType,
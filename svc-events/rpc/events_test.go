@@ -20,10 +20,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ODIM-Project/ODIM/lib-utilities/common"
 	"github.com/ODIM-Project/ODIM/lib-utilities/config"
 	eventsproto "github.com/ODIM-Project/ODIM/lib-utilities/proto/events"
+	"github.com/ODIM-Project/ODIM/svc-events/consumer"
 	"github.com/ODIM-Project/ODIM/svc-events/evcommon"
 	"github.com/ODIM-Project/ODIM/svc-events/events"
 	"github.com/ODIM-Project/ODIM/svc-events/evmodel"
@@ -315,6 +319,62 @@ func TestSubscribeEMB(t *testing.T) {
 	assert.True(t, resp.Status, "status should be true")
 }
 
+func TestIsEMBConsumed(t *testing.T) {
+	defer func(probe func(string, string) error, consumed func(string) (bool, error), save func(string) error, timeout, interval time.Duration) {
+		evcommon.EMBProbeFunc, evcommon.EMBProbeConsumedFunc, consumer.SaveEMBProbeFunc = probe, consumed, save
+		evcommon.EMBProbeTimeout, evcommon.EMBProbeInterval = timeout, interval
+	}(evcommon.EMBProbeFunc, evcommon.EMBProbeConsumedFunc, consumer.SaveEMBProbeFunc, evcommon.EMBProbeTimeout, evcommon.EMBProbeInterval)
+	evcommon.EMBProbeTimeout = 100 * time.Millisecond
+	evcommon.EMBProbeInterval = 10 * time.Millisecond
+
+	var lock sync.Mutex
+	consumedProbes := make(map[string]bool)
+	consumer.SaveEMBProbeFunc = func(probeID string) error {
+		lock.Lock()
+		defer lock.Unlock()
+		consumedProbes[probeID] = true
+		return nil
+	}
+	evcommon.EMBProbeConsumedFunc = func(probeID string) (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return consumedProbes[probeID], nil
+	}
+	// the message bus delivers the probes of the consumed queue to the consumer, as decoded by the message bus
+	publishedProbes := make(map[string]int)
+	evcommon.EMBProbeFunc = func(topicName, probeID string) error {
+		publishedProbes[topicName]++
+		if topicName != "GRF-EVENTS" {
+			return nil
+		}
+		data, _ := json.Marshal(common.Events{EventType: consumer.EMBProbeEventType, Request: []byte(probeID)})
+		var probe interface{}
+		json.Unmarshal(data, &probe)
+		consumer.EventSubscriber(probe)
+		return nil
+	}
+	events := getMockPluginContactInitializer()
+	req := &eventsproto.SubscribeEMBRequest{
+		PluginID:     "GRF",
+		EMBQueueName: []string{"GRF-EVENTS"},
+	}
+	resp, err := events.IsEMBConsumed(context.Background(), req)
+	assert.Nil(t, err, "There should be no error")
+	assert.True(t, resp.Status, "status should be true for the queue which is consumed")
+	assert.Equal(t, 1, publishedProbes["GRF-EVENTS"], "probe should not be published again once consumed")
+
+	req.EMBQueueName = []string{"GRF-EVENTS", "notconsumed"}
+	resp, err = events.IsEMBConsumed(context.Background(), req)
+	assert.Nil(t, err, "There should be no error")
+	assert.False(t, resp.Status, "status should be false for the queue which is not consumed")
+	assert.True(t, publishedProbes["notconsumed"] > 1, "probe should be published again till the timeout")
+
+	req.EMBQueueName = []string{}
+	resp, err = events.IsEMBConsumed(context.Background(), req)
+	assert.Nil(t, err, "There should be no error")
+	assert.True(t, resp.Status, "status should be true when there are no queues")
+}
+
 func TestEvents_RemoveEventSubscriptionsRPC(t *testing.T) {
 	events := getMockPluginContactInitializer()
 
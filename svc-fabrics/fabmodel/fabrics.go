@@ -49,7 +49,7 @@ type Fabric struct {
 func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 	var plugin Plugin
 
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return plugin, err
 	}
@@ -74,7 +74,7 @@ func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 
 //GetAllFabricPluginDetails fetches all fabric plugin information from plugin table
 func GetAllFabricPluginDetails() ([]string, error) {
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -88,12 +88,12 @@ func GetAllFabricPluginDetails() ([]string, error) {
 // AddFabricData will add the fabric uuid and pluginid details into ondisk
 func (fabric *Fabric) AddFabricData(fabuuid string) error {
 
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	//Create a header for data entry
+	const table string = "Fabric"
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return errors.PackError(errors.UndefinedErrorType, err)
 	}
-	//Create a header for data entry
-	const table string = "Fabric"
 	//Save data into Database
 	if cerr := conn.Create(table, fabuuid, fabric); cerr != nil {
 		if errors.DBKeyAlreadyExist != cerr.ErrNo() {
@@ -107,12 +107,12 @@ func (fabric *Fabric) AddFabricData(fabuuid string) error {
 
 // RemoveFabricData will remove the fabric uuid and pluginid details into ondisk
 func (fabric *Fabric) RemoveFabricData(fabuuid string) error {
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	//Create a header for data entry
+	const table string = "Fabric"
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return errors.PackError(errors.UndefinedErrorType, err)
 	}
-	//Create a header for data entry
-	const table string = "Fabric"
 	//Save data into Database
 	if cerr := conn.Delete(table, fabuuid); cerr != nil {
 		return cerr
@@ -123,7 +123,7 @@ func (fabric *Fabric) RemoveFabricData(fabuuid string) error {
 //GetManagingPluginIDForFabricID fetches the fabric details
 func GetManagingPluginIDForFabricID(fabID string) (Fabric, error) {
 	var fabric Fabric
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Fabric", common.OnDisk))
 	if err != nil {
 		return fabric, err
 	}
@@ -142,7 +142,7 @@ func GetManagingPluginIDForFabricID(fabID string) (Fabric, error) {
 
 //GetAllTheFabrics fetches all the fabrics details
 func GetAllTheFabrics() ([]Fabric, error) {
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Fabric", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
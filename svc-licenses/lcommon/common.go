@@ -37,9 +37,15 @@ var (
 	ConfigFilePath string
 )
 
+// getTableDBConnection returns the connection pool of the DB configured for the table
+// in DBConf.TableDBType, the connection pool of dbtype is returned if none is configured
+func getTableDBConnection(table string, dbtype persistencemgr.DbType) (*persistencemgr.ConnPool, *errors.Error) {
+	return persistencemgr.GetDBConnection(persistencemgr.DbType(common.GetTableDBType(table, common.DbType(dbtype))))
+}
+
 // GetAllKeysFromTable fetches all keys in a given table
 func GetAllKeysFromTable(table string, dbtype persistencemgr.DbType) ([]string, error) {
-	conn, err := persistencemgr.GetDBConnection(dbtype)
+	conn, err := getTableDBConnection(table, dbtype)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +58,7 @@ func GetAllKeysFromTable(table string, dbtype persistencemgr.DbType) ([]string,
 
 // GetResource fetches a resource from database using table and key
 func GetResource(Table, key string, dbtype persistencemgr.DbType) (interface{}, *errors.Error) {
-	conn, err := persistencemgr.GetDBConnection(dbtype)
+	conn, err := getTableDBConnection(Table, dbtype)
 	if err != nil {
 		return "", err
 	}
@@ -70,7 +76,7 @@ func GetResource(Table, key string, dbtype persistencemgr.DbType) (interface{},
 // GetTarget fetches the System(Target Device Credentials) table details
 func GetTarget(deviceUUID string) (*model.Target, *errors.Error) {
 	var target model.Target
-	conn, err := persistencemgr.GetDBConnection(persistencemgr.OnDisk)
+	conn, err := getTableDBConnection("System", persistencemgr.OnDisk)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +94,7 @@ func GetTarget(deviceUUID string) (*model.Target, *errors.Error) {
 func GetPluginData(pluginID string) (*model.Plugin, *errors.Error) {
 	var plugin model.Plugin
 
-	conn, err := persistencemgr.GetDBConnection(persistencemgr.OnDisk)
+	conn, err := getTableDBConnection("Plugin", persistencemgr.OnDisk)
 	if err != nil {
 		return nil, err
 	}
@@ -202,7 +208,7 @@ func callPlugin(ctx context.Context, req model.PluginContactRequest) (*http.Resp
 
 // GenericSave will save any resource data into the database
 func GenericSave(ctx context.Context, body []byte, table string, key string) error {
-	connPool, err := persistencemgr.GetDBConnection(persistencemgr.OnDisk)
+	connPool, err := getTableDBConnection(table, persistencemgr.OnDisk)
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
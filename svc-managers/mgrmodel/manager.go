@@ -162,7 +162,7 @@ type UpdateBMCAccount struct {
 
 //GetResource fetches a resource from database using table and key
 func GetResource(Table, key string) (string, *errors.Error) {
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(Table, common.InMemory))
 	if err != nil {
 		return "", err
 	}
@@ -179,7 +179,7 @@ func GetResource(Table, key string) (string, *errors.Error) {
 
 //GetAllKeysFromTable fetches all keys in a given table
 func GetAllKeysFromTable(table string) ([]string, error) {
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +193,7 @@ func GetAllKeysFromTable(table string) ([]string, error) {
 // GetManagerByURL fetches computer manager details by URL from database
 func GetManagerByURL(url string) (string, *errors.Error) {
 	var manager string
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Managers", common.InMemory))
 	if err != nil {
 		// connection error
 		return manager, err
@@ -210,7 +210,7 @@ func GetManagerByURL(url string) (string, *errors.Error) {
 
 // UpdateData will modify the current details to given changes
 func UpdateData(key string, updateData map[string]interface{}, table string) error {
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return fmt.Errorf("error trying to connect DB: %v", err)
 	}
@@ -227,7 +227,7 @@ func UpdateData(key string, updateData map[string]interface{}, table string) err
 //GenericSave will save any resource data into the database
 func GenericSave(body []byte, table string, key string) error {
 
-	connPool, err := GetDBConnectionFunc(common.InMemory)
+	connPool, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return fmt.Errorf("error trying to connect DB: %v", err.Error())
 	}
@@ -244,7 +244,7 @@ func AddManagertoDB(mgr RAManager) error {
 	if err != nil {
 		return fmt.Errorf("error trying to marshal manager data: %v", err)
 	}
-	connPool, connErr := GetDBConnectionFunc(common.InMemory)
+	connPool, connErr := GetDBConnectionFunc(common.GetTableDBType("Managers", common.InMemory))
 	if connErr != nil {
 		return fmt.Errorf("error trying to connect DB: %v", connErr.Error())
 	}
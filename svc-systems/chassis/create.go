@@ -64,7 +64,7 @@ func (h *Create) Handle(ctx context.Context, req *chassisproto.CreateChassisRequ
 		return common.GeneralError(http.StatusBadRequest, response.PropertyMissing, "", []interface{}{"Links.ManagedBy[0]"}, nil)
 	}
 
-	inMemoryConn, dbErr := GetDbConnectFunc(common.InMemory)
+	inMemoryConn, dbErr := GetDbConnectFunc(common.GetTableDBType("Managers", common.InMemory))
 	if dbErr != nil {
 		return common.GeneralError(http.StatusInternalServerError, response.InternalError, fmt.Sprintf("cannot acquire database connection: %v", dbErr), nil, nil)
 	}
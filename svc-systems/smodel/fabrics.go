@@ -34,7 +34,7 @@ var (
 // GetFabricManagers fetches all the fabrics details from DB
 func GetFabricManagers(ctx context.Context) ([]Plugin, error) {
 	l.LogWithFields(ctx).Debugf("incoming GetFabricManagers request")
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Fabric", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
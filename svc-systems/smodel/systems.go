@@ -87,7 +87,7 @@ type OdataIDLink struct {
 func GetSystemByUUID(ctx context.Context, systemUUID string) (string, *errors.Error) {
 	l.LogWithFields(ctx).Debugf("incoming GetSystemByUUID request with systemUUID: %s", systemUUID)
 	var system string
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("ComputerSystem", common.InMemory))
 	if err != nil {
 		// connection error
 		return system, err
@@ -106,7 +106,7 @@ func GetSystemByUUID(ctx context.Context, systemUUID string) (string, *errors.Er
 // GetResource fetches a resource from database using table and key
 func GetResource(ctx context.Context, Table, key string) (string, *errors.Error) {
 	l.LogWithFields(ctx).Debugf("incoming GetResource request with Table: %s, key: %s", Table, key)
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(Table, common.InMemory))
 	if err != nil {
 		return "", err
 	}
@@ -123,7 +123,7 @@ func GetResource(ctx context.Context, Table, key string) (string, *errors.Error)
 
 // Find fetches a resource from database using table and key and store the data to an interface
 func Find(table, key string, r interface{}) *errors.Error {
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return err
 	}
@@ -198,7 +198,7 @@ func scan(cp *persistencemgr.ConnPool, key string) ([]interface{}, error) {
 
 // GetAllKeysFromTable fetches all keys in a given table
 func GetAllKeysFromTable(table string) ([]string, error) {
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +213,7 @@ func GetAllKeysFromTable(table string) ([]string, error) {
 func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 	var plugin Plugin
 
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return plugin, err
 	}
@@ -239,7 +239,7 @@ func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 // GetTarget fetches the System(Target Device Credentials) table details
 func GetTarget(deviceUUID string) (*Target, *errors.Error) {
 	var target Target
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +255,7 @@ func GetTarget(deviceUUID string) (*Target, *errors.Error) {
 
 // GenericSave will save any resource data into the database
 func GenericSave(ctx context.Context, body []byte, table string, key string) error {
-	connPool, err := GetDBConnectionFunc(common.InMemory)
+	connPool, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -331,12 +331,12 @@ func GetRange(index string, min, max int, regexFlag bool) ([]string, error) {
 */
 func AddSystemResetInfo(ctx context.Context, systemID, resetType string) *errors.Error {
 	l.LogWithFields(ctx).Debugf("incoming AddSystemResetInfo request for SystemID: %s, resetType: %s", systemID, resetType)
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	//Create a header for data entry
+	const table string = "SystemReset"
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return err
 	}
-	//Create a header for data entry
-	const table string = "SystemReset"
 	//Save data into Database
 	if err = conn.AddResourceData(table, systemID, map[string]string{
 		"ResetType": resetType,
@@ -354,7 +354,7 @@ func GetSystemResetInfo(ctx context.Context, systemURI string) (map[string]strin
 	l.LogWithFields(ctx).Debugf("incoming GetSystemResetInfo request for URI: %s", systemURI)
 	var resetInfo map[string]string
 
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("SystemReset", common.InMemory))
 	if err != nil {
 		return resetInfo, err
 	}
@@ -373,7 +373,7 @@ func GetSystemResetInfo(ctx context.Context, systemURI string) (map[string]strin
 // DeleteVolume will delete the volume from InMemory
 func DeleteVolume(ctx context.Context, key string) *errors.Error {
 	l.LogWithFields(ctx).Debugf("incoming DeleteVolume request for key: %s", key)
-	connPool, err := GetDBConnectionFunc(common.InMemory)
+	connPool, err := GetDBConnectionFunc(common.GetTableDBType("Volumes", common.InMemory))
 	if err != nil {
 		return errors.PackError(err.ErrNo(), "error while trying to connecting to DB: ", err.Error())
 	}
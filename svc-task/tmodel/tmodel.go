@@ -145,7 +145,7 @@ type Oem struct {
 //	t pointer to Task to be stored.
 //	db of type common.DbType(int32)
 func PersistTask(ctx context.Context, t *Task, db common.DbType) error {
-	connPool, err := common.GetDBConnection(db)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("task", db))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -163,7 +163,7 @@ func PersistTask(ctx context.Context, t *Task, db common.DbType) error {
 //      On Success - return nil value
 //      On Failure - return non nill value
 func DeleteTaskFromDB(ctx context.Context, t *Task) error {
-	connPool, err := common.GetDBConnection(common.InMemory)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("task", common.InMemory))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -185,7 +185,7 @@ func DeleteTaskFromDB(ctx context.Context, t *Task) error {
 func GetTaskStatus(ctx context.Context, taskID string, db common.DbType) (*Task, error) {
 	task := new(Task)
 	var taskData string
-	connPool, err := common.GetDBConnection(common.InMemory)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("task", common.InMemory))
 	if err != nil {
 		l.LogWithFields(ctx).Error("GetTaskStatus : error while trying to get DB Connection : " + err.Error())
 		return task, fmt.Errorf("error while trying to connnect to DB: %v", err.Error())
@@ -210,7 +210,7 @@ func GetTaskStatus(ctx context.Context, taskID string, db common.DbType) (*Task,
 //	On Failure - error is set to appropriate reason why it got failed
 //	and slice of task is set to nil
 func GetAllTaskKeys(ctx context.Context) ([]string, error) {
-	connPool, err := common.GetDBConnection(common.InMemory)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("task", common.InMemory))
 	if err != nil {
 		return nil, fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -237,7 +237,7 @@ func Transaction(ctx context.Context, key string, cb func(context.Context, strin
 // Returns error with non nil value if username is not found in the db,
 // if username found in the db error is set to nil.
 func ValidateTaskUserName(ctx context.Context, userName string) error {
-	connPool, err := common.GetDBConnection(common.OnDisk)
+	connPool, err := common.GetDBConnection(common.GetTableDBType("User", common.OnDisk))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err)
 	}
@@ -53,7 +53,7 @@ var (
 
 // GetResource fetches a resource from database using table and key
 func GetResource(Table, key string, dbtype common.DbType) (string, *errors.Error) {
-	conn, err := GetDBConnectionFunc(common.InMemory)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(Table, dbtype))
 	if err != nil {
 		return "", err
 	}
@@ -70,7 +70,7 @@ func GetResource(Table, key string, dbtype common.DbType) (string, *errors.Error
 
 // GetAllKeysFromTable fetches all keys in a given table
 func GetAllKeysFromTable(table string, dbtype common.DbType) ([]string, error) {
-	conn, err := GetDBConnectionFunc(dbtype)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType(table, dbtype))
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +85,7 @@ func GetAllKeysFromTable(table string, dbtype common.DbType) ([]string, error) {
 func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 	var plugin Plugin
 
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return plugin, err
 	}
@@ -111,7 +111,7 @@ func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 // GetTarget fetches the System(Target Device Credentials) table details
 func GetTarget(deviceUUID string) (*Target, *errors.Error) {
 	var target Target
-	conn, err := GetDBConnectionFunc(common.OnDisk)
+	conn, err := GetDBConnectionFunc(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +127,7 @@ func GetTarget(deviceUUID string) (*Target, *errors.Error) {
 
 // GenericSave will save any resource data into the database
 func GenericSave(ctx context.Context, body []byte, table string, key string) error {
-	connPool, err := GetDBConnectionFunc(common.InMemory)
+	connPool, err := GetDBConnectionFunc(common.GetTableDBType(table, common.InMemory))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -277,6 +277,35 @@ func TestGenericSave(t *testing.T) {
 
 }
 
+func TestGenericSaveWithRemappedTable(t *testing.T) {
+	config.SetUpMockConfig(t)
+	config.Data.DBConf.TableDBType = map[string]string{
+		"MetricReports": config.OnDiskDBType,
+	}
+	defer func() {
+		config.Data.DBConf.TableDBType = nil
+		common.TruncateDB(common.OnDisk)
+		common.TruncateDB(common.InMemory)
+	}()
+	err := GenericSave(mockContext(), []byte("someReport"), "MetricReports", "someKey")
+	assert.Nil(t, err, "There should be no error")
+
+	// data should be written to the configured store and not to the default one
+	onDiskConn, _ := common.GetDBConnection(common.OnDisk)
+	_, dbErr := onDiskConn.Read("MetricReports", "someKey")
+	assert.Nil(t, dbErr, "remapped table data should be in OnDisk DB")
+	inMemoryConn, _ := common.GetDBConnection(common.InMemory)
+	_, dbErr = inMemoryConn.Read("MetricReports", "someKey")
+	assert.NotNil(t, dbErr, "remapped table data should not be in InMemory DB")
+
+	data, dbErr := GetResource("MetricReports", "someKey", common.InMemory)
+	assert.Nil(t, dbErr, "There should be no error")
+	assert.Equal(t, "someReport", data)
+	keys, keysErr := GetAllKeysFromTable("MetricReports", common.InMemory)
+	assert.Nil(t, keysErr, "There should be no error")
+	assert.Equal(t, []string{"someKey"}, keys)
+}
+
 func mockContext() context.Context {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, common.TransactionID, "xyz")
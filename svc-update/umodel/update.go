@@ -48,7 +48,7 @@ type Plugin struct {
 
 // GetAllKeysFromTable fetches all keys in a given table
 func GetAllKeysFromTable(table string, dbtype common.DbType) ([]string, error) {
-	conn, err := common.GetDBConnection(dbtype)
+	conn, err := common.GetDBConnection(common.GetTableDBType(table, dbtype))
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +61,7 @@ func GetAllKeysFromTable(table string, dbtype common.DbType) ([]string, error) {
 
 // GetResource fetches a resource from database using table and key
 func GetResource(Table, key string, dbtype common.DbType) (string, *errors.Error) {
-	conn, err := common.GetDBConnection(dbtype)
+	conn, err := common.GetDBConnection(common.GetTableDBType(Table, dbtype))
 	if err != nil {
 		return "", err
 	}
@@ -78,7 +78,7 @@ func GetResource(Table, key string, dbtype common.DbType) (string, *errors.Error
 
 // GenericSave will save any resource data into the database
 func GenericSave(ctx context.Context, body []byte, table string, key string) error {
-	connPool, err := common.GetDBConnection(common.OnDisk)
+	connPool, err := common.GetDBConnection(common.GetTableDBType(table, common.OnDisk))
 	if err != nil {
 		return fmt.Errorf("error while trying to connecting to DB: %v", err.Error())
 	}
@@ -94,7 +94,7 @@ func GenericSave(ctx context.Context, body []byte, table string, key string) err
 // GetTarget fetches the System(Target Device Credentials) table details
 func GetTarget(deviceUUID string) (*Target, *errors.Error) {
 	var target Target
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("System", common.OnDisk))
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +112,7 @@ func GetTarget(deviceUUID string) (*Target, *errors.Error) {
 func GetPluginData(pluginID string) (Plugin, *errors.Error) {
 	var plugin Plugin
 
-	conn, err := common.GetDBConnection(common.OnDisk)
+	conn, err := common.GetDBConnection(common.GetTableDBType("Plugin", common.OnDisk))
 	if err != nil {
 		return plugin, err
 	}